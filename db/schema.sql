@@ -17,6 +17,43 @@
 SET NAMES utf8mb4;
 SET FOREIGN_KEY_CHECKS = 0;
 
+-- ----------------------------
+-- Table structure for assignment_extensions
+-- ----------------------------
+DROP TABLE IF EXISTS `assignment_extensions`;
+CREATE TABLE `assignment_extensions` (
+  `assignments_id` int(10) unsigned NOT NULL,
+  `users_id` int(10) unsigned NOT NULL,
+  `due_date` datetime NOT NULL,
+  `close_date` datetime DEFAULT NULL,
+  `reason` varchar(1000) NOT NULL,
+  `granted_by` int(10) unsigned NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`assignments_id`,`users_id`) USING BTREE,
+  KEY `fk_assignment_extensions_users` (`users_id`) USING BTREE,
+  CONSTRAINT `fk_assignment_extensions_assignments` FOREIGN KEY (`assignments_id`) REFERENCES `assignments` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `fk_assignment_extensions_users` FOREIGN KEY (`users_id`) REFERENCES `users` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for assignment_groups
+-- ----------------------------
+DROP TABLE IF EXISTS `assignment_groups`;
+CREATE TABLE `assignment_groups` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `schedules_id` int(10) unsigned NOT NULL,
+  `assignments_id` int(10) unsigned DEFAULT NULL,
+  `name` varchar(50) NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  KEY `fk_assignment_groups_schedules` (`schedules_id`) USING BTREE,
+  KEY `fk_assignment_groups_assignments` (`assignments_id`) USING BTREE,
+  CONSTRAINT `fk_assignment_groups_assignments` FOREIGN KEY (`assignments_id`) REFERENCES `assignments` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `fk_assignment_groups_schedules` FOREIGN KEY (`schedules_id`) REFERENCES `schedules` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
 -- ----------------------------
 -- Table structure for assignments
 -- ----------------------------
@@ -32,8 +69,24 @@ CREATE TABLE `assignments` (
   `updated_at` datetime NOT NULL,
   `max_size` int(11) DEFAULT NULL,
   `type` varchar(0) DEFAULT NULL,
+  `late_policy` tinyint(3) unsigned NOT NULL DEFAULT '0',
+  `grace_period` int(10) unsigned NOT NULL DEFAULT '0',
+  `late_penalty` float(5,2) unsigned NOT NULL DEFAULT '0.00',
+  `close_date` datetime DEFAULT NULL,
+  `group_mode` tinyint(3) unsigned NOT NULL DEFAULT '0',
+  `rubrics_id` int(10) unsigned DEFAULT NULL,
+  `publish_at` datetime DEFAULT NULL,
+  `unpublish_at` datetime DEFAULT NULL,
+  `notified_at` datetime DEFAULT NULL,
+  `peer_reviewers` tinyint(3) unsigned NOT NULL DEFAULT '0',
+  `peer_review_close` datetime DEFAULT NULL,
+  `peer_weight` float(5,2) unsigned NOT NULL DEFAULT '0.00',
+  `peer_assigned_at` datetime DEFAULT NULL,
+  `anonymous_key` varchar(32) DEFAULT NULL,
+  `revealed_at` datetime DEFAULT NULL,
   PRIMARY KEY (`id`) USING BTREE,
   KEY `fk_assigments_gradeparameter1` (`grade_parameters_id`) USING BTREE,
+  KEY `fk_assignments_rubrics` (`rubrics_id`) USING BTREE,
   CONSTRAINT `fk_assigments_gradeparameter1` FOREIGN KEY (`grade_parameters_id`) REFERENCES `grade_parameters` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
 ) ENGINE=InnoDB AUTO_INCREMENT=9999123 DEFAULT CHARSET=utf8;
 
@@ -52,6 +105,64 @@ CREATE TABLE `attendances` (
   CONSTRAINT `fk_attendances_users` FOREIGN KEY (`users_id`) REFERENCES `users` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 
+-- ----------------------------
+-- Table structure for autograder_results
+-- ----------------------------
+DROP TABLE IF EXISTS `autograder_results`;
+CREATE TABLE `autograder_results` (
+  `autograder_runs_id` int(10) unsigned NOT NULL,
+  `users_id` int(10) unsigned NOT NULL,
+  `status` tinyint(1) unsigned NOT NULL DEFAULT '0',
+  `language` varchar(10) DEFAULT NULL,
+  `compile_output` text,
+  `passed` smallint(5) unsigned NOT NULL DEFAULT '0',
+  `total` smallint(5) unsigned NOT NULL DEFAULT '0',
+  `suggested_score` float(5,2) unsigned DEFAULT NULL,
+  `tests` mediumtext NOT NULL,
+  `confirmed_by` int(10) unsigned DEFAULT NULL,
+  `confirmed_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`autograder_runs_id`,`users_id`) USING BTREE,
+  KEY `fk_autograder_results_users` (`users_id`) USING BTREE,
+  CONSTRAINT `fk_autograder_results_runs` FOREIGN KEY (`autograder_runs_id`) REFERENCES `autograder_runs` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION,
+  CONSTRAINT `fk_autograder_results_users` FOREIGN KEY (`users_id`) REFERENCES `users` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for autograder_runs
+-- ----------------------------
+DROP TABLE IF EXISTS `autograder_runs`;
+CREATE TABLE `autograder_runs` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `assignments_id` int(10) unsigned NOT NULL,
+  `status` tinyint(1) unsigned NOT NULL DEFAULT '0',
+  `message` varchar(255) DEFAULT NULL,
+  `created_by` int(10) unsigned NOT NULL,
+  `created_at` datetime NOT NULL,
+  `finished_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  KEY `fk_autograder_runs_assignments` (`assignments_id`) USING BTREE,
+  CONSTRAINT `fk_autograder_runs_assignments` FOREIGN KEY (`assignments_id`) REFERENCES `assignments` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for autograder_tests
+-- ----------------------------
+DROP TABLE IF EXISTS `autograder_tests`;
+CREATE TABLE `autograder_tests` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `assignments_id` int(10) unsigned NOT NULL,
+  `name` varchar(100) NOT NULL,
+  `input` mediumtext NOT NULL,
+  `expected_output` mediumtext NOT NULL,
+  `timeout` int(10) unsigned NOT NULL,
+  `points` float(5,2) unsigned NOT NULL DEFAULT '1.00',
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  KEY `fk_autograder_tests_assignments` (`assignments_id`) USING BTREE,
+  CONSTRAINT `fk_autograder_tests_assignments` FOREIGN KEY (`assignments_id`) REFERENCES `assignments` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
 -- ----------------------------
 -- Table structure for bot_logs
 -- ----------------------------
@@ -100,6 +211,65 @@ CREATE TABLE `files` (
   CONSTRAINT `fk_files_users` FOREIGN KEY (`users_id`) REFERENCES `users` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 
+-- ----------------------------
+-- Table structure for forum_replies
+-- ----------------------------
+DROP TABLE IF EXISTS `forum_replies`;
+CREATE TABLE `forum_replies` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `content` text NOT NULL,
+  `forum_threads_id` int(10) unsigned NOT NULL,
+  `users_id` int(10) unsigned NOT NULL,
+  `status` tinyint(1) unsigned NOT NULL DEFAULT '1' COMMENT '0 = deleted, 1 = exist',
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  KEY `fk_forum_replies_forum_threads` (`forum_threads_id`) USING BTREE,
+  CONSTRAINT `fk_forum_replies_forum_threads` FOREIGN KEY (`forum_threads_id`) REFERENCES `forum_threads` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for forum_threads
+-- ----------------------------
+DROP TABLE IF EXISTS `forum_threads`;
+CREATE TABLE `forum_threads` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `title` varchar(100) NOT NULL,
+  `content` text NOT NULL,
+  `schedules_id` int(10) unsigned NOT NULL,
+  `users_id` int(10) unsigned NOT NULL,
+  `table_name` varchar(45) DEFAULT NULL COMMENT 'tutorials, assignments or meetings',
+  `table_id` int(10) unsigned DEFAULT NULL,
+  `accepted_replies_id` int(10) unsigned DEFAULT NULL,
+  `is_pinned` tinyint(1) unsigned NOT NULL DEFAULT '0',
+  `is_locked` tinyint(1) unsigned NOT NULL DEFAULT '0',
+  `status` tinyint(1) unsigned NOT NULL DEFAULT '1' COMMENT '0 = deleted, 1 = exist',
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  KEY `fk_forum_threads_schedules` (`schedules_id`) USING BTREE,
+  KEY `idx_forum_threads_relation` (`table_name`,`table_id`) USING BTREE,
+  CONSTRAINT `fk_forum_threads_schedules` FOREIGN KEY (`schedules_id`) REFERENCES `schedules` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for grade_parameter_overrides
+-- ----------------------------
+DROP TABLE IF EXISTS `grade_parameter_overrides`;
+CREATE TABLE `grade_parameter_overrides` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `schedules_id` int(10) unsigned NOT NULL,
+  `type` enum('QUIZ','ASSIGNMENT','FINAL','MID','ATTENDANCE') NOT NULL,
+  `old_percentage` float(5,2) unsigned DEFAULT NULL,
+  `new_percentage` float(5,2) unsigned DEFAULT NULL,
+  `reason` text NOT NULL,
+  `users_id` int(10) unsigned NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  KEY `fk_grade_parameter_overrides_schedules` (`schedules_id`) USING BTREE,
+  CONSTRAINT `fk_grade_parameter_overrides_schedules` FOREIGN KEY (`schedules_id`) REFERENCES `schedules` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
 -- ----------------------------
 -- Table structure for grade_parameters
 -- ----------------------------
@@ -118,6 +288,86 @@ CREATE TABLE `grade_parameters` (
   CONSTRAINT `fk_grade_parameters_schedules` FOREIGN KEY (`schedules_id`) REFERENCES `schedules` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
 ) ENGINE=InnoDB AUTO_INCREMENT=1231232 DEFAULT CHARSET=utf8;
 
+-- ----------------------------
+-- Table structure for grade_policies
+-- ----------------------------
+DROP TABLE IF EXISTS `grade_policies`;
+CREATE TABLE `grade_policies` (
+  `schedules_id` int(10) unsigned NOT NULL,
+  `missing` tinyint(1) unsigned NOT NULL DEFAULT '0' COMMENT '0 = counted as zero, 1 = excluded',
+  `drop_lowest` tinyint(3) unsigned NOT NULL DEFAULT '0',
+  `is_capped` tinyint(1) unsigned NOT NULL DEFAULT '0',
+  `rounding` tinyint(1) unsigned NOT NULL DEFAULT '0' COMMENT '0 = half up, 1 = down, 2 = up',
+  `decimals` tinyint(1) unsigned NOT NULL DEFAULT '2',
+  `regrade_days` smallint(5) unsigned NOT NULL DEFAULT '7' COMMENT 'days after the final grade is published until regrade requests are closed',
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`schedules_id`) USING BTREE,
+  CONSTRAINT `fk_grade_policies_schedules` FOREIGN KEY (`schedules_id`) REFERENCES `schedules` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for grade_publications
+-- ----------------------------
+DROP TABLE IF EXISTS `grade_publications`;
+CREATE TABLE `grade_publications` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `schedules_id` int(10) unsigned NOT NULL,
+  `status` tinyint(1) unsigned NOT NULL DEFAULT '1' COMMENT '0 = unpublished, 1 = published',
+  `published_by` int(10) unsigned NOT NULL,
+  `unpublished_by` int(10) unsigned DEFAULT NULL,
+  `reason` text,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  KEY `fk_grade_publications_schedules` (`schedules_id`) USING BTREE,
+  CONSTRAINT `fk_grade_publications_schedules` FOREIGN KEY (`schedules_id`) REFERENCES `schedules` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for grade_scales
+-- ----------------------------
+DROP TABLE IF EXISTS `grade_scales`;
+CREATE TABLE `grade_scales` (
+  `schedules_id` int(10) unsigned NOT NULL,
+  `letter` varchar(2) NOT NULL,
+  `min_score` float(5,2) unsigned NOT NULL,
+  `grade_point` float(3,2) unsigned NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`schedules_id`,`letter`) USING BTREE,
+  CONSTRAINT `fk_grade_scales_schedules` FOREIGN KEY (`schedules_id`) REFERENCES `schedules` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for grade_snapshot_parameters
+-- ----------------------------
+DROP TABLE IF EXISTS `grade_snapshot_parameters`;
+CREATE TABLE `grade_snapshot_parameters` (
+  `grade_publications_id` int(10) unsigned NOT NULL,
+  `users_id` int(10) unsigned NOT NULL,
+  `grade_parameters_id` int(10) unsigned NOT NULL,
+  `type` enum('QUIZ','ASSIGNMENT','FINAL','MID','ATTENDANCE') NOT NULL,
+  `score` float(6,2) unsigned NOT NULL,
+  PRIMARY KEY (`grade_publications_id`,`users_id`,`grade_parameters_id`) USING BTREE,
+  CONSTRAINT `fk_grade_snapshot_parameters_grade_publications` FOREIGN KEY (`grade_publications_id`) REFERENCES `grade_publications` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for grade_snapshots
+-- ----------------------------
+DROP TABLE IF EXISTS `grade_snapshots`;
+CREATE TABLE `grade_snapshots` (
+  `grade_publications_id` int(10) unsigned NOT NULL,
+  `users_id` int(10) unsigned NOT NULL,
+  `total` float(6,2) unsigned NOT NULL,
+  `letter` varchar(2) NOT NULL,
+  `grade_point` float(3,2) unsigned NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`grade_publications_id`,`users_id`) USING BTREE,
+  CONSTRAINT `fk_grade_snapshots_grade_publications` FOREIGN KEY (`grade_publications_id`) REFERENCES `grade_publications` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
 -- ----------------------------
 -- Table structure for informations
 -- ----------------------------
@@ -127,8 +377,13 @@ CREATE TABLE `informations` (
   `title` varchar(45) DEFAULT NULL,
   `description` text,
   `schedules_id` int(10) unsigned DEFAULT NULL,
+  `status` tinyint(1) unsigned NOT NULL DEFAULT '0' COMMENT '0 = general, 1 = material',
+  `is_pinned` tinyint(1) unsigned NOT NULL DEFAULT '0',
+  `created_by` int(10) unsigned NOT NULL,
   `created_at` datetime NOT NULL,
   `updated_at` datetime NOT NULL,
+  `publish_at` datetime DEFAULT NULL,
+  `unpublish_at` datetime DEFAULT NULL,
   PRIMARY KEY (`id`) USING BTREE,
   KEY `fk_informations_courses1_idx` (`schedules_id`) USING BTREE,
   CONSTRAINT `fk_informations_courses1` FOREIGN KEY (`schedules_id`) REFERENCES `schedules` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
@@ -207,6 +462,20 @@ CREATE TABLE `notifications` (
   CONSTRAINT `fk_notifications_users1` FOREIGN KEY (`users_id`) REFERENCES `users` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 
+-- ----------------------------
+-- Table structure for p_users_assignment_groups
+-- ----------------------------
+DROP TABLE IF EXISTS `p_users_assignment_groups`;
+CREATE TABLE `p_users_assignment_groups` (
+  `assignment_groups_id` int(10) unsigned NOT NULL,
+  `users_id` int(10) unsigned NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`assignment_groups_id`,`users_id`) USING BTREE,
+  KEY `fk_p_users_assignment_groups_users` (`users_id`) USING BTREE,
+  CONSTRAINT `fk_p_users_assignment_groups_groups` FOREIGN KEY (`assignment_groups_id`) REFERENCES `assignment_groups` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `fk_p_users_assignment_groups_users` FOREIGN KEY (`users_id`) REFERENCES `users` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
 -- ----------------------------
 -- Table structure for p_users_assignments
 -- ----------------------------
@@ -216,6 +485,12 @@ CREATE TABLE `p_users_assignments` (
   `users_id` int(10) unsigned NOT NULL,
   `score` float(5,2) unsigned DEFAULT NULL,
   `description` text,
+  `feedback` text,
+  `late_seconds` int(10) unsigned NOT NULL DEFAULT '0',
+  `assignment_groups_id` int(10) unsigned DEFAULT NULL,
+  `group_score` float(5,2) unsigned DEFAULT NULL,
+  `adjustment` float(5,2) NOT NULL DEFAULT '0.00',
+  `pinned_version` int(10) unsigned DEFAULT NULL,
   `created_at` datetime NOT NULL,
   `updated_at` datetime NOT NULL,
   PRIMARY KEY (`assignments_id`,`users_id`) USING BTREE,
@@ -256,6 +531,48 @@ CREATE TABLE `p_users_schedules` (
   CONSTRAINT `fk_users_has_lectures_users1` FOREIGN KEY (`users_id`) REFERENCES `users` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 
+-- ----------------------------
+-- Table structure for peer_review_scores
+-- ----------------------------
+DROP TABLE IF EXISTS `peer_review_scores`;
+CREATE TABLE `peer_review_scores` (
+  `peer_reviews_id` int(10) unsigned NOT NULL,
+  `rubric_criteria_id` int(10) unsigned NOT NULL,
+  `rubric_levels_id` int(10) unsigned NOT NULL,
+  `points` float(5,2) unsigned NOT NULL,
+  `comment` text,
+  PRIMARY KEY (`peer_reviews_id`,`rubric_criteria_id`) USING BTREE,
+  KEY `fk_peer_review_scores_rubric_criteria` (`rubric_criteria_id`) USING BTREE,
+  KEY `fk_peer_review_scores_rubric_levels` (`rubric_levels_id`) USING BTREE,
+  CONSTRAINT `fk_peer_review_scores_peer_reviews` FOREIGN KEY (`peer_reviews_id`) REFERENCES `peer_reviews` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION,
+  CONSTRAINT `fk_peer_review_scores_rubric_criteria` FOREIGN KEY (`rubric_criteria_id`) REFERENCES `rubric_criteria` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `fk_peer_review_scores_rubric_levels` FOREIGN KEY (`rubric_levels_id`) REFERENCES `rubric_levels` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for peer_reviews
+-- ----------------------------
+DROP TABLE IF EXISTS `peer_reviews`;
+CREATE TABLE `peer_reviews` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `assignments_id` int(10) unsigned NOT NULL,
+  `reviewer_id` int(10) unsigned NOT NULL,
+  `users_id` int(10) unsigned NOT NULL,
+  `status` tinyint(1) unsigned NOT NULL DEFAULT '0' COMMENT '0 = assigned, 1 = submitted, 2 = excluded',
+  `score` float(5,2) unsigned DEFAULT NULL,
+  `comment` varchar(1000) DEFAULT NULL,
+  `moderated_by` int(10) unsigned DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE KEY `uq_peer_reviews` (`assignments_id`,`reviewer_id`,`users_id`) USING BTREE,
+  KEY `fk_peer_reviews_reviewer` (`reviewer_id`) USING BTREE,
+  KEY `fk_peer_reviews_users` (`users_id`) USING BTREE,
+  CONSTRAINT `fk_peer_reviews_assignments` FOREIGN KEY (`assignments_id`) REFERENCES `assignments` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `fk_peer_reviews_reviewer` FOREIGN KEY (`reviewer_id`) REFERENCES `users` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `fk_peer_reviews_users` FOREIGN KEY (`users_id`) REFERENCES `users` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
 -- ----------------------------
 -- Table structure for places
 -- ----------------------------
@@ -268,6 +585,138 @@ CREATE TABLE `places` (
   PRIMARY KEY (`id`) USING BTREE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 
+-- ----------------------------
+-- Table structure for quiz_answers
+-- ----------------------------
+DROP TABLE IF EXISTS `quiz_answers`;
+CREATE TABLE `quiz_answers` (
+  `quiz_attempts_id` int(10) unsigned NOT NULL,
+  `quiz_questions_id` int(10) unsigned NOT NULL,
+  `position` int(10) unsigned NOT NULL,
+  `option_order` varchar(255) NOT NULL DEFAULT '',
+  `answer` varchar(1000) DEFAULT NULL,
+  `points` float(5,2) unsigned DEFAULT NULL,
+  `comment` text,
+  PRIMARY KEY (`quiz_attempts_id`,`quiz_questions_id`) USING BTREE,
+  KEY `fk_quiz_answers_questions` (`quiz_questions_id`) USING BTREE,
+  CONSTRAINT `fk_quiz_answers_attempts` FOREIGN KEY (`quiz_attempts_id`) REFERENCES `quiz_attempts` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `fk_quiz_answers_questions` FOREIGN KEY (`quiz_questions_id`) REFERENCES `quiz_questions` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for quiz_attempts
+-- ----------------------------
+DROP TABLE IF EXISTS `quiz_attempts`;
+CREATE TABLE `quiz_attempts` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `assignments_id` int(10) unsigned NOT NULL,
+  `users_id` int(10) unsigned NOT NULL,
+  `attempt` int(10) unsigned NOT NULL,
+  `status` tinyint(1) unsigned NOT NULL DEFAULT '0',
+  `started_at` datetime NOT NULL,
+  `submitted_at` datetime DEFAULT NULL,
+  `points` float(7,2) unsigned DEFAULT NULL,
+  `score` float(5,2) unsigned DEFAULT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE KEY `quiz_attempts_number` (`assignments_id`,`users_id`,`attempt`) USING BTREE,
+  KEY `fk_quiz_attempts_users` (`users_id`) USING BTREE,
+  CONSTRAINT `fk_quiz_attempts_quizzes` FOREIGN KEY (`assignments_id`) REFERENCES `quizzes` (`assignments_id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `fk_quiz_attempts_users` FOREIGN KEY (`users_id`) REFERENCES `users` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for quiz_banks
+-- ----------------------------
+DROP TABLE IF EXISTS `quiz_banks`;
+CREATE TABLE `quiz_banks` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `schedules_id` int(10) unsigned NOT NULL,
+  `name` varchar(50) NOT NULL,
+  `created_by` int(10) unsigned NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  KEY `fk_quiz_banks_schedules` (`schedules_id`) USING BTREE,
+  CONSTRAINT `fk_quiz_banks_schedules` FOREIGN KEY (`schedules_id`) REFERENCES `schedules` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for quiz_options
+-- ----------------------------
+DROP TABLE IF EXISTS `quiz_options`;
+CREATE TABLE `quiz_options` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `quiz_questions_id` int(10) unsigned NOT NULL,
+  `text` varchar(500) NOT NULL,
+  `is_correct` tinyint(1) unsigned NOT NULL DEFAULT '0',
+  `position` int(10) unsigned NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  KEY `fk_quiz_options_questions` (`quiz_questions_id`) USING BTREE,
+  CONSTRAINT `fk_quiz_options_questions` FOREIGN KEY (`quiz_questions_id`) REFERENCES `quiz_questions` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for quiz_questions
+-- ----------------------------
+DROP TABLE IF EXISTS `quiz_questions`;
+CREATE TABLE `quiz_questions` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `quiz_banks_id` int(10) unsigned NOT NULL,
+  `type` tinyint(1) unsigned NOT NULL,
+  `question` text NOT NULL,
+  `points` float(5,2) unsigned NOT NULL,
+  `answer` varchar(1000) DEFAULT NULL,
+  `tolerance` double unsigned NOT NULL DEFAULT '0',
+  `status` tinyint(1) unsigned NOT NULL DEFAULT '1',
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  KEY `fk_quiz_questions_banks` (`quiz_banks_id`) USING BTREE,
+  CONSTRAINT `fk_quiz_questions_banks` FOREIGN KEY (`quiz_banks_id`) REFERENCES `quiz_banks` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for quizzes
+-- ----------------------------
+DROP TABLE IF EXISTS `quizzes`;
+CREATE TABLE `quizzes` (
+  `assignments_id` int(10) unsigned NOT NULL,
+  `quiz_banks_id` int(10) unsigned NOT NULL,
+  `question_count` int(10) unsigned NOT NULL DEFAULT '0',
+  `time_limit` int(10) unsigned NOT NULL DEFAULT '0',
+  `max_attempts` int(10) unsigned NOT NULL DEFAULT '1',
+  `is_shuffled` tinyint(1) unsigned NOT NULL DEFAULT '0',
+  `is_option_shuffled` tinyint(1) unsigned NOT NULL DEFAULT '0',
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`assignments_id`) USING BTREE,
+  KEY `fk_quizzes_banks` (`quiz_banks_id`) USING BTREE,
+  CONSTRAINT `fk_quizzes_assignments` FOREIGN KEY (`assignments_id`) REFERENCES `assignments` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `fk_quizzes_banks` FOREIGN KEY (`quiz_banks_id`) REFERENCES `quiz_banks` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for regrade_requests
+-- ----------------------------
+DROP TABLE IF EXISTS `regrade_requests`;
+CREATE TABLE `regrade_requests` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `assignments_id` int(10) unsigned NOT NULL,
+  `users_id` int(10) unsigned NOT NULL,
+  `reason` varchar(1000) NOT NULL,
+  `status` tinyint(1) unsigned NOT NULL DEFAULT '0' COMMENT '0 = open, 1 = escalated, 2 = accepted, 3 = rejected, 4 = closed',
+  `response` varchar(1000) DEFAULT NULL,
+  `handled_by` int(10) unsigned DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  KEY `fk_regrade_requests_assignments` (`assignments_id`,`users_id`) USING BTREE,
+  KEY `fk_regrade_requests_users` (`users_id`) USING BTREE,
+  KEY `idx_regrade_requests_status` (`status`) USING BTREE,
+  CONSTRAINT `fk_regrade_requests_assignments` FOREIGN KEY (`assignments_id`) REFERENCES `assignments` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `fk_regrade_requests_users` FOREIGN KEY (`users_id`) REFERENCES `users` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
 -- ----------------------------
 -- Table structure for research_categories
 -- ----------------------------
@@ -327,6 +776,77 @@ CREATE TABLE `rolegroups_modules` (
   CONSTRAINT `fk_rolegroups_modules_rolegroups` FOREIGN KEY (`rolegroups_id`) REFERENCES `rolegroups` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
 ) ENGINE=InnoDB AUTO_INCREMENT=11 DEFAULT CHARSET=utf8;
 
+-- ----------------------------
+-- Table structure for rubric_criteria
+-- ----------------------------
+DROP TABLE IF EXISTS `rubric_criteria`;
+CREATE TABLE `rubric_criteria` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `rubrics_id` int(10) unsigned NOT NULL,
+  `name` varchar(50) NOT NULL,
+  `description` text,
+  `position` int(10) unsigned NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  KEY `fk_rubric_criteria_rubrics` (`rubrics_id`) USING BTREE,
+  CONSTRAINT `fk_rubric_criteria_rubrics` FOREIGN KEY (`rubrics_id`) REFERENCES `rubrics` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for rubric_levels
+-- ----------------------------
+DROP TABLE IF EXISTS `rubric_levels`;
+CREATE TABLE `rubric_levels` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `rubric_criteria_id` int(10) unsigned NOT NULL,
+  `name` varchar(50) NOT NULL,
+  `description` text,
+  `points` float(5,2) unsigned NOT NULL,
+  `position` int(10) unsigned NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  KEY `fk_rubric_levels_rubric_criteria` (`rubric_criteria_id`) USING BTREE,
+  CONSTRAINT `fk_rubric_levels_rubric_criteria` FOREIGN KEY (`rubric_criteria_id`) REFERENCES `rubric_criteria` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for rubric_scores
+-- ----------------------------
+DROP TABLE IF EXISTS `rubric_scores`;
+CREATE TABLE `rubric_scores` (
+  `assignments_id` int(10) unsigned NOT NULL,
+  `users_id` int(10) unsigned NOT NULL,
+  `rubric_criteria_id` int(10) unsigned NOT NULL,
+  `rubric_levels_id` int(10) unsigned NOT NULL,
+  `points` float(5,2) unsigned NOT NULL,
+  `comment` text,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`assignments_id`,`users_id`,`rubric_criteria_id`) USING BTREE,
+  KEY `fk_rubric_scores_users` (`users_id`) USING BTREE,
+  KEY `fk_rubric_scores_rubric_criteria` (`rubric_criteria_id`) USING BTREE,
+  KEY `fk_rubric_scores_rubric_levels` (`rubric_levels_id`) USING BTREE,
+  CONSTRAINT `fk_rubric_scores_assignments` FOREIGN KEY (`assignments_id`) REFERENCES `assignments` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `fk_rubric_scores_users` FOREIGN KEY (`users_id`) REFERENCES `users` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `fk_rubric_scores_rubric_criteria` FOREIGN KEY (`rubric_criteria_id`) REFERENCES `rubric_criteria` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `fk_rubric_scores_rubric_levels` FOREIGN KEY (`rubric_levels_id`) REFERENCES `rubric_levels` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for rubrics
+-- ----------------------------
+DROP TABLE IF EXISTS `rubrics`;
+CREATE TABLE `rubrics` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `schedules_id` int(10) unsigned NOT NULL,
+  `name` varchar(50) NOT NULL,
+  `description` text,
+  `created_by` int(10) unsigned NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  KEY `fk_rubrics_schedules` (`schedules_id`) USING BTREE,
+  CONSTRAINT `fk_rubrics_schedules` FOREIGN KEY (`schedules_id`) REFERENCES `schedules` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
 -- ----------------------------
 -- Table structure for schedules
 -- ----------------------------
@@ -334,6 +854,7 @@ DROP TABLE IF EXISTS `schedules`;
 CREATE TABLE `schedules` (
   `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
   `status` tinyint(4) unsigned NOT NULL DEFAULT '0',
+  `grade_parameter_status` tinyint(1) unsigned NOT NULL DEFAULT '0' COMMENT '0 = draft, 1 = published',
   `start_time` smallint(5) unsigned NOT NULL,
   `end_time` smallint(5) unsigned NOT NULL,
   `day` tinyint(1) unsigned NOT NULL,
@@ -355,6 +876,116 @@ CREATE TABLE `schedules` (
   CONSTRAINT `fk_schedules_courses` FOREIGN KEY (`courses_id`) REFERENCES `courses` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
 ) ENGINE=InnoDB AUTO_INCREMENT=100193 DEFAULT CHARSET=utf8;
 
+-- ----------------------------
+-- Table structure for score_logs
+-- ----------------------------
+DROP TABLE IF EXISTS `score_logs`;
+CREATE TABLE `score_logs` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `assignments_id` int(10) unsigned NOT NULL,
+  `users_id` int(10) unsigned NOT NULL,
+  `old_score` float(5,2) unsigned DEFAULT NULL,
+  `new_score` float(5,2) unsigned DEFAULT NULL,
+  `actor` int(10) unsigned NOT NULL,
+  `source` tinyint(1) unsigned NOT NULL DEFAULT '0' COMMENT '0 = api, 1 = import, 2 = regrade, 3 = quiz',
+  `reason` varchar(1000) DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  KEY `idx_score_logs_assignments_users` (`assignments_id`,`users_id`) USING BTREE,
+  KEY `idx_score_logs_users` (`users_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+DROP TRIGGER IF EXISTS `score_logs_no_update`;
+CREATE TRIGGER `score_logs_no_update` BEFORE UPDATE ON `score_logs` FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'score_logs is append-only';
+DROP TRIGGER IF EXISTS `score_logs_no_delete`;
+CREATE TRIGGER `score_logs_no_delete` BEFORE DELETE ON `score_logs` FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'score_logs is append-only';
+
+-- ----------------------------
+-- Table structure for similarity_pairs
+-- ----------------------------
+DROP TABLE IF EXISTS `similarity_pairs`;
+CREATE TABLE `similarity_pairs` (
+  `similarity_reports_id` int(10) unsigned NOT NULL,
+  `users_id` int(10) unsigned NOT NULL,
+  `other_users_id` int(10) unsigned NOT NULL,
+  `score` float(5,2) unsigned NOT NULL,
+  `score_user` float(5,2) unsigned NOT NULL,
+  `score_other` float(5,2) unsigned NOT NULL,
+  `matches` mediumtext NOT NULL,
+  PRIMARY KEY (`similarity_reports_id`,`users_id`,`other_users_id`) USING BTREE,
+  CONSTRAINT `fk_similarity_pairs_reports` FOREIGN KEY (`similarity_reports_id`) REFERENCES `similarity_reports` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for similarity_reports
+-- ----------------------------
+DROP TABLE IF EXISTS `similarity_reports`;
+CREATE TABLE `similarity_reports` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `assignments_id` int(10) unsigned NOT NULL,
+  `status` tinyint(1) unsigned NOT NULL DEFAULT '0',
+  `message` varchar(255) DEFAULT NULL,
+  `created_by` int(10) unsigned NOT NULL,
+  `created_at` datetime NOT NULL,
+  `finished_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  KEY `fk_similarity_reports_assignments` (`assignments_id`) USING BTREE,
+  CONSTRAINT `fk_similarity_reports_assignments` FOREIGN KEY (`assignments_id`) REFERENCES `assignments` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for submission_feedbacks
+-- ----------------------------
+DROP TABLE IF EXISTS `submission_feedbacks`;
+CREATE TABLE `submission_feedbacks` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `assignments_id` int(10) unsigned NOT NULL,
+  `users_id` int(10) unsigned NOT NULL,
+  `sender_id` int(10) unsigned NOT NULL,
+  `message` text,
+  `read_at` datetime DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  KEY `fk_submission_feedbacks_assignments` (`assignments_id`,`users_id`) USING BTREE,
+  KEY `fk_submission_feedbacks_users` (`users_id`) USING BTREE,
+  CONSTRAINT `fk_submission_feedbacks_assignments` FOREIGN KEY (`assignments_id`) REFERENCES `assignments` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `fk_submission_feedbacks_users` FOREIGN KEY (`users_id`) REFERENCES `users` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for submission_version_files
+-- ----------------------------
+DROP TABLE IF EXISTS `submission_version_files`;
+CREATE TABLE `submission_version_files` (
+  `submission_versions_id` int(10) unsigned NOT NULL,
+  `files_id` varchar(30) NOT NULL,
+  PRIMARY KEY (`submission_versions_id`,`files_id`) USING BTREE,
+  KEY `fk_submission_version_files_files` (`files_id`) USING BTREE,
+  CONSTRAINT `fk_submission_version_files_versions` FOREIGN KEY (`submission_versions_id`) REFERENCES `submission_versions` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `fk_submission_version_files_files` FOREIGN KEY (`files_id`) REFERENCES `files` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- ----------------------------
+-- Table structure for submission_versions
+-- ----------------------------
+DROP TABLE IF EXISTS `submission_versions`;
+CREATE TABLE `submission_versions` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `assignments_id` int(10) unsigned NOT NULL,
+  `users_id` int(10) unsigned NOT NULL,
+  `version` int(10) unsigned NOT NULL,
+  `description` text,
+  `late_seconds` int(10) unsigned NOT NULL DEFAULT '0',
+  `hash` char(64) NOT NULL,
+  `submitted_by` int(10) unsigned NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE KEY `uq_submission_versions` (`assignments_id`,`users_id`,`version`) USING BTREE,
+  KEY `fk_submission_versions_users` (`users_id`) USING BTREE,
+  CONSTRAINT `fk_submission_versions_assignments` FOREIGN KEY (`assignments_id`) REFERENCES `assignments` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  CONSTRAINT `fk_submission_versions_users` FOREIGN KEY (`users_id`) REFERENCES `users` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
 -- ----------------------------
 -- Table structure for tutorials
 -- ----------------------------
@@ -366,6 +997,8 @@ CREATE TABLE `tutorials` (
   `schedules_id` int(10) unsigned NOT NULL,
   `created_at` datetime NOT NULL,
   `updated_at` datetime NOT NULL,
+  `publish_at` datetime DEFAULT NULL,
+  `unpublish_at` datetime DEFAULT NULL,
   PRIMARY KEY (`id`) USING BTREE,
   KEY `fk_tutorials_schedules` (`schedules_id`) USING BTREE,
   CONSTRAINT `fk_tutorials_schedules` FOREIGN KEY (`schedules_id`) REFERENCES `schedules` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
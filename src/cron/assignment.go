@@ -0,0 +1,61 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+
+	asg "github.com/asepnur/meiko_course/src/module/assignment"
+	cs "github.com/asepnur/meiko_course/src/module/course"
+	nt "github.com/asepnur/meiko_course/src/module/notification"
+	"github.com/asepnur/meiko_course/src/util/conn"
+)
+
+// notifyPublishedAssignment sends the new assignment notification to enrolled students
+// once a scheduled assignment is published, an assignment which fails is retried on the next run
+func notifyPublishedAssignment() error {
+	assignments, err := asg.SelectUnnotified()
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, assignment := range assignments {
+		err = notifyAssignment(assignment)
+		if err != nil {
+			lastErr = fmt.Errorf("assignment %d: %s", assignment.ID, err.Error())
+		}
+	}
+	return lastErr
+}
+
+func notifyAssignment(assignment asg.Assignment) error {
+	scheduleID, err := cs.GetScheduleIDByGP(assignment.GradeParameterID)
+	if err != nil {
+		return err
+	}
+
+	studentsID, err := cs.SelectEnrolledStudentID(scheduleID)
+	if err != nil {
+		return err
+	}
+
+	tableID := strconv.FormatInt(assignment.ID, 10)
+	tx := conn.DB.MustBegin()
+	for _, studentID := range studentsID {
+		err = nt.Insert(studentID, "New assignment",
+			fmt.Sprintf("There is a new assignment %s", assignment.Name),
+			"assignments", tableID, tx)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	err = asg.UpdateNotified([]int64{assignment.ID}, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
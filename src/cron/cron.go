@@ -40,11 +40,13 @@ func Init() {
 			err := <-cronJob.ListenError()
 			if err == errStopCron {
 				//do sometihing if cron stopped
+				continue
 			}
+			log.Println(err.Error())
 		}
 	}()
-	//so that when we stop the service, it will signal cronjob to stop
-	defer cronJob.stop()
+	// the jobs run for as long as the service runs, stopping the cron when Init returns
+	// would stop every job right after it is registered
 }
 
 func new() *cron {
@@ -60,6 +62,21 @@ func new() *cron {
 			Handler: jobs.AutoScoreList,
 		})
 	*/
+	c.register(job{
+		name:    "Published Assignment Notification",
+		rule:    "0 * * * * *",
+		handler: notifyPublishedAssignment,
+	})
+	c.register(job{
+		name:    "Ended Regrade Request",
+		rule:    "0 0 * * * *",
+		handler: closeEndedRegrade,
+	})
+	c.register(job{
+		name:    "Peer Review Assignment",
+		rule:    "0 * * * * *",
+		handler: assignPeerReview,
+	})
 	return c
 }
 
@@ -70,16 +87,17 @@ func (c *cron) register(j job) {
 func (c *cron) run() {
 	if !enabled {
 		log.Println("Crontab is not enabled by setting. please make sure you already run this command: export CRON_ENABLED=true")
+		// jobs must not run on a service which has not enabled the cron
+		return
 	}
 	for _, j := range c.jobs {
 		c.rCron.AddFunc(j.rule, func(j job) func() {
+			// only failures are logged since most jobs run every minute
 			return func() {
-				log.Printf("Cron [%s] invoked", j.name)
 				err := j.handler()
 				if err != nil {
 					c.listenErrCh <- fmt.Errorf("Cron [%s] Error: %s", j.name, err.Error())
 				}
-				log.Printf("Cron [%s] executed", j.name)
 			}
 		}(j))
 	}
@@ -0,0 +1,34 @@
+package cron
+
+import (
+	"fmt"
+
+	asg "github.com/asepnur/meiko_course/src/module/assignment"
+	"github.com/asepnur/meiko_course/src/util/publish"
+)
+
+// assignPeerReview assigns the peer reviews of every assignment whose deadline has passed,
+// an assignment which fails is retried on the next run
+func assignPeerReview() error {
+	assignmentsID, err := asg.SelectPeerUnassignedID()
+	if err != nil {
+		return err
+	}
+
+	now, err := publish.Now()
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, id := range assignmentsID {
+		assignment, err := asg.GetByID(id)
+		if err == nil {
+			err = asg.AssignPeer(assignment, now)
+		}
+		if err != nil {
+			lastErr = fmt.Errorf("assignment %d: %s", id, err.Error())
+		}
+	}
+	return lastErr
+}
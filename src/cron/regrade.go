@@ -0,0 +1,38 @@
+package cron
+
+import (
+	asg "github.com/asepnur/meiko_course/src/module/assignment"
+	cs "github.com/asepnur/meiko_course/src/module/course"
+	"github.com/asepnur/meiko_course/src/module/grade"
+)
+
+// closeEndedRegrade closes the unresolved regrade requests of every schedule whose regrade period has ended,
+// so the requests are closed on time even when nobody reads them
+func closeEndedRegrade() error {
+	schedulesID, err := grade.SelectRegradeEndedSchedule()
+	if err != nil || len(schedulesID) < 1 {
+		return err
+	}
+
+	gps, err := cs.SelectGPBySchedule(schedulesID)
+	if err != nil {
+		return err
+	}
+	var gpsID []int64
+	for _, val := range gps {
+		gpsID = append(gpsID, val.ID)
+	}
+	if len(gpsID) < 1 {
+		return nil
+	}
+
+	assignments, err := asg.SelectByGP(gpsID, false)
+	if err != nil {
+		return err
+	}
+	var asgID []int64
+	for _, val := range assignments {
+		asgID = append(asgID, val.ID)
+	}
+	return asg.CloseRegrade(asgID, nil)
+}
@@ -0,0 +1,121 @@
+package assignment
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/jmoiron/sqlx"
+)
+
+// IsAnonymous returns true when the assignment is graded without showing who the students are
+func (a Assignment) IsAnonymous() bool {
+	return a.AnonymousKey.Valid
+}
+
+// IsIdentityHidden returns true when the assignment is anonymous and the identities have not been revealed
+func (a Assignment) IsIdentityHidden() bool {
+	return a.IsAnonymous() && !a.RevealedAt.Valid
+}
+
+// AnonymousCodes returns userID => anonymous code of the users on the assignment. A code only
+// depends on the key of the assignment and the user so it stays the same between requests,
+// a code taken by a user with a lower ID is moved to the next free code
+func (a Assignment) AnonymousCodes(usersID []int64) map[int64]int64 {
+	sorted := make([]int64, len(usersID))
+	copy(sorted, usersID)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	codes := map[int64]int64{}
+	used := map[int64]bool{}
+	span := uint64(AnonymousCodeMax - AnonymousCodeMin + 1)
+	for _, userID := range sorted {
+		if _, ok := codes[userID]; ok {
+			continue
+		}
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%d", a.AnonymousKey.String, userID)))
+		code := int64(binary.BigEndian.Uint64(sum[:8])%span) + AnonymousCodeMin
+		for used[code] {
+			code++
+			if code > AnonymousCodeMax {
+				code = AnonymousCodeMin
+			}
+		}
+		used[code] = true
+		codes[userID] = code
+	}
+	return codes
+}
+
+// UpdateAnonymous turns the anonymous mode of the assignment on or off, the key is kept
+// when it is already on so the anonymous codes do not change
+func UpdateAnonymous(id int64, anonymous bool, tx *sqlx.Tx) error {
+	queryKey := fmt.Sprintf("(NULL)")
+	queryRevealedAt := fmt.Sprintf("(NULL)")
+	if anonymous {
+		queryKey = fmt.Sprintf("COALESCE(anonymous_key, MD5(CONCAT(id, RAND(), NOW())))")
+		queryRevealedAt = fmt.Sprintf("IF(anonymous_key IS NULL, NULL, revealed_at)")
+	}
+
+	// revealed_at is set first because it reads the old key
+	query := fmt.Sprintf(`
+		UPDATE
+			assignments
+		SET
+			revealed_at = %s,
+			anonymous_key = %s
+		WHERE
+			id = (%d);
+		`, queryRevealedAt, queryKey, id)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Reveal shows the identities of the students on the anonymous assignment,
+// "No rows affected" is returned when the assignment is not anonymous or has been revealed
+func Reveal(id int64, tx *sqlx.Tx) error {
+	query := fmt.Sprintf(`
+		UPDATE
+			assignments
+		SET
+			revealed_at = NOW()
+		WHERE
+			id = (%d) AND
+			anonymous_key IS NOT NULL AND
+			revealed_at IS NULL;
+		`, id)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("No rows affected")
+	}
+	return nil
+}
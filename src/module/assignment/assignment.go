@@ -374,6 +374,20 @@ func GetByID(id int64) (Assignment, error) {
 			due_date,
 			max_size,
 			max_file,
+			late_policy,
+			grace_period,
+			late_penalty,
+			close_date,
+			group_mode,
+			rubrics_id,
+			publish_at,
+			unpublish_at,
+			peer_reviewers,
+			peer_review_close,
+			peer_weight,
+			peer_assigned_at,
+			anonymous_key,
+			revealed_at,
 			created_at,
 			updated_at
 		FROM
@@ -443,7 +457,8 @@ func IsAssignmentExistByGradeParameterID(assignmentID, gradeParameterID int64) b
 }
 
 // UpdateSubmit ...
-func UpdateSubmit(id, userID int64, desc sql.NullString, tx *sqlx.Tx) error {
+// late seconds is kept when grading is pinned to a previous version
+func UpdateSubmit(id, userID int64, desc sql.NullString, lateSeconds int64, tx *sqlx.Tx) error {
 	var result sql.Result
 	var err error
 
@@ -457,11 +472,12 @@ func UpdateSubmit(id, userID int64, desc sql.NullString, tx *sqlx.Tx) error {
 			p_users_assignments 
 		SET
 			description = %s,
+			late_seconds = IF(pinned_version IS NULL, (%d), late_seconds),
 			updated_at = NOW()
 		WHERE
 			assignments_id = (%d) AND
 			users_id = (%d);
-		`, queryDesc, id, userID)
+		`, queryDesc, lateSeconds, id, userID)
 
 	if tx != nil {
 		result, err = tx.Exec(query)
@@ -479,7 +495,7 @@ func UpdateSubmit(id, userID int64, desc sql.NullString, tx *sqlx.Tx) error {
 }
 
 // InsertSubmit ...
-func InsertSubmit(id, userID int64, desc sql.NullString, tx *sqlx.Tx) error {
+func InsertSubmit(id, userID int64, desc sql.NullString, lateSeconds int64, tx *sqlx.Tx) error {
 
 	var result sql.Result
 	var err error
@@ -495,6 +511,7 @@ func InsertSubmit(id, userID int64, desc sql.NullString, tx *sqlx.Tx) error {
 				assignments_id,
 				users_id,
 				description,
+				late_seconds,
 				created_at,
 				updated_at
 			)
@@ -502,10 +519,11 @@ func InsertSubmit(id, userID int64, desc sql.NullString, tx *sqlx.Tx) error {
 				(%d),
 				(%d),
 				%s,
+				(%d),
 				NOW(),
 				NOW()
 			);
-	`, id, userID, queryDesc)
+	`, id, userID, queryDesc, lateSeconds)
 
 	if tx != nil {
 		result, err = tx.Exec(query)
@@ -543,6 +561,13 @@ func SelectByGP(gpsID []int64, isSort bool) ([]Assignment, error) {
 			description,
 			grade_parameters_id,
 			due_date,
+			late_policy,
+			grace_period,
+			late_penalty,
+			close_date,
+			group_mode,
+			publish_at,
+			unpublish_at,
 			created_at,
 			updated_at
 		FROM
@@ -569,6 +594,12 @@ func GetSubmittedByUser(id int64, userID int64) (*UserAssignment, error) {
 			users_id,
 			score,
 			description,
+			feedback,
+			late_seconds,
+			assignment_groups_id,
+			group_score,
+			adjustment,
+			pinned_version,
 			created_at,
 			updated_at
 		FROM
@@ -602,6 +633,11 @@ func SelectSubmittedByUser(id []int64, userID int64) ([]UserAssignment, error) {
 			users_id,
 			score,
 			description,
+			late_seconds,
+			assignment_groups_id,
+			group_score,
+			adjustment,
+			pinned_version,
 			created_at,
 			updated_at
 		FROM
@@ -617,6 +653,40 @@ func SelectSubmittedByUser(id []int64, userID int64) ([]UserAssignment, error) {
 	return assignment, nil
 }
 
+// SelectSubmittedByAssignment returns submissions of all users on the assignments
+func SelectSubmittedByAssignment(id []int64) ([]UserAssignment, error) {
+	assignment := []UserAssignment{}
+	if len(id) < 1 {
+		return assignment, nil
+	}
+
+	queryID := strings.Join(helper.Int64ToStringSlice(id), ", ")
+	query := fmt.Sprintf(`
+		SELECT
+			assignments_id,
+			users_id,
+			score,
+			description,
+			feedback,
+			late_seconds,
+			assignment_groups_id,
+			group_score,
+			adjustment,
+			pinned_version,
+			created_at,
+			updated_at
+		FROM
+			p_users_assignments
+		WHERE
+			assignments_id IN (%s)`, queryID)
+
+	err := conn.DB.Select(&assignment, query)
+	if err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
 // SelectUserAssignmentByID ..
 func SelectUserAssignmentByID(assignmentID int64, limit, offset int) ([]UserAssignment, error) {
 	var assignment []UserAssignment
@@ -626,6 +696,11 @@ func SelectUserAssignmentByID(assignmentID int64, limit, offset int) ([]UserAssi
 			users_id,
 			score,
 			description,
+			late_seconds,
+			assignment_groups_id,
+			group_score,
+			adjustment,
+			pinned_version,
 			created_at,
 			updated_at
 		FROM
@@ -772,6 +847,81 @@ func GetAllUserAssignmentByAssignmentID(AssignmentID, limit, offset int64) ([]De
 
 }
 
+// IsScoredByGradeParameter returns true when any assignment of the grade parameters has been scored
+func IsScoredByGradeParameter(gpsID []int64) bool {
+	if len(gpsID) < 1 {
+		return false
+	}
+
+	var x string
+	query := fmt.Sprintf(`
+		SELECT
+			'x'
+		FROM
+			p_users_assignments pua
+		INNER JOIN
+			assignments a
+		ON
+			a.id = pua.assignments_id
+		WHERE
+			a.grade_parameters_id IN (%s) AND
+			pua.score IS NOT NULL
+		LIMIT 1;`, strings.Join(helper.Int64ToStringSlice(gpsID), ", "))
+	err := conn.DB.Get(&x, query)
+	if err != nil {
+		return false
+	}
+	return true
+}
+
+// UpsertScore sets score and feedback of the user, null feedback keeps the current one and empty feedback clears it.
+// The row is created when user has not submitted which is the case for assignment that does not require upload
+func UpsertScore(assignmentID, userID int64, score float32, feedback sql.NullString, tx *sqlx.Tx) error {
+
+	fb := "(NULL)"
+	queryFeedback := "feedback"
+	if feedback.Valid {
+		if !helper.IsEmpty(feedback.String) {
+			fb = fmt.Sprintf("('%s')", feedback.String)
+		}
+		queryFeedback = "VALUES(feedback)"
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO
+			p_users_assignments (
+				assignments_id,
+				users_id,
+				score,
+				feedback,
+				created_at,
+				updated_at
+			) VALUES (
+				(%d),
+				(%d),
+				(%g),
+				%s,
+				NOW(),
+				NOW()
+			)
+		ON DUPLICATE KEY UPDATE
+			score = VALUES(score),
+			feedback = %s,
+			updated_at = NOW();
+		`, assignmentID, userID, score, fb, queryFeedback)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 // UpdateScoreAssignment func ...
 func UpdateScoreAssignment(assignmentID, userID int64, score float32, tx *sqlx.Tx) error {
 	var result sql.Result
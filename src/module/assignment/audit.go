@@ -0,0 +1,156 @@
+package assignment
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/jmoiron/sqlx"
+)
+
+// SelectScoreByUser returns the current score of the users keyed by user id, user without submission is left out.
+// Rows are locked until the transaction ends so the score can not change before it is logged
+func SelectScoreByUser(assignmentID int64, usersID []int64, tx *sqlx.Tx) (map[int64]sql.NullFloat64, error) {
+	scores := map[int64]sql.NullFloat64{}
+	if len(usersID) < 1 {
+		return scores, nil
+	}
+
+	queryLock := ""
+	if tx != nil {
+		queryLock = "FOR UPDATE"
+	}
+	query := fmt.Sprintf(`
+		SELECT
+			users_id,
+			score
+		FROM
+			p_users_assignments
+		WHERE
+			assignments_id = (%d) AND
+			users_id IN (%s)
+		%s;`, assignmentID, strings.Join(helper.Int64ToStringSlice(usersID), ", "), queryLock)
+
+	var rows []struct {
+		UserID int64           `db:"users_id"`
+		Score  sql.NullFloat64 `db:"score"`
+	}
+	var err error
+	if tx != nil {
+		err = tx.Select(&rows, query)
+	} else {
+		err = conn.DB.Select(&rows, query)
+	}
+	if err != nil && err != sql.ErrNoRows {
+		return scores, err
+	}
+
+	for _, val := range rows {
+		scores[val.UserID] = val.Score
+	}
+	return scores, nil
+}
+
+// LogScore compares the current score of the users with the old score and appends a log entry
+// for every user whose score has changed, old score of user who is not in old is null.
+// It must be called in the same transaction as the score change
+func LogScore(assignmentID int64, usersID []int64, old map[int64]sql.NullFloat64, actor int64, source int8, reason sql.NullString, tx *sqlx.Tx) error {
+	current, err := SelectScoreByUser(assignmentID, usersID, tx)
+	if err != nil {
+		return err
+	}
+
+	queryReason := fmt.Sprintf("(NULL)")
+	if reason.Valid {
+		queryReason = fmt.Sprintf("('%s')", reason.String)
+	}
+
+	var values []string
+	for _, userID := range usersID {
+		before, after := old[userID], current[userID]
+		if isSameScore(before, after) {
+			continue
+		}
+		values = append(values, fmt.Sprintf("((%d), (%d), %s, %s, (%d), (%d), %s, NOW())",
+			assignmentID, userID, queryScore(before), queryScore(after), actor, source, queryReason))
+	}
+	if len(values) < 1 {
+		return nil
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO
+			score_logs (
+				assignments_id,
+				users_id,
+				old_score,
+				new_score,
+				actor,
+				source,
+				reason,
+				created_at
+			) VALUES %s;`, strings.Join(values, ", "))
+
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	return err
+}
+
+// SelectScoreLog returns the score history of the assignments from the newest entry,
+// set userID to only return the history of a user
+func SelectScoreLog(assignmentsID []int64, userID *int64) ([]ScoreLog, error) {
+	var logs []ScoreLog
+	if len(assignmentsID) < 1 {
+		return logs, nil
+	}
+
+	queryUser := ""
+	if userID != nil {
+		queryUser = fmt.Sprintf("AND users_id = (%d)", *userID)
+	}
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			assignments_id,
+			users_id,
+			old_score,
+			new_score,
+			actor,
+			source,
+			reason,
+			created_at
+		FROM
+			score_logs
+		WHERE
+			assignments_id IN (%s)
+			%s
+		ORDER BY
+			id DESC;`, strings.Join(helper.Int64ToStringSlice(assignmentsID), ", "), queryUser)
+
+	err := conn.DB.Select(&logs, query)
+	if err != nil && err != sql.ErrNoRows {
+		return logs, err
+	}
+	return logs, nil
+}
+
+// isSameScore compares scores on the stored precision of two decimals
+func isSameScore(a, b sql.NullFloat64) bool {
+	if a.Valid != b.Valid {
+		return false
+	}
+	return !a.Valid || math.Round(a.Float64*100) == math.Round(b.Float64*100)
+}
+
+func queryScore(score sql.NullFloat64) string {
+	if !score.Valid {
+		return "(NULL)"
+	}
+	return fmt.Sprintf("(%g)", score.Float64)
+}
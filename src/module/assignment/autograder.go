@@ -0,0 +1,373 @@
+package assignment
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/jmoiron/sqlx"
+)
+
+// InsertAutograderTest adds a test case to the assignment, input and expected output are saved as they are
+func InsertAutograderTest(test AutograderTest, tx *sqlx.Tx) (int64, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO
+			autograder_tests (
+				assignments_id,
+				name,
+				input,
+				expected_output,
+				timeout,
+				points,
+				created_at,
+				updated_at
+			) VALUES (
+				(%d),
+				('%s'),
+				('%s'),
+				('%s'),
+				(%d),
+				(%g),
+				NOW(),
+				NOW()
+			);`, test.AssignmentID, helper.EscapeText(test.Name), helper.EscapeText(test.Input), helper.EscapeText(test.ExpectedOutput),
+		test.Timeout, test.Points)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// SelectAutograderTest returns the test cases of the assignment in the order they are added
+func SelectAutograderTest(assignmentID int64) ([]AutograderTest, error) {
+	var tests []AutograderTest
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			assignments_id,
+			name,
+			input,
+			expected_output,
+			timeout,
+			points,
+			created_at,
+			updated_at
+		FROM
+			autograder_tests
+		WHERE
+			assignments_id = (%d)
+		ORDER BY
+			id ASC;`, assignmentID)
+	err := conn.DB.Select(&tests, query)
+	if err != nil && err != sql.ErrNoRows {
+		return tests, err
+	}
+	return tests, nil
+}
+
+// DeleteAutograderTest removes a test case of the assignment
+func DeleteAutograderTest(assignmentID, testID int64, tx *sqlx.Tx) error {
+	query := fmt.Sprintf(`
+		DELETE FROM
+			autograder_tests
+		WHERE
+			id = (%d) AND
+			assignments_id = (%d);`, testID, assignmentID)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("No rows affected")
+	}
+	return nil
+}
+
+// InsertAutograderRun starts a new running autograder run of the assignment, the run is only inserted
+// when the assignment has no other running run so the check can not race with another insert.
+// "No rows affected" is returned when a run is still running
+func InsertAutograderRun(assignmentID, createdBy int64, tx *sqlx.Tx) (int64, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO
+			autograder_runs (
+				assignments_id,
+				status,
+				created_by,
+				created_at
+			)
+		SELECT
+			(%d),
+			(%d),
+			(%d),
+			NOW()
+		FROM
+			DUAL
+		WHERE
+			NOT EXISTS (
+				SELECT
+					'x'
+				FROM
+					autograder_runs
+				WHERE
+					assignments_id = (%d) AND
+					status = (%d)
+			);`, assignmentID, AutograderRunning, createdBy, assignmentID, AutograderRunning)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if rows == 0 {
+		return 0, fmt.Errorf("No rows affected")
+	}
+	return result.LastInsertId()
+}
+
+// ExpireAutograderRun fails the running autograder runs of the assignment which are older than MaxAutograderRun,
+// the run of a crashed or restarted server is never finished otherwise
+func ExpireAutograderRun(assignmentID int64, tx *sqlx.Tx) error {
+	query := fmt.Sprintf(`
+		UPDATE
+			autograder_runs
+		SET
+			status = (%d),
+			message = ('Autograder run has expired'),
+			finished_at = NOW()
+		WHERE
+			assignments_id = (%d) AND
+			status = (%d) AND
+			created_at < DATE_SUB(NOW(), INTERVAL %d SECOND);`, AutograderFailed, assignmentID, AutograderRunning,
+		MaxAutograderRun)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// FinishAutograderRun sets the final status of a running autograder run, "No rows affected" is returned
+// when the run is not running anymore because it has expired
+func FinishAutograderRun(runID int64, status int8, message sql.NullString, tx *sqlx.Tx) error {
+	queryMessage := fmt.Sprintf("(NULL)")
+	if message.Valid {
+		queryMessage = fmt.Sprintf("('%s')", helper.EscapeText(message.String))
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE
+			autograder_runs
+		SET
+			status = (%d),
+			message = %s,
+			finished_at = NOW()
+		WHERE
+			id = (%d) AND
+			status = (%d);`, status, queryMessage, runID, AutograderRunning)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("No rows affected")
+	}
+	return nil
+}
+
+// GetLatestAutograderRun returns the last autograder run of the assignment
+func GetLatestAutograderRun(assignmentID int64) (AutograderRun, error) {
+	var run AutograderRun
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			assignments_id,
+			status,
+			message,
+			created_by,
+			created_at,
+			finished_at
+		FROM
+			autograder_runs
+		WHERE
+			assignments_id = (%d)
+		ORDER BY
+			id DESC
+		LIMIT 1;`, assignmentID)
+	err := conn.DB.Get(&run, query)
+	if err != nil {
+		return run, err
+	}
+	return run, nil
+}
+
+// InsertAutograderResult saves the results of an autograder run, the compile output
+// and the tests are raw program output so they are escaped here
+func InsertAutograderResult(results []AutograderResult, tx *sqlx.Tx) error {
+	if len(results) < 1 {
+		return nil
+	}
+
+	var values []string
+	for _, val := range results {
+		queryLanguage := fmt.Sprintf("(NULL)")
+		if val.Language.Valid {
+			queryLanguage = fmt.Sprintf("('%s')", val.Language.String)
+		}
+		queryCompileOutput := fmt.Sprintf("(NULL)")
+		if val.CompileOutput.Valid {
+			queryCompileOutput = fmt.Sprintf("('%s')", helper.EscapeText(val.CompileOutput.String))
+		}
+		querySuggestedScore := fmt.Sprintf("(NULL)")
+		if val.SuggestedScore.Valid {
+			querySuggestedScore = fmt.Sprintf("(%g)", val.SuggestedScore.Float64)
+		}
+		values = append(values, fmt.Sprintf("((%d), (%d), (%d), %s, %s, (%d), (%d), %s, ('%s'))",
+			val.RunID, val.UserID, val.Status, queryLanguage, queryCompileOutput, val.Passed, val.Total,
+			querySuggestedScore, helper.EscapeText(val.Tests)))
+	}
+	query := fmt.Sprintf(`
+		INSERT INTO
+			autograder_results (
+				autograder_runs_id,
+				users_id,
+				status,
+				language,
+				compile_output,
+				passed,
+				total,
+				suggested_score,
+				tests
+			) VALUES %s;`, strings.Join(values, ", "))
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SelectAutograderResult returns the results of an autograder run, set usersID to only return results of some users
+func SelectAutograderResult(runID int64, usersID []int64) ([]AutograderResult, error) {
+	var results []AutograderResult
+	queryUsers := ""
+	if len(usersID) > 0 {
+		queryUsers = fmt.Sprintf("AND users_id IN (%s)", strings.Join(helper.Int64ToStringSlice(usersID), ", "))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			autograder_runs_id,
+			users_id,
+			status,
+			language,
+			compile_output,
+			passed,
+			total,
+			suggested_score,
+			tests,
+			confirmed_by,
+			confirmed_at
+		FROM
+			autograder_results
+		WHERE
+			autograder_runs_id = (%d)
+			%s
+		ORDER BY
+			users_id ASC;`, runID, queryUsers)
+	err := conn.DB.Select(&results, query)
+	if err != nil && err != sql.ErrNoRows {
+		return results, err
+	}
+	return results, nil
+}
+
+// ConfirmAutograderResult marks the suggested scores of the users as confirmed,
+// "No rows affected" is returned when none of them has an unconfirmed suggested score
+func ConfirmAutograderResult(runID int64, usersID []int64, confirmedBy int64, tx *sqlx.Tx) error {
+	if len(usersID) < 1 {
+		return nil
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE
+			autograder_results
+		SET
+			confirmed_by = (%d),
+			confirmed_at = NOW()
+		WHERE
+			autograder_runs_id = (%d) AND
+			users_id IN (%s) AND
+			suggested_score IS NOT NULL AND
+			confirmed_at IS NULL;`, confirmedBy, runID, strings.Join(helper.Int64ToStringSlice(usersID), ", "))
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("No rows affected")
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package assignment
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/jmoiron/sqlx"
+)
+
+// Extend returns the assignment with due date and close date of the extension,
+// close date of the assignment is kept when the extension does not set it
+func (a Assignment) Extend(ext *Extension) Assignment {
+	if ext == nil {
+		return a
+	}
+	a.DueDate = ext.DueDate
+	if ext.CloseDate.Valid {
+		a.CloseDate = ext.CloseDate
+	}
+	return a
+}
+
+// GetExtension returns the extension of the user on the assignment, nil is returned when it does not exist
+func GetExtension(assignmentID, userID int64) (*Extension, error) {
+	ext := &Extension{}
+	query := fmt.Sprintf(`
+		SELECT
+			assignments_id,
+			users_id,
+			due_date,
+			close_date,
+			reason,
+			granted_by,
+			created_at,
+			updated_at
+		FROM
+			assignment_extensions
+		WHERE
+			assignments_id = (%d) AND
+			users_id = (%d)
+		LIMIT 1;`, assignmentID, userID)
+	err := conn.DB.Get(ext, query)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return ext, nil
+}
+
+// SelectExtension returns the extensions of the assignments, filtered by user when userID is not nil
+func SelectExtension(assignmentsID []int64, userID *int64) ([]Extension, error) {
+	var exts []Extension
+	if len(assignmentsID) < 1 {
+		return exts, nil
+	}
+
+	var queryUser string
+	if userID != nil {
+		queryUser = fmt.Sprintf("AND users_id = (%d)", *userID)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			assignments_id,
+			users_id,
+			due_date,
+			close_date,
+			reason,
+			granted_by,
+			created_at,
+			updated_at
+		FROM
+			assignment_extensions
+		WHERE
+			assignments_id IN (%s)
+			%s
+		ORDER BY
+			due_date ASC;`, strings.Join(helper.Int64ToStringSlice(assignmentsID), ", "), queryUser)
+	err := conn.DB.Select(&exts, query)
+	if err != nil && err != sql.ErrNoRows {
+		return exts, err
+	}
+	return exts, nil
+}
+
+// UpsertExtension grants the extension to the user, the previous extension of the user is replaced
+func UpsertExtension(ext Extension, tx *sqlx.Tx) error {
+	queryCloseDate := fmt.Sprintf("(NULL)")
+	if ext.CloseDate.Valid {
+		queryCloseDate = fmt.Sprintf("('%s')", ext.CloseDate.Time.Format("2006-01-02 15:04:05"))
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO
+			assignment_extensions (
+				assignments_id,
+				users_id,
+				due_date,
+				close_date,
+				reason,
+				granted_by,
+				created_at,
+				updated_at
+			) VALUES (
+				(%d),
+				(%d),
+				('%s'),
+				%s,
+				('%s'),
+				(%d),
+				NOW(),
+				NOW()
+			)
+		ON DUPLICATE KEY UPDATE
+			due_date = VALUES(due_date),
+			close_date = VALUES(close_date),
+			reason = VALUES(reason),
+			granted_by = VALUES(granted_by),
+			updated_at = NOW();
+		`, ext.AssignmentID, ext.UserID, ext.DueDate.Format("2006-01-02 15:04:05"), queryCloseDate, ext.Reason, ext.GrantedBy)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteExtension revokes the extension of the user, every extension of the assignment is revoked when userID is nil
+func DeleteExtension(assignmentID int64, userID *int64, tx *sqlx.Tx) error {
+	var queryUser string
+	if userID != nil {
+		queryUser = fmt.Sprintf("AND users_id = (%d)", *userID)
+	}
+
+	query := fmt.Sprintf(`
+		DELETE FROM
+			assignment_extensions
+		WHERE
+			assignments_id = (%d)
+			%s;`, assignmentID, queryUser)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if userID != nil && rows == 0 {
+		return fmt.Errorf("No rows affected")
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package assignment
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/jmoiron/sqlx"
+)
+
+// InsertFeedback adds a message to the feedback thread of the user submission
+func InsertFeedback(assignmentID, userID, senderID int64, message sql.NullString, tx *sqlx.Tx) (int64, error) {
+	queryMessage := fmt.Sprintf("(NULL)")
+	if message.Valid {
+		queryMessage = fmt.Sprintf("('%s')", message.String)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO
+			submission_feedbacks (
+				assignments_id,
+				users_id,
+				sender_id,
+				message,
+				created_at,
+				updated_at
+			) VALUES (
+				(%d),
+				(%d),
+				(%d),
+				%s,
+				NOW(),
+				NOW()
+			);`, assignmentID, userID, senderID, queryMessage)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// SelectFeedback returns the feedback thread of the user submission, oldest message first
+func SelectFeedback(assignmentID, userID int64) ([]Feedback, error) {
+	var feedbacks []Feedback
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			assignments_id,
+			users_id,
+			sender_id,
+			message,
+			read_at,
+			created_at
+		FROM
+			submission_feedbacks
+		WHERE
+			assignments_id = (%d) AND
+			users_id = (%d)
+		ORDER BY
+			created_at ASC,
+			id ASC;`, assignmentID, userID)
+	err := conn.DB.Select(&feedbacks, query)
+	if err != nil && err != sql.ErrNoRows {
+		return feedbacks, err
+	}
+	return feedbacks, nil
+}
+
+// ReadFeedback marks every unread message on the thread which is not sent by the reader as read
+func ReadFeedback(assignmentID, userID, readerID int64, tx *sqlx.Tx) error {
+	query := fmt.Sprintf(`
+		UPDATE
+			submission_feedbacks
+		SET
+			read_at = NOW()
+		WHERE
+			assignments_id = (%d) AND
+			users_id = (%d) AND
+			sender_id != (%d) AND
+			read_at IS NULL;`, assignmentID, userID, readerID)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
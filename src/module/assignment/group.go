@@ -0,0 +1,420 @@
+package assignment
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/jmoiron/sqlx"
+)
+
+// GroupScope returns the assignment id which owns the groups used by the assignment,
+// invalid value means the groups of the schedule are used
+func (a Assignment) GroupScope() sql.NullInt64 {
+	if a.GroupMode == GroupModeAssignment {
+		return sql.NullInt64{Valid: true, Int64: a.ID}
+	}
+	return sql.NullInt64{}
+}
+
+func queryGroupScope(assignmentID sql.NullInt64) string {
+	if assignmentID.Valid {
+		return fmt.Sprintf("assignments_id = (%d)", assignmentID.Int64)
+	}
+	return "assignments_id IS NULL"
+}
+
+// UpdateGroupMode sets the group mode of the assignment
+func UpdateGroupMode(id int64, mode int8, tx *sqlx.Tx) error {
+	query := fmt.Sprintf(`
+		UPDATE
+			assignments
+		SET
+			group_mode = (%d)
+		WHERE
+			id = (%d);
+		`, mode, id)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SelectGroup returns groups of the assignment when assignmentID is valid, otherwise groups of the schedule
+func SelectGroup(scheduleID int64, assignmentID sql.NullInt64) ([]Group, error) {
+	var groups []Group
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			schedules_id,
+			assignments_id,
+			name,
+			created_at,
+			updated_at
+		FROM
+			assignment_groups
+		WHERE
+			schedules_id = (%d) AND
+			%s
+		ORDER BY
+			name ASC;`, scheduleID, queryGroupScope(assignmentID))
+	err := conn.DB.Select(&groups, query)
+	if err != nil && err != sql.ErrNoRows {
+		return groups, err
+	}
+	return groups, nil
+}
+
+// GetGroup returns the group, nil is returned when it does not exist
+func GetGroup(id int64) (*Group, error) {
+	group := &Group{}
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			schedules_id,
+			assignments_id,
+			name,
+			created_at,
+			updated_at
+		FROM
+			assignment_groups
+		WHERE
+			id = (%d)
+		LIMIT 1;`, id)
+	err := conn.DB.Get(group, query)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return group, nil
+}
+
+// GetGroupByUser returns the group of the user in the scope, nil is returned when user is not in any group
+func GetGroupByUser(scheduleID int64, assignmentID sql.NullInt64, userID int64) (*Group, error) {
+	group := &Group{}
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			schedules_id,
+			assignments_id,
+			name,
+			created_at,
+			updated_at
+		FROM
+			assignment_groups
+		WHERE
+			schedules_id = (%d) AND
+			%s AND
+			id IN (
+				SELECT
+					assignment_groups_id
+				FROM
+					p_users_assignment_groups
+				WHERE
+					users_id = (%d)
+			)
+		LIMIT 1;`, scheduleID, queryGroupScope(assignmentID), userID)
+	err := conn.DB.Get(group, query)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return group, nil
+}
+
+// SelectGroupMember returns members of the groups
+func SelectGroupMember(groupsID []int64) ([]GroupMember, error) {
+	var members []GroupMember
+	if len(groupsID) < 1 {
+		return members, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			assignment_groups_id,
+			users_id
+		FROM
+			p_users_assignment_groups
+		WHERE
+			assignment_groups_id IN (%s);`, strings.Join(helper.Int64ToStringSlice(groupsID), ", "))
+	err := conn.DB.Select(&members, query)
+	if err != nil && err != sql.ErrNoRows {
+		return members, err
+	}
+	return members, nil
+}
+
+// InsertGroup creates a group of the schedule, it belongs to the assignment when assignmentID is valid
+func InsertGroup(scheduleID int64, assignmentID sql.NullInt64, name string, tx *sqlx.Tx) (int64, error) {
+	queryAssignment := fmt.Sprintf("(NULL)")
+	if assignmentID.Valid {
+		queryAssignment = fmt.Sprintf("(%d)", assignmentID.Int64)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO
+			assignment_groups (
+				schedules_id,
+				assignments_id,
+				name,
+				created_at,
+				updated_at
+			) VALUES (
+				(%d),
+				%s,
+				('%s'),
+				NOW(),
+				NOW()
+			);`, scheduleID, queryAssignment, name)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// UpdateGroup renames the group
+func UpdateGroup(id int64, name string, tx *sqlx.Tx) error {
+	query := fmt.Sprintf(`
+		UPDATE
+			assignment_groups
+		SET
+			name = ('%s'),
+			updated_at = NOW()
+		WHERE
+			id = (%d);`, name, id)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("No rows affected")
+	}
+	return nil
+}
+
+// ReplaceGroupMember deletes the old members of the group and inserts the new one
+func ReplaceGroupMember(groupID int64, usersID []int64, tx *sqlx.Tx) error {
+	queries := []string{fmt.Sprintf(`
+		DELETE FROM
+			p_users_assignment_groups
+		WHERE
+			assignment_groups_id = (%d);`, groupID)}
+
+	var values []string
+	for _, val := range usersID {
+		values = append(values, fmt.Sprintf("((%d), (%d), NOW())", groupID, val))
+	}
+	if len(values) > 0 {
+		queries = append(queries, fmt.Sprintf(`
+			INSERT INTO
+				p_users_assignment_groups (
+					assignment_groups_id,
+					users_id,
+					created_at
+				) VALUES %s;`, strings.Join(values, ", ")))
+	}
+
+	for _, query := range queries {
+		var err error
+		if tx != nil {
+			_, err = tx.Exec(query)
+		} else {
+			_, err = conn.DB.Exec(query)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteGroup deletes the group and its members
+func DeleteGroup(id int64, tx *sqlx.Tx) error {
+	err := ReplaceGroupMember(id, nil, tx)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		DELETE FROM
+			assignment_groups
+		WHERE
+			id = (%d);`, id)
+
+	var result sql.Result
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("No rows affected")
+	}
+	return nil
+}
+
+// IsGroupSubmitted returns true when the group has submitted or been scored on any assignment
+func IsGroupSubmitted(groupID int64) bool {
+	var x string
+	query := fmt.Sprintf(`
+		SELECT
+			'x'
+		FROM
+			p_users_assignments
+		WHERE
+			assignment_groups_id = (%d)
+		LIMIT 1;`, groupID)
+	err := conn.DB.Get(&x, query)
+	if err != nil {
+		return false
+	}
+	return true
+}
+
+// SetSubmissionGroup marks the submission of the users as the submission of the group
+func SetSubmissionGroup(assignmentID, groupID int64, usersID []int64, tx *sqlx.Tx) error {
+	if len(usersID) < 1 {
+		return nil
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE
+			p_users_assignments
+		SET
+			assignment_groups_id = (%d)
+		WHERE
+			assignments_id = (%d) AND
+			users_id IN (%s);`, groupID, assignmentID, strings.Join(helper.Int64ToStringSlice(usersID), ", "))
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpsertGroupScore sets the score of the group to every member, null feedback keeps the current one and empty
+// feedback clears it. Adjustment of each member is added to the score and the result is kept between min and max score
+func UpsertGroupScore(assignmentID, groupID int64, usersID []int64, score float32, feedback sql.NullString, tx *sqlx.Tx) error {
+	if len(usersID) < 1 {
+		return nil
+	}
+
+	queryFeedback := fmt.Sprintf("(NULL)")
+	updateFeedback := "feedback"
+	if feedback.Valid {
+		if !helper.IsEmpty(feedback.String) {
+			queryFeedback = fmt.Sprintf("('%s')", feedback.String)
+		}
+		updateFeedback = "VALUES(feedback)"
+	}
+
+	var values []string
+	for _, val := range usersID {
+		values = append(values, fmt.Sprintf("((%d), (%d), (%d), (%g), (%g), %s, NOW(), NOW())",
+			assignmentID, val, groupID, score, score, queryFeedback))
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO
+			p_users_assignments (
+				assignments_id,
+				users_id,
+				assignment_groups_id,
+				group_score,
+				score,
+				feedback,
+				created_at,
+				updated_at
+			) VALUES %s
+		ON DUPLICATE KEY UPDATE
+			assignment_groups_id = VALUES(assignment_groups_id),
+			group_score = VALUES(group_score),
+			score = LEAST(GREATEST(VALUES(group_score) + adjustment, %d), %d),
+			feedback = %s,
+			updated_at = NOW();
+		`, strings.Join(values, ", "), MinScore, MaxScore, updateFeedback)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateAdjustment sets the score adjustment of a member, the score is recalculated when the group has been scored
+func UpdateAdjustment(assignmentID, userID int64, adjustment float64, tx *sqlx.Tx) error {
+	query := fmt.Sprintf(`
+		UPDATE
+			p_users_assignments
+		SET
+			adjustment = (%g),
+			score = IF(group_score IS NULL, score, LEAST(GREATEST(group_score + (%g), %d), %d)),
+			updated_at = NOW()
+		WHERE
+			assignments_id = (%d) AND
+			users_id = (%d);
+		`, adjustment, adjustment, MinScore, MaxScore, assignmentID, userID)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("No rows affected")
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package assignment
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+)
+
+// Deadline returns the last time a submission is accepted based on the late policy
+func (a Assignment) Deadline() time.Time {
+	switch a.LatePolicy {
+	case LatePolicyGrace:
+		return a.DueDate.Add(time.Duration(a.GracePeriod) * time.Minute)
+	case LatePolicyPenalty:
+		if a.CloseDate.Valid && a.CloseDate.Time.After(a.DueDate) {
+			return a.CloseDate.Time
+		}
+	}
+	return a.DueDate
+}
+
+// IsAccepting returns true when a submission at t is still accepted
+func (a Assignment) IsAccepting(t time.Time) bool {
+	return !t.After(a.Deadline())
+}
+
+// Lateness returns how many seconds a submission at t is late, zero when it is on time
+func (a Assignment) Lateness(t time.Time) int64 {
+	if !t.After(a.DueDate) {
+		return 0
+	}
+	return int64(math.Ceil(t.Sub(a.DueDate).Seconds()))
+}
+
+// Penalty returns the percentage deducted from the score of a submission which is late by lateSeconds,
+// only penalty policy deducts score and it is charged for every started day
+func (a Assignment) Penalty(lateSeconds int64) float64 {
+	if a.LatePolicy != LatePolicyPenalty || lateSeconds < 1 {
+		return 0
+	}
+
+	days := math.Ceil(float64(lateSeconds) / (24 * 60 * 60))
+	penalty := days * a.LatePenalty
+	if penalty > MaxLatePenalty {
+		return MaxLatePenalty
+	}
+	return penalty
+}
+
+// UpdateLatePolicy sets the late policy of the assignment
+func UpdateLatePolicy(id int64, policy int8, gracePeriod int64, penalty float64, closeDate mysql.NullTime, tx *sqlx.Tx) error {
+	queryCloseDate := fmt.Sprintf("(NULL)")
+	if closeDate.Valid {
+		queryCloseDate = fmt.Sprintf("('%s')", closeDate.Time.Format("2006-01-02 15:04:05"))
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE
+			assignments
+		SET
+			late_policy = (%d),
+			grace_period = (%d),
+			late_penalty = (%g),
+			close_date = %s
+		WHERE
+			id = (%d);
+		`, policy, gracePeriod, penalty, queryCloseDate, id)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -3,6 +3,8 @@ package assignment
 import (
 	"database/sql"
 	"time"
+
+	"github.com/go-sql-driver/mysql"
 )
 
 // ...
@@ -15,8 +17,177 @@ const (
 	MaxSizeFile             = 100
 	MinSizeFile             = 1
 	MaxPage                 = 10
+	MinScore                = 0
+	MaxScore                = 100
+	MaxFeedback             = 5000
+
+	// LatePolicyCutoff rejects any submission after the due date
+	LatePolicyCutoff = 0
+	// LatePolicyGrace accepts submission until the grace period ends without penalty
+	LatePolicyGrace = 1
+	// LatePolicyPenalty accepts submission until the close date with penalty for every started day late
+	LatePolicyPenalty = 2
+
+	MaxGracePeriod = 10080
+	MaxLatePenalty = 100
+	MaxReason      = 1000
+
+	// GroupModeNone is an individual assignment
+	GroupModeNone = 0
+	// GroupModeAssignment uses groups which are defined only for the assignment
+	GroupModeAssignment = 1
+	// GroupModeSchedule reuses groups of the schedule
+	GroupModeSchedule = 2
+
+	MaxGroupName  = 50
+	MaxAdjustment = 100
+
+	// SimilarityRunning is a similarity report which is still being processed
+	SimilarityRunning = 0
+	// SimilarityDone is a similarity report which has finished
+	SimilarityDone = 1
+	// SimilarityFailed is a similarity report which stopped because of an error
+	SimilarityFailed = 2
+	// MaxSimilarityReport is how long a similarity report may take in seconds, a report which is still running
+	// after that is expired so a crashed report does not block the assignment
+	MaxSimilarityReport = 60 * 60
+
+	// ScoreSourceAPI is a score set by an assistant through the score API
+	ScoreSourceAPI = 0
+	// ScoreSourceImport is a score set from an imported score sheet or gradebook
+	ScoreSourceImport = 1
+	// ScoreSourceRegrade is a score changed when a regrade request is resolved
+	ScoreSourceRegrade = 2
+	// ScoreSourceQuiz is a score set by the quiz auto grading
+	ScoreSourceQuiz = 3
+	// ScoreSourcePeer is a score combined with the peer review score
+	ScoreSourcePeer = 4
+	// ScoreSourceAutograder is a suggested score of the autograder confirmed by an assistant
+	ScoreSourceAutograder = 5
+
+	// RegradeOpen is a regrade request waiting for an assistant
+	RegradeOpen = 0
+	// RegradeEscalated is a regrade request waiting for the schedule creator
+	RegradeEscalated = 1
+	// RegradeAccepted is a regrade request which has changed the score
+	RegradeAccepted = 2
+	// RegradeRejected is a regrade request which is rejected with an explanation
+	RegradeRejected = 3
+	// RegradeClosed is a regrade request which is closed after the regrade period ends
+	RegradeClosed = 4
+
+	// PeerAssigned is a peer review which has not been filled by the reviewer
+	PeerAssigned = 0
+	// PeerSubmitted is a peer review which counts to the peer score
+	PeerSubmitted = 1
+	// PeerExcluded is a peer review which is excluded from the peer score by an assistant
+	PeerExcluded = 2
+
+	MaxPeerReviewers = 10
+	MaxPeerWeight    = 100
+	// PeerOutlierDistance is the distance from the median score of a submission
+	// which marks a peer review as an outlier
+	PeerOutlierDistance = 20
+	// PeerOutlierMinReviews is the number of submitted reviews needed to find outliers
+	PeerOutlierMinReviews = 3
+
+	// AnonymousCodeMin and AnonymousCodeMax are the range of anonymous codes,
+	// they are shorter than identity codes so both can not be mistaken
+	AnonymousCodeMin = 10000000
+	AnonymousCodeMax = 99999999
+
+	DefaultHistogramBins = 10
+	MaxHistogramBins     = 100
+
+	// AutograderRunning is an autograder run which is still being processed
+	AutograderRunning = 0
+	// AutograderDone is an autograder run which has finished
+	AutograderDone = 1
+	// AutograderFailed is an autograder run which stopped because of an error
+	AutograderFailed = 2
+	// MaxAutograderRun is how long an autograder run may take in seconds, a run which is still running
+	// after that is expired so a crashed run does not block the assignment
+	MaxAutograderRun = 3 * 60 * 60
+
+	// AutograderGraded is a submission which has been compiled and run against the tests
+	AutograderGraded = 0
+	// AutograderNoSource is a submission without a source file of a supported language
+	AutograderNoSource = 1
+	// AutograderCompileError is a submission which can not be compiled
+	AutograderCompileError = 2
+
+	MaxAutograderTests    = 50
+	MaxAutograderTestName = 100
+	// MaxAutograderText is the maximum length of the input and the expected output of a test
+	MaxAutograderText = 65535
+	// MaxAutograderTimeout is the maximum time limit of a test in milliseconds
+	MaxAutograderTimeout = 10000
+	MaxAutograderPoints  = 100
+	// MaxAutograderOutput is the length of the program output kept on the result of every test
+	MaxAutograderOutput = 1000
 )
 
+// LatePolicies is the name of every late policy
+var LatePolicies = map[int8]string{
+	LatePolicyCutoff:  "cutoff",
+	LatePolicyGrace:   "grace",
+	LatePolicyPenalty: "penalty",
+}
+
+// SimilarityStatuses is the name of every similarity report status
+var SimilarityStatuses = map[int8]string{
+	SimilarityRunning: "running",
+	SimilarityDone:    "done",
+	SimilarityFailed:  "failed",
+}
+
+// ScoreSources is the name of every score log source
+var ScoreSources = map[int8]string{
+	ScoreSourceAPI:        "api",
+	ScoreSourceImport:     "import",
+	ScoreSourceRegrade:    "regrade",
+	ScoreSourceQuiz:       "quiz",
+	ScoreSourcePeer:       "peer",
+	ScoreSourceAutograder: "autograder",
+}
+
+// AutograderStatuses is the name of every autograder run status
+var AutograderStatuses = map[int8]string{
+	AutograderRunning: "running",
+	AutograderDone:    "done",
+	AutograderFailed:  "failed",
+}
+
+// AutograderResultStatuses is the name of every autograder result status
+var AutograderResultStatuses = map[int8]string{
+	AutograderGraded:       "graded",
+	AutograderNoSource:     "no_source",
+	AutograderCompileError: "compile_error",
+}
+
+// RegradeStatuses is the name of every regrade request status
+var RegradeStatuses = map[int8]string{
+	RegradeOpen:      "open",
+	RegradeEscalated: "escalated",
+	RegradeAccepted:  "accepted",
+	RegradeRejected:  "rejected",
+	RegradeClosed:    "closed",
+}
+
+// PeerStatuses is the name of every peer review status
+var PeerStatuses = map[int8]string{
+	PeerAssigned:  "assigned",
+	PeerSubmitted: "submitted",
+	PeerExcluded:  "excluded",
+}
+
+// GroupModes is the name of every group mode
+var GroupModes = map[int8]string{
+	GroupModeNone:       "individual",
+	GroupModeAssignment: "assignment",
+	GroupModeSchedule:   "schedule",
+}
+
 // Assignment struct ...
 type Assignment struct {
 	ID               int64          `db:"id"`
@@ -27,6 +198,20 @@ type Assignment struct {
 	DueDate          time.Time      `db:"due_date"`
 	MaxSize          sql.NullInt64  `db:"max_size"`
 	MaxFile          sql.NullInt64  `db:"max_file"`
+	LatePolicy       int8           `db:"late_policy"`
+	GracePeriod      int64          `db:"grace_period"`
+	LatePenalty      float64        `db:"late_penalty"`
+	CloseDate        mysql.NullTime `db:"close_date"`
+	GroupMode        int8           `db:"group_mode"`
+	RubricID         sql.NullInt64  `db:"rubrics_id"`
+	PublishAt        mysql.NullTime `db:"publish_at"`
+	UnpublishAt      mysql.NullTime `db:"unpublish_at"`
+	PeerReviewers    int8           `db:"peer_reviewers"`
+	PeerReviewClose  mysql.NullTime `db:"peer_review_close"`
+	PeerWeight       float64        `db:"peer_weight"`
+	PeerAssignedAt   mysql.NullTime `db:"peer_assigned_at"`
+	AnonymousKey     sql.NullString `db:"anonymous_key"`
+	RevealedAt       mysql.NullTime `db:"revealed_at"`
 	CreatedAt        time.Time      `db:"created_at"`
 	UpdatedAt        time.Time      `db:"updated_at"`
 }
@@ -41,12 +226,106 @@ type ConciseAssignment struct {
 
 // UserAssignment struct ...
 type UserAssignment struct {
-	UserID       int64           `db:"users_id"`
-	AssignmentID int64           `db:"assignments_id"`
-	Score        sql.NullFloat64 `db:"score"`
-	Description  sql.NullString  `db:"description"`
-	CreatedAt    time.Time       `db:"created_at"`
-	UpdatedAt    time.Time       `db:"updated_at"`
+	UserID        int64           `db:"users_id"`
+	AssignmentID  int64           `db:"assignments_id"`
+	Score         sql.NullFloat64 `db:"score"`
+	Description   sql.NullString  `db:"description"`
+	Feedback      sql.NullString  `db:"feedback"`
+	LateSeconds   int64           `db:"late_seconds"`
+	GroupID       sql.NullInt64   `db:"assignment_groups_id"`
+	GroupScore    sql.NullFloat64 `db:"group_score"`
+	Adjustment    float64         `db:"adjustment"`
+	PinnedVersion sql.NullInt64   `db:"pinned_version"`
+	CreatedAt     time.Time       `db:"created_at"`
+	UpdatedAt     time.Time       `db:"updated_at"`
+}
+
+// Extension is the due date and close date granted to a user on an assignment
+type Extension struct {
+	AssignmentID int64          `db:"assignments_id"`
+	UserID       int64          `db:"users_id"`
+	DueDate      time.Time      `db:"due_date"`
+	CloseDate    mysql.NullTime `db:"close_date"`
+	Reason       string         `db:"reason"`
+	GrantedBy    int64          `db:"granted_by"`
+	CreatedAt    time.Time      `db:"created_at"`
+	UpdatedAt    time.Time      `db:"updated_at"`
+}
+
+// Group is a group of students on a schedule, it belongs to a single assignment when AssignmentID is valid
+type Group struct {
+	ID           int64         `db:"id"`
+	ScheduleID   int64         `db:"schedules_id"`
+	AssignmentID sql.NullInt64 `db:"assignments_id"`
+	Name         string        `db:"name"`
+	CreatedAt    time.Time     `db:"created_at"`
+	UpdatedAt    time.Time     `db:"updated_at"`
+}
+
+// GroupMember is a student in a group
+type GroupMember struct {
+	GroupID int64 `db:"assignment_groups_id"`
+	UserID  int64 `db:"users_id"`
+}
+
+// Version is an immutable submission attempt of a user on an assignment
+type Version struct {
+	ID           int64          `db:"id"`
+	AssignmentID int64          `db:"assignments_id"`
+	UserID       int64          `db:"users_id"`
+	Version      int64          `db:"version"`
+	Description  sql.NullString `db:"description"`
+	LateSeconds  int64          `db:"late_seconds"`
+	Hash         string         `db:"hash"`
+	SubmittedBy  int64          `db:"submitted_by"`
+	CreatedAt    time.Time      `db:"created_at"`
+}
+
+// VersionFile is a file submitted on a version
+type VersionFile struct {
+	VersionID int64  `db:"submission_versions_id"`
+	FileID    string `db:"files_id"`
+}
+
+// PinnedFile is a file of the pinned version of a user, file is null when the version has no file
+type PinnedFile struct {
+	UserID int64          `db:"users_id"`
+	FileID sql.NullString `db:"files_id"`
+}
+
+// Feedback is a message on the submission of a user, sent by an assistant or the user itself.
+// ReadAt is set when the other side has read it
+type Feedback struct {
+	ID           int64          `db:"id"`
+	AssignmentID int64          `db:"assignments_id"`
+	UserID       int64          `db:"users_id"`
+	SenderID     int64          `db:"sender_id"`
+	Message      sql.NullString `db:"message"`
+	ReadAt       mysql.NullTime `db:"read_at"`
+	CreatedAt    time.Time      `db:"created_at"`
+}
+
+// SimilarityReport is a run of the similarity checker on the submissions of an assignment
+type SimilarityReport struct {
+	ID           int64          `db:"id"`
+	AssignmentID int64          `db:"assignments_id"`
+	Status       int8           `db:"status"`
+	Message      sql.NullString `db:"message"`
+	CreatedBy    int64          `db:"created_by"`
+	CreatedAt    time.Time      `db:"created_at"`
+	FinishedAt   mysql.NullTime `db:"finished_at"`
+}
+
+// SimilarityPair is the similarity between the submissions of two users,
+// Matches holds the json encoded matching regions of both submissions
+type SimilarityPair struct {
+	ReportID    int64   `db:"similarity_reports_id"`
+	UserID      int64   `db:"users_id"`
+	OtherUserID int64   `db:"other_users_id"`
+	Score       float64 `db:"score"`
+	ScoreUser   float64 `db:"score_user"`
+	ScoreOther  float64 `db:"score_other"`
+	Matches     string  `db:"matches"`
 }
 
 // File struct ...
@@ -91,3 +370,95 @@ type DetailUploadedAssignment struct {
 	DueDate               string
 	PathFile              sql.NullString
 }
+
+// ScoreLog is an entry of the append only score history, score is null when the user has no score
+type ScoreLog struct {
+	ID           int64           `db:"id"`
+	AssignmentID int64           `db:"assignments_id"`
+	UserID       int64           `db:"users_id"`
+	OldScore     sql.NullFloat64 `db:"old_score"`
+	NewScore     sql.NullFloat64 `db:"new_score"`
+	Actor        int64           `db:"actor"`
+	Source       int8            `db:"source"`
+	Reason       sql.NullString  `db:"reason"`
+	CreatedAt    time.Time       `db:"created_at"`
+}
+
+// Regrade is a request of the user to review the score of the submission,
+// Response is the explanation of the assistant who handles it
+type Regrade struct {
+	ID           int64          `db:"id"`
+	AssignmentID int64          `db:"assignments_id"`
+	UserID       int64          `db:"users_id"`
+	Reason       string         `db:"reason"`
+	Status       int8           `db:"status"`
+	Response     sql.NullString `db:"response"`
+	HandledBy    sql.NullInt64  `db:"handled_by"`
+	CreatedAt    time.Time      `db:"created_at"`
+	UpdatedAt    time.Time      `db:"updated_at"`
+}
+
+// PeerReview is the review of the submission of the user by a classmate,
+// Score is the rubric score of the review once it is submitted
+type PeerReview struct {
+	ID           int64           `db:"id"`
+	AssignmentID int64           `db:"assignments_id"`
+	ReviewerID   int64           `db:"reviewer_id"`
+	UserID       int64           `db:"users_id"`
+	Status       int8            `db:"status"`
+	Score        sql.NullFloat64 `db:"score"`
+	Comment      sql.NullString  `db:"comment"`
+	ModeratedBy  sql.NullInt64   `db:"moderated_by"`
+	CreatedAt    time.Time       `db:"created_at"`
+	UpdatedAt    time.Time       `db:"updated_at"`
+}
+
+// PeerReviewScore is the chosen rubric level of a criterion on a peer review
+type PeerReviewScore struct {
+	ReviewID    int64          `db:"peer_reviews_id"`
+	CriterionID int64          `db:"rubric_criteria_id"`
+	LevelID     int64          `db:"rubric_levels_id"`
+	Points      float64        `db:"points"`
+	Comment     sql.NullString `db:"comment"`
+}
+
+// AutograderTest is a test case of the assignment, the program is given Input as stdin
+// and has to print ExpectedOutput within Timeout milliseconds
+type AutograderTest struct {
+	ID             int64     `db:"id"`
+	AssignmentID   int64     `db:"assignments_id"`
+	Name           string    `db:"name"`
+	Input          string    `db:"input"`
+	ExpectedOutput string    `db:"expected_output"`
+	Timeout        int64     `db:"timeout"`
+	Points         float64   `db:"points"`
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+// AutograderRun is a run of the autograder on the submissions of an assignment
+type AutograderRun struct {
+	ID           int64          `db:"id"`
+	AssignmentID int64          `db:"assignments_id"`
+	Status       int8           `db:"status"`
+	Message      sql.NullString `db:"message"`
+	CreatedBy    int64          `db:"created_by"`
+	CreatedAt    time.Time      `db:"created_at"`
+	FinishedAt   mysql.NullTime `db:"finished_at"`
+}
+
+// AutograderResult is the result of the submission of the user on an autograder run, Tests holds the json encoded
+// result of every test and SuggestedScore is null when the submission could not be run
+type AutograderResult struct {
+	RunID          int64           `db:"autograder_runs_id"`
+	UserID         int64           `db:"users_id"`
+	Status         int8            `db:"status"`
+	Language       sql.NullString  `db:"language"`
+	CompileOutput  sql.NullString  `db:"compile_output"`
+	Passed         int             `db:"passed"`
+	Total          int             `db:"total"`
+	SuggestedScore sql.NullFloat64 `db:"suggested_score"`
+	Tests          string          `db:"tests"`
+	ConfirmedBy    sql.NullInt64   `db:"confirmed_by"`
+	ConfirmedAt    mysql.NullTime  `db:"confirmed_at"`
+}
@@ -0,0 +1,527 @@
+package assignment
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	cs "github.com/asepnur/meiko_course/src/module/course"
+	nt "github.com/asepnur/meiko_course/src/module/notification"
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+)
+
+// IsPeerReview returns true when the submissions of the assignment are reviewed by classmates
+func (a Assignment) IsPeerReview() bool {
+	return a.PeerReviewers > 0
+}
+
+// IsPeerReviewOpen returns true when the reviewers may fill their reviews at t,
+// the review window starts when submission is no longer accepted
+func (a Assignment) IsPeerReviewOpen(t time.Time) bool {
+	if !a.IsPeerReview() || !t.After(a.Deadline()) {
+		return false
+	}
+	return !a.PeerReviewClose.Valid || !t.After(a.PeerReviewClose.Time)
+}
+
+// IsPeerReviewClosed returns true when the review window has ended at t
+func (a Assignment) IsPeerReviewClosed(t time.Time) bool {
+	return a.IsPeerReview() && a.PeerReviewClose.Valid && t.After(a.PeerReviewClose.Time)
+}
+
+// PeerDeadline returns the latest deadline of the assignment after the extensions of the students,
+// the submissions are allocated to the reviewers only after it so every student is included
+func (a Assignment) PeerDeadline(exts []Extension) time.Time {
+	deadline := a.Deadline()
+	for i := range exts {
+		extended := a.Extend(&exts[i]).Deadline()
+		if extended.After(deadline) {
+			deadline = extended
+		}
+	}
+	return deadline
+}
+
+// AssignPeer assigns the submission of every enrolled student to classmates once submission is no longer accepted
+// from any student including the extended ones and notifies the reviewers, now is the database time.
+// Nothing is done before the deadline or when the assignment has been assigned
+func AssignPeer(assignment Assignment, now time.Time) error {
+	if !assignment.IsPeerReview() || assignment.PeerAssignedAt.Valid {
+		return nil
+	}
+	exts, err := SelectExtension([]int64{assignment.ID}, nil)
+	if err != nil {
+		return err
+	}
+	if !now.After(assignment.PeerDeadline(exts)) {
+		return nil
+	}
+
+	submitted, err := SelectSubmittedByAssignment([]int64{assignment.ID})
+	if err != nil {
+		return err
+	}
+	scheduleID, err := cs.GetScheduleIDByGP(assignment.GradeParameterID)
+	if err != nil {
+		return err
+	}
+	studentsID, err := cs.SelectEnrolledStudentID(scheduleID)
+	if err != nil {
+		return err
+	}
+	enrolled := map[int64]bool{}
+	for _, val := range studentsID {
+		enrolled[val] = true
+	}
+
+	var usersID []int64
+	for _, val := range submitted {
+		if enrolled[val.UserID] {
+			usersID = append(usersID, val.UserID)
+		}
+	}
+	rand.Shuffle(len(usersID), func(i, j int) {
+		usersID[i], usersID[j] = usersID[j], usersID[i]
+	})
+	pairs := PeerPairs(usersID, int(assignment.PeerReviewers))
+
+	tx := conn.DB.MustBegin()
+	err = InsertPeerReview(assignment.ID, pairs, tx)
+	if err != nil {
+		tx.Rollback()
+		// assigned by another request
+		if err.Error() == "No rows affected" {
+			return nil
+		}
+		return err
+	}
+
+	tableID := strconv.FormatInt(assignment.ID, 10)
+	for reviewerID, val := range pairs {
+		err = nt.Insert(reviewerID, "Peer review",
+			fmt.Sprintf("You have %d submissions to review on %s", len(val), assignment.Name),
+			"assignments", tableID, tx)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// SelectPeerUnassignedID returns peer reviewed assignments which have passed their due date
+// but have not been assigned, the extended deadline is checked by AssignPeer
+func SelectPeerUnassignedID() ([]int64, error) {
+	var assignmentsID []int64
+	query := fmt.Sprintf(`
+		SELECT
+			id
+		FROM
+			assignments
+		WHERE
+			peer_reviewers > 0 AND
+			peer_assigned_at IS NULL AND
+			due_date < NOW();`)
+	err := conn.DB.Select(&assignmentsID, query)
+	if err != nil && err != sql.ErrNoRows {
+		return assignmentsID, err
+	}
+	return assignmentsID, nil
+}
+
+// PeerPairs assigns every user to review the submissions of the next n users in the given order,
+// so each submission gets n reviewers and each reviewer gets n submissions. The returned map is
+// reviewer => users to review, n is reduced when there are not enough users
+func PeerPairs(usersID []int64, n int) map[int64][]int64 {
+	pairs := map[int64][]int64{}
+	if n > len(usersID)-1 {
+		n = len(usersID) - 1
+	}
+	if n < 1 {
+		return pairs
+	}
+	for i, reviewerID := range usersID {
+		for k := 1; k <= n; k++ {
+			pairs[reviewerID] = append(pairs[reviewerID], usersID[(i+k)%len(usersID)])
+		}
+	}
+	return pairs
+}
+
+// PeerScores returns the average score of the submitted reviews of every user
+func PeerScores(reviews []PeerReview) map[int64]float64 {
+	total := map[int64]float64{}
+	count := map[int64]int{}
+	for _, val := range reviews {
+		if val.Status != PeerSubmitted || !val.Score.Valid {
+			continue
+		}
+		total[val.UserID] += val.Score.Float64
+		count[val.UserID]++
+	}
+
+	scores := map[int64]float64{}
+	for userID, val := range total {
+		scores[userID] = math.Round(val/float64(count[userID])*100) / 100
+	}
+	return scores
+}
+
+// PeerOutliers returns the id of submitted reviews whose score is too far from the median score
+// of the same submission, it needs enough reviews on the submission to find the median
+func PeerOutliers(reviews []PeerReview) map[int64]bool {
+	userScores := map[int64][]float64{}
+	for _, val := range reviews {
+		if val.Status != PeerSubmitted || !val.Score.Valid {
+			continue
+		}
+		userScores[val.UserID] = append(userScores[val.UserID], val.Score.Float64)
+	}
+
+	medians := map[int64]float64{}
+	for userID, scores := range userScores {
+		if len(scores) < PeerOutlierMinReviews {
+			continue
+		}
+		sort.Float64s(scores)
+		mid := len(scores) / 2
+		medians[userID] = scores[mid]
+		if len(scores)%2 == 0 {
+			medians[userID] = (scores[mid-1] + scores[mid]) / 2
+		}
+	}
+
+	outliers := map[int64]bool{}
+	for _, val := range reviews {
+		median, ok := medians[val.UserID]
+		if !ok || val.Status != PeerSubmitted || !val.Score.Valid {
+			continue
+		}
+		if math.Abs(val.Score.Float64-median) > PeerOutlierDistance {
+			outliers[val.ID] = true
+		}
+	}
+	return outliers
+}
+
+// StaffScore returns the score of the user before any peer score was combined into it,
+// logs must be the score history of the user from the newest one and current is the current score
+func StaffScore(logs []ScoreLog, current sql.NullFloat64) sql.NullFloat64 {
+	score := current
+	for _, val := range logs {
+		if val.Source != ScoreSourcePeer {
+			break
+		}
+		score = val.OldScore
+	}
+	return score
+}
+
+// UpdatePeerReview sets the peer review mode of the assignment, zero reviewers disables it
+func UpdatePeerReview(id int64, reviewers int8, closeDate mysql.NullTime, weight float64, tx *sqlx.Tx) error {
+	queryCloseDate := fmt.Sprintf("(NULL)")
+	if closeDate.Valid {
+		queryCloseDate = fmt.Sprintf("('%s')", closeDate.Time.Format("2006-01-02 15:04:05"))
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE
+			assignments
+		SET
+			peer_reviewers = (%d),
+			peer_review_close = %s,
+			peer_weight = (%g)
+		WHERE
+			id = (%d);
+		`, reviewers, queryCloseDate, weight, id)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// InsertPeerReview marks the assignment as assigned and creates the peer reviews from pairs of
+// reviewer => users, "No rows affected" is returned when the assignment has been assigned before
+func InsertPeerReview(assignmentID int64, pairs map[int64][]int64, tx *sqlx.Tx) error {
+	var values []string
+	for reviewerID, usersID := range pairs {
+		for _, userID := range usersID {
+			values = append(values, fmt.Sprintf("((%d), (%d), (%d), (%d), NOW(), NOW())",
+				assignmentID, reviewerID, userID, PeerAssigned))
+		}
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE
+			assignments
+		SET
+			peer_assigned_at = NOW()
+		WHERE
+			id = (%d) AND
+			peer_assigned_at IS NULL;
+		`, assignmentID)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("No rows affected")
+	}
+
+	if len(values) < 1 {
+		return nil
+	}
+	query = fmt.Sprintf(`
+		INSERT INTO
+			peer_reviews (
+				assignments_id,
+				reviewer_id,
+				users_id,
+				status,
+				created_at,
+				updated_at
+			) VALUES %s;`, strings.Join(values, ", "))
+
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetPeerReview returns the peer review, nil is returned when it does not exist
+func GetPeerReview(id int64) (*PeerReview, error) {
+	var review PeerReview
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			assignments_id,
+			reviewer_id,
+			users_id,
+			status,
+			score,
+			comment,
+			moderated_by,
+			created_at,
+			updated_at
+		FROM
+			peer_reviews
+		WHERE
+			id = (%d)
+		LIMIT 1;`, id)
+	err := conn.DB.Get(&review, query)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &review, nil
+}
+
+// SelectPeerReview returns the peer reviews of the assignment, set reviewerID to only return reviews
+// filled by a user and userID to only return reviews on the submission of a user
+func SelectPeerReview(assignmentID int64, reviewerID, userID *int64) ([]PeerReview, error) {
+	var reviews []PeerReview
+	queryReviewer := ""
+	if reviewerID != nil {
+		queryReviewer = fmt.Sprintf("AND reviewer_id = (%d)", *reviewerID)
+	}
+	queryUser := ""
+	if userID != nil {
+		queryUser = fmt.Sprintf("AND users_id = (%d)", *userID)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			assignments_id,
+			reviewer_id,
+			users_id,
+			status,
+			score,
+			comment,
+			moderated_by,
+			created_at,
+			updated_at
+		FROM
+			peer_reviews
+		WHERE
+			assignments_id = (%d)
+			%s
+			%s
+		ORDER BY
+			id ASC;`, assignmentID, queryReviewer, queryUser)
+	err := conn.DB.Select(&reviews, query)
+	if err != nil && err != sql.ErrNoRows {
+		return reviews, err
+	}
+	return reviews, nil
+}
+
+// SubmitPeerReview saves the filled rubric of the peer review, a submitted review may be filled again
+// but an excluded review is kept as it is
+func SubmitPeerReview(id int64, score float64, comment sql.NullString, scores []PeerReviewScore, tx *sqlx.Tx) error {
+	queryComment := fmt.Sprintf("(NULL)")
+	if comment.Valid {
+		queryComment = fmt.Sprintf("('%s')", comment.String)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE
+			peer_reviews
+		SET
+			status = (%d),
+			score = (%g),
+			comment = %s,
+			updated_at = NOW()
+		WHERE
+			id = (%d) AND
+			status IN (%d, %d);`, PeerSubmitted, score, queryComment, id, PeerAssigned, PeerSubmitted)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("No rows affected")
+	}
+
+	queries := []string{fmt.Sprintf(`
+		DELETE FROM
+			peer_review_scores
+		WHERE
+			peer_reviews_id = (%d);`, id)}
+
+	var values []string
+	for _, val := range scores {
+		queryScoreComment := fmt.Sprintf("(NULL)")
+		if val.Comment.Valid {
+			queryScoreComment = fmt.Sprintf("('%s')", val.Comment.String)
+		}
+		values = append(values, fmt.Sprintf("((%d), (%d), (%d), (%g), %s)",
+			id, val.CriterionID, val.LevelID, val.Points, queryScoreComment))
+	}
+	if len(values) > 0 {
+		queries = append(queries, fmt.Sprintf(`
+			INSERT INTO
+				peer_review_scores (
+					peer_reviews_id,
+					rubric_criteria_id,
+					rubric_levels_id,
+					points,
+					comment
+				) VALUES %s;`, strings.Join(values, ", ")))
+	}
+
+	for _, query := range queries {
+		if tx != nil {
+			_, err = tx.Exec(query)
+		} else {
+			_, err = conn.DB.Exec(query)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SelectPeerReviewScore returns the filled rubric of the peer reviews
+func SelectPeerReviewScore(reviewsID []int64) ([]PeerReviewScore, error) {
+	var scores []PeerReviewScore
+	if len(reviewsID) < 1 {
+		return scores, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			peer_reviews_id,
+			rubric_criteria_id,
+			rubric_levels_id,
+			points,
+			comment
+		FROM
+			peer_review_scores
+		WHERE
+			peer_reviews_id IN (%s);`, strings.Join(helper.Int64ToStringSlice(reviewsID), ", "))
+	err := conn.DB.Select(&scores, query)
+	if err != nil && err != sql.ErrNoRows {
+		return scores, err
+	}
+	return scores, nil
+}
+
+// ModeratePeerReview changes the status of the peer review when its current status is from
+func ModeratePeerReview(id int64, from, status int8, moderatedBy int64, tx *sqlx.Tx) error {
+	query := fmt.Sprintf(`
+		UPDATE
+			peer_reviews
+		SET
+			status = (%d),
+			moderated_by = (%d),
+			updated_at = NOW()
+		WHERE
+			id = (%d) AND
+			status = (%d);`, status, moderatedBy, id, from)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("No rows affected")
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package assignment
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/asepnur/meiko_course/src/util/publish"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+)
+
+// IsPublished returns true when the assignment is visible to students at now, now is the database time
+// from publish.Now. An assignment without publish date is published since it is created
+func (a Assignment) IsPublished(now time.Time) bool {
+	return publish.IsPublished(a.PublishAt, a.UnpublishAt, now)
+}
+
+// UpdatePublish sets the publish window of the assignment, the new assignment notification
+// is sent again when the assignment is moved to be published later
+func UpdatePublish(id int64, publishAt, unpublishAt mysql.NullTime, tx *sqlx.Tx) error {
+	err := publish.Update("assignments", id, publishAt, unpublishAt, tx)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE
+			assignments
+		SET
+			notified_at = NULL
+		WHERE
+			id = (%d) AND
+			publish_at > NOW();
+		`, id)
+
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SelectUnnotified returns scheduled assignments which have been published
+// but their new assignment notification has not been sent
+func SelectUnnotified() ([]Assignment, error) {
+	var assignments []Assignment
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			name,
+			status,
+			description,
+			grade_parameters_id,
+			due_date,
+			publish_at,
+			unpublish_at,
+			created_at,
+			updated_at
+		FROM
+			assignments
+		WHERE
+			publish_at IS NOT NULL AND
+			publish_at <= NOW() AND
+			notified_at IS NULL AND
+			(unpublish_at IS NULL OR unpublish_at > NOW())
+		ORDER BY
+			publish_at ASC;`)
+	err := conn.DB.Select(&assignments, query)
+	if err != nil && err != sql.ErrNoRows {
+		return assignments, err
+	}
+	return assignments, nil
+}
+
+// UpdateNotified marks the new assignment notification of the assignments as sent
+func UpdateNotified(id []int64, tx *sqlx.Tx) error {
+	if len(id) < 1 {
+		return nil
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE
+			assignments
+		SET
+			notified_at = NOW()
+		WHERE
+			id IN (%s);
+		`, strings.Join(helper.Int64ToStringSlice(id), ", "))
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package assignment
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/jmoiron/sqlx"
+)
+
+// InsertRegrade creates an open regrade request on the submission of the user
+func InsertRegrade(assignmentID, userID int64, reason string, tx *sqlx.Tx) (int64, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO
+			regrade_requests (
+				assignments_id,
+				users_id,
+				reason,
+				status,
+				created_at,
+				updated_at
+			) VALUES (
+				(%d),
+				(%d),
+				('%s'),
+				(%d),
+				NOW(),
+				NOW()
+			);`, assignmentID, userID, reason, RegradeOpen)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// GetRegrade returns the regrade request, nil is returned when it does not exist
+func GetRegrade(id int64) (*Regrade, error) {
+	var regrade Regrade
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			assignments_id,
+			users_id,
+			reason,
+			status,
+			response,
+			handled_by,
+			created_at,
+			updated_at
+		FROM
+			regrade_requests
+		WHERE
+			id = (%d)
+		LIMIT 1;`, id)
+	err := conn.DB.Get(&regrade, query)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &regrade, nil
+}
+
+// IsRegradeOpen checks whether the user has a regrade request on the assignment which has not been resolved
+func IsRegradeOpen(assignmentID, userID int64) bool {
+	var x string
+	query := fmt.Sprintf(`
+		SELECT
+			'x'
+		FROM
+			regrade_requests
+		WHERE
+			assignments_id = (%d) AND
+			users_id = (%d) AND
+			status IN (%d, %d)
+		LIMIT 1;`, assignmentID, userID, RegradeOpen, RegradeEscalated)
+	err := conn.DB.Get(&x, query)
+	if err != nil {
+		return false
+	}
+	return true
+}
+
+// SelectRegrade returns regrade requests of the assignments from the oldest one,
+// set userID to only return requests of a user and status to filter by status
+func SelectRegrade(assignmentsID []int64, userID *int64, status []int8) ([]Regrade, error) {
+	var regrades []Regrade
+	if len(assignmentsID) < 1 {
+		return regrades, nil
+	}
+
+	queryUser := ""
+	if userID != nil {
+		queryUser = fmt.Sprintf("AND users_id = (%d)", *userID)
+	}
+	queryStatus := ""
+	if len(status) > 0 {
+		var statuses []string
+		for _, val := range status {
+			statuses = append(statuses, fmt.Sprintf("%d", val))
+		}
+		queryStatus = fmt.Sprintf("AND status IN (%s)", strings.Join(statuses, ", "))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			assignments_id,
+			users_id,
+			reason,
+			status,
+			response,
+			handled_by,
+			created_at,
+			updated_at
+		FROM
+			regrade_requests
+		WHERE
+			assignments_id IN (%s)
+			%s
+			%s
+		ORDER BY
+			id ASC;`, strings.Join(helper.Int64ToStringSlice(assignmentsID), ", "), queryUser, queryStatus)
+
+	err := conn.DB.Select(&regrades, query)
+	if err != nil && err != sql.ErrNoRows {
+		return regrades, err
+	}
+	return regrades, nil
+}
+
+// UpdateRegrade changes the status of the regrade request when its current status is one of from,
+// so a request can not be resolved twice
+func UpdateRegrade(id int64, from []int8, status int8, handledBy int64, response sql.NullString, tx *sqlx.Tx) error {
+	queryResponse := fmt.Sprintf("(NULL)")
+	if response.Valid {
+		queryResponse = fmt.Sprintf("('%s')", response.String)
+	}
+	var statuses []string
+	for _, val := range from {
+		statuses = append(statuses, fmt.Sprintf("%d", val))
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE
+			regrade_requests
+		SET
+			status = (%d),
+			response = %s,
+			handled_by = (%d),
+			updated_at = NOW()
+		WHERE
+			id = (%d) AND
+			status IN (%s);`, status, queryResponse, handledBy, id, strings.Join(statuses, ", "))
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("No rows affected")
+	}
+	return nil
+}
+
+// CloseRegrade closes every unresolved regrade request of the assignments
+func CloseRegrade(assignmentsID []int64, tx *sqlx.Tx) error {
+	if len(assignmentsID) < 1 {
+		return nil
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE
+			regrade_requests
+		SET
+			status = (%d),
+			updated_at = NOW()
+		WHERE
+			assignments_id IN (%s) AND
+			status IN (%d, %d);`, RegradeClosed, strings.Join(helper.Int64ToStringSlice(assignmentsID), ", "),
+		RegradeOpen, RegradeEscalated)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
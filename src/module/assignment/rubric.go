@@ -0,0 +1,44 @@
+package assignment
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/jmoiron/sqlx"
+)
+
+// UpdateRubric attaches the rubric to the assignment, invalid rubricID detaches the current rubric
+func UpdateRubric(id int64, rubricID sql.NullInt64, tx *sqlx.Tx) error {
+	queryRubric := fmt.Sprintf("(NULL)")
+	if rubricID.Valid {
+		queryRubric = fmt.Sprintf("(%d)", rubricID.Int64)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE
+			assignments
+		SET
+			rubrics_id = %s,
+			updated_at = NOW()
+		WHERE
+			id = (%d);
+		`, queryRubric, id)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("No rows affected")
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+package assignment
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/jmoiron/sqlx"
+)
+
+// InsertSimilarityReport starts a new running similarity report of the assignment, the report is only inserted
+// when the assignment has no other running report so the check can not race with another insert.
+// "No rows affected" is returned when a report is still running
+func InsertSimilarityReport(assignmentID, createdBy int64, tx *sqlx.Tx) (int64, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO
+			similarity_reports (
+				assignments_id,
+				status,
+				created_by,
+				created_at
+			)
+		SELECT
+			(%d),
+			(%d),
+			(%d),
+			NOW()
+		FROM
+			DUAL
+		WHERE
+			NOT EXISTS (
+				SELECT
+					'x'
+				FROM
+					similarity_reports
+				WHERE
+					assignments_id = (%d) AND
+					status = (%d)
+			);`, assignmentID, SimilarityRunning, createdBy, assignmentID, SimilarityRunning)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if rows == 0 {
+		return 0, fmt.Errorf("No rows affected")
+	}
+	return result.LastInsertId()
+}
+
+// ExpireSimilarityReport fails the running similarity reports of the assignment which are older than
+// MaxSimilarityReport, the report of a crashed or restarted server is never finished otherwise
+func ExpireSimilarityReport(assignmentID int64, tx *sqlx.Tx) error {
+	query := fmt.Sprintf(`
+		UPDATE
+			similarity_reports
+		SET
+			status = (%d),
+			message = ('Similarity report has expired'),
+			finished_at = NOW()
+		WHERE
+			assignments_id = (%d) AND
+			status = (%d) AND
+			created_at < DATE_SUB(NOW(), INTERVAL %d SECOND);`, SimilarityFailed, assignmentID, SimilarityRunning,
+		MaxSimilarityReport)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// FinishSimilarityReport sets the final status of a running similarity report, "No rows affected" is returned
+// when the report is not running anymore because it has expired
+func FinishSimilarityReport(reportID int64, status int8, message sql.NullString, tx *sqlx.Tx) error {
+	queryMessage := fmt.Sprintf("(NULL)")
+	if message.Valid {
+		queryMessage = fmt.Sprintf("('%s')", helper.EscapeText(message.String))
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE
+			similarity_reports
+		SET
+			status = (%d),
+			message = %s,
+			finished_at = NOW()
+		WHERE
+			id = (%d) AND
+			status = (%d);`, status, queryMessage, reportID, SimilarityRunning)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("No rows affected")
+	}
+	return nil
+}
+
+// InsertSimilarityPair saves the compared pairs of a similarity report
+func InsertSimilarityPair(pairs []SimilarityPair, tx *sqlx.Tx) error {
+	if len(pairs) < 1 {
+		return nil
+	}
+
+	// matches is json of extracted text, backslash and quote are escaped so the json stays intact
+	var values []string
+	for _, val := range pairs {
+		values = append(values, fmt.Sprintf("((%d), (%d), (%d), (%g), (%g), (%g), ('%s'))",
+			val.ReportID, val.UserID, val.OtherUserID, val.Score, val.ScoreUser, val.ScoreOther,
+			helper.EscapeText(val.Matches)))
+	}
+	query := fmt.Sprintf(`
+		INSERT INTO
+			similarity_pairs (
+				similarity_reports_id,
+				users_id,
+				other_users_id,
+				score,
+				score_user,
+				score_other,
+				matches
+			) VALUES %s;`, strings.Join(values, ", "))
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetLatestSimilarityReport returns the last similarity report of the assignment
+func GetLatestSimilarityReport(assignmentID int64) (SimilarityReport, error) {
+	var report SimilarityReport
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			assignments_id,
+			status,
+			message,
+			created_by,
+			created_at,
+			finished_at
+		FROM
+			similarity_reports
+		WHERE
+			assignments_id = (%d)
+		ORDER BY
+			id DESC
+		LIMIT 1;`, assignmentID)
+	err := conn.DB.Get(&report, query)
+	if err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// SelectSimilarityPair returns the pairs of a similarity report, the most similar pair first.
+// Matches is not selected, use GetSimilarityPair to get the matching regions
+func SelectSimilarityPair(reportID int64) ([]SimilarityPair, error) {
+	var pairs []SimilarityPair
+	query := fmt.Sprintf(`
+		SELECT
+			similarity_reports_id,
+			users_id,
+			other_users_id,
+			score,
+			score_user,
+			score_other
+		FROM
+			similarity_pairs
+		WHERE
+			similarity_reports_id = (%d)
+		ORDER BY
+			score DESC;`, reportID)
+	err := conn.DB.Select(&pairs, query)
+	if err != nil && err != sql.ErrNoRows {
+		return pairs, err
+	}
+	return pairs, nil
+}
+
+// GetSimilarityPair returns the pair of two users on a similarity report in any order
+func GetSimilarityPair(reportID, userID, otherUserID int64) (SimilarityPair, error) {
+	var pair SimilarityPair
+	query := fmt.Sprintf(`
+		SELECT
+			similarity_reports_id,
+			users_id,
+			other_users_id,
+			score,
+			score_user,
+			score_other,
+			matches
+		FROM
+			similarity_pairs
+		WHERE
+			similarity_reports_id = (%d) AND
+			(
+				(users_id = (%d) AND other_users_id = (%d)) OR
+				(users_id = (%d) AND other_users_id = (%d))
+			)
+		LIMIT 1;`, reportID, userID, otherUserID, otherUserID, userID)
+	err := conn.DB.Get(&pair, query)
+	if err != nil {
+		return pair, err
+	}
+	return pair, nil
+}
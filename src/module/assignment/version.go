@@ -0,0 +1,318 @@
+package assignment
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/jmoiron/sqlx"
+)
+
+// InsertVersion records a submission attempt of the user with its files,
+// version number continues from the last version of the user on the assignment
+func InsertVersion(assignmentID, userID int64, desc sql.NullString, lateSeconds int64, hash string, submittedBy int64, filesID []string, tx *sqlx.Tx) error {
+	queryDesc := fmt.Sprintf("(NULL)")
+	if desc.Valid {
+		queryDesc = fmt.Sprintf("('%s')", desc.String)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO
+			submission_versions (
+				assignments_id,
+				users_id,
+				version,
+				description,
+				late_seconds,
+				hash,
+				submitted_by,
+				created_at
+			)
+		SELECT
+			(%d),
+			(%d),
+			COALESCE(MAX(version), 0) + 1,
+			%s,
+			(%d),
+			('%s'),
+			(%d),
+			NOW()
+		FROM
+			submission_versions
+		WHERE
+			assignments_id = (%d) AND
+			users_id = (%d);
+		`, assignmentID, userID, queryDesc, lateSeconds, hash, submittedBy, assignmentID, userID)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+
+	versionID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	if len(filesID) < 1 {
+		return nil
+	}
+
+	var values []string
+	for _, val := range filesID {
+		values = append(values, fmt.Sprintf("((%d), ('%s'))", versionID, val))
+	}
+	query = fmt.Sprintf(`
+		INSERT INTO
+			submission_version_files (
+				submission_versions_id,
+				files_id
+			) VALUES %s;`, strings.Join(values, ", "))
+
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SelectVersion returns every version of the user on the assignment, latest version first
+func SelectVersion(assignmentID, userID int64) ([]Version, error) {
+	var versions []Version
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			assignments_id,
+			users_id,
+			version,
+			description,
+			late_seconds,
+			hash,
+			submitted_by,
+			created_at
+		FROM
+			submission_versions
+		WHERE
+			assignments_id = (%d) AND
+			users_id = (%d)
+		ORDER BY
+			version DESC;`, assignmentID, userID)
+	err := conn.DB.Select(&versions, query)
+	if err != nil && err != sql.ErrNoRows {
+		return versions, err
+	}
+	return versions, nil
+}
+
+// GetVersion returns the version of the user, nil is returned when it does not exist
+func GetVersion(assignmentID, userID, version int64) (*Version, error) {
+	v := &Version{}
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			assignments_id,
+			users_id,
+			version,
+			description,
+			late_seconds,
+			hash,
+			submitted_by,
+			created_at
+		FROM
+			submission_versions
+		WHERE
+			assignments_id = (%d) AND
+			users_id = (%d) AND
+			version = (%d)
+		LIMIT 1;`, assignmentID, userID, version)
+	err := conn.DB.Get(v, query)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return v, nil
+}
+
+// SelectGradedVersion returns the graded version of every user on the assignment,
+// it is the pinned version or the latest version when none is pinned
+func SelectGradedVersion(assignmentID int64) ([]Version, error) {
+	var versions []Version
+	query := fmt.Sprintf(`
+		SELECT
+			sv.id,
+			sv.assignments_id,
+			sv.users_id,
+			sv.version,
+			sv.description,
+			sv.late_seconds,
+			sv.hash,
+			sv.submitted_by,
+			sv.created_at
+		FROM
+			submission_versions sv
+		LEFT JOIN
+			p_users_assignments pua
+		ON
+			pua.assignments_id = sv.assignments_id AND
+			pua.users_id = sv.users_id
+		WHERE
+			sv.assignments_id = (%d) AND
+			sv.version = COALESCE(pua.pinned_version, (
+				SELECT
+					MAX(latest.version)
+				FROM
+					submission_versions latest
+				WHERE
+					latest.assignments_id = sv.assignments_id AND
+					latest.users_id = sv.users_id
+			));`, assignmentID)
+	err := conn.DB.Select(&versions, query)
+	if err != nil && err != sql.ErrNoRows {
+		return versions, err
+	}
+	return versions, nil
+}
+
+// SelectVersionFile returns files of the versions
+func SelectVersionFile(versionsID []int64) ([]VersionFile, error) {
+	var files []VersionFile
+	if len(versionsID) < 1 {
+		return files, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			submission_versions_id,
+			files_id
+		FROM
+			submission_version_files
+		WHERE
+			submission_versions_id IN (%s);`, strings.Join(helper.Int64ToStringSlice(versionsID), ", "))
+	err := conn.DB.Select(&files, query)
+	if err != nil && err != sql.ErrNoRows {
+		return files, err
+	}
+	return files, nil
+}
+
+// SelectPinnedFileID returns the files of the pinned version of every user whose grading is pinned, keyed by the
+// graded user and filtered by user when userID is not nil. A pinned version without files still has an entry
+// since the latest files of the user are not graded either
+func SelectPinnedFileID(assignmentID int64, userID *int64) (map[int64][]string, error) {
+	pinned := map[int64][]string{}
+
+	var queryUserID string
+	if userID != nil {
+		queryUserID = fmt.Sprintf("AND pua.users_id = (%d)", *userID)
+	}
+	query := fmt.Sprintf(`
+		SELECT
+			pua.users_id,
+			svf.files_id
+		FROM
+			p_users_assignments pua
+		LEFT JOIN
+			submission_versions sv
+		ON
+			sv.assignments_id = pua.assignments_id AND
+			sv.users_id = pua.users_id AND
+			sv.version = pua.pinned_version
+		LEFT JOIN
+			submission_version_files svf
+		ON
+			svf.submission_versions_id = sv.id
+		WHERE
+			pua.assignments_id = (%d) AND
+			pua.pinned_version IS NOT NULL
+			%s;
+		`, assignmentID, queryUserID)
+	var files []PinnedFile
+	err := conn.DB.Select(&files, query)
+	if err != nil && err != sql.ErrNoRows {
+		return pinned, err
+	}
+
+	for _, val := range files {
+		if _, ok := pinned[val.UserID]; !ok {
+			pinned[val.UserID] = []string{}
+		}
+		if val.FileID.Valid {
+			pinned[val.UserID] = append(pinned[val.UserID], val.FileID.String)
+		}
+	}
+	return pinned, nil
+}
+
+// UpdateVersionLateness sets the late seconds of a version, it is used when the due date of the user changes
+func UpdateVersionLateness(versionID, lateSeconds int64, tx *sqlx.Tx) error {
+	query := fmt.Sprintf(`
+		UPDATE
+			submission_versions
+		SET
+			late_seconds = (%d)
+		WHERE
+			id = (%d);
+		`, lateSeconds, versionID)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdatePinnedVersion sets the version used for grading, invalid version means the latest version is graded.
+// Late seconds is replaced with the late seconds of the graded version
+func UpdatePinnedVersion(assignmentID, userID int64, version sql.NullInt64, lateSeconds int64, tx *sqlx.Tx) error {
+	queryVersion := fmt.Sprintf("(NULL)")
+	if version.Valid {
+		queryVersion = fmt.Sprintf("(%d)", version.Int64)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE
+			p_users_assignments
+		SET
+			pinned_version = %s,
+			late_seconds = (%d),
+			updated_at = NOW()
+		WHERE
+			assignments_id = (%d) AND
+			users_id = (%d);
+		`, queryVersion, lateSeconds, assignmentID, userID)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("No rows affected")
+	}
+	return nil
+}
@@ -213,9 +213,9 @@ func SelectAssignmentWithCourse(userID int64, rgxCourse sql.NullString, t []time
 	// days query
 	switch len(t) {
 	case 1:
-		queryTime = fmt.Sprintf("date(a.due_date) = ('%s') AND", t[0].Format("2006-01-02"))
+		queryTime = fmt.Sprintf("date(COALESCE(e.due_date, a.due_date)) = ('%s') AND", t[0].Format("2006-01-02"))
 	case 2:
-		queryTime = fmt.Sprintf("date(a.due_date) BETWEEN ('%s') AND ('%s') AND", t[0].Format("2006-01-02"), t[1].Format("2006-01-02"))
+		queryTime = fmt.Sprintf("date(COALESCE(e.due_date, a.due_date)) BETWEEN ('%s') AND ('%s') AND", t[0].Format("2006-01-02"), t[1].Format("2006-01-02"))
 	}
 
 	query := fmt.Sprintf(`
@@ -223,15 +223,18 @@ func SelectAssignmentWithCourse(userID int64, rgxCourse sql.NullString, t []time
 			a.id,
 			a.name,
 			COALESCE(a.description, '-') as description,
-			a.due_date,
+			COALESCE(e.due_date, a.due_date) as due_date,
 			c.name as course_name
 		FROM
 			assignments a
 		INNER JOIN grade_parameters g ON g.id = a.grade_parameters_id
 		INNER JOIN schedules s ON s.id = g.schedules_id
 		INNER JOIN courses c ON c.id = s.courses_id
+		LEFT JOIN assignment_extensions e ON e.assignments_id = a.id AND e.users_id = (%d)
 		WHERE
 			%s %s
+			(a.publish_at IS NULL OR a.publish_at <= NOW()) AND
+			(a.unpublish_at IS NULL OR a.unpublish_at > NOW()) AND
 			a.id NOT IN (
 				SELECT
 					assignments_id
@@ -249,9 +252,9 @@ func SelectAssignmentWithCourse(userID int64, rgxCourse sql.NullString, t []time
 					users_id = (%d) AND
 					status = 1
 				)
-		ORDER BY a.due_date ASC
+		ORDER BY due_date ASC
 		LIMIT 5;
-		`, queryTime, queryCourse, userID, userID)
+		`, userID, queryTime, queryCourse, userID, userID)
 
 	err := conn.DB.Select(&assignments, query)
 	if err != nil {
@@ -285,7 +288,9 @@ func SelectGradeWithCourse(userID int64, rgxCourse sql.NullString, t []time.Time
 			a.id,
 			a.name,
 			c.name as course_name,
+			s.id as schedules_id,
 			p.score,
+			p.late_seconds,
 			p.updated_at
 		FROM
 			assignments a
@@ -353,7 +358,10 @@ func SelectInfoWithFile(scheduleID []int64, t []time.Time) ([]Information, error
 			(
 				schedules_id IS NULL OR
 				schedules_id IN (%s)
-			) %s
+			) AND
+			(i.publish_at IS NULL OR i.publish_at <= NOW()) AND
+			(i.unpublish_at IS NULL OR i.unpublish_at > NOW())
+			%s
 		ORDER BY i.created_at DESC
 		LIMIT 5`, ids, queryTime)
 	err := conn.DB.Select(&info, query)
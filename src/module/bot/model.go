@@ -48,7 +48,9 @@ type Grade struct {
 	AssignmentID string    `db:"id"`
 	Name         string    `db:"name"`
 	Score        float32   `db:"score"`
+	LateSeconds  int64     `db:"late_seconds"`
 	CourseName   string    `db:"course_name"`
+	ScheduleID   int64     `db:"schedules_id"`
 	UpdatedAt    time.Time `db:"updated_at"`
 }
 
@@ -780,6 +780,10 @@ func SelectByName(name string) ([]Course, error) {
 
 func InsertGradeParameter(typ string, percentage float32, statusChange uint8, scheduleID int64, tx *sqlx.Tx) error {
 
+	if percentage <= 0 || percentage > MaxPercentage {
+		return fmt.Errorf("Percentage must be between 0 and %d", MaxPercentage)
+	}
+
 	query := fmt.Sprintf(`
 		INSERT INTO
 		grade_parameters (
@@ -901,6 +905,10 @@ func DeleteGradeParameter(id int64, tx *sqlx.Tx) error {
 
 func UpdateGradeParameter(typ string, percentage float32, statusChange uint8, scheduleID int64, tx *sqlx.Tx) error {
 
+	if percentage <= 0 || percentage > MaxPercentage {
+		return fmt.Errorf("Percentage must be between 0 and %d", MaxPercentage)
+	}
+
 	query := fmt.Sprintf(`
 		UPDATE
 			grade_parameters
@@ -1293,3 +1301,39 @@ func DeleteUserRelation(userID, scheduleID int64) error {
 
 	return nil
 }
+
+// SelectSiblingSchedule returns every class of the same course on the same semester as the schedule,
+// the schedule itself is included
+func SelectSiblingSchedule(scheduleID int64) ([]Schedule, error) {
+	var schedules []Schedule
+	query := fmt.Sprintf(`
+		SELECT
+			sc.id,
+			sc.status,
+			sc.start_time,
+			sc.end_time,
+			sc.day,
+			sc.class,
+			sc.semester,
+			sc.year,
+			sc.courses_id,
+			sc.places_id,
+			sc.created_by
+		FROM
+			schedules sc
+		JOIN
+			schedules cur
+		ON
+			sc.courses_id = cur.courses_id AND
+			sc.semester = cur.semester AND
+			sc.year = cur.year
+		WHERE
+			cur.id = (%d)
+		ORDER BY
+			sc.class ASC;`, scheduleID)
+	err := conn.DB.Select(&schedules, query)
+	if err != nil && err != sql.ErrNoRows {
+		return schedules, err
+	}
+	return schedules, nil
+}
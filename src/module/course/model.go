@@ -2,6 +2,7 @@ package course
 
 import (
 	"database/sql"
+	"time"
 )
 
 const (
@@ -23,8 +24,22 @@ const (
 
 	GradeParameterStatusUnchange = 0
 	GradeParameterStatusChange   = 1
+
+	GradeParameterDraft     = 0
+	GradeParameterPublished = 1
+
+	MaxPercentage = 100
 )
 
+// GradeParameterTypes is the list of valid grade parameter type
+var GradeParameterTypes = []string{
+	GradeParameterAssignment,
+	GradeParameterAttendance,
+	GradeParameterFinal,
+	GradeParameterMid,
+	GradeParameterQuiz,
+}
+
 // Course struct user detail information to get course that will be send to server in database
 type Course struct {
 	ID          string         `db:"id"`
@@ -76,3 +91,16 @@ type User struct {
 	Phone        sql.NullString `json:"phone"`
 	RoleGroupsID sql.NullInt64  `json:"rolegroups_id"`
 }
+
+// GradeParameterOverride is the record of percentage change which is made after scores exist,
+// empty old percentage means the parameter is inserted and empty new percentage means it is deleted
+type GradeParameterOverride struct {
+	ID            int64           `db:"id"`
+	ScheduleID    int64           `db:"schedules_id"`
+	Type          string          `db:"type"`
+	OldPercentage sql.NullFloat64 `db:"old_percentage"`
+	NewPercentage sql.NullFloat64 `db:"new_percentage"`
+	Reason        string          `db:"reason"`
+	UserID        int64           `db:"users_id"`
+	CreatedAt     time.Time       `db:"created_at"`
+}
@@ -0,0 +1,156 @@
+package course
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/jmoiron/sqlx"
+)
+
+// ValidateGradeParameters checks that every type is valid and used once, and the percentages total exactly 100
+func ValidateGradeParameters(gps []GradeParameter) error {
+	if len(gps) < 1 {
+		return fmt.Errorf("Grade parameter can not be empty")
+	}
+
+	used := map[string]bool{}
+	total := float64(0)
+	for _, val := range gps {
+		isValid := false
+		for _, typ := range GradeParameterTypes {
+			if val.Type == typ {
+				isValid = true
+			}
+		}
+		if !isValid {
+			return fmt.Errorf("Invalid grade parameter %s", val.Type)
+		}
+
+		if used[val.Type] {
+			return fmt.Errorf("Grade parameter %s is duplicated", val.Type)
+		}
+		used[val.Type] = true
+
+		if val.Percentage <= 0 || val.Percentage > MaxPercentage {
+			return fmt.Errorf("Percentage of %s must be between 0 and %d", val.Type, MaxPercentage)
+		}
+		total += float64(val.Percentage)
+	}
+
+	// percentage is stored with 2 decimals
+	if math.Abs(total-MaxPercentage) > 0.001 {
+		return fmt.Errorf("Total percentage must be 100%%, got %g%%", math.Round(total*100)/100)
+	}
+	return nil
+}
+
+// GetGPStatus returns whether the grade parameters of the schedule is still a draft or has been published
+func GetGPStatus(scheduleID int64) (int8, error) {
+	var status int8
+	query := fmt.Sprintf(`
+		SELECT
+			grade_parameter_status
+		FROM
+			schedules
+		WHERE
+			id = (%d)
+		LIMIT 1;`, scheduleID)
+	err := conn.DB.Get(&status, query)
+	if err != nil {
+		return status, err
+	}
+	return status, nil
+}
+
+// UpdateGPStatus sets the grade parameters status of the schedule
+func UpdateGPStatus(scheduleID int64, status int8, tx *sqlx.Tx) error {
+	query := fmt.Sprintf(`
+		UPDATE
+			schedules
+		SET
+			grade_parameter_status = (%d)
+		WHERE
+			id = (%d);`, status, scheduleID)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// InsertGPOverride records a percentage change which is made after scores exist
+func InsertGPOverride(scheduleID int64, typ string, oldPercentage, newPercentage sql.NullFloat64, reason string, userID int64, tx *sqlx.Tx) error {
+	oldValue := "(NULL)"
+	if oldPercentage.Valid {
+		oldValue = fmt.Sprintf("(%f)", oldPercentage.Float64)
+	}
+	newValue := "(NULL)"
+	if newPercentage.Valid {
+		newValue = fmt.Sprintf("(%f)", newPercentage.Float64)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO
+			grade_parameter_overrides (
+				schedules_id,
+				type,
+				old_percentage,
+				new_percentage,
+				reason,
+				users_id,
+				created_at
+			) VALUES (
+				(%d),
+				('%s'),
+				%s,
+				%s,
+				('%s'),
+				(%d),
+				NOW()
+			);`, scheduleID, typ, oldValue, newValue, reason, userID)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SelectGPOverride returns the percentage overrides of the schedule from the newest
+func SelectGPOverride(scheduleID int64) ([]GradeParameterOverride, error) {
+	var overrides []GradeParameterOverride
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			schedules_id,
+			type,
+			old_percentage,
+			new_percentage,
+			reason,
+			users_id,
+			created_at
+		FROM
+			grade_parameter_overrides
+		WHERE
+			schedules_id = (%d)
+		ORDER BY
+			id DESC;`, scheduleID)
+	err := conn.DB.Select(&overrides, query)
+	if err != nil && err != sql.ErrNoRows {
+		return overrides, err
+	}
+	return overrides, nil
+}
@@ -0,0 +1,82 @@
+package course
+
+import "testing"
+
+func TestValidateGradeParameters(t *testing.T) {
+	cases := []struct {
+		name    string
+		gps     []GradeParameter
+		isValid bool
+	}{
+		{
+			name: "total 100",
+			gps: []GradeParameter{
+				{Type: GradeParameterQuiz, Percentage: 20},
+				{Type: GradeParameterMid, Percentage: 35.5},
+				{Type: GradeParameterFinal, Percentage: 44.5},
+			},
+			isValid: true,
+		},
+		{
+			name:    "empty",
+			gps:     nil,
+			isValid: false,
+		},
+		{
+			name: "total over 100",
+			gps: []GradeParameter{
+				{Type: GradeParameterQuiz, Percentage: 30},
+				{Type: GradeParameterMid, Percentage: 50},
+				{Type: GradeParameterFinal, Percentage: 50},
+			},
+			isValid: false,
+		},
+		{
+			name: "total under 100",
+			gps: []GradeParameter{
+				{Type: GradeParameterMid, Percentage: 30},
+				{Type: GradeParameterFinal, Percentage: 30},
+			},
+			isValid: false,
+		},
+		{
+			name: "total almost 100",
+			gps: []GradeParameter{
+				{Type: GradeParameterMid, Percentage: 49.6},
+				{Type: GradeParameterFinal, Percentage: 50},
+			},
+			isValid: false,
+		},
+		{
+			name: "duplicate type",
+			gps: []GradeParameter{
+				{Type: GradeParameterMid, Percentage: 50},
+				{Type: GradeParameterMid, Percentage: 50},
+			},
+			isValid: false,
+		},
+		{
+			name: "invalid type",
+			gps: []GradeParameter{
+				{Type: "PROJECT", Percentage: 50},
+				{Type: GradeParameterMid, Percentage: 50},
+			},
+			isValid: false,
+		},
+		{
+			name: "zero percentage",
+			gps: []GradeParameter{
+				{Type: GradeParameterQuiz, Percentage: 0},
+				{Type: GradeParameterFinal, Percentage: 100},
+			},
+			isValid: false,
+		},
+	}
+
+	for _, c := range cases {
+		err := ValidateGradeParameters(c.gps)
+		if (err == nil) != c.isValid {
+			t.Errorf("%s: ValidateGradeParameters() expected valid %t, got error %v", c.name, c.isValid, err)
+		}
+	}
+}
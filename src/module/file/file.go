@@ -3,11 +3,13 @@ package file
 import (
 	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/jmoiron/sqlx"
 
 	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/util/helper"
 )
 
 func GetByIDExt(id string, column ...string) (File, error) {
@@ -639,3 +641,82 @@ func SelectCountTypeByID(assignmentID int64) (int, error) {
 	}
 	return count, nil
 }
+
+// SelectByID returns files regardless of their status, deleted files are still kept on disk
+func SelectByID(filesID []string) ([]File, error) {
+	var files []File
+	if len(filesID) < 1 {
+		return files, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			name,
+			extension,
+			mime,
+			type,
+			users_id,
+			table_id
+		FROM
+			files
+		WHERE
+			id IN ('%s');
+		`, strings.Join(filesID, "', '"))
+	err := conn.DB.Select(&files, query)
+	if err != nil {
+		return files, err
+	}
+	return files, nil
+}
+
+// SelectGradedUpload returns the uploaded files of the assignment which are graded, filtered by user when userID
+// is not nil. pinned holds the files of the pinned version of every user whose grading is pinned, those files are
+// graded instead of the latest ones and they are returned regardless of their status since deleted files are still
+// kept on disk
+func SelectGradedUpload(assignmentID int64, userID *int64, pinned map[int64][]string) ([]File, error) {
+	tableID := strconv.FormatInt(assignmentID, 10)
+	files, err := SelectByRelation(TypAssignmentUpload, []string{tableID}, userID)
+	if err != nil {
+		return files, err
+	}
+	if len(pinned) < 1 {
+		return files, nil
+	}
+
+	var filesID []string
+	for _, val := range pinned {
+		for _, id := range val {
+			if !helper.IsStringInSlice(id, filesID) {
+				filesID = append(filesID, id)
+			}
+		}
+	}
+	versionFiles, err := SelectByID(filesID)
+	if err != nil {
+		return files, err
+	}
+	fileMap := map[string]File{}
+	for _, val := range versionFiles {
+		fileMap[val.ID] = val
+	}
+
+	graded := []File{}
+	for _, val := range files {
+		if _, ok := pinned[val.UserID]; !ok {
+			graded = append(graded, val)
+		}
+	}
+	// the file belongs to the graded user, a group member may have uploaded it
+	for user, val := range pinned {
+		for _, id := range val {
+			file, ok := fileMap[id]
+			if !ok {
+				continue
+			}
+			file.UserID = user
+			graded = append(graded, file)
+		}
+	}
+	return graded, nil
+}
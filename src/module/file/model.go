@@ -18,10 +18,14 @@ const (
 	TypProfPictThumb    = "PL-IMG-T"
 	TypAssignment       = "ASG-FILE"
 	TypAssignmentUpload = "ASG-UPL"
+	TypAssignmentReturn = "ASG-RTN"
+	TypRegrade          = "ASG-RGR"
 	TypTutorial         = "TT-FILE"
 	TypInfPict          = "INF-IMG-M"
 	TypInfPictThumb     = "INF-IMG-T"
 	TypInf              = "INF-FILE"
+	TypForumThread      = "FRM-THR"
+	TypForumReply       = "FRM-RPL"
 
 	TableAssignment = "assignments"
 	TableTutorial   = "tutorials"
@@ -0,0 +1,42 @@
+package file
+
+import (
+	"github.com/jmoiron/sqlx"
+)
+
+// ReplaceRelation relates the new files to the table row and deletes the files which are no longer related
+func ReplaceRelation(typ string, filesID []string, tableID string, tx *sqlx.Tx) error {
+
+	active, err := SelectByRelation(typ, []string{tableID}, nil)
+	if err != nil {
+		return err
+	}
+
+	activeID := map[string]bool{}
+	for _, val := range active {
+		activeID[val.ID] = true
+	}
+
+	inputID := map[string]bool{}
+	for _, val := range filesID {
+		inputID[val] = true
+		if activeID[val] {
+			continue
+		}
+		err = UpdateRelation(val, typ, tableID, tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, val := range active {
+		if inputID[val.ID] {
+			continue
+		}
+		err = Delete(val.ID, tx)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
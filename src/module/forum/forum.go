@@ -0,0 +1,471 @@
+package forum
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/util/helper"
+)
+
+// GetThreadByID ...
+func GetThreadByID(id int64) (Thread, error) {
+	var thread Thread
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			title,
+			content,
+			schedules_id,
+			users_id,
+			table_name,
+			table_id,
+			accepted_replies_id,
+			is_pinned,
+			is_locked,
+			created_at,
+			updated_at
+		FROM
+			forum_threads
+		WHERE
+			id = (%d) AND
+			status = (%d)
+		LIMIT 1;
+		`, id, StatusExist)
+
+	err := conn.DB.Get(&thread, query)
+	if err != nil {
+		return thread, err
+	}
+	return thread, nil
+}
+
+// SelectThreadByPage lists threads of the schedule, filtered by the related table when tableName is not empty
+func SelectThreadByPage(scheduleID int64, tableName string, tableID int64, limit, offset int, isCount bool) ([]Thread, int, error) {
+	var threads []Thread
+	var count int
+
+	var queryRelation string
+	if !helper.IsEmpty(tableName) {
+		queryRelation = fmt.Sprintf("table_name = ('%s') AND table_id = (%d) AND", tableName, tableID)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			title,
+			content,
+			schedules_id,
+			users_id,
+			table_name,
+			table_id,
+			accepted_replies_id,
+			is_pinned,
+			is_locked,
+			created_at,
+			updated_at
+		FROM
+			forum_threads
+		WHERE
+			%s
+			schedules_id = (%d) AND
+			status = (%d)
+		ORDER BY is_pinned DESC, updated_at DESC
+		LIMIT %d
+		OFFSET %d;
+		`, queryRelation, scheduleID, StatusExist, limit, offset)
+	err := conn.DB.Select(&threads, query)
+	if err != nil {
+		return threads, count, err
+	}
+
+	if !isCount {
+		return threads, count, nil
+	}
+
+	query = fmt.Sprintf(`
+		SELECT
+			COUNT(*)
+		FROM
+			forum_threads
+		WHERE
+			%s
+			schedules_id = (%d) AND
+			status = (%d);
+		`, queryRelation, scheduleID, StatusExist)
+	err = conn.DB.Get(&count, query)
+	if err != nil {
+		return threads, count, err
+	}
+	return threads, count, nil
+}
+
+// InsertThread ...
+func InsertThread(title, content string, scheduleID, userID int64, tableName sql.NullString, tableID sql.NullInt64, tx *sqlx.Tx) (int64, error) {
+
+	tblName := "(NULL)"
+	tblID := "(NULL)"
+	if tableName.Valid && tableID.Valid {
+		tblName = fmt.Sprintf("('%s')", tableName.String)
+		tblID = fmt.Sprintf("(%d)", tableID.Int64)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO
+			forum_threads (
+				title,
+				content,
+				schedules_id,
+				users_id,
+				table_name,
+				table_id,
+				is_pinned,
+				is_locked,
+				status,
+				created_at,
+				updated_at
+			) VALUES (
+				('%s'),
+				('%s'),
+				(%d),
+				(%d),
+				%s,
+				%s,
+				(%d),
+				(%d),
+				(%d),
+				NOW(),
+				NOW()
+			);
+		`, title, content, scheduleID, userID, tblName, tblID, StatusUnpinned, StatusUnlocked, StatusExist)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("Error getting inserted ID")
+	}
+	return id, nil
+}
+
+// UpdateThread ...
+func UpdateThread(id int64, title, content string, tx *sqlx.Tx) error {
+
+	query := fmt.Sprintf(`
+		UPDATE
+			forum_threads
+		SET
+			title = ('%s'),
+			content = ('%s'),
+			updated_at = NOW()
+		WHERE
+			id = (%d) AND
+			status = (%d);
+		`, title, content, id, StatusExist)
+
+	return exec(query, tx)
+}
+
+// UpdateThreadPinned ...
+func UpdateThreadPinned(id int64, isPinned int8, tx *sqlx.Tx) error {
+
+	query := fmt.Sprintf(`
+		UPDATE
+			forum_threads
+		SET
+			is_pinned = (%d)
+		WHERE
+			id = (%d) AND
+			status = (%d);
+		`, isPinned, id, StatusExist)
+
+	return exec(query, tx)
+}
+
+// UpdateThreadLocked ...
+func UpdateThreadLocked(id int64, isLocked int8, tx *sqlx.Tx) error {
+
+	query := fmt.Sprintf(`
+		UPDATE
+			forum_threads
+		SET
+			is_locked = (%d)
+		WHERE
+			id = (%d) AND
+			status = (%d);
+		`, isLocked, id, StatusExist)
+
+	return exec(query, tx)
+}
+
+// UpdateThreadAccepted marks the reply as the accepted answer, replyID which is not valid unmarks it
+func UpdateThreadAccepted(id int64, replyID sql.NullInt64, tx *sqlx.Tx) error {
+
+	accepted := "(NULL)"
+	if replyID.Valid {
+		accepted = fmt.Sprintf("(%d)", replyID.Int64)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE
+			forum_threads
+		SET
+			accepted_replies_id = %s
+		WHERE
+			id = (%d) AND
+			status = (%d);
+		`, accepted, id, StatusExist)
+
+	return exec(query, tx)
+}
+
+// DeleteThread ...
+func DeleteThread(id int64, tx *sqlx.Tx) error {
+
+	query := fmt.Sprintf(`
+		UPDATE
+			forum_threads
+		SET
+			status = (%d),
+			updated_at = NOW()
+		WHERE
+			id = (%d) AND
+			status = (%d);
+		`, StatusDeleted, id, StatusExist)
+
+	return exec(query, tx)
+}
+
+// TouchThread updates the thread activity time so the thread goes up in the list
+func TouchThread(id int64, tx *sqlx.Tx) error {
+
+	query := fmt.Sprintf(`
+		UPDATE
+			forum_threads
+		SET
+			updated_at = NOW()
+		WHERE
+			id = (%d);
+		`, id)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetReplyByID ...
+func GetReplyByID(id int64) (Reply, error) {
+	var reply Reply
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			content,
+			forum_threads_id,
+			users_id,
+			created_at,
+			updated_at
+		FROM
+			forum_replies
+		WHERE
+			id = (%d) AND
+			status = (%d)
+		LIMIT 1;
+		`, id, StatusExist)
+
+	err := conn.DB.Get(&reply, query)
+	if err != nil {
+		return reply, err
+	}
+	return reply, nil
+}
+
+// SelectReplyByPage ...
+func SelectReplyByPage(threadID int64, limit, offset int, isCount bool) ([]Reply, int, error) {
+	var replies []Reply
+	var count int
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			content,
+			forum_threads_id,
+			users_id,
+			created_at,
+			updated_at
+		FROM
+			forum_replies
+		WHERE
+			forum_threads_id = (%d) AND
+			status = (%d)
+		ORDER BY created_at ASC
+		LIMIT %d
+		OFFSET %d;
+		`, threadID, StatusExist, limit, offset)
+	err := conn.DB.Select(&replies, query)
+	if err != nil {
+		return replies, count, err
+	}
+
+	if !isCount {
+		return replies, count, nil
+	}
+
+	query = fmt.Sprintf(`
+		SELECT
+			COUNT(*)
+		FROM
+			forum_replies
+		WHERE
+			forum_threads_id = (%d) AND
+			status = (%d);
+		`, threadID, StatusExist)
+	err = conn.DB.Get(&count, query)
+	if err != nil {
+		return replies, count, err
+	}
+	return replies, count, nil
+}
+
+// CountReplyByThreadID ...
+func CountReplyByThreadID(threadsID []int64) (map[int64]int, error) {
+	res := map[int64]int{}
+	if len(threadsID) < 1 {
+		return res, nil
+	}
+
+	var counts []ReplyCount
+	queryThreadID := strings.Join(helper.Int64ToStringSlice(threadsID), ", ")
+	query := fmt.Sprintf(`
+		SELECT
+			forum_threads_id,
+			COUNT(*) AS total
+		FROM
+			forum_replies
+		WHERE
+			forum_threads_id IN (%s) AND
+			status = (%d)
+		GROUP BY
+			forum_threads_id;
+		`, queryThreadID, StatusExist)
+	err := conn.DB.Select(&counts, query)
+	if err != nil {
+		return res, err
+	}
+
+	for _, val := range counts {
+		res[val.ThreadID] = val.Total
+	}
+	return res, nil
+}
+
+// InsertReply ...
+func InsertReply(threadID, userID int64, content string, tx *sqlx.Tx) (int64, error) {
+
+	query := fmt.Sprintf(`
+		INSERT INTO
+			forum_replies (
+				content,
+				forum_threads_id,
+				users_id,
+				status,
+				created_at,
+				updated_at
+			) VALUES (
+				('%s'),
+				(%d),
+				(%d),
+				(%d),
+				NOW(),
+				NOW()
+			);
+		`, content, threadID, userID, StatusExist)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("Error getting inserted ID")
+	}
+	return id, nil
+}
+
+// UpdateReply ...
+func UpdateReply(id int64, content string, tx *sqlx.Tx) error {
+
+	query := fmt.Sprintf(`
+		UPDATE
+			forum_replies
+		SET
+			content = ('%s'),
+			updated_at = NOW()
+		WHERE
+			id = (%d) AND
+			status = (%d);
+		`, content, id, StatusExist)
+
+	return exec(query, tx)
+}
+
+// DeleteReply ...
+func DeleteReply(id int64, tx *sqlx.Tx) error {
+
+	query := fmt.Sprintf(`
+		UPDATE
+			forum_replies
+		SET
+			status = (%d),
+			updated_at = NOW()
+		WHERE
+			id = (%d) AND
+			status = (%d);
+		`, StatusDeleted, id, StatusExist)
+
+	return exec(query, tx)
+}
+
+func exec(query string, tx *sqlx.Tx) error {
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("No rows affected")
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package forum
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	StatusDeleted = 0
+	StatusExist   = 1
+
+	StatusUnpinned = 0
+	StatusPinned   = 1
+
+	StatusUnlocked = 0
+	StatusLocked   = 1
+
+	TableTutorial   = "tutorials"
+	TableAssignment = "assignments"
+	TableMeeting    = "meetings"
+
+	MaxTitle   = 100
+	MaxContent = 10000
+)
+
+// Thread struct ...
+type Thread struct {
+	ID              int64          `db:"id"`
+	Title           string         `db:"title"`
+	Content         string         `db:"content"`
+	ScheduleID      int64          `db:"schedules_id"`
+	UserID          int64          `db:"users_id"`
+	TableName       sql.NullString `db:"table_name"`
+	TableID         sql.NullInt64  `db:"table_id"`
+	AcceptedReplyID sql.NullInt64  `db:"accepted_replies_id"`
+	IsPinned        int8           `db:"is_pinned"`
+	IsLocked        int8           `db:"is_locked"`
+	CreatedAt       time.Time      `db:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at"`
+}
+
+// Reply struct ...
+type Reply struct {
+	ID        int64     `db:"id"`
+	Content   string    `db:"content"`
+	ThreadID  int64     `db:"forum_threads_id"`
+	UserID    int64     `db:"users_id"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// ReplyCount struct ...
+type ReplyCount struct {
+	ThreadID int64 `db:"forum_threads_id"`
+	Total    int   `db:"total"`
+}
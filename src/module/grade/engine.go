@@ -0,0 +1,121 @@
+package grade
+
+import (
+	"math"
+	"sort"
+	"strconv"
+)
+
+// DefaultPolicy is used by schedule which has not set its own policy,
+// it follows the rule used before policy was configurable
+func DefaultPolicy(scheduleID int64) Policy {
+	return Policy{
+		ScheduleID:  scheduleID,
+		Missing:     MissingZero,
+		DropLowest:  0,
+		IsCapped:    StatusUncapped,
+		Rounding:    RoundHalfUp,
+		Decimals:    DefaultDecimals,
+		RegradeDays: DefaultRegradeDays,
+	}
+}
+
+// AttendanceScore converts the attendance of a user into a percentage score
+func AttendanceScore(attended, meetings int) Score {
+	if meetings < 1 {
+		return Score{}
+	}
+	return Score{
+		Value:       float64(attended) / float64(meetings) * 100,
+		IsSubmitted: true,
+		IsGraded:    true,
+	}
+}
+
+// Final returns the value after the late penalty is deducted
+func (s Score) Final() float64 {
+	return s.Value * (100 - s.Penalty) / 100
+}
+
+// Calculate computes the score of every parameter and the weighted total, values are rounded using the policy
+func (p Policy) Calculate(params []Parameter) Result {
+	res := Result{
+		Parameters: map[int64]float64{},
+	}
+
+	total := float64(0)
+	for _, param := range params {
+		avg := p.Average(param.Scores)
+		res.Parameters[param.ID] = p.Round(avg)
+		total += avg * param.Percentage / 100
+	}
+
+	res.Total = p.Round(p.cap(total))
+	return res
+}
+
+// Average returns the average of the scores after missing and lowest scores are handled
+func (p Policy) Average(scores []Score) float64 {
+	var values []float64
+	for _, val := range scores {
+		if !val.IsSubmitted {
+			if p.Missing == MissingZero {
+				values = append(values, 0)
+			}
+			continue
+		}
+		if !val.IsGraded {
+			continue
+		}
+		values = append(values, val.Final())
+	}
+
+	if len(values) < 1 {
+		return 0
+	}
+
+	// at least one score is kept
+	drop := int(p.DropLowest)
+	if drop > len(values)-1 {
+		drop = len(values) - 1
+	}
+	if drop > 0 {
+		sort.Float64s(values)
+		values = values[drop:]
+	}
+
+	sum := float64(0)
+	for _, val := range values {
+		sum += val
+	}
+	return p.cap(sum / float64(len(values)))
+}
+
+// Round rounds the value into the policy decimals using the policy rounding mode
+func (p Policy) Round(value float64) float64 {
+	factor := math.Pow(10, float64(p.Decimals))
+
+	// remove floating point noise so 84.995 is not stored as 84.99499999
+	scaled := math.Round(value*factor*1e6) / 1e6
+	switch p.Rounding {
+	case RoundDown:
+		scaled = math.Floor(scaled)
+	case RoundUp:
+		scaled = math.Ceil(scaled)
+	default:
+		scaled = math.Floor(scaled + 0.5)
+	}
+	return scaled / factor
+}
+
+// Format rounds the value and formats it with the policy decimals
+func (p Policy) Format(value float64) string {
+	return strconv.FormatFloat(p.Round(value), 'f', int(p.Decimals), 64)
+}
+
+func (p Policy) cap(value float64) float64 {
+	if p.IsCapped == StatusCapped && value > MaxScore {
+		return MaxScore
+	}
+	return value
+}
@@ -0,0 +1,162 @@
+package grade
+
+import (
+	"math"
+	"testing"
+)
+
+func graded(values ...float64) []Score {
+	var scores []Score
+	for _, val := range values {
+		scores = append(scores, Score{Value: val, IsSubmitted: true, IsGraded: true})
+	}
+	return scores
+}
+
+func TestPolicy_Average(t *testing.T) {
+	missing := Score{}
+	ungraded := Score{IsSubmitted: true}
+
+	cases := []struct {
+		name     string
+		policy   Policy
+		scores   []Score
+		expected float64
+	}{
+		{
+			name:     "empty",
+			policy:   DefaultPolicy(1),
+			scores:   nil,
+			expected: 0,
+		},
+		{
+			name:     "missing counted as zero",
+			policy:   DefaultPolicy(1),
+			scores:   append(graded(80, 90), missing),
+			expected: 170.0 / 3,
+		},
+		{
+			name:     "missing excluded",
+			policy:   Policy{Missing: MissingExcluded},
+			scores:   append(graded(80, 90), missing),
+			expected: 85,
+		},
+		{
+			name:     "ungraded is always excluded",
+			policy:   DefaultPolicy(1),
+			scores:   append(graded(80, 90), ungraded),
+			expected: 85,
+		},
+		{
+			name:     "drop lowest",
+			policy:   Policy{DropLowest: 2},
+			scores:   graded(50, 100, 70, 90),
+			expected: 95,
+		},
+		{
+			name:     "drop lowest counts missing as zero",
+			policy:   Policy{DropLowest: 1},
+			scores:   append(graded(60, 80), missing),
+			expected: 70,
+		},
+		{
+			name:     "drop lowest keeps at least one score",
+			policy:   Policy{DropLowest: 5},
+			scores:   graded(60, 80),
+			expected: 80,
+		},
+		{
+			name:     "late penalty",
+			policy:   DefaultPolicy(1),
+			scores:   append(graded(80), Score{Value: 80, Penalty: 25, IsSubmitted: true, IsGraded: true}),
+			expected: 70,
+		},
+		{
+			name:     "late penalty applied before drop lowest",
+			policy:   Policy{DropLowest: 1},
+			scores:   append(graded(70), Score{Value: 90, Penalty: 50, IsSubmitted: true, IsGraded: true}),
+			expected: 70,
+		},
+		{
+			name:     "capped",
+			policy:   Policy{IsCapped: StatusCapped},
+			scores:   graded(110, 120),
+			expected: 100,
+		},
+		{
+			name:     "uncapped",
+			policy:   DefaultPolicy(1),
+			scores:   graded(110, 120),
+			expected: 115,
+		},
+	}
+
+	for _, c := range cases {
+		result := c.policy.Average(c.scores)
+		if math.Abs(result-c.expected) > 1e-9 {
+			t.Errorf("%s: Average() expected %v, got %v", c.name, c.expected, result)
+		}
+	}
+}
+
+func TestPolicy_Round(t *testing.T) {
+	cases := []struct {
+		policy   Policy
+		value    float64
+		expected string
+	}{
+		{policy: Policy{Rounding: RoundHalfUp, Decimals: 2}, value: 84.995, expected: "85.00"},
+		{policy: Policy{Rounding: RoundHalfUp, Decimals: 2}, value: 84.994, expected: "84.99"},
+		{policy: Policy{Rounding: RoundDown, Decimals: 1}, value: 84.99, expected: "84.9"},
+		{policy: Policy{Rounding: RoundUp, Decimals: 1}, value: 84.91, expected: "85.0"},
+		{policy: Policy{Rounding: RoundUp, Decimals: 2}, value: 84.1, expected: "84.10"},
+		{policy: Policy{Rounding: RoundHalfUp, Decimals: 0}, value: 84.5, expected: "85"},
+		{policy: Policy{Rounding: RoundDown, Decimals: 0}, value: 84.5, expected: "84"},
+	}
+
+	for _, c := range cases {
+		result := c.policy.Format(c.value)
+		if result != c.expected {
+			t.Errorf("Format(%v) with rounding %d expected %s, got %s", c.value, c.policy.Rounding, c.expected, result)
+		}
+	}
+}
+
+func TestPolicy_Calculate(t *testing.T) {
+	params := []Parameter{
+		{ID: 1, Type: "ATTENDANCE", Percentage: 10, Scores: []Score{AttendanceScore(7, 14)}},
+		{ID: 2, Type: "ASSIGNMENT", Percentage: 30, Scores: append(graded(80, 100), Score{})},
+		{ID: 3, Type: "MID", Percentage: 30, Scores: graded(70)},
+		{ID: 4, Type: "FINAL", Percentage: 30, Scores: []Score{{IsSubmitted: true}}},
+	}
+
+	result := DefaultPolicy(1).Calculate(params)
+	expected := map[int64]float64{1: 50, 2: 60, 3: 70, 4: 0}
+	for id, val := range expected {
+		if result.Parameters[id] != val {
+			t.Errorf("Calculate() parameter %d expected %v, got %v", id, val, result.Parameters[id])
+		}
+	}
+	// 5 + 18 + 21 + 0
+	if result.Total != 44 {
+		t.Errorf("Calculate() total expected 44, got %v", result.Total)
+	}
+
+	result = Policy{Missing: MissingExcluded, Decimals: 2}.Calculate(params)
+	// 5 + 27 + 21 + 0
+	if result.Total != 53 {
+		t.Errorf("Calculate() total with missing excluded expected 53, got %v", result.Total)
+	}
+}
+
+func TestAttendanceScore(t *testing.T) {
+	score := AttendanceScore(0, 0)
+	if score.IsGraded {
+		t.Errorf("AttendanceScore() without meeting should not be graded")
+	}
+
+	score = AttendanceScore(3, 4)
+	if !score.IsGraded || score.Value != 75 {
+		t.Errorf("AttendanceScore(3, 4) expected 75, got %v", score.Value)
+	}
+}
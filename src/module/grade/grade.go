@@ -0,0 +1,468 @@
+package grade
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/jmoiron/sqlx"
+)
+
+// GetPolicy returns the policy of the schedule, default policy is returned when it has not been set
+func GetPolicy(scheduleID int64) (Policy, error) {
+	var policy Policy
+	query := fmt.Sprintf(`
+		SELECT
+			schedules_id,
+			missing,
+			drop_lowest,
+			is_capped,
+			rounding,
+			decimals,
+			regrade_days,
+			created_at,
+			updated_at
+		FROM
+			grade_policies
+		WHERE
+			schedules_id = (%d)
+		LIMIT 1;`, scheduleID)
+	err := conn.DB.Get(&policy, query)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return DefaultPolicy(scheduleID), nil
+		}
+		return policy, err
+	}
+	return policy, nil
+}
+
+// SelectPolicy returns the policy of every schedule keyed by schedule id, filled with default policy when it has not been set
+func SelectPolicy(schedulesID []int64) (map[int64]Policy, error) {
+	policies := map[int64]Policy{}
+	if len(schedulesID) < 1 {
+		return policies, nil
+	}
+
+	var rows []Policy
+	query := fmt.Sprintf(`
+		SELECT
+			schedules_id,
+			missing,
+			drop_lowest,
+			is_capped,
+			rounding,
+			decimals,
+			regrade_days,
+			created_at,
+			updated_at
+		FROM
+			grade_policies
+		WHERE
+			schedules_id IN (%s);`, strings.Join(helper.Int64ToStringSlice(schedulesID), ", "))
+	err := conn.DB.Select(&rows, query)
+	if err != nil && err != sql.ErrNoRows {
+		return policies, err
+	}
+
+	for _, val := range schedulesID {
+		policies[val] = DefaultPolicy(val)
+	}
+	for _, val := range rows {
+		policies[val.ScheduleID] = val
+	}
+	return policies, nil
+}
+
+// UpsertPolicy creates or replaces the policy of the schedule
+func UpsertPolicy(policy Policy, tx *sqlx.Tx) error {
+	query := fmt.Sprintf(`
+		INSERT INTO
+			grade_policies (
+				schedules_id,
+				missing,
+				drop_lowest,
+				is_capped,
+				rounding,
+				decimals,
+				regrade_days,
+				created_at,
+				updated_at
+			) VALUES (
+				(%d),
+				(%d),
+				(%d),
+				(%d),
+				(%d),
+				(%d),
+				(%d),
+				NOW(),
+				NOW()
+			)
+		ON DUPLICATE KEY UPDATE
+			missing = VALUES(missing),
+			drop_lowest = VALUES(drop_lowest),
+			is_capped = VALUES(is_capped),
+			rounding = VALUES(rounding),
+			decimals = VALUES(decimals),
+			regrade_days = VALUES(regrade_days),
+			updated_at = NOW();
+		`, policy.ScheduleID, policy.Missing, policy.DropLowest, policy.IsCapped, policy.Rounding, policy.Decimals, policy.RegradeDays)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SelectScale returns the letter grade scale of the schedule from the highest letter,
+// default scale is returned when it has not been set
+func SelectScale(scheduleID int64) ([]Scale, error) {
+	var scales []Scale
+	query := fmt.Sprintf(`
+		SELECT
+			schedules_id,
+			letter,
+			min_score,
+			grade_point,
+			created_at,
+			updated_at
+		FROM
+			grade_scales
+		WHERE
+			schedules_id = (%d)
+		ORDER BY
+			min_score DESC;`, scheduleID)
+	err := conn.DB.Select(&scales, query)
+	if err != nil && err != sql.ErrNoRows {
+		return scales, err
+	}
+
+	if len(scales) < 1 {
+		return DefaultScales(scheduleID), nil
+	}
+	return scales, nil
+}
+
+// ReplaceScale deletes the old letter grade scale of the schedule and inserts the new one
+func ReplaceScale(scheduleID int64, scales []Scale, tx *sqlx.Tx) error {
+	queries := []string{fmt.Sprintf(`
+		DELETE FROM
+			grade_scales
+		WHERE
+			schedules_id = (%d);`, scheduleID)}
+
+	var values []string
+	for _, val := range scales {
+		values = append(values, fmt.Sprintf(`((%d), ('%s'), (%g), (%g), NOW(), NOW())`,
+			scheduleID, val.Letter, val.MinScore, val.GradePoint))
+	}
+	if len(values) > 0 {
+		queries = append(queries, fmt.Sprintf(`
+			INSERT INTO
+				grade_scales (
+					schedules_id,
+					letter,
+					min_score,
+					grade_point,
+					created_at,
+					updated_at
+				) VALUES %s;`, strings.Join(values, ", ")))
+	}
+
+	for _, query := range queries {
+		var err error
+		if tx != nil {
+			_, err = tx.Exec(query)
+		} else {
+			_, err = conn.DB.Exec(query)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetPublication returns the latest publication of the schedule, nil is returned when it has never been published
+func GetPublication(scheduleID int64) (*Publication, error) {
+	var publication Publication
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			schedules_id,
+			status,
+			published_by,
+			unpublished_by,
+			reason,
+			created_at,
+			updated_at
+		FROM
+			grade_publications
+		WHERE
+			schedules_id = (%d)
+		ORDER BY
+			id DESC
+		LIMIT 1;`, scheduleID)
+	err := conn.DB.Get(&publication, query)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &publication, nil
+}
+
+// SelectPublishedPublication returns the active publication of every published schedule keyed by schedule id
+func SelectPublishedPublication(schedulesID []int64) (map[int64]Publication, error) {
+	publications := map[int64]Publication{}
+	if len(schedulesID) < 1 {
+		return publications, nil
+	}
+
+	var rows []Publication
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			schedules_id,
+			status,
+			published_by,
+			unpublished_by,
+			reason,
+			created_at,
+			updated_at
+		FROM
+			grade_publications
+		WHERE
+			schedules_id IN (%s) AND
+			status = (%d);`, strings.Join(helper.Int64ToStringSlice(schedulesID), ", "), StatusPublished)
+	err := conn.DB.Select(&rows, query)
+	if err != nil && err != sql.ErrNoRows {
+		return publications, err
+	}
+
+	for _, val := range rows {
+		publications[val.ScheduleID] = val
+	}
+	return publications, nil
+}
+
+// SelectRegradeEndedSchedule returns every published schedule whose regrade period after the publication has ended
+func SelectRegradeEndedSchedule() ([]int64, error) {
+	var schedulesID []int64
+	query := fmt.Sprintf(`
+		SELECT
+			p.schedules_id
+		FROM
+			grade_publications p
+		LEFT JOIN
+			grade_policies gp
+		ON
+			gp.schedules_id = p.schedules_id
+		WHERE
+			p.status = (%d) AND
+			DATE_ADD(p.created_at, INTERVAL COALESCE(gp.regrade_days, %d) DAY) <= NOW();`, StatusPublished, DefaultRegradeDays)
+	err := conn.DB.Select(&schedulesID, query)
+	if err != nil && err != sql.ErrNoRows {
+		return schedulesID, err
+	}
+	return schedulesID, nil
+}
+
+// InsertPublication publishes final grade of the schedule
+func InsertPublication(scheduleID, userID int64, tx *sqlx.Tx) (int64, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO
+			grade_publications (
+				schedules_id,
+				status,
+				published_by,
+				created_at,
+				updated_at
+			) VALUES (
+				(%d),
+				(%d),
+				(%d),
+				NOW(),
+				NOW()
+			);`, scheduleID, StatusPublished, userID)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// UnpublishPublication marks the publication as unpublished with the reason
+func UnpublishPublication(id, userID int64, reason string, tx *sqlx.Tx) error {
+	query := fmt.Sprintf(`
+		UPDATE
+			grade_publications
+		SET
+			status = (%d),
+			unpublished_by = (%d),
+			reason = ('%s'),
+			updated_at = NOW()
+		WHERE
+			id = (%d) AND
+			status = (%d);`, StatusUnpublished, userID, reason, id, StatusPublished)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("No rows affected")
+	}
+	return nil
+}
+
+// InsertSnapshot stores the final grade of every user on the publication including the parameter scores
+func InsertSnapshot(snapshots []Snapshot, tx *sqlx.Tx) error {
+	if len(snapshots) < 1 {
+		return nil
+	}
+
+	var values, parameters []string
+	for _, val := range snapshots {
+		values = append(values, fmt.Sprintf(`((%d), (%d), (%g), ('%s'), (%g), NOW())`,
+			val.PublicationID, val.UserID, val.Total, val.Letter, val.GradePoint))
+		for _, param := range val.Parameters {
+			parameters = append(parameters, fmt.Sprintf(`((%d), (%d), (%d), ('%s'), (%g))`,
+				val.PublicationID, val.UserID, param.GradeParameterID, param.Type, param.Score))
+		}
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO
+			grade_snapshots (
+				grade_publications_id,
+				users_id,
+				total,
+				letter,
+				grade_point,
+				created_at
+			) VALUES %s;`, strings.Join(values, ", "))
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	if len(parameters) < 1 {
+		return nil
+	}
+
+	query = fmt.Sprintf(`
+		INSERT INTO
+			grade_snapshot_parameters (
+				grade_publications_id,
+				users_id,
+				grade_parameters_id,
+				type,
+				score
+			) VALUES %s;`, strings.Join(parameters, ", "))
+
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SelectSnapshot returns the snapshots of the publications, filtered by user when userID is not nil
+func SelectSnapshot(publicationsID []int64, userID *int64) ([]Snapshot, error) {
+	var snapshots []Snapshot
+	if len(publicationsID) < 1 {
+		return snapshots, nil
+	}
+
+	var queryUser string
+	if userID != nil {
+		queryUser = fmt.Sprintf("AND users_id = (%d)", *userID)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			grade_publications_id,
+			users_id,
+			total,
+			letter,
+			grade_point,
+			created_at
+		FROM
+			grade_snapshots
+		WHERE
+			grade_publications_id IN (%s)
+			%s;`, strings.Join(helper.Int64ToStringSlice(publicationsID), ", "), queryUser)
+	err := conn.DB.Select(&snapshots, query)
+	if err != nil && err != sql.ErrNoRows {
+		return snapshots, err
+	}
+	if len(snapshots) < 1 {
+		return snapshots, nil
+	}
+
+	var parameters []SnapshotParameter
+	query = fmt.Sprintf(`
+		SELECT
+			grade_publications_id,
+			users_id,
+			grade_parameters_id,
+			type,
+			score
+		FROM
+			grade_snapshot_parameters
+		WHERE
+			grade_publications_id IN (%s)
+			%s;`, strings.Join(helper.Int64ToStringSlice(publicationsID), ", "), queryUser)
+	err = conn.DB.Select(&parameters, query)
+	if err != nil && err != sql.ErrNoRows {
+		return snapshots, err
+	}
+
+	// publication id => user id => parameters
+	snapshotParameters := map[int64]map[int64][]SnapshotParameter{}
+	for _, val := range parameters {
+		if _, ok := snapshotParameters[val.PublicationID]; !ok {
+			snapshotParameters[val.PublicationID] = map[int64][]SnapshotParameter{}
+		}
+		snapshotParameters[val.PublicationID][val.UserID] = append(snapshotParameters[val.PublicationID][val.UserID], val)
+	}
+	for i, val := range snapshots {
+		snapshots[i].Parameters = snapshotParameters[val.PublicationID][val.UserID]
+	}
+	return snapshots, nil
+}
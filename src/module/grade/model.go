@@ -0,0 +1,117 @@
+package grade
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	// MissingZero counts assignment which is not submitted as zero
+	MissingZero = 0
+	// MissingExcluded drops assignment which is not submitted from the average
+	MissingExcluded = 1
+
+	StatusUncapped = 0
+	StatusCapped   = 1
+
+	RoundHalfUp = 0
+	RoundDown   = 1
+	RoundUp     = 2
+
+	MaxScore      = 100
+	MaxDropLowest = 10
+	MaxDecimals   = 4
+
+	DefaultDecimals = 2
+
+	DefaultRegradeDays = 7
+	MaxRegradeDays     = 365
+
+	StatusUnpublished = 0
+	StatusPublished   = 1
+
+	MaxReason = 1000
+)
+
+// Letters is the list of letter grade from the highest
+var Letters = []string{"A", "AB", "B", "BC", "C", "D", "E"}
+
+// Policy is the grade computation rule of a schedule
+type Policy struct {
+	ScheduleID int64 `db:"schedules_id"`
+	Missing    int8  `db:"missing"`
+	DropLowest int8  `db:"drop_lowest"`
+	IsCapped   int8  `db:"is_capped"`
+	Rounding   int8  `db:"rounding"`
+	Decimals   int8  `db:"decimals"`
+	// RegradeDays is how long regrade requests stay open after the final grade is published
+	RegradeDays int16     `db:"regrade_days"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// Score is the score of a single assessment, ungraded submission is always left out of the average.
+// Penalty is the percentage deducted from the value for late submission
+type Score struct {
+	Value       float64
+	Penalty     float64
+	IsSubmitted bool
+	IsGraded    bool
+}
+
+// Parameter is a weighted grade parameter with the score of every assessment on it
+type Parameter struct {
+	ID         int64
+	Type       string
+	Percentage float64
+	Scores     []Score
+}
+
+// Result is the computed grade, parameter score is keyed by parameter id
+type Result struct {
+	Parameters map[int64]float64
+	Total      float64
+}
+
+// Scale is the minimum total score to get the letter grade on a schedule
+type Scale struct {
+	ScheduleID int64     `db:"schedules_id"`
+	Letter     string    `db:"letter"`
+	MinScore   float64   `db:"min_score"`
+	GradePoint float64   `db:"grade_point"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+// Publication is the final grade publication of a schedule, the latest one is the current state
+type Publication struct {
+	ID            int64          `db:"id"`
+	ScheduleID    int64          `db:"schedules_id"`
+	Status        int8           `db:"status"`
+	PublishedBy   int64          `db:"published_by"`
+	UnpublishedBy sql.NullInt64  `db:"unpublished_by"`
+	Reason        sql.NullString `db:"reason"`
+	CreatedAt     time.Time      `db:"created_at"`
+	UpdatedAt     time.Time      `db:"updated_at"`
+}
+
+// Snapshot is the frozen final grade of a user when it is published
+type Snapshot struct {
+	PublicationID int64     `db:"grade_publications_id"`
+	UserID        int64     `db:"users_id"`
+	Total         float64   `db:"total"`
+	Letter        string    `db:"letter"`
+	GradePoint    float64   `db:"grade_point"`
+	CreatedAt     time.Time `db:"created_at"`
+	// Parameters is the weighted score of every grade parameter the total is made of
+	Parameters []SnapshotParameter `db:"-"`
+}
+
+// SnapshotParameter is the frozen weighted score of a grade parameter of a user
+type SnapshotParameter struct {
+	PublicationID    int64   `db:"grade_publications_id"`
+	UserID           int64   `db:"users_id"`
+	GradeParameterID int64   `db:"grade_parameters_id"`
+	Type             string  `db:"type"`
+	Score            float64 `db:"score"`
+}
@@ -0,0 +1,42 @@
+package grade
+
+import (
+	asg "github.com/asepnur/meiko_course/src/module/assignment"
+	att "github.com/asepnur/meiko_course/src/module/attendance"
+	cs "github.com/asepnur/meiko_course/src/module/course"
+)
+
+// Parameters converts grade parameters of a schedule into grade engine input,
+// assignment which does not require upload is never treated as missing, it is only waiting to be scored
+func Parameters(gps []cs.GradeParameter, gpAsg map[int64][]asg.Assignment, asgSubmit map[int64]asg.UserAssignment, attendance att.AttendanceReport) []Parameter {
+	params := []Parameter{}
+	for _, gp := range gps {
+		param := Parameter{
+			ID:         gp.ID,
+			Type:       gp.Type,
+			Percentage: float64(gp.Percentage),
+		}
+		if gp.Type == cs.GradeParameterAttendance {
+			param.Scores = []Score{AttendanceScore(attendance.AttendanceTotal, attendance.MeetingTotal)}
+			params = append(params, param)
+			continue
+		}
+		for _, assignment := range gpAsg[gp.ID] {
+			submit, exist := asgSubmit[assignment.ID]
+			param.Scores = append(param.Scores, SubmissionScore(assignment, submit, exist))
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+// SubmissionScore converts the submission of an assignment into grade engine input,
+// exist is false when the user has not submitted the assignment
+func SubmissionScore(assignment asg.Assignment, submit asg.UserAssignment, exist bool) Score {
+	return Score{
+		Value:       submit.Score.Float64,
+		Penalty:     assignment.Penalty(submit.LateSeconds),
+		IsSubmitted: exist || assignment.Status == asg.StatusUploadNotRequired,
+		IsGraded:    exist && submit.Score.Valid,
+	}
+}
@@ -0,0 +1,83 @@
+package grade
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DefaultScales is used by schedule which has not set its own letter grade scale
+func DefaultScales(scheduleID int64) []Scale {
+	minScores := []float64{85, 80, 70, 65, 55, 40, 0}
+	gradePoints := []float64{4, 3.5, 3, 2.5, 2, 1, 0}
+
+	scales := []Scale{}
+	for i, letter := range Letters {
+		scales = append(scales, Scale{
+			ScheduleID: scheduleID,
+			Letter:     letter,
+			MinScore:   minScores[i],
+			GradePoint: gradePoints[i],
+		})
+	}
+	return scales
+}
+
+// ValidateScales checks that every letter exists once and a higher letter needs a higher score and grade point,
+// the lowest letter must start from zero so every total gets a letter
+func ValidateScales(scales []Scale) error {
+	if len(scales) != len(Letters) {
+		return fmt.Errorf("Scale must contain %d letters", len(Letters))
+	}
+
+	byLetter := map[string]Scale{}
+	for _, val := range scales {
+		if _, ok := byLetter[val.Letter]; ok {
+			return fmt.Errorf("Letter %s is duplicated", val.Letter)
+		}
+		byLetter[val.Letter] = val
+	}
+
+	for i, letter := range Letters {
+		scale, ok := byLetter[letter]
+		if !ok {
+			return fmt.Errorf("Letter %s does not exist", letter)
+		}
+		if scale.MinScore < 0 || scale.MinScore > MaxScore {
+			return fmt.Errorf("Minimum score of %s must be between 0 and %d", letter, MaxScore)
+		}
+		if scale.GradePoint < 0 || scale.GradePoint > 4 {
+			return fmt.Errorf("Grade point of %s must be between 0 and 4", letter)
+		}
+		if i == 0 {
+			continue
+		}
+		higher := byLetter[Letters[i-1]]
+		if scale.MinScore >= higher.MinScore {
+			return fmt.Errorf("Minimum score of %s must be lower than %s", letter, higher.Letter)
+		}
+		if scale.GradePoint > higher.GradePoint {
+			return fmt.Errorf("Grade point of %s must not be higher than %s", letter, higher.Letter)
+		}
+	}
+
+	if byLetter[Letters[len(Letters)-1]].MinScore != 0 {
+		return fmt.Errorf("Minimum score of %s must be 0", Letters[len(Letters)-1])
+	}
+	return nil
+}
+
+// Letter returns the scale which the total falls into
+func Letter(scales []Scale, total float64) (Scale, bool) {
+	sorted := make([]Scale, len(scales))
+	copy(sorted, scales)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].MinScore > sorted[j].MinScore
+	})
+
+	for _, val := range sorted {
+		if total >= val.MinScore {
+			return val, true
+		}
+	}
+	return Scale{}, false
+}
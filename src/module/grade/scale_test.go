@@ -0,0 +1,60 @@
+package grade
+
+import "testing"
+
+func TestValidateScales(t *testing.T) {
+	if err := ValidateScales(DefaultScales(1)); err != nil {
+		t.Errorf("ValidateScales() default scale got error %s", err.Error())
+	}
+
+	cases := []struct {
+		name   string
+		modify func([]Scale) []Scale
+	}{
+		{name: "missing letter", modify: func(s []Scale) []Scale { return s[1:] }},
+		{name: "duplicate letter", modify: func(s []Scale) []Scale { s[1].Letter = "A"; return s }},
+		{name: "unknown letter", modify: func(s []Scale) []Scale { s[1].Letter = "F"; return s }},
+		{name: "not descending score", modify: func(s []Scale) []Scale { s[1].MinScore = 90; return s }},
+		{name: "not descending point", modify: func(s []Scale) []Scale { s[1].GradePoint = 4.5; return s }},
+		{name: "score over max", modify: func(s []Scale) []Scale { s[0].MinScore = 101; return s }},
+		{name: "lowest is not zero", modify: func(s []Scale) []Scale { s[6].MinScore = 10; return s }},
+	}
+
+	for _, c := range cases {
+		if err := ValidateScales(c.modify(DefaultScales(1))); err == nil {
+			t.Errorf("%s: ValidateScales() expected error", c.name)
+		}
+	}
+}
+
+func TestLetter(t *testing.T) {
+	scales := DefaultScales(1)
+	// unordered scale should give the same result
+	scales[0], scales[6] = scales[6], scales[0]
+
+	cases := []struct {
+		total      float64
+		letter     string
+		gradePoint float64
+	}{
+		{total: 100, letter: "A", gradePoint: 4},
+		{total: 85, letter: "A", gradePoint: 4},
+		{total: 84.99, letter: "AB", gradePoint: 3.5},
+		{total: 70, letter: "B", gradePoint: 3},
+		{total: 65, letter: "BC", gradePoint: 2.5},
+		{total: 55, letter: "C", gradePoint: 2},
+		{total: 40, letter: "D", gradePoint: 1},
+		{total: 0, letter: "E", gradePoint: 0},
+	}
+
+	for _, c := range cases {
+		scale, ok := Letter(scales, c.total)
+		if !ok || scale.Letter != c.letter || scale.GradePoint != c.gradePoint {
+			t.Errorf("Letter(%v) expected %s (%v), got %s (%v)", c.total, c.letter, c.gradePoint, scale.Letter, scale.GradePoint)
+		}
+	}
+
+	if _, ok := Letter(nil, 50); ok {
+		t.Errorf("Letter() without scale expected not found")
+	}
+}
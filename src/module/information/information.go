@@ -0,0 +1,294 @@
+package information
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/asepnur/meiko_course/src/util/publish"
+)
+
+// GetByID ...
+func GetByID(id int64) (Information, error) {
+	var information Information
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			title,
+			description,
+			schedules_id,
+			status,
+			is_pinned,
+			created_by,
+			publish_at,
+			unpublish_at,
+			created_at,
+			updated_at
+		FROM
+			informations
+		WHERE
+			id = (%d)
+		LIMIT 1;
+		`, id)
+
+	err := conn.DB.Get(&information, query)
+	if err != nil {
+		return information, err
+	}
+	return information, nil
+}
+
+// SelectByPage is used for listing informations of one schedule, or global informations when scheduleID is not valid
+func SelectByPage(scheduleID sql.NullInt64, limit, offset int, isCount bool) ([]Information, int, error) {
+	var informations []Information
+	var count int
+
+	querySchedule := "schedules_id IS NULL"
+	if scheduleID.Valid {
+		querySchedule = fmt.Sprintf("schedules_id = (%d)", scheduleID.Int64)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			title,
+			description,
+			schedules_id,
+			status,
+			is_pinned,
+			created_by,
+			publish_at,
+			unpublish_at,
+			created_at,
+			updated_at
+		FROM
+			informations
+		WHERE
+			%s
+		ORDER BY is_pinned DESC, created_at DESC
+		LIMIT %d
+		OFFSET %d;
+		`, querySchedule, limit, offset)
+	err := conn.DB.Select(&informations, query)
+	if err != nil {
+		return informations, count, err
+	}
+
+	if !isCount {
+		return informations, count, nil
+	}
+
+	query = fmt.Sprintf(`
+		SELECT
+			COUNT(*)
+		FROM
+			informations
+		WHERE
+			%s;
+		`, querySchedule)
+	err = conn.DB.Get(&count, query)
+	if err != nil {
+		return informations, count, err
+	}
+	return informations, count, nil
+}
+
+// SelectBySchedule is used for listing informations of the schedules including the global informations,
+// informations which are not published are excluded
+func SelectBySchedule(schedulesID []int64, limit, offset int, isCount bool) ([]Information, int, error) {
+	var informations []Information
+	var count int
+
+	querySchedule := "schedules_id IS NULL"
+	if len(schedulesID) > 0 {
+		querySchID := strings.Join(helper.Int64ToStringSlice(schedulesID), ", ")
+		querySchedule = fmt.Sprintf("(schedules_id IS NULL OR schedules_id IN (%s))", querySchID)
+	}
+	querySchedule = fmt.Sprintf("%s %s", querySchedule, publish.QueryPublished)
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			title,
+			description,
+			schedules_id,
+			status,
+			is_pinned,
+			created_by,
+			publish_at,
+			unpublish_at,
+			created_at,
+			updated_at
+		FROM
+			informations
+		WHERE
+			%s
+		ORDER BY is_pinned DESC, created_at DESC
+		LIMIT %d
+		OFFSET %d;
+		`, querySchedule, limit, offset)
+	err := conn.DB.Select(&informations, query)
+	if err != nil {
+		return informations, count, err
+	}
+
+	if !isCount {
+		return informations, count, nil
+	}
+
+	query = fmt.Sprintf(`
+		SELECT
+			COUNT(*)
+		FROM
+			informations
+		WHERE
+			%s;
+		`, querySchedule)
+	err = conn.DB.Get(&count, query)
+	if err != nil {
+		return informations, count, err
+	}
+	return informations, count, nil
+}
+
+// Insert ...
+func Insert(title string, description sql.NullString, scheduleID sql.NullInt64, status int8, userID int64, tx *sqlx.Tx) (int64, error) {
+
+	desc := "(NULL)"
+	if description.Valid {
+		desc = fmt.Sprintf("('%s')", description.String)
+	}
+
+	schID := "(NULL)"
+	if scheduleID.Valid {
+		schID = fmt.Sprintf("(%d)", scheduleID.Int64)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO
+			informations (
+				title,
+				description,
+				schedules_id,
+				status,
+				is_pinned,
+				created_by,
+				created_at,
+				updated_at
+			) VALUES (
+				('%s'),
+				%s,
+				%s,
+				(%d),
+				(%d),
+				(%d),
+				NOW(),
+				NOW()
+			);
+		`, title, desc, schID, status, StatusUnpinned, userID)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("Error getting inserted ID")
+	}
+	return id, nil
+}
+
+// Update ...
+func Update(id int64, title string, description sql.NullString, status int8, tx *sqlx.Tx) error {
+
+	desc := "(NULL)"
+	if description.Valid {
+		desc = fmt.Sprintf("('%s')", description.String)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE
+			informations
+		SET
+			title = ('%s'),
+			description = %s,
+			status = (%d),
+			updated_at = NOW()
+		WHERE
+			id = (%d);
+		`, title, desc, status, id)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdatePinned is used for pin or unpin the information
+func UpdatePinned(id int64, isPinned int8, tx *sqlx.Tx) error {
+
+	query := fmt.Sprintf(`
+		UPDATE
+			informations
+		SET
+			is_pinned = (%d),
+			updated_at = NOW()
+		WHERE
+			id = (%d);
+		`, isPinned, id)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete ...
+func Delete(id int64, tx *sqlx.Tx) error {
+
+	query := fmt.Sprintf(`
+		DELETE FROM
+			informations
+		WHERE
+			id = (%d);
+		`, id)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("No rows affected")
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package information
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	StatusUnpinned = 0
+	StatusPinned   = 1
+
+	MaxTitle = 45
+	MaxDesc  = 5000
+)
+
+// Information struct ...
+type Information struct {
+	ID          int64          `db:"id"`
+	Title       string         `db:"title"`
+	Description sql.NullString `db:"description"`
+	ScheduleID  sql.NullInt64  `db:"schedules_id"`
+	Status      int8           `db:"status"`
+	IsPinned    int8           `db:"is_pinned"`
+	CreatedBy   int64          `db:"created_by"`
+	PublishAt   mysql.NullTime `db:"publish_at"`
+	UnpublishAt mysql.NullTime `db:"unpublish_at"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+}
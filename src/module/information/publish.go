@@ -0,0 +1,21 @@
+package information
+
+import (
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/asepnur/meiko_course/src/util/publish"
+)
+
+// IsPublished returns true when the information is visible to students at now, now is the database time
+// from publish.Now. An information without publish date is published since it is created
+func (i Information) IsPublished(now time.Time) bool {
+	return publish.IsPublished(i.PublishAt, i.UnpublishAt, now)
+}
+
+// UpdatePublish sets the publish window of the information
+func UpdatePublish(id int64, publishAt, unpublishAt mysql.NullTime, tx *sqlx.Tx) error {
+	return publish.Update("informations", id, publishAt, unpublishAt, tx)
+}
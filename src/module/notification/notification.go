@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/jmoiron/sqlx"
 )
 
 func Get(userID int64, page uint16, limit uint8) ([]Notification, error) {
@@ -21,6 +22,22 @@ func Get(userID int64, page uint16, limit uint8) ([]Notification, error) {
 	return notifications, nil
 }
 
+// Insert sends a notification to the user about the row of the table
+func Insert(userID int64, name, description, tableName, tableID string, tx *sqlx.Tx) error {
+	query := fmt.Sprintf(queryInsert, name, description, tableID, tableName, userID)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 func (n Notification) GetURL() string {
 	return "http://URL.com"
 }
@@ -17,3 +17,24 @@ const queryGet = `
 		created_at DESC
 	LIMIT %d, %d
 `
+
+const queryInsert = `
+	INSERT INTO
+		notifications (
+			name,
+			descriptions,
+			table_id,
+			table_name,
+			users_id,
+			created_at,
+			updated_at
+		) VALUES (
+			('%s'),
+			('%s'),
+			('%s'),
+			('%s'),
+			(%d),
+			NOW(),
+			NOW()
+		);
+`
@@ -0,0 +1,307 @@
+package quiz
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/jmoiron/sqlx"
+)
+
+// SelectAttempt returns attempts of the user on the quiz, the first attempt first
+func SelectAttempt(assignmentID, userID int64) ([]Attempt, error) {
+	var attempts []Attempt
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			assignments_id,
+			users_id,
+			attempt,
+			status,
+			started_at,
+			submitted_at,
+			points,
+			score
+		FROM
+			quiz_attempts
+		WHERE
+			assignments_id = (%d) AND
+			users_id = (%d)
+		ORDER BY
+			attempt ASC;`, assignmentID, userID)
+	err := conn.DB.Select(&attempts, query)
+	if err != nil && err != sql.ErrNoRows {
+		return attempts, err
+	}
+	return attempts, nil
+}
+
+// SelectAttemptByStatus returns attempts of every user on the quiz with the status, the oldest submission first
+func SelectAttemptByStatus(assignmentID int64, status int8) ([]Attempt, error) {
+	var attempts []Attempt
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			assignments_id,
+			users_id,
+			attempt,
+			status,
+			started_at,
+			submitted_at,
+			points,
+			score
+		FROM
+			quiz_attempts
+		WHERE
+			assignments_id = (%d) AND
+			status = (%d)
+		ORDER BY
+			submitted_at ASC,
+			id ASC;`, assignmentID, status)
+	err := conn.DB.Select(&attempts, query)
+	if err != nil && err != sql.ErrNoRows {
+		return attempts, err
+	}
+	return attempts, nil
+}
+
+// GetAttempt returns the attempt, nil is returned when it does not exist
+func GetAttempt(id int64) (*Attempt, error) {
+	var attempt Attempt
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			assignments_id,
+			users_id,
+			attempt,
+			status,
+			started_at,
+			submitted_at,
+			points,
+			score
+		FROM
+			quiz_attempts
+		WHERE
+			id = (%d)
+		LIMIT 1;`, id)
+	err := conn.DB.Get(&attempt, query)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// InsertAttempt starts a new attempt of the user with the drawn questions,
+// attempt number continues from the last attempt of the user on the quiz
+func InsertAttempt(assignmentID, userID int64, answers []Answer, tx *sqlx.Tx) (int64, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO
+			quiz_attempts (
+				assignments_id,
+				users_id,
+				attempt,
+				status,
+				started_at
+			)
+		SELECT
+			(%d),
+			(%d),
+			COALESCE(MAX(attempt), 0) + 1,
+			(%d),
+			NOW()
+		FROM
+			quiz_attempts
+		WHERE
+			assignments_id = (%d) AND
+			users_id = (%d);`, assignmentID, userID, AttemptInProgress, assignmentID, userID)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	if len(answers) < 1 {
+		return id, nil
+	}
+
+	var values []string
+	for i, val := range answers {
+		values = append(values, fmt.Sprintf("((%d), (%d), (%d), ('%s'))", id, val.QuestionID, i+1, val.OptionOrder))
+	}
+	query = fmt.Sprintf(`
+		INSERT INTO
+			quiz_answers (
+				quiz_attempts_id,
+				quiz_questions_id,
+				position,
+				option_order
+			) VALUES %s;`, strings.Join(values, ", "))
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// SelectAnswer returns the answers of the attempt in the order the questions are shown
+func SelectAnswer(attemptID int64) ([]Answer, error) {
+	var answers []Answer
+	query := fmt.Sprintf(`
+		SELECT
+			quiz_attempts_id,
+			quiz_questions_id,
+			position,
+			option_order,
+			answer,
+			points,
+			comment
+		FROM
+			quiz_answers
+		WHERE
+			quiz_attempts_id = (%d)
+		ORDER BY
+			position ASC;`, attemptID)
+	err := conn.DB.Select(&answers, query)
+	if err != nil && err != sql.ErrNoRows {
+		return answers, err
+	}
+	return answers, nil
+}
+
+// UpdateAnswer saves the answer of a question on the attempt
+func UpdateAnswer(attemptID, questionID int64, answer sql.NullString, tx *sqlx.Tx) error {
+	query := fmt.Sprintf(`
+		UPDATE
+			quiz_answers
+		SET
+			answer = %s
+		WHERE
+			quiz_attempts_id = (%d) AND
+			quiz_questions_id = (%d);`, queryNullString(answer), attemptID, questionID)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GradeAnswer sets the points and the comment of an answer, null points leaves it for review
+func GradeAnswer(attemptID, questionID int64, points sql.NullFloat64, comment sql.NullString, tx *sqlx.Tx) error {
+	query := fmt.Sprintf(`
+		UPDATE
+			quiz_answers
+		SET
+			points = %s,
+			comment = %s
+		WHERE
+			quiz_attempts_id = (%d) AND
+			quiz_questions_id = (%d);`, queryNullFloat(points), queryNullString(comment), attemptID, questionID)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateAttempt sets the status and the result of the attempt, submission time is set only once
+func UpdateAttempt(id int64, status int8, points, score sql.NullFloat64, tx *sqlx.Tx) error {
+	query := fmt.Sprintf(`
+		UPDATE
+			quiz_attempts
+		SET
+			status = (%d),
+			submitted_at = COALESCE(submitted_at, NOW()),
+			points = %s,
+			score = %s
+		WHERE
+			id = (%d);`, status, queryNullFloat(points), queryNullFloat(score), id)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("No rows affected")
+	}
+	return nil
+}
+
+// GetBestScore returns the highest score of the graded attempts of the user
+func GetBestScore(assignmentID, userID int64, tx *sqlx.Tx) (sql.NullFloat64, error) {
+	var score sql.NullFloat64
+	query := fmt.Sprintf(`
+		SELECT
+			MAX(score)
+		FROM
+			quiz_attempts
+		WHERE
+			assignments_id = (%d) AND
+			users_id = (%d) AND
+			status = (%d);`, assignmentID, userID, AttemptGraded)
+
+	var err error
+	if tx != nil {
+		err = tx.Get(&score, query)
+	} else {
+		err = conn.DB.Get(&score, query)
+	}
+	if err != nil {
+		return score, err
+	}
+	return score, nil
+}
+
+// IsAttempted is used to check whether the quiz has been attempted by any user or not
+func IsAttempted(assignmentID int64) bool {
+	var x string
+	query := fmt.Sprintf(`
+		SELECT
+			'x'
+		FROM
+			quiz_attempts
+		WHERE
+			assignments_id = (%d)
+		LIMIT 1;`, assignmentID)
+	err := conn.DB.Get(&x, query)
+	if err != nil {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,509 @@
+package quiz
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/jmoiron/sqlx"
+)
+
+func queryNullString(val sql.NullString) string {
+	if val.Valid {
+		return fmt.Sprintf("('%s')", val.String)
+	}
+	return fmt.Sprintf("(NULL)")
+}
+
+func queryNullFloat(val sql.NullFloat64) string {
+	if val.Valid {
+		return fmt.Sprintf("(%g)", val.Float64)
+	}
+	return fmt.Sprintf("(NULL)")
+}
+
+// SelectBank returns question banks of the schedule
+func SelectBank(scheduleID int64) ([]Bank, error) {
+	var banks []Bank
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			schedules_id,
+			name,
+			created_by,
+			created_at,
+			updated_at
+		FROM
+			quiz_banks
+		WHERE
+			schedules_id = (%d)
+		ORDER BY
+			name ASC;`, scheduleID)
+	err := conn.DB.Select(&banks, query)
+	if err != nil && err != sql.ErrNoRows {
+		return banks, err
+	}
+	return banks, nil
+}
+
+// GetBank returns the question bank, nil is returned when it does not exist
+func GetBank(id int64) (*Bank, error) {
+	var bank Bank
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			schedules_id,
+			name,
+			created_by,
+			created_at,
+			updated_at
+		FROM
+			quiz_banks
+		WHERE
+			id = (%d)
+		LIMIT 1;`, id)
+	err := conn.DB.Get(&bank, query)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &bank, nil
+}
+
+// InsertBank creates a question bank of the schedule
+func InsertBank(scheduleID int64, name string, createdBy int64, tx *sqlx.Tx) (int64, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO
+			quiz_banks (
+				schedules_id,
+				name,
+				created_by,
+				created_at,
+				updated_at
+			) VALUES (
+				(%d),
+				('%s'),
+				(%d),
+				NOW(),
+				NOW()
+			);`, scheduleID, name, createdBy)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// UpdateBank renames the question bank
+func UpdateBank(id int64, name string, tx *sqlx.Tx) error {
+	query := fmt.Sprintf(`
+		UPDATE
+			quiz_banks
+		SET
+			name = ('%s'),
+			updated_at = NOW()
+		WHERE
+			id = (%d);`, name, id)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteBank removes the question bank with its questions, bank which is used by a quiz must not be deleted
+func DeleteBank(id int64, tx *sqlx.Tx) error {
+	queries := []string{
+		fmt.Sprintf(`
+			DELETE
+				o
+			FROM
+				quiz_options o
+			JOIN
+				quiz_questions q
+			ON
+				o.quiz_questions_id = q.id
+			WHERE
+				q.quiz_banks_id = (%d);`, id),
+		fmt.Sprintf(`
+			DELETE FROM
+				quiz_questions
+			WHERE
+				quiz_banks_id = (%d);`, id),
+		fmt.Sprintf(`
+			DELETE FROM
+				quiz_banks
+			WHERE
+				id = (%d);`, id),
+	}
+
+	for _, query := range queries {
+		var err error
+		if tx != nil {
+			_, err = tx.Exec(query)
+		} else {
+			_, err = conn.DB.Exec(query)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CountQuestion returns the number of active questions of every bank
+func CountQuestion(banksID []int64) (map[int64]int, error) {
+	count := map[int64]int{}
+	if len(banksID) < 1 {
+		return count, nil
+	}
+
+	var rows []struct {
+		BankID int64 `db:"quiz_banks_id"`
+		Count  int   `db:"count"`
+	}
+	query := fmt.Sprintf(`
+		SELECT
+			quiz_banks_id,
+			COUNT(*) AS count
+		FROM
+			quiz_questions
+		WHERE
+			quiz_banks_id IN (%s) AND
+			status = (%d)
+		GROUP BY
+			quiz_banks_id;`, strings.Join(helper.Int64ToStringSlice(banksID), ", "), StatusQuestionActive)
+	err := conn.DB.Select(&rows, query)
+	if err != nil && err != sql.ErrNoRows {
+		return count, err
+	}
+	for _, val := range rows {
+		count[val.BankID] = val.Count
+	}
+	return count, nil
+}
+
+// SelectQuestion returns active questions of the bank with their options
+func SelectQuestion(bankID int64) ([]Question, error) {
+	var questions []Question
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			quiz_banks_id,
+			type,
+			question,
+			points,
+			answer,
+			tolerance,
+			status,
+			created_at,
+			updated_at
+		FROM
+			quiz_questions
+		WHERE
+			quiz_banks_id = (%d) AND
+			status = (%d)
+		ORDER BY
+			id ASC;`, bankID, StatusQuestionActive)
+	err := conn.DB.Select(&questions, query)
+	if err != nil && err != sql.ErrNoRows {
+		return questions, err
+	}
+	return selectOption(questions)
+}
+
+// SelectQuestionByID returns questions with their options including the deleted one,
+// it is used to show questions of past attempts
+func SelectQuestionByID(id []int64) ([]Question, error) {
+	var questions []Question
+	if len(id) < 1 {
+		return questions, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			quiz_banks_id,
+			type,
+			question,
+			points,
+			answer,
+			tolerance,
+			status,
+			created_at,
+			updated_at
+		FROM
+			quiz_questions
+		WHERE
+			id IN (%s);`, strings.Join(helper.Int64ToStringSlice(id), ", "))
+	err := conn.DB.Select(&questions, query)
+	if err != nil && err != sql.ErrNoRows {
+		return questions, err
+	}
+	return selectOption(questions)
+}
+
+// GetQuestion returns the active question with its options, nil is returned when it does not exist
+func GetQuestion(id int64) (*Question, error) {
+	var question Question
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			quiz_banks_id,
+			type,
+			question,
+			points,
+			answer,
+			tolerance,
+			status,
+			created_at,
+			updated_at
+		FROM
+			quiz_questions
+		WHERE
+			id = (%d) AND
+			status = (%d)
+		LIMIT 1;`, id, StatusQuestionActive)
+	err := conn.DB.Get(&question, query)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	questions, err := selectOption([]Question{question})
+	if err != nil {
+		return nil, err
+	}
+	return &questions[0], nil
+}
+
+// selectOption fills the options of the questions ordered by their position
+func selectOption(questions []Question) ([]Question, error) {
+	if len(questions) < 1 {
+		return questions, nil
+	}
+
+	var questionsID []int64
+	for _, val := range questions {
+		questionsID = append(questionsID, val.ID)
+	}
+
+	var options []Option
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			quiz_questions_id,
+			text,
+			is_correct,
+			position
+		FROM
+			quiz_options
+		WHERE
+			quiz_questions_id IN (%s)
+		ORDER BY
+			position ASC;`, strings.Join(helper.Int64ToStringSlice(questionsID), ", "))
+	err := conn.DB.Select(&options, query)
+	if err != nil && err != sql.ErrNoRows {
+		return questions, err
+	}
+
+	optionMap := map[int64][]Option{}
+	for _, val := range options {
+		optionMap[val.QuestionID] = append(optionMap[val.QuestionID], val)
+	}
+	for i := range questions {
+		questions[i].Options = optionMap[questions[i].ID]
+	}
+	return questions, nil
+}
+
+// InsertQuestion creates an active question of the bank with its options
+func InsertQuestion(question Question, tx *sqlx.Tx) (int64, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO
+			quiz_questions (
+				quiz_banks_id,
+				type,
+				question,
+				points,
+				answer,
+				tolerance,
+				status,
+				created_at,
+				updated_at
+			) VALUES (
+				(%d),
+				(%d),
+				('%s'),
+				(%g),
+				%s,
+				(%g),
+				(%d),
+				NOW(),
+				NOW()
+			);`, question.BankID, question.Type, question.Question, question.Points,
+		queryNullString(question.Answer), question.Tolerance, StatusQuestionActive)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	err = insertOption(id, question.Options, tx)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func insertOption(questionID int64, options []Option, tx *sqlx.Tx) error {
+	if len(options) < 1 {
+		return nil
+	}
+
+	var values []string
+	for i, val := range options {
+		isCorrect := 0
+		if val.IsCorrect {
+			isCorrect = 1
+		}
+		values = append(values, fmt.Sprintf("((%d), ('%s'), (%d), (%d))", questionID, val.Text, isCorrect, i+1))
+	}
+	query := fmt.Sprintf(`
+		INSERT INTO
+			quiz_options (
+				quiz_questions_id,
+				text,
+				is_correct,
+				position
+			) VALUES %s;`, strings.Join(values, ", "))
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateQuestion replaces the question with its options, question which has been answered must not be updated
+func UpdateQuestion(question Question, tx *sqlx.Tx) error {
+	query := fmt.Sprintf(`
+		UPDATE
+			quiz_questions
+		SET
+			type = (%d),
+			question = ('%s'),
+			points = (%g),
+			answer = %s,
+			tolerance = (%g),
+			updated_at = NOW()
+		WHERE
+			id = (%d);`, question.Type, question.Question, question.Points,
+		queryNullString(question.Answer), question.Tolerance, question.ID)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf(`
+		DELETE FROM
+			quiz_options
+		WHERE
+			quiz_questions_id = (%d);`, question.ID)
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return insertOption(question.ID, question.Options, tx)
+}
+
+// DeleteQuestion removes the question from the bank, it is kept for the attempts which have answered it
+func DeleteQuestion(id int64, tx *sqlx.Tx) error {
+	query := fmt.Sprintf(`
+		UPDATE
+			quiz_questions
+		SET
+			status = (%d),
+			updated_at = NOW()
+		WHERE
+			id = (%d);`, StatusQuestionDeleted, id)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("No rows affected")
+	}
+	return nil
+}
+
+// IsQuestionAnswered is used to check whether the question has been drawn on any attempt or not
+func IsQuestionAnswered(id int64) bool {
+	var x string
+	query := fmt.Sprintf(`
+		SELECT
+			'x'
+		FROM
+			quiz_answers
+		WHERE
+			quiz_questions_id = (%d)
+		LIMIT 1;`, id)
+	err := conn.DB.Get(&x, query)
+	if err != nil {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,181 @@
+package quiz
+
+import (
+	"database/sql"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Grade returns the points of the answer and whether it has been graded automatically,
+// short answer which does not match any accepted answer is left for manual review
+func (q Question) Grade(answer sql.NullString) (float64, bool) {
+	if !answer.Valid || strings.TrimSpace(answer.String) == "" {
+		return 0, true
+	}
+
+	switch q.Type {
+	case TypeMultipleChoice:
+		chosen := SplitID(answer.String)
+		if len(chosen) != 1 {
+			return 0, true
+		}
+		for _, val := range q.Options {
+			if val.ID == chosen[0] && val.IsCorrect {
+				return q.Points, true
+			}
+		}
+		return 0, true
+	case TypeMultipleAnswer:
+		return q.gradeMultipleAnswer(SplitID(answer.String)), true
+	case TypeShortAnswer:
+		text := normalizeText(answer.String)
+		for _, val := range strings.Split(q.Answer.String, "~") {
+			if val = normalizeText(val); val != "" && val == text {
+				return q.Points, true
+			}
+		}
+		return 0, false
+	case TypeNumeric:
+		expected, err := strconv.ParseFloat(q.Answer.String, 64)
+		if err != nil {
+			return 0, false
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(answer.String), 64)
+		if err != nil {
+			return 0, true
+		}
+		// small epsilon keeps 0.1 + 0.2 within zero tolerance of 0.3
+		if math.Abs(value-expected) <= q.Tolerance+1e-9 {
+			return q.Points, true
+		}
+		return 0, true
+	}
+	return 0, true
+}
+
+// gradeMultipleAnswer gives partial points, every wrong option cancels a correct one
+func (q Question) gradeMultipleAnswer(chosen []int64) float64 {
+	var correct, right, wrong int
+	for _, option := range q.Options {
+		isChosen := false
+		for _, val := range chosen {
+			if val == option.ID {
+				isChosen = true
+				break
+			}
+		}
+		if option.IsCorrect {
+			correct++
+			if isChosen {
+				right++
+			}
+		} else if isChosen {
+			wrong++
+		}
+	}
+	if correct < 1 || right <= wrong {
+		return 0
+	}
+	return round(q.Points * float64(right-wrong) / float64(correct))
+}
+
+// Draw picks the questions of an attempt, count 0 uses every question.
+// Drawn questions keep the bank order unless they are shuffled
+func Draw(questions []Question, count int, isShuffled bool, rnd *rand.Rand) []Question {
+	drawn := make([]Question, len(questions))
+	copy(drawn, questions)
+
+	if count > 0 && count < len(drawn) {
+		rnd.Shuffle(len(drawn), func(i, j int) {
+			drawn[i], drawn[j] = drawn[j], drawn[i]
+		})
+		drawn = drawn[:count]
+		if !isShuffled {
+			position := map[int64]int{}
+			for i, val := range questions {
+				position[val.ID] = i
+			}
+			sort.Slice(drawn, func(i, j int) bool {
+				return position[drawn[i].ID] < position[drawn[j].ID]
+			})
+		}
+		return drawn
+	}
+
+	if isShuffled {
+		rnd.Shuffle(len(drawn), func(i, j int) {
+			drawn[i], drawn[j] = drawn[j], drawn[i]
+		})
+	}
+	return drawn
+}
+
+// OptionOrder returns the option ids of the question in the order they are shown
+func OptionOrder(q Question, isShuffled bool, rnd *rand.Rand) []int64 {
+	var order []int64
+	for _, val := range q.Options {
+		order = append(order, val.ID)
+	}
+	if isShuffled {
+		rnd.Shuffle(len(order), func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
+	}
+	return order
+}
+
+// Total returns the points and the score of the answers scaled to MaxScore,
+// it is not graded while any answer is waiting for review
+func Total(questions map[int64]Question, answers []Answer) (float64, float64, bool) {
+	var points, max float64
+	isGraded := true
+	for _, val := range answers {
+		max += questions[val.QuestionID].Points
+		if !val.Points.Valid {
+			isGraded = false
+			continue
+		}
+		points += val.Points.Float64
+	}
+	if max <= 0 {
+		return round(points), 0, isGraded
+	}
+	return round(points), round(points / max * MaxScore), isGraded
+}
+
+// EndAt returns the time the attempt ends, the earlier of the time limit and the deadline of the assignment
+func (q Quiz) EndAt(startedAt, deadline time.Time) time.Time {
+	if q.TimeLimit < 1 {
+		return deadline
+	}
+	end := startedAt.Add(time.Duration(q.TimeLimit) * time.Minute)
+	if end.After(deadline) {
+		return deadline
+	}
+	return end
+}
+
+// SplitID parses the option ids which are separated by "~", invalid id is skipped
+func SplitID(val string) []int64 {
+	var ids []int64
+	for _, s := range strings.Split(val, "~") {
+		id, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func normalizeText(val string) string {
+	return strings.Join(strings.Fields(strings.ToLower(val)), " ")
+}
+
+func round(val float64) float64 {
+	return math.Round(val*100) / 100
+}
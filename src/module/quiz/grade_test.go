@@ -0,0 +1,163 @@
+package quiz
+
+import (
+	"database/sql"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func answer(val string) sql.NullString {
+	return sql.NullString{Valid: true, String: val}
+}
+
+func TestQuestion_Grade(t *testing.T) {
+	choice := Question{
+		Type:   TypeMultipleChoice,
+		Points: 10,
+		Options: []Option{
+			{ID: 1},
+			{ID: 2, IsCorrect: true},
+			{ID: 3},
+		},
+	}
+	multiple := Question{
+		Type:   TypeMultipleAnswer,
+		Points: 10,
+		Options: []Option{
+			{ID: 1, IsCorrect: true},
+			{ID: 2, IsCorrect: true},
+			{ID: 3},
+			{ID: 4},
+		},
+	}
+	short := Question{
+		Type:   TypeShortAnswer,
+		Points: 5,
+		Answer: answer("Jakarta~DKI  Jakarta"),
+	}
+	numeric := Question{
+		Type:      TypeNumeric,
+		Points:    4,
+		Answer:    answer("3.14"),
+		Tolerance: 0.01,
+	}
+
+	cases := []struct {
+		name     string
+		question Question
+		answer   sql.NullString
+		points   float64
+		isGraded bool
+	}{
+		{"empty answer", choice, sql.NullString{}, 0, true},
+		{"choice correct", choice, answer("2"), 10, true},
+		{"choice wrong", choice, answer("1"), 0, true},
+		{"choice more than one", choice, answer("2~1"), 0, true},
+		{"multiple all correct", multiple, answer("1~2"), 10, true},
+		{"multiple partial", multiple, answer("1"), 5, true},
+		{"multiple wrong cancels correct", multiple, answer("1~2~3"), 5, true},
+		{"multiple all options", multiple, answer("1~2~3~4"), 0, true},
+		{"short match", short, answer("  dki jakarta "), 5, true},
+		{"short not match", short, answer("Bandung"), 0, false},
+		{"numeric within tolerance", numeric, answer("3.15"), 4, true},
+		{"numeric outside tolerance", numeric, answer("3.2"), 0, true},
+		{"numeric invalid", numeric, answer("pi"), 0, true},
+	}
+	for _, c := range cases {
+		points, isGraded := c.question.Grade(c.answer)
+		if points != c.points || isGraded != c.isGraded {
+			t.Errorf("%s: Grade() expected %v %v, got %v %v", c.name, c.points, c.isGraded, points, isGraded)
+		}
+	}
+}
+
+func TestDraw(t *testing.T) {
+	var questions []Question
+	for i := int64(1); i <= 10; i++ {
+		questions = append(questions, Question{ID: i})
+	}
+
+	all := Draw(questions, 0, false, rand.New(rand.NewSource(1)))
+	for i, val := range all {
+		if val.ID != questions[i].ID {
+			t.Fatalf("Draw() without shuffle expected bank order")
+		}
+	}
+
+	subset := Draw(questions, 4, false, rand.New(rand.NewSource(1)))
+	if len(subset) != 4 {
+		t.Fatalf("Draw() expected 4 questions, got %d", len(subset))
+	}
+	for i := 1; i < len(subset); i++ {
+		if subset[i].ID <= subset[i-1].ID {
+			t.Errorf("Draw() subset without shuffle expected bank order, got %v", subset)
+		}
+	}
+
+	shuffled := Draw(questions, 0, true, rand.New(rand.NewSource(1)))
+	again := Draw(questions, 0, true, rand.New(rand.NewSource(1)))
+	seen := map[int64]bool{}
+	isSame := true
+	for i, val := range shuffled {
+		seen[val.ID] = true
+		if val.ID != again[i].ID {
+			t.Errorf("Draw() with the same seed expected the same order")
+		}
+		if val.ID != questions[i].ID {
+			isSame = false
+		}
+	}
+	if len(seen) != len(questions) {
+		t.Errorf("Draw() shuffle expected every question once")
+	}
+	if isSame {
+		t.Errorf("Draw() shuffle expected a different order")
+	}
+	if questions[0].ID != 1 {
+		t.Errorf("Draw() must not modify the bank questions")
+	}
+}
+
+func TestTotal(t *testing.T) {
+	questions := map[int64]Question{
+		1: {ID: 1, Points: 10},
+		2: {ID: 2, Points: 30},
+	}
+	graded := []Answer{
+		{QuestionID: 1, Points: sql.NullFloat64{Valid: true, Float64: 10}},
+		{QuestionID: 2, Points: sql.NullFloat64{Valid: true, Float64: 20}},
+	}
+	points, score, isGraded := Total(questions, graded)
+	if points != 30 || score != 75 || !isGraded {
+		t.Errorf("Total() expected 30 75 true, got %v %v %v", points, score, isGraded)
+	}
+
+	pending := []Answer{
+		{QuestionID: 1, Points: sql.NullFloat64{Valid: true, Float64: 10}},
+		{QuestionID: 2},
+	}
+	_, _, isGraded = Total(questions, pending)
+	if isGraded {
+		t.Errorf("Total() with pending answer expected not graded")
+	}
+}
+
+func TestQuiz_EndAt(t *testing.T) {
+	start := time.Date(2017, 5, 1, 10, 0, 0, 0, time.UTC)
+	deadline := start.Add(time.Hour)
+
+	cases := []struct {
+		limit    int
+		expected time.Time
+	}{
+		{0, deadline},
+		{30, start.Add(30 * time.Minute)},
+		{90, deadline},
+	}
+	for _, c := range cases {
+		if got := (Quiz{TimeLimit: c.limit}).EndAt(start, deadline); !got.Equal(c.expected) {
+			t.Errorf("EndAt() with limit %d expected %v, got %v", c.limit, c.expected, got)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package quiz
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	MaxName      = 50
+	MaxQuestion  = 2000
+	MaxOption    = 500
+	MaxAnswer    = 1000
+	MaxComment   = 1000
+	MaxOptions   = 10
+	MaxPoints    = 100
+	MaxTimeLimit = 1440
+	MaxAttempts  = 10
+
+	// TypeMultipleChoice is a question with a single correct option
+	TypeMultipleChoice = 0
+	// TypeMultipleAnswer is a question with one or more correct options
+	TypeMultipleAnswer = 1
+	// TypeShortAnswer is a free text question, answer which does not match the accepted answers is reviewed manually
+	TypeShortAnswer = 2
+	// TypeNumeric is a question which is answered with a number within the tolerance
+	TypeNumeric = 3
+
+	StatusQuestionDeleted = 0
+	StatusQuestionActive  = 1
+
+	// AttemptInProgress is an attempt which has not been submitted
+	AttemptInProgress = 0
+	// AttemptReview is a submitted attempt which has answers waiting for manual review
+	AttemptReview = 1
+	// AttemptGraded is a submitted attempt which every answer has been graded
+	AttemptGraded = 2
+
+	// TimeGrace is the seconds answers are still accepted after the attempt ends to cover network delay
+	TimeGrace = 30
+
+	// MaxScore is the score of an attempt which gets full points on every question
+	MaxScore = 100
+)
+
+// Types is the name of every question type
+var Types = map[int8]string{
+	TypeMultipleChoice: "multiple_choice",
+	TypeMultipleAnswer: "multiple_answer",
+	TypeShortAnswer:    "short_answer",
+	TypeNumeric:        "numeric",
+}
+
+// AttemptStatuses is the name of every attempt status
+var AttemptStatuses = map[int8]string{
+	AttemptInProgress: "in_progress",
+	AttemptReview:     "review",
+	AttemptGraded:     "graded",
+}
+
+// Bank is a reusable set of questions owned by a schedule
+type Bank struct {
+	ID         int64     `db:"id"`
+	ScheduleID int64     `db:"schedules_id"`
+	Name       string    `db:"name"`
+	CreatedBy  int64     `db:"created_by"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+// Question is a question of a bank, Answer holds the accepted answers of a short answer question
+// separated by "~" or the value of a numeric question
+type Question struct {
+	ID        int64          `db:"id"`
+	BankID    int64          `db:"quiz_banks_id"`
+	Type      int8           `db:"type"`
+	Question  string         `db:"question"`
+	Points    float64        `db:"points"`
+	Answer    sql.NullString `db:"answer"`
+	Tolerance float64        `db:"tolerance"`
+	Status    int8           `db:"status"`
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at"`
+	Options   []Option       `db:"-"`
+}
+
+// Option is a choice of a multiple choice or multiple answer question
+type Option struct {
+	ID         int64  `db:"id"`
+	QuestionID int64  `db:"quiz_questions_id"`
+	Text       string `db:"text"`
+	IsCorrect  bool   `db:"is_correct"`
+	Position   int64  `db:"position"`
+}
+
+// Quiz is the setting of an assignment which is answered online using questions of a bank,
+// QuestionCount 0 uses every question, TimeLimit 0 has no time limit and MaxAttempts 0 is unlimited
+type Quiz struct {
+	AssignmentID     int64     `db:"assignments_id"`
+	BankID           int64     `db:"quiz_banks_id"`
+	QuestionCount    int       `db:"question_count"`
+	TimeLimit        int       `db:"time_limit"`
+	MaxAttempts      int       `db:"max_attempts"`
+	IsShuffled       bool      `db:"is_shuffled"`
+	IsOptionShuffled bool      `db:"is_option_shuffled"`
+	CreatedAt        time.Time `db:"created_at"`
+	UpdatedAt        time.Time `db:"updated_at"`
+}
+
+// Attempt is a single try of a user on a quiz, Score is set once every answer has been graded
+type Attempt struct {
+	ID           int64           `db:"id"`
+	AssignmentID int64           `db:"assignments_id"`
+	UserID       int64           `db:"users_id"`
+	Attempt      int             `db:"attempt"`
+	Status       int8            `db:"status"`
+	StartedAt    time.Time       `db:"started_at"`
+	SubmittedAt  mysql.NullTime  `db:"submitted_at"`
+	Points       sql.NullFloat64 `db:"points"`
+	Score        sql.NullFloat64 `db:"score"`
+}
+
+// Answer is the answer of a question drawn on an attempt, OptionOrder holds the shown option ids separated by "~".
+// Points is null until the answer is graded
+type Answer struct {
+	AttemptID   int64           `db:"quiz_attempts_id"`
+	QuestionID  int64           `db:"quiz_questions_id"`
+	Position    int64           `db:"position"`
+	OptionOrder string          `db:"option_order"`
+	Answer      sql.NullString  `db:"answer"`
+	Points      sql.NullFloat64 `db:"points"`
+	Comment     sql.NullString  `db:"comment"`
+}
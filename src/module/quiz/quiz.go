@@ -0,0 +1,111 @@
+package quiz
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/jmoiron/sqlx"
+)
+
+// Get returns the quiz setting of the assignment, nil is returned when the assignment is not a quiz
+func Get(assignmentID int64) (*Quiz, error) {
+	var quiz Quiz
+	query := fmt.Sprintf(`
+		SELECT
+			assignments_id,
+			quiz_banks_id,
+			question_count,
+			time_limit,
+			max_attempts,
+			is_shuffled,
+			is_option_shuffled,
+			created_at,
+			updated_at
+		FROM
+			quizzes
+		WHERE
+			assignments_id = (%d)
+		LIMIT 1;`, assignmentID)
+	err := conn.DB.Get(&quiz, query)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &quiz, nil
+}
+
+// Upsert sets the quiz setting of the assignment
+func Upsert(quiz Quiz, tx *sqlx.Tx) error {
+	isShuffled, isOptionShuffled := 0, 0
+	if quiz.IsShuffled {
+		isShuffled = 1
+	}
+	if quiz.IsOptionShuffled {
+		isOptionShuffled = 1
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO
+			quizzes (
+				assignments_id,
+				quiz_banks_id,
+				question_count,
+				time_limit,
+				max_attempts,
+				is_shuffled,
+				is_option_shuffled,
+				created_at,
+				updated_at
+			) VALUES (
+				(%d),
+				(%d),
+				(%d),
+				(%d),
+				(%d),
+				(%d),
+				(%d),
+				NOW(),
+				NOW()
+			)
+		ON DUPLICATE KEY UPDATE
+			quiz_banks_id = VALUES(quiz_banks_id),
+			question_count = VALUES(question_count),
+			time_limit = VALUES(time_limit),
+			max_attempts = VALUES(max_attempts),
+			is_shuffled = VALUES(is_shuffled),
+			is_option_shuffled = VALUES(is_option_shuffled),
+			updated_at = NOW();`, quiz.AssignmentID, quiz.BankID, quiz.QuestionCount,
+		quiz.TimeLimit, quiz.MaxAttempts, isShuffled, isOptionShuffled)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// IsBankUsed is used to check whether the bank is used by any quiz or not
+func IsBankUsed(bankID int64) bool {
+	var x string
+	query := fmt.Sprintf(`
+		SELECT
+			'x'
+		FROM
+			quizzes
+		WHERE
+			quiz_banks_id = (%d)
+		LIMIT 1;`, bankID)
+	err := conn.DB.Get(&x, query)
+	if err != nil {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package rubric
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	MaxName        = 50
+	MaxDescription = 1000
+	MaxComment     = 1000
+	MaxCriteria    = 20
+	MaxLevels      = 10
+	MaxPoints      = 100
+
+	// MaxScore is the score of a submission which gets the highest level on every criterion
+	MaxScore = 100
+)
+
+// Rubric is a reusable set of criteria owned by a schedule
+type Rubric struct {
+	ID          int64          `db:"id"`
+	ScheduleID  int64          `db:"schedules_id"`
+	Name        string         `db:"name"`
+	Description sql.NullString `db:"description"`
+	CreatedBy   int64          `db:"created_by"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+	Criteria    []Criterion    `db:"-"`
+}
+
+// Criterion is a single aspect which is graded by choosing one of its levels
+type Criterion struct {
+	ID          int64          `db:"id"`
+	RubricID    int64          `db:"rubrics_id"`
+	Name        string         `db:"name"`
+	Description sql.NullString `db:"description"`
+	Position    int64          `db:"position"`
+	Levels      []Level        `db:"-"`
+}
+
+// Level is a performance level of a criterion with its points
+type Level struct {
+	ID          int64          `db:"id"`
+	CriterionID int64          `db:"rubric_criteria_id"`
+	Name        string         `db:"name"`
+	Description sql.NullString `db:"description"`
+	Points      float64        `db:"points"`
+	Position    int64          `db:"position"`
+}
+
+// Score is the chosen level of a criterion on the submission of a user
+type Score struct {
+	AssignmentID int64          `db:"assignments_id"`
+	UserID       int64          `db:"users_id"`
+	CriterionID  int64          `db:"rubric_criteria_id"`
+	LevelID      int64          `db:"rubric_levels_id"`
+	Points       float64        `db:"points"`
+	Comment      sql.NullString `db:"comment"`
+	CreatedAt    time.Time      `db:"created_at"`
+	UpdatedAt    time.Time      `db:"updated_at"`
+}
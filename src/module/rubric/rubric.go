@@ -0,0 +1,486 @@
+package rubric
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/jmoiron/sqlx"
+)
+
+func queryNullString(val sql.NullString) string {
+	if val.Valid {
+		return fmt.Sprintf("('%s')", val.String)
+	}
+	return fmt.Sprintf("(NULL)")
+}
+
+// SelectBySchedule returns rubrics of the schedule with their criteria and levels
+func SelectBySchedule(scheduleID int64) ([]Rubric, error) {
+	var rubrics []Rubric
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			schedules_id,
+			name,
+			description,
+			created_by,
+			created_at,
+			updated_at
+		FROM
+			rubrics
+		WHERE
+			schedules_id = (%d)
+		ORDER BY
+			name ASC;`, scheduleID)
+	err := conn.DB.Select(&rubrics, query)
+	if err != nil && err != sql.ErrNoRows {
+		return rubrics, err
+	}
+	return selectCriteria(rubrics)
+}
+
+// Get returns the rubric with its criteria and levels, nil is returned when it does not exist
+func Get(id int64) (*Rubric, error) {
+	var rubric Rubric
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			schedules_id,
+			name,
+			description,
+			created_by,
+			created_at,
+			updated_at
+		FROM
+			rubrics
+		WHERE
+			id = (%d)
+		LIMIT 1;`, id)
+	err := conn.DB.Get(&rubric, query)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	rubrics, err := selectCriteria([]Rubric{rubric})
+	if err != nil {
+		return nil, err
+	}
+	return &rubrics[0], nil
+}
+
+// selectCriteria fills the criteria and the levels of the rubrics ordered by their position
+func selectCriteria(rubrics []Rubric) ([]Rubric, error) {
+	if len(rubrics) < 1 {
+		return rubrics, nil
+	}
+
+	var rubricsID []int64
+	for _, val := range rubrics {
+		rubricsID = append(rubricsID, val.ID)
+	}
+
+	var criteria []Criterion
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			rubrics_id,
+			name,
+			description,
+			position
+		FROM
+			rubric_criteria
+		WHERE
+			rubrics_id IN (%s)
+		ORDER BY
+			position ASC;`, strings.Join(helper.Int64ToStringSlice(rubricsID), ", "))
+	err := conn.DB.Select(&criteria, query)
+	if err != nil && err != sql.ErrNoRows {
+		return rubrics, err
+	}
+	if len(criteria) < 1 {
+		return rubrics, nil
+	}
+
+	var criteriaID []int64
+	for _, val := range criteria {
+		criteriaID = append(criteriaID, val.ID)
+	}
+
+	var levels []Level
+	query = fmt.Sprintf(`
+		SELECT
+			id,
+			rubric_criteria_id,
+			name,
+			description,
+			points,
+			position
+		FROM
+			rubric_levels
+		WHERE
+			rubric_criteria_id IN (%s)
+		ORDER BY
+			position ASC;`, strings.Join(helper.Int64ToStringSlice(criteriaID), ", "))
+	err = conn.DB.Select(&levels, query)
+	if err != nil && err != sql.ErrNoRows {
+		return rubrics, err
+	}
+
+	levelMap := map[int64][]Level{}
+	for _, val := range levels {
+		levelMap[val.CriterionID] = append(levelMap[val.CriterionID], val)
+	}
+	criteriaMap := map[int64][]Criterion{}
+	for _, val := range criteria {
+		val.Levels = levelMap[val.ID]
+		criteriaMap[val.RubricID] = append(criteriaMap[val.RubricID], val)
+	}
+	for i := range rubrics {
+		rubrics[i].Criteria = criteriaMap[rubrics[i].ID]
+	}
+	return rubrics, nil
+}
+
+// Insert creates the rubric with its criteria and levels
+func Insert(rubric Rubric, tx *sqlx.Tx) (int64, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO
+			rubrics (
+				schedules_id,
+				name,
+				description,
+				created_by,
+				created_at,
+				updated_at
+			) VALUES (
+				(%d),
+				('%s'),
+				%s,
+				(%d),
+				NOW(),
+				NOW()
+			);`, rubric.ScheduleID, rubric.Name, queryNullString(rubric.Description), rubric.CreatedBy)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	err = insertCriteria(id, rubric.Criteria, tx)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func insertCriteria(rubricID int64, criteria []Criterion, tx *sqlx.Tx) error {
+	for i, criterion := range criteria {
+		query := fmt.Sprintf(`
+			INSERT INTO
+				rubric_criteria (
+					rubrics_id,
+					name,
+					description,
+					position
+				) VALUES (
+					(%d),
+					('%s'),
+					%s,
+					(%d)
+				);`, rubricID, criterion.Name, queryNullString(criterion.Description), i+1)
+
+		var result sql.Result
+		var err error
+		if tx != nil {
+			result, err = tx.Exec(query)
+		} else {
+			result, err = conn.DB.Exec(query)
+		}
+		if err != nil {
+			return err
+		}
+
+		criterionID, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		var values []string
+		for j, level := range criterion.Levels {
+			values = append(values, fmt.Sprintf("((%d), ('%s'), %s, (%g), (%d))",
+				criterionID, level.Name, queryNullString(level.Description), level.Points, j+1))
+		}
+		if len(values) < 1 {
+			continue
+		}
+
+		query = fmt.Sprintf(`
+			INSERT INTO
+				rubric_levels (
+					rubric_criteria_id,
+					name,
+					description,
+					points,
+					position
+				) VALUES %s;`, strings.Join(values, ", "))
+		if tx != nil {
+			_, err = tx.Exec(query)
+		} else {
+			_, err = conn.DB.Exec(query)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deleteCriteria(rubricID int64, tx *sqlx.Tx) error {
+	queries := []string{
+		fmt.Sprintf(`
+			DELETE FROM
+				rubric_levels
+			WHERE
+				rubric_criteria_id IN (
+					SELECT
+						id
+					FROM
+						rubric_criteria
+					WHERE
+						rubrics_id = (%d)
+				);`, rubricID),
+		fmt.Sprintf(`
+			DELETE FROM
+				rubric_criteria
+			WHERE
+				rubrics_id = (%d);`, rubricID),
+	}
+
+	for _, query := range queries {
+		var err error
+		if tx != nil {
+			_, err = tx.Exec(query)
+		} else {
+			_, err = conn.DB.Exec(query)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Update renames the rubric and replaces its criteria and levels
+func Update(rubric Rubric, tx *sqlx.Tx) error {
+	query := fmt.Sprintf(`
+		UPDATE
+			rubrics
+		SET
+			name = ('%s'),
+			description = %s,
+			updated_at = NOW()
+		WHERE
+			id = (%d);`, rubric.Name, queryNullString(rubric.Description), rubric.ID)
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("No rows affected")
+	}
+
+	err = deleteCriteria(rubric.ID, tx)
+	if err != nil {
+		return err
+	}
+	return insertCriteria(rubric.ID, rubric.Criteria, tx)
+}
+
+// Delete deletes the rubric with its criteria and levels
+func Delete(id int64, tx *sqlx.Tx) error {
+	err := deleteCriteria(id, tx)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		DELETE FROM
+			rubrics
+		WHERE
+			id = (%d);`, id)
+
+	var result sql.Result
+	if tx != nil {
+		result, err = tx.Exec(query)
+	} else {
+		result, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("No rows affected")
+	}
+	return nil
+}
+
+// IsAttached returns true when the rubric is attached to any assignment
+func IsAttached(id int64) bool {
+	var x string
+	query := fmt.Sprintf(`
+		SELECT
+			'x'
+		FROM
+			assignments
+		WHERE
+			rubrics_id = (%d)
+		LIMIT 1;`, id)
+	err := conn.DB.Get(&x, query)
+	if err != nil {
+		return false
+	}
+	return true
+}
+
+// IsScored returns true when any submission has been graded with the rubric
+func IsScored(id int64) bool {
+	var x string
+	query := fmt.Sprintf(`
+		SELECT
+			'x'
+		FROM
+			rubric_scores
+		WHERE
+			rubric_criteria_id IN (
+				SELECT
+					id
+				FROM
+					rubric_criteria
+				WHERE
+					rubrics_id = (%d)
+			)
+		LIMIT 1;`, id)
+	err := conn.DB.Get(&x, query)
+	if err != nil {
+		return false
+	}
+	return true
+}
+
+// IsAssignmentScored returns true when any submission of the assignment has been graded with a rubric
+func IsAssignmentScored(assignmentID int64) bool {
+	var x string
+	query := fmt.Sprintf(`
+		SELECT
+			'x'
+		FROM
+			rubric_scores
+		WHERE
+			assignments_id = (%d)
+		LIMIT 1;`, assignmentID)
+	err := conn.DB.Get(&x, query)
+	if err != nil {
+		return false
+	}
+	return true
+}
+
+// SelectScore returns the filled rubric of the users on the assignment
+func SelectScore(assignmentID int64, usersID []int64) ([]Score, error) {
+	var scores []Score
+	if len(usersID) < 1 {
+		return scores, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			assignments_id,
+			users_id,
+			rubric_criteria_id,
+			rubric_levels_id,
+			points,
+			comment,
+			created_at,
+			updated_at
+		FROM
+			rubric_scores
+		WHERE
+			assignments_id = (%d) AND
+			users_id IN (%s);`, assignmentID, strings.Join(helper.Int64ToStringSlice(usersID), ", "))
+	err := conn.DB.Select(&scores, query)
+	if err != nil && err != sql.ErrNoRows {
+		return scores, err
+	}
+	return scores, nil
+}
+
+// ReplaceScore replaces the filled rubric of the user on the assignment
+func ReplaceScore(assignmentID, userID int64, scores []Score, tx *sqlx.Tx) error {
+	queries := []string{fmt.Sprintf(`
+		DELETE FROM
+			rubric_scores
+		WHERE
+			assignments_id = (%d) AND
+			users_id = (%d);`, assignmentID, userID)}
+
+	var values []string
+	for _, val := range scores {
+		values = append(values, fmt.Sprintf("((%d), (%d), (%d), (%d), (%g), %s, NOW(), NOW())",
+			assignmentID, userID, val.CriterionID, val.LevelID, val.Points, queryNullString(val.Comment)))
+	}
+	if len(values) > 0 {
+		queries = append(queries, fmt.Sprintf(`
+			INSERT INTO
+				rubric_scores (
+					assignments_id,
+					users_id,
+					rubric_criteria_id,
+					rubric_levels_id,
+					points,
+					comment,
+					created_at,
+					updated_at
+				) VALUES %s;`, strings.Join(values, ", ")))
+	}
+
+	for _, query := range queries {
+		var err error
+		if tx != nil {
+			_, err = tx.Exec(query)
+		} else {
+			_, err = conn.DB.Exec(query)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package rubric
+
+import (
+	"fmt"
+	"math"
+)
+
+// MaxPoints returns the points of the highest level of the criterion
+func (c Criterion) MaxPoints() float64 {
+	var max float64
+	for _, val := range c.Levels {
+		if val.Points > max {
+			max = val.Points
+		}
+	}
+	return max
+}
+
+// MaxPoints returns the sum of the highest points of every criterion
+func (r Rubric) MaxPoints() float64 {
+	var max float64
+	for _, val := range r.Criteria {
+		max += val.MaxPoints()
+	}
+	return max
+}
+
+// Fill builds the scores of the rubric from the chosen level of every criterion,
+// every criterion must be filled with a level of its own
+func (r Rubric) Fill(levels map[int64]int64, comments map[int64]string) ([]Score, error) {
+	var scores []Score
+	for _, criterion := range r.Criteria {
+		levelID, ok := levels[criterion.ID]
+		if !ok {
+			return nil, fmt.Errorf("Criterion %s has not been filled", criterion.Name)
+		}
+
+		var level *Level
+		for i := range criterion.Levels {
+			if criterion.Levels[i].ID == levelID {
+				level = &criterion.Levels[i]
+				break
+			}
+		}
+		if level == nil {
+			return nil, fmt.Errorf("Invalid level on criterion %s", criterion.Name)
+		}
+
+		score := Score{
+			CriterionID: criterion.ID,
+			LevelID:     level.ID,
+			Points:      level.Points,
+		}
+		if comment, ok := comments[criterion.ID]; ok && comment != "" {
+			score.Comment.Valid = true
+			score.Comment.String = comment
+		}
+		scores = append(scores, score)
+	}
+
+	for criterionID := range levels {
+		isExist := false
+		for _, val := range r.Criteria {
+			if val.ID == criterionID {
+				isExist = true
+				break
+			}
+		}
+		if !isExist {
+			return nil, fmt.Errorf("Criterion %d does not belong to the rubric", criterionID)
+		}
+	}
+	return scores, nil
+}
+
+// Total returns the sum of the points and the points scaled into the assignment score
+func (r Rubric) Total(scores []Score) (float64, float64) {
+	var points float64
+	for _, val := range scores {
+		points += val.Points
+	}
+
+	max := r.MaxPoints()
+	if max <= 0 {
+		return points, 0
+	}
+	score := math.Round(points/max*MaxScore*100) / 100
+	if score > MaxScore {
+		score = MaxScore
+	}
+	return points, score
+}
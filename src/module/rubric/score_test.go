@@ -0,0 +1,98 @@
+package rubric
+
+import "testing"
+
+func testRubric() Rubric {
+	return Rubric{
+		ID: 1,
+		Criteria: []Criterion{
+			{
+				ID:   1,
+				Name: "Analysis",
+				Levels: []Level{
+					{ID: 1, CriterionID: 1, Name: "Poor", Points: 0},
+					{ID: 2, CriterionID: 1, Name: "Good", Points: 5},
+					{ID: 3, CriterionID: 1, Name: "Excellent", Points: 10},
+				},
+			},
+			{
+				ID:   2,
+				Name: "Report",
+				Levels: []Level{
+					{ID: 4, CriterionID: 2, Name: "Poor", Points: 2},
+					{ID: 5, CriterionID: 2, Name: "Good", Points: 6},
+				},
+			},
+		},
+	}
+}
+
+func TestMaxPoints(t *testing.T) {
+	r := testRubric()
+	if max := r.MaxPoints(); max != 16 {
+		t.Errorf("MaxPoints() expected 16, got %v", max)
+	}
+	if max := (Rubric{}).MaxPoints(); max != 0 {
+		t.Errorf("MaxPoints() without criteria expected 0, got %v", max)
+	}
+}
+
+func TestFill(t *testing.T) {
+	cases := []struct {
+		name    string
+		levels  map[int64]int64
+		wantErr bool
+	}{
+		{name: "all filled", levels: map[int64]int64{1: 3, 2: 4}},
+		{name: "missing criterion", levels: map[int64]int64{1: 3}, wantErr: true},
+		{name: "level of another criterion", levels: map[int64]int64{1: 4, 2: 5}, wantErr: true},
+		{name: "unknown criterion", levels: map[int64]int64{1: 3, 2: 4, 9: 1}, wantErr: true},
+	}
+
+	for _, c := range cases {
+		_, err := testRubric().Fill(c.levels, nil)
+		if (err != nil) != c.wantErr {
+			t.Errorf("%s: Fill() error = %v, wantErr %v", c.name, err, c.wantErr)
+		}
+	}
+
+	scores, err := testRubric().Fill(map[int64]int64{1: 2, 2: 5}, map[int64]string{1: "Needs more detail", 2: ""})
+	if err != nil {
+		t.Fatalf("Fill() got error %s", err.Error())
+	}
+	if len(scores) != 2 || scores[0].Points != 5 || scores[1].Points != 6 {
+		t.Errorf("Fill() got unexpected points %+v", scores)
+	}
+	if !scores[0].Comment.Valid || scores[1].Comment.Valid {
+		t.Errorf("Fill() expected comment only on the first criterion, got %+v", scores)
+	}
+}
+
+func TestTotal(t *testing.T) {
+	r := testRubric()
+	cases := []struct {
+		levels map[int64]int64
+		points float64
+		score  float64
+	}{
+		{levels: map[int64]int64{1: 3, 2: 5}, points: 16, score: 100},
+		{levels: map[int64]int64{1: 2, 2: 5}, points: 11, score: 68.75},
+		{levels: map[int64]int64{1: 1, 2: 4}, points: 2, score: 12.5},
+		{levels: map[int64]int64{1: 2, 2: 4}, points: 7, score: 43.75},
+	}
+
+	for _, c := range cases {
+		scores, err := r.Fill(c.levels, nil)
+		if err != nil {
+			t.Fatalf("Fill(%v) got error %s", c.levels, err.Error())
+		}
+		points, score := r.Total(scores)
+		if points != c.points || score != c.score {
+			t.Errorf("Total(%v) expected %v (%v), got %v (%v)", c.levels, c.points, c.score, points, score)
+		}
+	}
+
+	if _, score := (Rubric{}).Total(nil); score != 0 {
+		t.Errorf("Total() without criteria expected 0, got %v", score)
+	}
+}
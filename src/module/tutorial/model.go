@@ -1,7 +1,11 @@
 package tutorial
 
-import "database/sql"
-import "time"
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
 
 // Tutorial ...
 type Tutorial struct {
@@ -9,5 +13,7 @@ type Tutorial struct {
 	Name        string         `db:"name"`
 	Description sql.NullString `db:"description"`
 	ScheduleID  int64          `db:"schedules_id"`
+	PublishAt   mysql.NullTime `db:"publish_at"`
+	UnpublishAt mysql.NullTime `db:"unpublish_at"`
 	CreatedAt   time.Time      `db:"created_at"`
 }
@@ -0,0 +1,21 @@
+package tutorial
+
+import (
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/asepnur/meiko_course/src/util/publish"
+)
+
+// IsPublished returns true when the tutorial is visible to students at now, now is the database time
+// from publish.Now. A tutorial without publish date is published since it is created
+func (t Tutorial) IsPublished(now time.Time) bool {
+	return publish.IsPublished(t.PublishAt, t.UnpublishAt, now)
+}
+
+// UpdatePublish sets the publish window of the tutorial
+func UpdatePublish(id int64, publishAt, unpublishAt mysql.NullTime, tx *sqlx.Tx) error {
+	return publish.Update("tutorials", id, publishAt, unpublishAt, tx)
+}
@@ -7,25 +7,34 @@ import (
 	"github.com/jmoiron/sqlx"
 
 	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/util/publish"
 )
 
 // SelectByPage ...
-func SelectByPage(scheduleID int64, limit, offset int, isCount bool) ([]Tutorial, int, error) {
+// set isPublished to only return tutorials which are visible to students
+func SelectByPage(scheduleID int64, limit, offset int, isCount, isPublished bool) ([]Tutorial, int, error) {
 	var tutorials []Tutorial
 	var count int
+	queryPublished := ""
+	if isPublished {
+		queryPublished = publish.QueryPublished
+	}
 	query := fmt.Sprintf(`
 		SELECT
 			id,
 			name,
 			description,
+			publish_at,
+			unpublish_at,
 			created_at
 		FROM
 			tutorials
 		WHERE
 			schedules_id = (%d)
+			%s
 		LIMIT %d
 		OFFSET %d;
-		`, scheduleID, limit, offset)
+		`, scheduleID, queryPublished, limit, offset)
 	err := conn.DB.Select(&tutorials, query)
 	if err != nil {
 		return tutorials, count, err
@@ -41,8 +50,9 @@ func SelectByPage(scheduleID int64, limit, offset int, isCount bool) ([]Tutorial
 		FROM
 			tutorials
 		WHERE
-			schedules_id = (%d);
-		`, scheduleID)
+			schedules_id = (%d)
+			%s;
+		`, scheduleID, queryPublished)
 	err = conn.DB.Get(&count, query)
 	if err != nil {
 		return tutorials, count, err
@@ -59,6 +69,8 @@ func GetByID(id int64) (Tutorial, error) {
 			name,
 			description,
 			schedules_id,
+			publish_at,
+			unpublish_at,
 			created_at
 		FROM
 			tutorials
@@ -223,6 +223,11 @@ func Float32Round(value float32) float32 {
 	return float32(math.Ceil(v - 0.5))
 }
 
+// EscapeText escapes backslash and quote of a raw text so it can be put inside a quoted sql string
+func EscapeText(text string) string {
+	return strings.Replace(strings.Replace(text, `\`, `\\`, -1), `'`, `\'`, -1)
+}
+
 // MimeToThumbnail ...
 func MimeToThumbnail(mime string) string {
 	imageMaps := map[string]string{
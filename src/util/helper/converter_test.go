@@ -684,6 +684,39 @@ func TestInt64ToStringSlice(t *testing.T) {
 	}
 }
 
+func TestEscapeText(t *testing.T) {
+	type args struct {
+		text string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Plain text",
+			args: args{
+				text: "hello world",
+			},
+			want: "hello world",
+		},
+		{
+			name: "Quote and backslash",
+			args: args{
+				text: `it's C:\tmp`,
+			},
+			want: `it\'s C:\\tmp`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EscapeText(tt.args.text); got != tt.want {
+				t.Errorf("EscapeText() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestTimeToDayInt(t *testing.T) {
 	type args struct {
 		time []time.Time
@@ -1,12 +1,15 @@
 package helper
 
 import (
+	"bytes"
 	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/asepnur/meiko_course/src/util/alias"
+	"github.com/asepnur/meiko_course/src/util/similarity"
 )
 
 // IsAlpha Check if string given alphabet only
@@ -158,6 +161,70 @@ func IsImageExtension(extension string) bool {
 	return false
 }
 
+// IsFileSignature Check the content of the file match its extension by the leading magic bytes,
+// text based and source code file must not contain any binary data. Extension without a known
+// signature is accepted since only a mismatching known signature proves the file is renamed
+/*
+	@params:
+		extension	= string
+		head		= []byte, first 512 bytes of the file
+	@example:
+		extension	= pdf
+		head		= %PDF-1.4...
+	@return
+		true/false
+*/
+func IsFileSignature(extension string, head []byte) bool {
+	switch strings.ToLower(extension) {
+	case "jpg", "jpeg":
+		return bytes.HasPrefix(head, []byte{0xFF, 0xD8, 0xFF})
+	case "png":
+		return bytes.HasPrefix(head, []byte("\x89PNG\r\n\x1a\n"))
+	case "pdf":
+		return bytes.HasPrefix(head, []byte("%PDF-"))
+	case "doc", "ppt", "xls":
+		return bytes.HasPrefix(head, []byte("\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1"))
+	case "zip", "docx", "pptx", "xlsx":
+		return bytes.HasPrefix(head, []byte("PK\x03\x04")) || bytes.HasPrefix(head, []byte("PK\x05\x06"))
+	case "rar":
+		return bytes.HasPrefix(head, []byte("Rar!\x1a\x07"))
+	case "mp3":
+		return bytes.HasPrefix(head, []byte("ID3")) || (len(head) > 1 && head[0] == 0xFF && head[1]&0xE0 == 0xE0)
+	case "wav":
+		return len(head) >= 12 && bytes.HasPrefix(head, []byte("RIFF")) && bytes.Equal(head[8:12], []byte("WAVE"))
+	case "avi":
+		return len(head) >= 12 && bytes.HasPrefix(head, []byte("RIFF")) && bytes.Equal(head[8:12], []byte("AVI "))
+	case "mp4":
+		return len(head) >= 8 && bytes.Equal(head[4:8], []byte("ftyp"))
+	case "db":
+		return bytes.HasPrefix(head, []byte("SQLite format 3\x00"))
+	case "svg":
+		text := bytes.TrimSpace(bytes.TrimPrefix(head, []byte("\xEF\xBB\xBF")))
+		return isText(head) && (bytes.HasPrefix(text, []byte("<svg")) || bytes.HasPrefix(text, []byte("<?xml")))
+	}
+	for _, val := range similarity.PlainExtensions {
+		if val == strings.ToLower(extension) {
+			return isText(head)
+		}
+	}
+	return true
+}
+
+// isText reports whether the data looks like text, the last rune may be cut in the middle
+func isText(data []byte) bool {
+	if bytes.IndexByte(data, 0) >= 0 {
+		return false
+	}
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		if r == utf8.RuneError && size == 1 {
+			return !utf8.FullRune(data)
+		}
+		data = data[size:]
+	}
+	return true
+}
+
 // Normalize normalize input text to appropiate text
 /*
 	@params:
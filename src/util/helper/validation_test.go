@@ -1344,3 +1344,183 @@ func TestIsValidFileID(t *testing.T) {
 		})
 	}
 }
+
+func TestIsFileSignature(t *testing.T) {
+	type args struct {
+		extension string
+		head      []byte
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "Empty file",
+			args: args{
+				extension: "pdf",
+				head:      []byte{},
+			},
+			want: false,
+		},
+		{
+			name: "Pdf",
+			args: args{
+				extension: "pdf",
+				head:      []byte("%PDF-1.4\n"),
+			},
+			want: true,
+		},
+		{
+			name: "Renamed executable as pdf",
+			args: args{
+				extension: "pdf",
+				head:      []byte("MZ\x90\x00\x03"),
+			},
+			want: false,
+		},
+		{
+			name: "Jpeg with uppercase extension",
+			args: args{
+				extension: "JPG",
+				head:      []byte{0xFF, 0xD8, 0xFF, 0xE0},
+			},
+			want: true,
+		},
+		{
+			name: "Png as jpg",
+			args: args{
+				extension: "jpg",
+				head:      []byte("\x89PNG\r\n\x1a\n"),
+			},
+			want: false,
+		},
+		{
+			name: "Docx",
+			args: args{
+				extension: "docx",
+				head:      []byte("PK\x03\x04\x14\x00"),
+			},
+			want: true,
+		},
+		{
+			name: "Wav",
+			args: args{
+				extension: "wav",
+				head:      []byte("RIFF\x24\x08\x00\x00WAVEfmt "),
+			},
+			want: true,
+		},
+		{
+			name: "Avi as wav",
+			args: args{
+				extension: "wav",
+				head:      []byte("RIFF\x24\x08\x00\x00AVI LIST"),
+			},
+			want: false,
+		},
+		{
+			name: "Mp4",
+			args: args{
+				extension: "mp4",
+				head:      []byte("\x00\x00\x00\x18ftypmp42"),
+			},
+			want: true,
+		},
+		{
+			name: "Text",
+			args: args{
+				extension: "txt",
+				head:      []byte("Hello, dünya"),
+			},
+			want: true,
+		},
+		{
+			name: "Text cut in the middle of a rune",
+			args: args{
+				extension: "csv",
+				head:      []byte("a,b\nd\xC3"),
+			},
+			want: true,
+		},
+		{
+			name: "Binary as text",
+			args: args{
+				extension: "sql",
+				head:      []byte("SELECT\x00\x01"),
+			},
+			want: false,
+		},
+		{
+			name: "Svg",
+			args: args{
+				extension: "svg",
+				head:      []byte("  <?xml version=\"1.0\"?><svg>"),
+			},
+			want: true,
+		},
+		{
+			name: "Html as svg",
+			args: args{
+				extension: "svg",
+				head:      []byte("<html><script>"),
+			},
+			want: false,
+		},
+		{
+			name: "C source",
+			args: args{
+				extension: "c",
+				head:      []byte("#include <stdio.h>\nint main(void) { return 0; }\n"),
+			},
+			want: true,
+		},
+		{
+			name: "Python source with uppercase extension",
+			args: args{
+				extension: "PY",
+				head:      []byte("print('hello')\n"),
+			},
+			want: true,
+		},
+		{
+			name: "Executable as c",
+			args: args{
+				extension: "c",
+				head:      []byte("\x7fELF\x02\x01\x01\x00"),
+			},
+			want: false,
+		},
+		{
+			name: "Doc",
+			args: args{
+				extension: "doc",
+				head:      []byte("\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1\x00\x00"),
+			},
+			want: true,
+		},
+		{
+			name: "Pdf as doc",
+			args: args{
+				extension: "doc",
+				head:      []byte("%PDF-1.4\n"),
+			},
+			want: false,
+		},
+		{
+			name: "Unknown extension",
+			args: args{
+				extension: "exe",
+				head:      []byte("MZ\x90\x00"),
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsFileSignature(tt.args.extension, tt.args.head); got != tt.want {
+				t.Errorf("IsFileSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Package publish contains the publish window shared by assignments, tutorials and informations.
+// Visibility is always decided by the database clock so a single row and a listing never disagree
+package publish
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/asepnur/meiko_course/src/util/conn"
+)
+
+// QueryPublished is appended to a WHERE clause to only return rows which are visible to students
+const QueryPublished = `AND
+			(publish_at IS NULL OR publish_at <= NOW()) AND
+			(unpublish_at IS NULL OR unpublish_at > NOW())`
+
+// Now returns the current time of the database, it is the time publish windows are compared with
+func Now() (time.Time, error) {
+	var now time.Time
+	err := conn.DB.Get(&now, `SELECT NOW();`)
+	if err != nil {
+		return now, err
+	}
+	return now, nil
+}
+
+// IsPublished returns true when the publish window contains now,
+// a row without publish date is published since it is created
+func IsPublished(publishAt, unpublishAt mysql.NullTime, now time.Time) bool {
+	if publishAt.Valid && now.Before(publishAt.Time) {
+		return false
+	}
+	if unpublishAt.Valid && !now.Before(unpublishAt.Time) {
+		return false
+	}
+	return true
+}
+
+// Update sets the publish window of the row on the table
+func Update(table string, id int64, publishAt, unpublishAt mysql.NullTime, tx *sqlx.Tx) error {
+	queryPublishAt := "(NULL)"
+	if publishAt.Valid {
+		queryPublishAt = fmt.Sprintf("('%s')", publishAt.Time.Format("2006-01-02 15:04:05"))
+	}
+	queryUnpublishAt := "(NULL)"
+	if unpublishAt.Valid {
+		queryUnpublishAt = fmt.Sprintf("('%s')", unpublishAt.Time.Format("2006-01-02 15:04:05"))
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE
+			%s
+		SET
+			publish_at = %s,
+			unpublish_at = %s
+		WHERE
+			id = (%d);
+	`, table, queryPublishAt, queryUnpublishAt, id)
+
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query)
+	} else {
+		_, err = conn.DB.Exec(query)
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package publish
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestIsPublished(t *testing.T) {
+	now := time.Date(2017, 9, 1, 8, 0, 0, 0, time.Local)
+	at := func(d time.Duration) mysql.NullTime {
+		return mysql.NullTime{Time: now.Add(d), Valid: true}
+	}
+	cases := []struct {
+		name        string
+		publishAt   mysql.NullTime
+		unpublishAt mysql.NullTime
+		published   bool
+	}{
+		{"no window", mysql.NullTime{}, mysql.NullTime{}, true},
+		{"publish later", at(time.Hour), mysql.NullTime{}, false},
+		{"published now", at(0), mysql.NullTime{}, true},
+		{"unpublished now", mysql.NullTime{}, at(0), false},
+		{"unpublish later", at(-time.Hour), at(time.Hour), true},
+		{"unpublished", at(-2 * time.Hour), at(-time.Hour), false},
+	}
+	for _, c := range cases {
+		if published := IsPublished(c.publishAt, c.unpublishAt, now); published != c.published {
+			t.Errorf("IsPublished() %s expected %t, got %t", c.name, c.published, published)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package sandbox
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Language is how source files of a language are compiled and run inside a box
+type Language struct {
+	Name      string
+	Extension string
+	// Tool is the compiler or the interpreter of the language, it is looked up on PATH
+	Tool string
+	// env returns the environment variables of the language with dir as the box directory inside the sandbox
+	env func(dir string) []string
+	// compile returns the compile command of the source files, nil when the language is interpreted
+	compile func(tool string, files []string) []string
+	run     func(tool string, files []string) []string
+}
+
+// Languages is every supported language keyed by the file extension
+var Languages = map[string]Language{
+	"go": {
+		Name:      "Go",
+		Extension: "go",
+		Tool:      "go",
+		// module mode is off so nothing is downloaded
+		env: func(dir string) []string {
+			return []string{"GO111MODULE=off", "GOTOOLCHAIN=local", "CGO_ENABLED=0",
+				"GOCACHE=" + filepath.Join(dir, ".cache"), "GOPATH=" + filepath.Join(dir, ".gopath")}
+		},
+		compile: func(tool string, files []string) []string {
+			return append([]string{tool, "build", "-o", "main"}, files...)
+		},
+		run: func(tool string, files []string) []string {
+			return []string{"./main"}
+		},
+	},
+	"py": {
+		Name:      "Python",
+		Extension: "py",
+		Tool:      "python3",
+		run: func(tool string, files []string) []string {
+			return []string{tool, entry(files, "main.py")}
+		},
+	},
+	"c": {
+		Name:      "C",
+		Extension: "c",
+		Tool:      "gcc",
+		compile: func(tool string, files []string) []string {
+			return append(append([]string{tool, "-O2", "-std=c11", "-o", "main"}, files...), "-lm")
+		},
+		run: func(tool string, files []string) []string {
+			return []string{"./main"}
+		},
+	},
+}
+
+// Compile compiles the source files inside the box, an interpreted language is not compiled
+// and returns an ok result. The result status is not ok when the source can not be compiled
+func (b *Box) Compile(lang Language, files []string) (Result, error) {
+	if lang.compile == nil {
+		return Result{Status: StatusOK}, nil
+	}
+	tool, err := lookTool(lang)
+	if err != nil {
+		return Result{}, err
+	}
+	return b.Exec(lang.compile(tool, files), b.env(lang), "", Limit{
+		Time:   CompileTime,
+		Output: DefaultOutput,
+	})
+}
+
+// Run runs the compiled source files with stdin as the input
+func (b *Box) Run(lang Language, files []string, stdin string, limit Limit) (Result, error) {
+	tool, err := lookTool(lang)
+	if err != nil {
+		return Result{}, err
+	}
+	return b.Exec(lang.run(tool, files), b.env(lang), stdin, limit)
+}
+
+func (b *Box) env(lang Language) []string {
+	if lang.env == nil {
+		return nil
+	}
+	return lang.env(WorkDir)
+}
+
+// lookTool returns the path of the language tool on the box PATH, the path is the same inside the box
+// because the directory is mounted there
+func lookTool(lang Language) (string, error) {
+	for _, dir := range filepath.SplitList(Path) {
+		tool := filepath.Join(dir, lang.Tool)
+		info, err := os.Stat(tool)
+		if err == nil && !info.IsDir() && info.Mode()&0111 != 0 {
+			return tool, nil
+		}
+	}
+	return "", fmt.Errorf("%s is not installed", lang.Name)
+}
+
+// entry returns the file named main when it exists otherwise the first file by name
+func entry(files []string, main string) string {
+	sorted := make([]string, len(files))
+	copy(sorted, files)
+	sort.Strings(sorted)
+	for _, val := range sorted {
+		if filepath.Base(val) == main {
+			return val
+		}
+	}
+	if len(sorted) < 1 {
+		return main
+	}
+	return sorted[0]
+}
@@ -0,0 +1,338 @@
+// Package sandbox compiles and runs untrusted programs with cpu, memory, file, process, output and time limits.
+// Programs run as an unprivileged user on their own mount, pid and network namespace inside a root directory
+// which only contains the read only toolchain and the box, so they can not reach the network or the server files.
+// Creating the namespaces, mounting the root directory and dropping to the unprivileged user need root, so the
+// server has to run as root (or inside a container as its root user) for the autograder to work
+package sandbox
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// This constant is the status of a finished program
+const (
+	StatusOK           = 0
+	StatusTimeout      = 1
+	StatusRuntimeError = 2
+	StatusOutputLimit  = 3
+)
+
+// This constant is the default limit of compiling and running a program
+const (
+	DefaultMemory = 256 << 20
+	DefaultOutput = 64 << 10
+	CompileTime   = 60 * time.Second
+	// MaxFileSize is the largest file a program may write, it has to fit a compiled binary
+	MaxFileSize = 64 << 20
+	// MaxProcesses is the number of processes the sandbox user may have, it stops fork bombs
+	MaxProcesses = 64
+	// NobodyID is the default user and group programs run as
+	NobodyID = 65534
+	// WorkDir is where the box directory is mounted inside the sandbox
+	WorkDir = "/box"
+)
+
+// Statuses is the name of every program status
+var Statuses = map[int8]string{
+	StatusOK:           "ok",
+	StatusTimeout:      "timeout",
+	StatusRuntimeError: "runtime_error",
+	StatusOutputLimit:  "output_limit",
+}
+
+// UserID and GroupID is the unprivileged user programs run as, it should be dedicated to the sandbox
+// because the process limit is counted on every process of the user
+var (
+	UserID  = NobodyID
+	GroupID = NobodyID
+)
+
+// Path is the PATH of programs inside the box, the compilers and the interpreters are only looked up here
+// because they have to be mounted inside the box
+var Path = "/usr/local/go/bin:/usr/local/bin:/usr/bin:/bin"
+
+// Mounts is every path of the host which is mounted read only inside the box, it has to contain the
+// compilers, the interpreters and their libraries. Paths which do not exist are skipped
+var Mounts = []string{
+	"/bin", "/lib", "/lib32", "/lib64", "/usr/bin", "/usr/lib", "/usr/lib32", "/usr/lib64", "/usr/libexec",
+	"/usr/include", "/usr/local/bin", "/usr/local/lib", "/usr/local/go", "/etc/alternatives", "/etc/ld.so.cache",
+}
+
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// setup creates the root directory of the sandbox from $1 the root, $2 the box directory and $3 the mounts
+// separated by new line, proc only shows the pid namespace. It reports on fd 3 so a failed setup is not
+// mistaken for a failed program
+const setup = `set -e
+mount --make-rprivate /
+mount -t tmpfs -o size=16m,mode=0755,nosuid,nodev sandbox "$1"
+echo "$3" | while read -r path; do
+	[ -n "$path" ] || continue
+	target="$1$path"
+	if [ -L "$path" ]; then
+		mkdir -p "$(dirname "$target")"
+		ln -s "$(readlink "$path")" "$target"
+		continue
+	elif [ -d "$path" ]; then
+		mkdir -p "$target"
+	elif [ -f "$path" ]; then
+		mkdir -p "$(dirname "$target")"
+		touch "$target"
+	else
+		continue
+	fi
+	mount --bind "$path" "$target"
+	mount -o remount,bind,ro,nosuid,nodev "$target"
+done
+mkdir -p "$1/box" "$1/dev" "$1/proc" "$1/tmp"
+mount -t proc -o nosuid,nodev,noexec proc "$1/proc"
+chmod 1777 "$1/tmp"
+mount --bind "$2" "$1/box"
+mount -o remount,bind,nosuid,nodev "$1/box"
+for dev in null zero urandom; do
+	touch "$1/dev/$dev"
+	mount --bind "/dev/$dev" "$1/dev/$dev"
+done
+echo ok >&3
+exec 3>&-
+set +e
+`
+
+// Limit is the resource limit of a program, zero Memory is unlimited
+type Limit struct {
+	Time   time.Duration
+	Memory int64
+	Output int64
+}
+
+// Result is how a program finished with its captured output, output beyond the limit is dropped.
+// ExitCode is 128 plus the signal number when the program is killed by a signal
+type Result struct {
+	Status   int8
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	Duration time.Duration
+}
+
+// Box is a temporary directory where a submission is compiled and run, Dir is the directory on the host
+// which is seen as WorkDir by the programs
+type Box struct {
+	Dir  string
+	base string
+	root string
+}
+
+type limitWriter struct {
+	buf      bytes.Buffer
+	max      int64
+	exceeded bool
+	onExceed func()
+}
+
+func (w *limitWriter) Write(p []byte) (int, error) {
+	left := w.max - int64(w.buf.Len())
+	if int64(len(p)) > left {
+		if left > 0 {
+			w.buf.Write(p[:left])
+		}
+		if !w.exceeded {
+			w.exceeded = true
+			w.onExceed()
+		}
+		return len(p), nil
+	}
+	return w.buf.Write(p)
+}
+
+// New creates an empty box, the box has to be closed to remove its directory. The server has to run as root
+// to create the namespaces and switch to the sandbox user, no program is run without the isolation
+func New() (*Box, error) {
+	if os.Getuid() != 0 {
+		return nil, fmt.Errorf("Sandbox has to run as root")
+	}
+
+	base, err := ioutil.TempDir("", "sandbox")
+	if err != nil {
+		return nil, err
+	}
+	b := &Box{
+		Dir:  filepath.Join(base, "box"),
+		base: base,
+		root: filepath.Join(base, "root"),
+	}
+	for _, dir := range []string{b.Dir, b.root} {
+		err = os.Mkdir(dir, 0700)
+		if err != nil {
+			os.RemoveAll(base)
+			return nil, err
+		}
+	}
+	err = os.Chown(b.Dir, UserID, GroupID)
+	if err != nil {
+		os.RemoveAll(base)
+		return nil, err
+	}
+	return b, nil
+}
+
+// Close removes the box with everything the program has written
+func (b *Box) Close() error {
+	return os.RemoveAll(b.base)
+}
+
+// Write copies a source file into the box, the name is made safe and unique
+// and the returned name is the one the file is saved as
+func (b *Box) Write(name string, r io.Reader) (string, error) {
+	name = unsafeName.ReplaceAllString(filepath.Base(name), "_")
+	if strings.HasPrefix(name, ".") {
+		name = "_" + name
+	}
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for i := 1; ; i++ {
+		if _, err := os.Stat(filepath.Join(b.Dir, name)); os.IsNotExist(err) {
+			break
+		}
+		name = fmt.Sprintf("%s_%d%s", base, i, ext)
+	}
+
+	f, err := os.OpenFile(filepath.Join(b.Dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return name, err
+	}
+	defer f.Close()
+	_, err = io.Copy(f, r)
+	return name, err
+}
+
+// Exec runs the command inside the box with stdin as its input. The limits are set with prlimit
+// before the command is executed, the whole pid namespace is killed once the time limit is reached
+func (b *Box) Exec(command, env []string, stdin string, limit Limit) (Result, error) {
+	var result Result
+	if len(command) < 1 {
+		return result, fmt.Errorf("Command can not be empty")
+	}
+	chroot, err := exec.LookPath("chroot")
+	if err != nil {
+		return result, fmt.Errorf("Sandbox needs chroot to be installed")
+	}
+	prlimit, err := exec.LookPath("prlimit")
+	if err != nil {
+		return result, fmt.Errorf("Sandbox needs prlimit to be installed")
+	}
+
+	// the program gets SIGXCPU on the soft cpu limit which is a little longer than the time limit
+	// so the time limit is reported first, memory is limited on the data segment instead of the
+	// address space because the go runtime reserves far more address space than it uses
+	cpu := int64(math.Ceil(limit.Time.Seconds())) + 1
+	limits := fmt.Sprintf("--cpu=%d:%d --fsize=%d --nproc=%d", cpu, cpu+1, MaxFileSize, MaxProcesses)
+	if limit.Memory > 0 {
+		limits += fmt.Sprintf(" --data=%d", limit.Memory)
+	}
+	script := setup + fmt.Sprintf(`root="$1"
+shift 3
+exec %s %s %s --userspec=%d:%d "$root" /bin/sh -c 'cd %s && exec "$@"' sandbox "$@"`,
+		prlimit, limits, chroot, UserID, GroupID, WorkDir)
+	args := append([]string{"-c", script, "sandbox", b.root, b.Dir, strings.Join(Mounts, "\n")}, command...)
+
+	ready, readyWriter, err := os.Pipe()
+	if err != nil {
+		return result, err
+	}
+	defer ready.Close()
+
+	cmd := exec.Command("/bin/sh", args...)
+	cmd.Dir = b.Dir
+	cmd.Env = append([]string{"PATH=" + Path + ":/usr/sbin:/sbin", "HOME=" + WorkDir, "TMPDIR=" + WorkDir}, env...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.ExtraFiles = []*os.File{readyWriter}
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid:    true,
+		Cloneflags: syscall.CLONE_NEWNS | syscall.CLONE_NEWPID | syscall.CLONE_NEWNET | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS,
+	}
+
+	// the program is the init of the pid namespace, every process it starts dies with it
+	kill := func() {
+		if cmd.Process != nil {
+			syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+			cmd.Process.Kill()
+		}
+	}
+	stdout := &limitWriter{max: limit.Output, onExceed: kill}
+	stderr := &limitWriter{max: limit.Output, onExceed: kill}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err = cmd.Start()
+	readyWriter.Close()
+	if err != nil {
+		return result, err
+	}
+	// the time limit starts once the root directory is ready
+	isReady, _ := ioutil.ReadAll(ready)
+	if strings.TrimSpace(string(isReady)) != "ok" {
+		cmd.Wait()
+		return result, fmt.Errorf("Sandbox can not be set up: %s", strings.TrimSpace(stderr.buf.String()))
+	}
+
+	start := time.Now()
+	timeout := make(chan struct{})
+	timer := time.AfterFunc(limit.Time, func() {
+		close(timeout)
+		kill()
+	})
+	err = cmd.Wait()
+	timer.Stop()
+
+	result = Result{
+		Status:   StatusOK,
+		Stdout:   stdout.buf.String(),
+		Stderr:   stderr.buf.String(),
+		ExitCode: cmd.ProcessState.ExitCode(),
+		Duration: time.Since(start),
+	}
+	if status, ok := cmd.ProcessState.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		result.ExitCode = 128 + int(status.Signal())
+	}
+	isTimeout := result.ExitCode == 128+int(syscall.SIGXCPU)
+	select {
+	case <-timeout:
+		isTimeout = true
+	default:
+	}
+
+	switch {
+	case isTimeout:
+		result.Status = StatusTimeout
+	case stdout.exceeded || stderr.exceeded:
+		result.Status = StatusOutputLimit
+	case err != nil:
+		result.Status = StatusRuntimeError
+	}
+	return result, nil
+}
+
+// IsSameOutput compares the program output with the expected output,
+// trailing spaces of every line and trailing empty lines are ignored
+func IsSameOutput(expected, actual string) bool {
+	normalize := func(text string) string {
+		lines := strings.Split(strings.Replace(text, "\r\n", "\n", -1), "\n")
+		for i := range lines {
+			lines[i] = strings.TrimRight(lines[i], " \t")
+		}
+		return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+	}
+	return normalize(expected) == normalize(actual)
+}
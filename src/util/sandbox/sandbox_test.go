@@ -0,0 +1,136 @@
+package sandbox
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func newBox(t *testing.T) *Box {
+	box, err := New()
+	if err != nil {
+		t.Skipf("sandbox is not available: %s", err.Error())
+	}
+	// the sandbox needs namespaces which are not allowed everywhere
+	_, err = box.Exec([]string{"/bin/true"}, nil, "", Limit{Time: 5 * time.Second, Output: DefaultOutput})
+	if err != nil {
+		box.Close()
+		t.Skipf("sandbox is not available: %s", err.Error())
+	}
+	return box
+}
+
+func TestIsSameOutput(t *testing.T) {
+	cases := []struct {
+		expected string
+		actual   string
+		same     bool
+	}{
+		{"3\n", "3", true},
+		{"1 2\n3\n", "1 2  \r\n3\r\n\n\n", true},
+		{"a\nb", "a\n\nb", false},
+		{"12", "1 2", false},
+		{"", "\n", true},
+	}
+	for _, c := range cases {
+		if same := IsSameOutput(c.expected, c.actual); same != c.same {
+			t.Errorf("IsSameOutput(%q, %q) expected %t, got %t", c.expected, c.actual, c.same, same)
+		}
+	}
+}
+
+func TestExec(t *testing.T) {
+	box := newBox(t)
+	defer box.Close()
+	limit := Limit{Time: 5 * time.Second, Memory: DefaultMemory, Output: DefaultOutput}
+
+	result, err := box.Exec([]string{"/bin/cat"}, nil, "hello\n", limit)
+	if err != nil {
+		t.Fatalf("Exec() expected no error, got %s", err.Error())
+	}
+	if result.Status != StatusOK || result.Stdout != "hello\n" {
+		t.Errorf("Exec() cat expected ok with the input, got %s %q", Statuses[result.Status], result.Stdout)
+	}
+
+	result, _ = box.Exec([]string{"/bin/sh", "-c", "exit 3"}, nil, "", limit)
+	if result.Status != StatusRuntimeError || result.ExitCode != 3 {
+		t.Errorf("Exec() exit 3 expected runtime error with code 3, got %s %d", Statuses[result.Status], result.ExitCode)
+	}
+
+	result, _ = box.Exec([]string{"/bin/sleep", "10"}, nil, "", Limit{Time: 200 * time.Millisecond, Output: DefaultOutput})
+	if result.Status != StatusTimeout {
+		t.Errorf("Exec() sleep expected timeout, got %s", Statuses[result.Status])
+	}
+	if result.Duration > 5*time.Second {
+		t.Errorf("Exec() sleep expected to be killed, took %s", result.Duration)
+	}
+
+	result, _ = box.Exec([]string{"/bin/sh", "-c", "while true; do echo spam; done"}, nil, "", Limit{Time: 5 * time.Second, Output: 1024})
+	if result.Status != StatusOutputLimit || len(result.Stdout) != 1024 {
+		t.Errorf("Exec() spam expected output limit with 1024 bytes, got %s %d", Statuses[result.Status], len(result.Stdout))
+	}
+}
+
+func TestExecIsolation(t *testing.T) {
+	box := newBox(t)
+	defer box.Close()
+	limit := Limit{Time: 5 * time.Second, Memory: DefaultMemory, Output: DefaultOutput}
+
+	result, _ := box.Exec([]string{"/bin/sh", "-c", "id -u"}, nil, "", limit)
+	if strings.TrimSpace(result.Stdout) != fmt.Sprint(UserID) {
+		t.Errorf("Exec() id expected the sandbox user %d, got %q", UserID, result.Stdout)
+	}
+
+	result, _ = box.Exec([]string{"/bin/sh", "-c", "cat /etc/passwd"}, nil, "", limit)
+	if result.Status != StatusRuntimeError {
+		t.Errorf("Exec() expected the host files to be hidden, got %s %q", Statuses[result.Status], result.Stdout)
+	}
+
+	result, _ = box.Exec([]string{"/bin/sh", "-c", "while true; do :; done"}, nil, "", Limit{Time: 500 * time.Millisecond, Output: DefaultOutput})
+	if result.Status != StatusTimeout || result.ExitCode != 128+int(syscall.SIGKILL) {
+		t.Errorf("Exec() busy loop expected to be killed, got %s %d", Statuses[result.Status], result.ExitCode)
+	}
+
+	result, _ = box.Exec([]string{"/bin/sh", "-c", "touch /usr/bin/sandbox"}, nil, "", limit)
+	if result.Status != StatusRuntimeError {
+		t.Errorf("Exec() expected the toolchain to be read only, got %s", Statuses[result.Status])
+	}
+
+	result, _ = box.Exec([]string{"/bin/sh", "-c", "echo ok > out && cat out && pwd"}, nil, "", limit)
+	if result.Status != StatusOK || result.Stdout != "ok\n"+WorkDir+"\n" {
+		t.Errorf("Exec() expected the box to be writable, got %s %q", Statuses[result.Status], result.Stdout)
+	}
+}
+
+func TestRunC(t *testing.T) {
+	if _, err := lookTool(Languages["c"]); err != nil {
+		t.Skip("gcc is not installed")
+	}
+	box := newBox(t)
+	defer box.Close()
+
+	name, err := box.Write("../sum.c", strings.NewReader(`#include <stdio.h>
+int main(void) { int a, b; scanf("%d %d", &a, &b); printf("%d\n", a + b); return 0; }
+`))
+	if err != nil {
+		t.Fatalf("Write() expected no error, got %s", err.Error())
+	}
+	if name != "sum.c" {
+		t.Errorf("Write() expected the name sum.c, got %s", name)
+	}
+
+	lang := Languages["c"]
+	result, err := box.Compile(lang, []string{name})
+	if err != nil || result.Status != StatusOK {
+		t.Fatalf("Compile() expected ok, got %v %+v", err, result)
+	}
+	result, err = box.Run(lang, []string{name}, "2 3\n", Limit{Time: 5 * time.Second, Memory: DefaultMemory, Output: DefaultOutput})
+	if err != nil {
+		t.Fatalf("Run() expected no error, got %s", err.Error())
+	}
+	if !IsSameOutput("5", result.Stdout) {
+		t.Errorf("Run() expected 5, got %q", result.Stdout)
+	}
+}
@@ -0,0 +1,198 @@
+package similarity
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// MaxSize is the maximum size of a file which is read for text extraction
+const MaxSize = 10 << 20
+
+// PlainExtensions is the list of plain text and source code extension which is read as is
+var PlainExtensions = []string{
+	"txt", "md", "csv", "sql", "c", "h", "cpp", "hpp", "cs", "java", "py", "go",
+	"js", "ts", "php", "rb", "html", "css", "xml", "json", "kt", "swift", "m", "r",
+}
+
+// IsSupported is used to check whether text can be extracted from the file extension or not
+func IsSupported(ext string) bool {
+	ext = strings.ToLower(ext)
+	if ext == "docx" || ext == "pdf" {
+		return true
+	}
+	for _, val := range PlainExtensions {
+		if val == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// Extract returns the text content of the file based on its extension
+func Extract(r io.Reader, ext string) (string, error) {
+	ext = strings.ToLower(ext)
+	if !IsSupported(ext) {
+		return "", fmt.Errorf("Unsupported file extension %s", ext)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(r, MaxSize))
+	if err != nil {
+		return "", err
+	}
+
+	switch ext {
+	case "docx":
+		return extractDOCX(data)
+	case "pdf":
+		return extractPDF(data), nil
+	}
+	return string(data), nil
+}
+
+// extractDOCX reads the text runs of word/document.xml, every paragraph is written on its own line
+func extractDOCX(data []byte) (string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", err
+	}
+
+	for _, f := range zr.File {
+		if f.Name != "word/document.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		defer rc.Close()
+
+		var buf bytes.Buffer
+		isText := false
+		decoder := xml.NewDecoder(io.LimitReader(rc, MaxSize))
+		for {
+			token, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", err
+			}
+
+			switch t := token.(type) {
+			case xml.StartElement:
+				switch t.Name.Local {
+				case "t":
+					isText = true
+				case "tab":
+					buf.WriteByte('\t')
+				}
+			case xml.EndElement:
+				switch t.Name.Local {
+				case "t":
+					isText = false
+				case "p":
+					buf.WriteByte('\n')
+				}
+			case xml.CharData:
+				if isText {
+					buf.Write(t)
+				}
+			}
+		}
+		return buf.String(), nil
+	}
+	return "", fmt.Errorf("Invalid docx file")
+}
+
+// extractPDF reads literal strings shown by text operators of every content stream,
+// compressed stream is inflated first. Text drawn with hex strings of embedded fonts can not be read
+func extractPDF(data []byte) string {
+	var buf bytes.Buffer
+	rest := data
+	for {
+		start := bytes.Index(rest, []byte("stream"))
+		if start < 0 {
+			break
+		}
+		rest = rest[start+len("stream"):]
+		rest = bytes.TrimLeft(rest, "\r\n")
+
+		end := bytes.Index(rest, []byte("endstream"))
+		if end < 0 {
+			break
+		}
+		content := rest[:end]
+		rest = rest[end+len("endstream"):]
+
+		if zr, err := zlib.NewReader(bytes.NewReader(content)); err == nil {
+			inflated, err := ioutil.ReadAll(io.LimitReader(zr, MaxSize))
+			zr.Close()
+			if err == nil || len(inflated) > 0 {
+				content = inflated
+			}
+		}
+		extractPDFText(content, &buf)
+	}
+	return buf.String()
+}
+
+// extractPDFText writes the literal strings inside BT and ET operators of a content stream
+func extractPDFText(content []byte, buf *bytes.Buffer) {
+	isText := false
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case !isText && c == 'B' && i+1 < len(content) && content[i+1] == 'T':
+			isText = true
+			i++
+		case isText && c == 'E' && i+1 < len(content) && content[i+1] == 'T':
+			isText = false
+			buf.WriteByte('\n')
+			i++
+		case isText && c == '(':
+			i = readPDFString(content, i+1, buf)
+			buf.WriteByte(' ')
+		}
+	}
+}
+
+// readPDFString writes the literal string started at i and returns the index of its closing parenthesis
+func readPDFString(content []byte, i int, buf *bytes.Buffer) int {
+	depth := 1
+	for ; i < len(content); i++ {
+		c := content[i]
+		switch c {
+		case '\\':
+			if i+1 >= len(content) {
+				return i
+			}
+			i++
+			switch content[i] {
+			case 'n':
+				buf.WriteByte('\n')
+			case 'r', 't':
+				buf.WriteByte(' ')
+			case '(', ')', '\\':
+				buf.WriteByte(content[i])
+			}
+		case '(':
+			depth++
+			buf.WriteByte(c)
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+			buf.WriteByte(c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return i
+}
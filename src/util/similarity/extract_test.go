@@ -0,0 +1,87 @@
+package similarity
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"strings"
+	"testing"
+)
+
+func TestIsSupported(t *testing.T) {
+	cases := map[string]bool{
+		"txt":  true,
+		"GO":   true,
+		"docx": true,
+		"pdf":  true,
+		"zip":  false,
+		"png":  false,
+		"":     false,
+	}
+	for ext, want := range cases {
+		if got := IsSupported(ext); got != want {
+			t.Errorf("IsSupported(%s) expected %v, got %v", ext, want, got)
+		}
+	}
+}
+
+func TestExtractPlain(t *testing.T) {
+	got, err := Extract(strings.NewReader("package main\n"), "go")
+	if err != nil || got != "package main\n" {
+		t.Errorf("Extract() got %q, %v", got, err)
+	}
+
+	if _, err := Extract(strings.NewReader("x"), "zip"); err == nil {
+		t.Errorf("Extract() unsupported extension expected error")
+	}
+}
+
+func TestExtractDOCX(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	f, _ := zw.Create("word/document.xml")
+	f.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:r><w:t>Hello</w:t></w:r><w:r><w:tab/><w:t xml:space="preserve">world</w:t></w:r></w:p>
+<w:p><w:r><w:t>Second &amp; last</w:t></w:r></w:p>
+</w:body></w:document>`))
+	zw.Close()
+
+	got, err := Extract(bytes.NewReader(buf.Bytes()), "docx")
+	if err != nil {
+		t.Fatalf("Extract() docx got error %s", err.Error())
+	}
+	if got != "Hello\tworld\nSecond & last\n" {
+		t.Errorf("Extract() docx got %q", got)
+	}
+
+	if _, err := Extract(strings.NewReader("not a zip"), "docx"); err == nil {
+		t.Errorf("Extract() invalid docx expected error")
+	}
+}
+
+func TestExtractPDF(t *testing.T) {
+	plain := "BT /F1 12 Tf 72 712 Td (Hello \\(pdf\\)) Tj ET\nBT [(Wor) -20 (ld)] TJ ET"
+
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	zw.Write([]byte("BT (Compressed text) Tj ET"))
+	zw.Close()
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n1 0 obj\n<< /Length 10 >>\nstream\n")
+	pdf.WriteString(plain)
+	pdf.WriteString("\nendstream\nendobj\n2 0 obj\n<< /Filter /FlateDecode >>\nstream\r\n")
+	pdf.Write(compressed.Bytes())
+	pdf.WriteString("\nendstream\nendobj\n%%EOF")
+
+	got, err := Extract(bytes.NewReader(pdf.Bytes()), "pdf")
+	if err != nil {
+		t.Fatalf("Extract() pdf got error %s", err.Error())
+	}
+	for _, want := range []string{"Hello (pdf)", "Wor ld", "Compressed text"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Extract() pdf expected to contain %q, got %q", want, got)
+		}
+	}
+}
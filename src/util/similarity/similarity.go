@@ -0,0 +1,187 @@
+// Package similarity contains k-gram winnowing fingerprint used for detecting copied text between documents
+package similarity
+
+import (
+	"hash/fnv"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+)
+
+// This constant is the default noise threshold and window size, any match which is at least
+// K + W - 1 normalized characters long is guaranteed to be detected
+const (
+	DefaultK = 15
+	DefaultW = 10
+)
+
+// Print is a selected k-gram hash with its position on the original text
+type Print struct {
+	Hash  uint64
+	Start int
+	End   int
+}
+
+// Region is a range of the original text which matches the other document
+type Region struct {
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Text  string `json:"text"`
+}
+
+// Result is the comparison of two documents, ScoreA is the percentage of A fingerprint found in B and vice versa
+type Result struct {
+	Score   float64
+	ScoreA  float64
+	ScoreB  float64
+	RegionA []Region
+	RegionB []Region
+}
+
+// normalize keeps only letters and digits in lower case so formatting and whitespace do not hide a copy,
+// offsets holds the byte offset of every normalized rune on the original text
+func normalize(text string) ([]rune, []int) {
+	var runes []rune
+	var offsets []int
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			runes = append(runes, unicode.ToLower(r))
+			offsets = append(offsets, i)
+		}
+	}
+	return runes, offsets
+}
+
+// Fingerprint returns the winnowed fingerprint of the text, the smallest hash of every window
+// of w consecutive k-grams is selected and the rightmost one is taken on ties
+func Fingerprint(text string, k, w int) []Print {
+	if k < 1 || w < 1 {
+		return nil
+	}
+
+	runes, offsets := normalize(text)
+	if len(runes) < k {
+		return nil
+	}
+
+	hashes := make([]uint64, len(runes)-k+1)
+	buf := make([]byte, 0, k*utf8.UTFMax)
+	for i := range hashes {
+		buf = buf[:0]
+		for _, r := range runes[i : i+k] {
+			buf = append(buf, string(r)...)
+		}
+		h := fnv.New64a()
+		h.Write(buf)
+		hashes[i] = h.Sum64()
+	}
+
+	end := func(i int) int {
+		last := offsets[i+k-1]
+		_, size := utf8.DecodeRuneInString(text[last:])
+		return last + size
+	}
+
+	if len(hashes) < w {
+		w = len(hashes)
+	}
+
+	var prints []Print
+	selected := -1
+	for i := 0; i+w <= len(hashes); i++ {
+		min := i
+		for j := i; j < i+w; j++ {
+			if hashes[j] <= hashes[min] {
+				min = j
+			}
+		}
+		if min != selected {
+			selected = min
+			prints = append(prints, Print{
+				Hash:  hashes[min],
+				Start: offsets[min],
+				End:   end(min),
+			})
+		}
+	}
+	return prints
+}
+
+// Compare returns the similarity of two fingerprints with the matching regions on both texts
+func Compare(textA string, a []Print, textB string, b []Print) Result {
+	var result Result
+	if len(a) < 1 || len(b) < 1 {
+		return result
+	}
+
+	hashA := map[uint64]bool{}
+	for _, val := range a {
+		hashA[val.Hash] = true
+	}
+	hashB := map[uint64]bool{}
+	for _, val := range b {
+		hashB[val.Hash] = true
+	}
+
+	var matchA, matchB []Print
+	for _, val := range a {
+		if hashB[val.Hash] {
+			matchA = append(matchA, val)
+		}
+	}
+	for _, val := range b {
+		if hashA[val.Hash] {
+			matchB = append(matchB, val)
+		}
+	}
+
+	result.ScoreA = percentage(len(matchA), len(a))
+	result.ScoreB = percentage(len(matchB), len(b))
+	result.Score = result.ScoreA
+	if result.ScoreB > result.Score {
+		result.Score = result.ScoreB
+	}
+	result.RegionA = regions(textA, matchA)
+	result.RegionB = regions(textB, matchB)
+	return result
+}
+
+func percentage(n, total int) float64 {
+	if total < 1 {
+		return 0
+	}
+	p := float64(n) / float64(total) * 100
+	return float64(int64(p*100+0.5)) / 100
+}
+
+// regions merges the overlapping matched prints into continuous ranges of the text
+func regions(text string, prints []Print) []Region {
+	if len(prints) < 1 {
+		return nil
+	}
+
+	sorted := make([]Print, len(prints))
+	copy(sorted, prints)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start < sorted[j].Start
+	})
+
+	var result []Region
+	current := Region{Start: sorted[0].Start, End: sorted[0].End}
+	for _, val := range sorted[1:] {
+		if val.Start <= current.End {
+			if val.End > current.End {
+				current.End = val.End
+			}
+			continue
+		}
+		result = append(result, current)
+		current = Region{Start: val.Start, End: val.End}
+	}
+	result = append(result, current)
+
+	for i := range result {
+		result[i].Text = text[result[i].Start:result[i].End]
+	}
+	return result
+}
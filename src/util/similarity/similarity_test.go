@@ -0,0 +1,88 @@
+package similarity
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	textOriginal = `The experiment measures the period of a simple pendulum for several lengths
+of string. We found that the period grows with the square root of the length, which agrees with theory.`
+	textCopied = `Introduction. THE EXPERIMENT measures the period of a simple pendulum
+for several lengths of string! Our own conclusion is different.`
+	textOther = `Resistors in series add their resistance while resistors in parallel add their conductance,
+this lab verifies both rules with a multimeter.`
+)
+
+func TestFingerprint(t *testing.T) {
+	if prints := Fingerprint("short", DefaultK, DefaultW); prints != nil {
+		t.Errorf("Fingerprint() of text shorter than k expected nil, got %v", prints)
+	}
+	if prints := Fingerprint(textOriginal, 0, DefaultW); prints != nil {
+		t.Errorf("Fingerprint() with invalid k expected nil, got %v", prints)
+	}
+
+	prints := Fingerprint(textOriginal, DefaultK, DefaultW)
+	if len(prints) < 1 {
+		t.Fatalf("Fingerprint() expected prints")
+	}
+	for i, val := range prints {
+		if val.Start < 0 || val.End > len(textOriginal) || val.Start >= val.End {
+			t.Errorf("Fingerprint() print %d has invalid range %d-%d", i, val.Start, val.End)
+		}
+		if i > 0 && val.Start <= prints[i-1].Start {
+			t.Errorf("Fingerprint() prints are not ordered by position")
+		}
+	}
+
+	// formatting must not change the fingerprint
+	formatted := Fingerprint(strings.ToUpper(strings.Replace(textOriginal, " ", "  ", -1)), DefaultK, DefaultW)
+	if len(formatted) != len(prints) {
+		t.Fatalf("Fingerprint() of formatted text expected %d prints, got %d", len(prints), len(formatted))
+	}
+	for i := range prints {
+		if prints[i].Hash != formatted[i].Hash {
+			t.Errorf("Fingerprint() of formatted text has different hash at %d", i)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	original := Fingerprint(textOriginal, DefaultK, DefaultW)
+	copied := Fingerprint(textCopied, DefaultK, DefaultW)
+	other := Fingerprint(textOther, DefaultK, DefaultW)
+
+	same := Compare(textOriginal, original, textOriginal, original)
+	if same.Score != 100 || same.ScoreA != 100 || same.ScoreB != 100 {
+		t.Errorf("Compare() of the same text expected 100, got %+v", same)
+	}
+	if len(same.RegionA) != 1 {
+		t.Errorf("Compare() of the same text expected a single region, got %d", len(same.RegionA))
+	}
+
+	result := Compare(textOriginal, original, textCopied, copied)
+	if result.Score <= 0 || result.Score >= 100 {
+		t.Errorf("Compare() of copied text expected partial score, got %v", result.Score)
+	}
+	if result.ScoreB <= result.ScoreA {
+		t.Errorf("Compare() expected the shorter copy to have higher score, got %v and %v", result.ScoreA, result.ScoreB)
+	}
+	if len(result.RegionB) < 1 || !strings.Contains(strings.ToLower(result.RegionB[0].Text), "experiment measures the period") {
+		t.Errorf("Compare() expected copied region, got %+v", result.RegionB)
+	}
+	for _, val := range result.RegionA {
+		if textOriginal[val.Start:val.End] != val.Text {
+			t.Errorf("Compare() region text does not match the original text")
+		}
+	}
+
+	unrelated := Compare(textOriginal, original, textOther, other)
+	if unrelated.Score != 0 || len(unrelated.RegionA) != 0 {
+		t.Errorf("Compare() of unrelated text expected 0, got %+v", unrelated)
+	}
+
+	empty := Compare(textOriginal, original, "", nil)
+	if empty.Score != 0 {
+		t.Errorf("Compare() with empty text expected 0, got %v", empty.Score)
+	}
+}
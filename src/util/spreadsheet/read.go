@@ -0,0 +1,215 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MaxReadCells is the maximum number of cells which are read from a sheet
+const MaxReadCells = 200000
+
+// Read is used to read rows of the first sheet from r using the format
+func Read(r io.ReaderAt, size int64, format string) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		return ReadCSV(io.NewSectionReader(r, 0, size))
+	case FormatXLSX:
+		return ReadXLSX(r, size)
+	}
+	return nil, fmt.Errorf("Unsupported format %s", format)
+}
+
+// ReadCSV is used to read every row of a CSV file, rows may have different number of cells
+func ReadCSV(r io.Reader) ([][]string, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	return cr.ReadAll()
+}
+
+// ReadXLSX is used to read rows of the first worksheet of a XLSX workbook,
+// empty cells in the middle of a row are returned as empty string
+func ReadXLSX(r io.ReaderAt, size int64) ([][]string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid XLSX file")
+	}
+
+	files := map[string]*zip.File{}
+	var sheets []string
+	for _, f := range zr.File {
+		files[f.Name] = f
+		if strings.HasPrefix(f.Name, "xl/worksheets/sheet") && strings.HasSuffix(f.Name, ".xml") {
+			sheets = append(sheets, f.Name)
+		}
+	}
+	if len(sheets) < 1 {
+		return nil, fmt.Errorf("XLSX file does not contain any sheet")
+	}
+
+	// sheet1.xml is the first sheet on every common writer, fall back to the lowest sheet number
+	sheet := "xl/worksheets/sheet1.xml"
+	if _, ok := files[sheet]; !ok {
+		sort.Slice(sheets, func(i, j int) bool {
+			return sheetNumber(sheets[i]) < sheetNumber(sheets[j])
+		})
+		sheet = sheets[0]
+	}
+
+	var shared []string
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		shared, err = readSharedStrings(f)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return readSheet(files[sheet], shared)
+}
+
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) < 1 {
+		return t.Text
+	}
+	var b strings.Builder
+	for _, val := range t.Runs {
+		b.WriteString(val.Text)
+	}
+	return b.String()
+}
+
+func readSharedStrings(f *zip.File) ([]string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var sst struct {
+		Items []xlsxText `xml:"si"`
+	}
+	err = xml.NewDecoder(rc).Decode(&sst)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid XLSX shared strings")
+	}
+
+	shared := []string{}
+	for _, val := range sst.Items {
+		shared = append(shared, val.String())
+	}
+	return shared, nil
+}
+
+func readSheet(f *zip.File, shared []string) ([][]string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var ws struct {
+		Rows []struct {
+			Index int `xml:"r,attr"`
+			Cells []struct {
+				Ref    string   `xml:"r,attr"`
+				Type   string   `xml:"t,attr"`
+				Value  string   `xml:"v"`
+				Inline xlsxText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	err = xml.NewDecoder(rc).Decode(&ws)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid XLSX sheet")
+	}
+
+	rows := [][]string{}
+	cells := 0
+	for i, row := range ws.Rows {
+		index := row.Index
+		if index < 1 {
+			index = len(rows) + 1
+		}
+		if index-1 < len(rows) && i > 0 {
+			return nil, fmt.Errorf("Invalid XLSX row order")
+		}
+		if index > MaxReadCells {
+			return nil, fmt.Errorf("XLSX sheet is too large")
+		}
+		for len(rows) < index-1 {
+			rows = append(rows, []string{})
+		}
+
+		values := []string{}
+		for _, c := range row.Cells {
+			col := len(values)
+			if c.Ref != "" {
+				col = ColumnIndex(c.Ref)
+				if col < len(values) {
+					return nil, fmt.Errorf("Invalid XLSX cell %s", c.Ref)
+				}
+			}
+			cells += col - len(values) + 1
+			if cells > MaxReadCells {
+				return nil, fmt.Errorf("XLSX sheet is too large")
+			}
+			for len(values) < col {
+				values = append(values, "")
+			}
+
+			value := c.Value
+			switch c.Type {
+			case "s":
+				n, err := strconv.Atoi(c.Value)
+				if err != nil || n < 0 || n >= len(shared) {
+					return nil, fmt.Errorf("Invalid XLSX shared string on cell %s", c.Ref)
+				}
+				value = shared[n]
+			case "inlineStr":
+				value = c.Inline.String()
+			}
+			values = append(values, value)
+		}
+		rows = append(rows, values)
+	}
+	return rows, nil
+}
+
+// ColumnIndex converts cell reference or column name into zero based column index, A1 = 0, AA = 26.
+// It returns -1 when the reference does not start with a column name
+func ColumnIndex(ref string) int {
+	index := 0
+	n := 0
+	for _, r := range strings.ToUpper(ref) {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A') + 1
+		n++
+		if n > 3 {
+			return -1
+		}
+	}
+	return index - 1
+}
+
+func sheetNumber(name string) int {
+	n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "xl/worksheets/sheet"), ".xml"))
+	if err != nil {
+		return int(^uint(0) >> 1)
+	}
+	return n
+}
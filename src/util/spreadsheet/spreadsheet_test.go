@@ -0,0 +1,209 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestColumnName(t *testing.T) {
+	cases := []struct {
+		index    int
+		expected string
+	}{
+		{index: 0, expected: "A"},
+		{index: 25, expected: "Z"},
+		{index: 26, expected: "AA"},
+		{index: 51, expected: "AZ"},
+		{index: 52, expected: "BA"},
+		{index: 701, expected: "ZZ"},
+		{index: 702, expected: "AAA"},
+	}
+
+	for _, c := range cases {
+		result := ColumnName(c.index)
+		if result != c.expected {
+			t.Errorf("ColumnName(%d) expected %s, got %s", c.index, c.expected, result)
+		}
+	}
+}
+
+func TestIsNumeric(t *testing.T) {
+	cases := []struct {
+		value    string
+		expected bool
+	}{
+		{value: "", expected: false},
+		{value: "0", expected: true},
+		{value: "85.5", expected: true},
+		{value: "-3", expected: true},
+		{value: "0.25", expected: true},
+		{value: "007", expected: false},
+		{value: "1e5", expected: false},
+		{value: "NaN", expected: false},
+		{value: "Inf", expected: false},
+		{value: "abc", expected: false},
+		{value: "1234567890123456", expected: false},
+	}
+
+	for _, c := range cases {
+		result := isNumeric(c.value)
+		if result != c.expected {
+			t.Errorf("isNumeric(%s) expected %t, got %t", c.value, c.expected, result)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, [][]string{
+		{"name", "score"},
+		{"Doe, John", "80"},
+	})
+	if err != nil {
+		t.Fatalf("WriteCSV got error %s", err.Error())
+	}
+
+	expected := "name,score\n\"Doe, John\",80\n"
+	if buf.String() != expected {
+		t.Errorf("WriteCSV expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteXLSX(&buf, "Roster A/2017", [][]string{
+		{"name", "score"},
+		{"<John & Jane>", "80.5"},
+	})
+	if err != nil {
+		t.Fatalf("WriteXLSX got error %s", err.Error())
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("WriteXLSX result is not a zip file: %s", err.Error())
+	}
+
+	contents := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("Failed to open %s: %s", f.Name, err.Error())
+		}
+		b, _ := ioutil.ReadAll(rc)
+		rc.Close()
+		contents[f.Name] = string(b)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		if _, ok := contents[name]; !ok {
+			t.Errorf("WriteXLSX missing part %s", name)
+		}
+	}
+
+	if !strings.Contains(contents["xl/workbook.xml"], `name="Roster A 2017"`) {
+		t.Errorf("WriteXLSX sheet name is not sanitized: %s", contents["xl/workbook.xml"])
+	}
+
+	sheet := contents["xl/worksheets/sheet1.xml"]
+	cases := []string{
+		`<c r="A1" t="inlineStr"><is><t xml:space="preserve">name</t></is></c>`,
+		`<c r="A2" t="inlineStr"><is><t xml:space="preserve">&lt;John &amp; Jane&gt;</t></is></c>`,
+		`<c r="B2"><v>80.5</v></c>`,
+	}
+	for _, c := range cases {
+		if !strings.Contains(sheet, c) {
+			t.Errorf("WriteXLSX sheet expected to contain %s", c)
+		}
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	cases := []struct {
+		ref      string
+		expected int
+	}{
+		{ref: "A1", expected: 0},
+		{ref: "z9", expected: 25},
+		{ref: "AA10", expected: 26},
+		{ref: "AZ", expected: 51},
+		{ref: "1", expected: -1},
+		{ref: "ABCD1", expected: -1},
+	}
+	for _, c := range cases {
+		if got := ColumnIndex(c.ref); got != c.expected {
+			t.Errorf("ColumnIndex(%s) expected %d, got %d", c.ref, c.expected, got)
+		}
+	}
+}
+
+func TestReadXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	rows := [][]string{
+		{"name", "score"},
+		{"<John & Jane>", "80.5"},
+		{},
+		{"Doe", "", "007"},
+	}
+	err := WriteXLSX(&buf, "Sheet", rows)
+	if err != nil {
+		t.Fatalf("WriteXLSX got error %s", err.Error())
+	}
+
+	got, err := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()), FormatXLSX)
+	if err != nil {
+		t.Fatalf("Read got error %s", err.Error())
+	}
+	if len(got) != len(rows) {
+		t.Fatalf("Read expected %d rows, got %d", len(rows), len(got))
+	}
+	for i := range rows {
+		if strings.Join(got[i], "|") != strings.Join(rows[i], "|") {
+			t.Errorf("Read row %d expected %q, got %q", i+1, rows[i], got[i])
+		}
+	}
+}
+
+func TestReadXLSX_sharedStrings(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	parts := map[string]string{
+		"xl/sharedStrings.xml": `<sst><si><t>identity</t></si><si><r><t>Jo</t></r><r><t>hn</t></r></si></sst>`,
+		"xl/worksheets/sheet2.xml": `<worksheet><sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1"><v>90</v></c></row>` +
+			`<row r="3"><c r="B3" t="s"><v>1</v></c></row>` +
+			`</sheetData></worksheet>`,
+	}
+	for name, content := range parts {
+		fw, _ := zw.Create(name)
+		fw.Write([]byte(content))
+	}
+	zw.Close()
+
+	got, err := ReadXLSX(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("ReadXLSX got error %s", err.Error())
+	}
+	expected := []string{"identity||90", "", "|John"}
+	if len(got) != len(expected) {
+		t.Fatalf("ReadXLSX expected %d rows, got %d", len(expected), len(got))
+	}
+	for i := range expected {
+		if strings.Join(got[i], "|") != expected[i] {
+			t.Errorf("ReadXLSX row %d expected %q, got %q", i+1, expected[i], strings.Join(got[i], "|"))
+		}
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	got, err := Read(strings.NewReader("name, score\n\"Doe, John\",80\n"), 29, FormatCSV)
+	if err != nil {
+		t.Fatalf("Read got error %s", err.Error())
+	}
+	if len(got) != 2 || got[0][1] != "score" || got[1][0] != "Doe, John" {
+		t.Errorf("Read got unexpected rows %q", got)
+	}
+}
@@ -0,0 +1,92 @@
+// Package statistic contains descriptive statistics used for reporting scores
+package statistic
+
+import (
+	"math"
+	"sort"
+)
+
+// Summary is the descriptive statistics of a set of values, StdDev is the population standard deviation
+type Summary struct {
+	Count  int
+	Mean   float64
+	Median float64
+	StdDev float64
+	Min    float64
+	Max    float64
+}
+
+// Bin is a histogram bin counting values from From up to but excluding To,
+// the last bin also counts values equal to To
+type Bin struct {
+	From  float64
+	To    float64
+	Count int
+}
+
+// Summarize returns the descriptive statistics of values, every field is zero when there is no value
+func Summarize(values []float64) Summary {
+	var summary Summary
+	if len(values) < 1 {
+		return summary
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	var total float64
+	for _, val := range sorted {
+		total += val
+	}
+	mean := total / float64(len(sorted))
+
+	var variance float64
+	for _, val := range sorted {
+		variance += (val - mean) * (val - mean)
+	}
+	variance /= float64(len(sorted))
+
+	mid := len(sorted) / 2
+	median := sorted[mid]
+	if len(sorted)%2 == 0 {
+		median = (sorted[mid-1] + sorted[mid]) / 2
+	}
+
+	return Summary{
+		Count:  len(sorted),
+		Mean:   mean,
+		Median: median,
+		StdDev: math.Sqrt(variance),
+		Min:    sorted[0],
+		Max:    sorted[len(sorted)-1],
+	}
+}
+
+// Histogram splits the range from min to max into bins of the same width and counts values on every bin,
+// values outside the range are counted on the first or the last bin. Nil is returned for an invalid range
+func Histogram(values []float64, min, max float64, bins int) []Bin {
+	if bins < 1 || max <= min {
+		return nil
+	}
+
+	width := (max - min) / float64(bins)
+	hist := make([]Bin, bins)
+	for i := range hist {
+		hist[i].From = min + float64(i)*width
+		hist[i].To = min + float64(i+1)*width
+	}
+	hist[bins-1].To = max
+
+	for _, val := range values {
+		i := int(math.Floor((val - min) / width))
+		if i < 0 {
+			i = 0
+		}
+		if i >= bins {
+			i = bins - 1
+		}
+		hist[i].Count++
+	}
+	return hist
+}
@@ -0,0 +1,96 @@
+package statistic
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	if summary := Summarize(nil); summary != (Summary{}) {
+		t.Errorf("Summarize() of no value expected zero summary, got %+v", summary)
+	}
+
+	values := []float64{90, 70, 80, 100, 60}
+	summary := Summarize(values)
+	expected := Summary{
+		Count:  5,
+		Mean:   80,
+		Median: 80,
+		StdDev: math.Sqrt(200),
+		Min:    60,
+		Max:    100,
+	}
+	if summary != expected {
+		t.Errorf("Summarize() expected %+v, got %+v", expected, summary)
+	}
+	if values[0] != 90 {
+		t.Errorf("Summarize() must not sort the given values")
+	}
+
+	even := Summarize([]float64{10, 40, 20, 30})
+	if even.Median != 25 {
+		t.Errorf("Summarize() median of even count expected 25, got %g", even.Median)
+	}
+
+	single := Summarize([]float64{75})
+	if single.Mean != 75 || single.Median != 75 || single.StdDev != 0 {
+		t.Errorf("Summarize() of a single value expected mean and median 75 without deviation, got %+v", single)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	cases := []struct {
+		name   string
+		values []float64
+		min    float64
+		max    float64
+		bins   int
+		counts []int
+	}{
+		{
+			name:   "edges",
+			values: []float64{0, 19.99, 20, 50, 99, 100},
+			min:    0,
+			max:    100,
+			bins:   5,
+			counts: []int{2, 1, 1, 0, 2},
+		},
+		{
+			name:   "outside range",
+			values: []float64{-5, 105},
+			min:    0,
+			max:    100,
+			bins:   2,
+			counts: []int{1, 1},
+		},
+		{
+			name:   "single bin",
+			values: []float64{1, 2, 3},
+			min:    0,
+			max:    10,
+			bins:   1,
+			counts: []int{3},
+		},
+	}
+	for _, c := range cases {
+		hist := Histogram(c.values, c.min, c.max, c.bins)
+		if len(hist) != len(c.counts) {
+			t.Fatalf("Histogram() %s expected %d bins, got %d", c.name, len(c.counts), len(hist))
+		}
+		for i, val := range hist {
+			if val.Count != c.counts[i] {
+				t.Errorf("Histogram() %s bin %d expected %d, got %d", c.name, i, c.counts[i], val.Count)
+			}
+		}
+		if hist[0].From != c.min || hist[len(hist)-1].To != c.max {
+			t.Errorf("Histogram() %s expected range %g-%g, got %g-%g", c.name, c.min, c.max, hist[0].From, hist[len(hist)-1].To)
+		}
+	}
+
+	if hist := Histogram([]float64{1}, 0, 100, 0); hist != nil {
+		t.Errorf("Histogram() without bins expected nil, got %v", hist)
+	}
+	if hist := Histogram([]float64{1}, 100, 100, 5); hist != nil {
+		t.Errorf("Histogram() with empty range expected nil, got %v", hist)
+	}
+}
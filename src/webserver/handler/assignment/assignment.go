@@ -1,20 +1,28 @@
 package assignment
 
 import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"time"
 
 	"github.com/asepnur/meiko_course/src/util/conn"
 
 	asg "github.com/asepnur/meiko_course/src/module/assignment"
-	att "github.com/asepnur/meiko_course/src/module/attendance"
 	cs "github.com/asepnur/meiko_course/src/module/course"
 	fl "github.com/asepnur/meiko_course/src/module/file"
+	"github.com/asepnur/meiko_course/src/module/grade"
+	rb "github.com/asepnur/meiko_course/src/module/rubric"
 	usr "github.com/asepnur/meiko_course/src/module/user"
 	"github.com/asepnur/meiko_course/src/util/auth"
 	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/asepnur/meiko_course/src/util/publish"
+	"github.com/asepnur/meiko_course/src/util/similarity"
+	"github.com/asepnur/meiko_course/src/util/spreadsheet"
 	"github.com/asepnur/meiko_course/src/webserver/template"
 	"github.com/julienschmidt/httprouter"
 )
@@ -80,8 +88,17 @@ func GetHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	}
 
 	var gpsID []int64
+	gpSchedule := map[int64]int64{}
 	for _, val := range gps {
 		gpsID = append(gpsID, val.ID)
+		gpSchedule[val.ID] = val.ScheduleID
+	}
+
+	policies, err := grade.SelectPolicy(schedulesID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
 	}
 
 	assignments, err := asg.SelectByGP(gpsID, true)
@@ -91,10 +108,23 @@ func GetHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 		return
 	}
 
+	// hide assignments which are not published yet
+	now, err := publish.Now()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	var published []asg.Assignment
 	var asgID []int64
 	for _, val := range assignments {
+		if !val.IsPublished(now) {
+			continue
+		}
+		published = append(published, val)
 		asgID = append(asgID, val.ID)
 	}
+	assignments = published
 
 	if len(asgID) < 1 {
 		template.RenderJSONResponse(w, new(template.Response).
@@ -115,9 +145,24 @@ func GetHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 		submitMap[val.AssignmentID] = val
 	}
 
+	extensions, err := asg.SelectExtension(asgID, &sess.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	extMap := map[int64]asg.Extension{}
+	for _, val := range extensions {
+		extMap[val.AssignmentID] = val
+	}
+
 	var desc, score, status string
 	var isAllowUpload bool
 	for _, assignment := range assignments {
+		if ext, ok := extMap[assignment.ID]; ok {
+			assignment = assignment.Extend(&ext)
+		}
 		submit, exist := submitMap[assignment.ID]
 		desc = "-"
 		score = "-"
@@ -125,14 +170,15 @@ func GetHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 		isAllowUpload = true
 		if assignment.DueDate.Before(time.Now()) {
 			status = "overdue"
-			isAllowUpload = false
+			isAllowUpload = assignment.IsAccepting(time.Now())
 		}
 		if exist {
 			status = "submitted"
 			if submit.Score.Valid {
 				status = "done"
 				isAllowUpload = false
-				score = fmt.Sprintf("%.3g", submit.Score.Float64)
+				policy := policies[gpSchedule[assignment.GradeParameterID]]
+				score = policy.Format(grade.SubmissionScore(assignment, submit, exist).Final())
 			}
 		}
 		if assignment.Status == asg.StatusUploadNotRequired {
@@ -186,6 +232,17 @@ func GetDetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Para
 			SetCode(http.StatusNoContent))
 		return
 	}
+	now, err := publish.Now()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if !assignment.IsPublished(now) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNoContent))
+		return
+	}
 
 	scheduleID, err := cs.GetScheduleIDByGP(assignment.GradeParameterID)
 	if err != nil {
@@ -200,6 +257,14 @@ func GetDetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Para
 		return
 	}
 
+	ext, err := asg.GetExtension(args.id, sess.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	assignment = assignment.Extend(ext)
+
 	submitted, err := asg.GetSubmittedByUser(args.id, sess.ID)
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
@@ -207,24 +272,35 @@ func GetDetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Para
 		return
 	}
 
+	policy, err := grade.GetPolicy(scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
 	// response data preparation
 	status := "unsubmitted"
 	score := "-"
 	submittedDate := "-"
 	submittedDesc := ""
+	feedback := ""
+	late := "-"
 	isAllowUpload := true
 	if assignment.DueDate.Before(time.Now()) {
 		status = "overdue"
-		isAllowUpload = false
+		isAllowUpload = assignment.IsAccepting(time.Now())
 	}
 	if submitted != nil {
 		status = "submitted"
+		late = handleLateness(submitted.LateSeconds)
 		submittedDesc = submitted.Description.String
 		submittedDate = submitted.UpdatedAt.Format("Monday, 2 January 2006 15:04:05")
 		if submitted.Score.Valid {
 			isAllowUpload = false
 			status = "done"
-			score = fmt.Sprintf("%.3g", submitted.Score.Float64)
+			score = policy.Format(grade.SubmissionScore(assignment, *submitted, true).Final())
+			feedback = submitted.Feedback.String
 		}
 	}
 	if assignment.Status == asg.StatusUploadNotRequired {
@@ -247,6 +323,41 @@ func GetDetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Para
 		return
 	}
 
+	// group member sees the shared files of the group
+	var rGroup *groupResponse
+	if assignment.GroupMode != asg.GroupModeNone {
+		group, err := asg.GetGroupByUser(scheduleID, assignment.GroupScope(), sess.ID)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		if group != nil {
+			rGroup, submittedFile, err = handleGroupDetail(*group, tableID[0])
+			if err != nil {
+				template.RenderJSONResponse(w, new(template.Response).
+					SetCode(http.StatusInternalServerError))
+				return
+			}
+		}
+	}
+
+	// scored submission shows the files the score applies to, which is the pinned version when grading is pinned
+	if submitted != nil && submitted.Score.Valid && submitted.PinnedVersion.Valid {
+		pinned, err := asg.SelectPinnedFileID(args.id, &sess.ID)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		submittedFile, err = fl.SelectGradedUpload(args.id, &sess.ID, pinned)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
 	// file from assistant
 	rAsgFile := []file{}
 	for _, val := range asgFile {
@@ -269,12 +380,55 @@ func GetDetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Para
 		})
 	}
 
+	// criteria of the rubric are shown before grading so student knows how the submission is graded
+	var rRubric *rubricResponse
+	if assignment.RubricID.Valid {
+		rubric, err := rb.Get(assignment.RubricID.Int64)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		if rubric != nil {
+			rRubric, err = handleRubricScore(*rubric, assignment.ID, sess.ID)
+			if err != nil {
+				template.RenderJSONResponse(w, new(template.Response).
+					SetCode(http.StatusInternalServerError))
+				return
+			}
+		}
+	}
+
+	feedbacks, err := asg.SelectFeedback(assignment.ID, sess.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	rFeedback, err := handleFeedbackResponse(feedbacks, usr.UserReq{ID: sess.ID, Name: sess.Name})
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	// opening the detail page is the read receipt of assistant feedbacks
+	err = asg.ReadFeedback(assignment.ID, sess.ID, sess.ID, nil)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
 	resp := getDetailResponse{
 		ID:                   assignment.ID,
 		Name:                 assignment.Name,
 		Status:               status,
 		Description:          assignment.Description.String,
 		DueDate:              assignment.DueDate.Format("Monday, 2 January 2006 15:04:05"),
+		CloseDate:            assignment.Deadline().Format("Monday, 2 January 2006 15:04:05"),
+		LatePolicy:           asg.LatePolicies[assignment.LatePolicy],
+		Late:                 late,
 		Score:                score,
 		CreatedAt:            assignment.CreatedAt.Format("Monday, 2 January 2006"),
 		UpdatedAt:            assignment.UpdatedAt.Format("Monday, 2 January 2006"),
@@ -283,6 +437,10 @@ func GetDetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Para
 		SubmittedDescription: submittedDesc,
 		SubmittedFile:        rSubmittedFile,
 		SubmittedDate:        submittedDate,
+		Feedback:             feedback,
+		Group:                rGroup,
+		Rubric:               rRubric,
+		Feedbacks:            rFeedback,
 	}
 
 	template.RenderJSONResponse(w, new(template.Response).
@@ -317,6 +475,17 @@ func SubmitHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		return
 	}
 
+	now, err := publish.Now()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if !assignment.IsPublished(now) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNoContent))
+		return
+	}
 	if assignment.Status == asg.StatusUploadNotRequired {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusForbidden).
@@ -324,12 +493,32 @@ func SubmitHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		return
 	}
 
-	if assignment.DueDate.Before(time.Now()) {
+	// group assignments are not extended, the group submits by the deadline of the assignment
+	extended := assignment
+	if assignment.GroupMode == asg.GroupModeNone {
+		ext, err := asg.GetExtension(assignment.ID, sess.ID)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		extended = assignment.Extend(ext)
+	}
+
+	if !extended.IsAccepting(now) {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusForbidden).
 			AddError("Sorry, you can't upload this assignment because of overdue."))
 		return
 	}
+	// the files are already out for review
+	if assignment.PeerAssignedAt.Valid {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("Sorry, you can't upload this assignment because it is already out for peer review."))
+		return
+	}
+	lateSeconds := extended.Lateness(now)
 
 	scheduleID, err := cs.GetScheduleIDByGP(assignment.GradeParameterID)
 	if err != nil {
@@ -345,6 +534,73 @@ func SubmitHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		return
 	}
 
+	// group submission is shared by every member
+	if assignment.GroupMode != asg.GroupModeNone {
+		group, err := asg.GetGroupByUser(scheduleID, assignment.GroupScope(), sess.ID)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+
+		if group == nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusForbidden).
+				AddError("You are not a member of any group on this assignment"))
+			return
+		}
+
+		members, err := asg.SelectGroupMember([]int64{group.ID})
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		var membersID []int64
+		for _, val := range members {
+			membersID = append(membersID, val.UserID)
+		}
+		attached, err := handleGroupFileID(strconv.FormatInt(assignment.ID, 10), membersID)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		code, err := handleSubmitFile(assignment, sess.ID, attached, args.fileID)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(code).
+				AddError(err.Error()))
+			return
+		}
+
+		err = handleSubmitGroup(assignment, group.ID, sess.ID, args.description, args.fileID, now)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusOK).
+			SetMessage("Success"))
+		return
+	}
+
+	attached, err := fl.SelectIDByRelation(fl.TypAssignmentUpload, strconv.FormatInt(assignment.ID, 10), sess.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	code, err := handleSubmitFile(assignment, sess.ID, attached, args.fileID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
 	upload, err := asg.GetSubmittedByUser(args.id, sess.ID)
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
@@ -354,7 +610,7 @@ func SubmitHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 
 	// update
 	if upload != nil {
-		err = handleSubmitUpdate(assignment.ID, sess.ID, args.description, args.fileID)
+		err = handleSubmitUpdate(assignment.ID, sess.ID, args.description, lateSeconds, args.fileID)
 		if err != nil {
 			template.RenderJSONResponse(w, new(template.Response).
 				SetCode(http.StatusInternalServerError))
@@ -368,7 +624,7 @@ func SubmitHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 	}
 
 	// insert
-	err = handleSubmitInsert(assignment.ID, sess.ID, args.description, args.fileID)
+	err = handleSubmitInsert(assignment.ID, sess.ID, args.description, lateSeconds, args.fileID)
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusInternalServerError))
@@ -535,6 +791,17 @@ func CreateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		allowedTypesFile: r.FormValue("allowed_types"),
 		maxFile:          r.FormValue("max_file"),
 		maxSizeFile:      r.FormValue("max_size"),
+		latePolicy:       r.FormValue("late_policy"),
+		gracePeriod:      r.FormValue("grace_period"),
+		latePenalty:      r.FormValue("late_penalty"),
+		closeDate:        r.FormValue("close_date"),
+		groupMode:        r.FormValue("group_mode"),
+		publishAt:        r.FormValue("publish_at"),
+		unpublishAt:      r.FormValue("unpublish_at"),
+		peerReviewers:    r.FormValue("peer_reviewers"),
+		peerReviewClose:  r.FormValue("peer_review_close"),
+		peerWeight:       r.FormValue("peer_weight"),
+		anonymous:        r.FormValue("anonymous"),
 	}
 	args, err := params.validate()
 	if err != nil {
@@ -578,6 +845,42 @@ func CreateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 	tx := conn.DB.MustBegin()
 	id, err := asg.Insert(args.name, args.description, args.gpID, args.maxSizeFile, args.maxFile, args.dueDate, args.status, tx)
 	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	err = asg.UpdateLatePolicy(id, args.late.policy, args.late.gracePeriod, args.late.penalty, args.late.closeDate, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	err = asg.UpdateGroupMode(id, args.groupMode, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	err = asg.UpdatePublish(id, args.publish.publishAt, args.publish.unpublishAt, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	err = asg.UpdatePeerReview(id, args.peer.reviewers, args.peer.closeDate, args.peer.weight, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	err = asg.UpdateAnonymous(id, args.anonymous, tx)
+	if err != nil {
+		tx.Rollback()
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusInternalServerError))
 		return
@@ -632,6 +935,17 @@ func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		allowedTypesFile: r.FormValue("allowed_types"),
 		maxFile:          r.FormValue("max_file"),
 		maxSizeFile:      r.FormValue("max_size"),
+		latePolicy:       r.FormValue("late_policy"),
+		gracePeriod:      r.FormValue("grace_period"),
+		latePenalty:      r.FormValue("late_penalty"),
+		closeDate:        r.FormValue("close_date"),
+		groupMode:        r.FormValue("group_mode"),
+		publishAt:        r.FormValue("publish_at"),
+		unpublishAt:      r.FormValue("unpublish_at"),
+		peerReviewers:    r.FormValue("peer_reviewers"),
+		peerReviewClose:  r.FormValue("peer_review_close"),
+		peerWeight:       r.FormValue("peer_weight"),
+		anonymous:        r.FormValue("anonymous"),
 	}
 	args, err := params.validate()
 	if err != nil {
@@ -654,6 +968,35 @@ func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 			AddError("You don't have privilege"))
 		return
 	}
+
+	current, err := asg.GetByID(args.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if current.GroupMode != args.groupMode && asg.IsExistSubmitted(args.ID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Group mode can not be changed after the assignment has been submitted"))
+		return
+	}
+
+	if current.PeerAssignedAt.Valid && current.PeerReviewers != args.peer.reviewers {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Peer reviewers can not be changed after the reviews have been assigned"))
+		return
+	}
+
+	if current.IsIdentityHidden() && !args.anonymous {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Anonymous grading can only be ended by revealing the identities"))
+		return
+	}
+
 	tx := conn.DB.MustBegin()
 	if len(args.filesID) > 0 {
 		filesID, err := fl.SelectIDStatusByID(args.filesID)
@@ -808,6 +1151,41 @@ func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 			SetCode(http.StatusInternalServerError))
 		return
 	}
+	err = asg.UpdateLatePolicy(args.ID, args.late.policy, args.late.gracePeriod, args.late.penalty, args.late.closeDate, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	err = asg.UpdateGroupMode(args.ID, args.groupMode, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	err = asg.UpdatePublish(args.ID, args.publish.publishAt, args.publish.unpublishAt, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	err = asg.UpdatePeerReview(args.ID, args.peer.reviewers, args.peer.closeDate, args.peer.weight, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	err = asg.UpdateAnonymous(args.ID, args.anonymous, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
 	err = tx.Commit()
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
@@ -909,6 +1287,21 @@ func DetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		if assignment.Description.Valid {
 			desc = assignment.Description.String
 		}
+		var closeDate *time.Time
+		if assignment.CloseDate.Valid {
+			closeDate = &assignment.CloseDate.Time
+		}
+		var peerReviewClose *time.Time
+		if assignment.PeerReviewClose.Valid {
+			peerReviewClose = &assignment.PeerReviewClose.Time
+		}
+		var publishAt, unpublishAt *time.Time
+		if assignment.PublishAt.Valid {
+			publishAt = &assignment.PublishAt.Time
+		}
+		if assignment.UnpublishAt.Valid {
+			unpublishAt = &assignment.UnpublishAt.Time
+		}
 		res := respDetailUpdate{}
 		if assignment.Status == 1 {
 			var size int8
@@ -928,6 +1321,18 @@ func DetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 				Status:           assignment.Status,
 				MaxFile:          max,
 				MaxSize:          size,
+				LatePolicy:       asg.LatePolicies[assignment.LatePolicy],
+				GracePeriod:      assignment.GracePeriod,
+				LatePenalty:      assignment.LatePenalty,
+				CloseDate:        closeDate,
+				GroupMode:        asg.GroupModes[assignment.GroupMode],
+				PublishAt:        publishAt,
+				UnpublishAt:      unpublishAt,
+				PeerReviewers:    assignment.PeerReviewers,
+				PeerReviewClose:  peerReviewClose,
+				PeerWeight:       assignment.PeerWeight,
+				IsAnonymous:      assignment.IsAnonymous(),
+				IsRevealed:       assignment.RevealedAt.Valid,
 				Type:             typs,
 				FilesID:          rAsgFile,
 			}
@@ -939,6 +1344,18 @@ func DetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 				DueDate:          assignment.DueDate,
 				GradeParameterID: assignment.GradeParameterID,
 				Status:           assignment.Status,
+				LatePolicy:       asg.LatePolicies[assignment.LatePolicy],
+				GracePeriod:      assignment.GracePeriod,
+				LatePenalty:      assignment.LatePenalty,
+				CloseDate:        closeDate,
+				GroupMode:        asg.GroupModes[assignment.GroupMode],
+				PublishAt:        publishAt,
+				UnpublishAt:      unpublishAt,
+				PeerReviewers:    assignment.PeerReviewers,
+				PeerReviewClose:  peerReviewClose,
+				PeerWeight:       assignment.PeerWeight,
+				IsAnonymous:      assignment.IsAnonymous(),
+				IsRevealed:       assignment.RevealedAt.Valid,
 				FilesID:          rAsgFile,
 			}
 		}
@@ -949,7 +1366,12 @@ func DetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 
 	}
 	offset := (args.page - 1) * args.total
-	assignment := asg.GetAssignmentByID(args.ID)
+	assignment, err := asg.GetByID(args.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
 	var res respDetAsgUser
 	asgUser := []detAsgUser{}
 	var totalPg int
@@ -970,11 +1392,17 @@ func DetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 				SetCode(http.StatusInternalServerError))
 			return
 		}
+		userMap, err := handleShownStudents(assignment, scheduleID, users)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
 		if len(users) > 0 {
 			for _, val := range users {
 				asgUser = append(asgUser, detAsgUser{
-					ID:          val.IdentityCode,
-					Name:        val.Name,
+					ID:          userMap[val.ID].IdentityCode,
+					Name:        userMap[val.ID].Name,
 					Description: "-",
 					UploadedAt:  "-",
 					Link:        "-",
@@ -1009,28 +1437,36 @@ func DetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 					SetCode(http.StatusInternalServerError))
 				return
 			}
+			userMap, err := handleShownStudents(assignment, scheduleID, users)
+			if err != nil {
+				template.RenderJSONResponse(w, new(template.Response).
+					SetCode(http.StatusInternalServerError))
+				return
+			}
 			for _, val := range sbmtdAsg {
 				var desc string
 				if val.Description.Valid {
 					desc = val.Description.String
 				}
 				asgUser = append(asgUser, detAsgUser{
-					ID:          val.UserID,
+					ID:          userMap[val.UserID].IdentityCode,
+					Name:        userMap[val.UserID].Name,
 					Description: desc,
 					UploadedAt:  val.UpdatedAt.Format("Monday, 2 January 2006 15:04:05"),
 					Link:        "-",
+					IsLate:      val.LateSeconds > 0,
+					Late:        handleLateness(val.LateSeconds),
+					Penalty:     assignment.Penalty(val.LateSeconds),
 				})
 			}
-			for _, a := range asgUser {
-				for i, u := range users {
-					if a.ID == u.ID {
-						asgUser[i].ID = u.IdentityCode
-						asgUser[i].Name = u.Name
-					}
-				}
-			}
 		}
 	}
+	// students are ordered by name, it would tell who they are
+	if assignment.IsIdentityHidden() {
+		sort.Slice(asgUser, func(i, j int) bool {
+			return asgUser[i].ID < asgUser[j].ID
+		})
+	}
 	status := "must_upload"
 	if assignment.Status == 0 {
 		status = "upload_not_required"
@@ -1042,6 +1478,9 @@ func DetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		Name:        assignment.Name,
 		Status:      status,
 		DueDate:     assignment.DueDate.Format("Monday, 2 January 2006 15:04:05"),
+		CloseDate:   assignment.Deadline().Format("Monday, 2 January 2006 15:04:05"),
+		LatePolicy:  asg.LatePolicies[assignment.LatePolicy],
+		IsAnonymous: assignment.IsIdentityHidden(),
 		DetAsgUser:  asgUser,
 	}
 	template.RenderJSONResponse(w, new(template.Response).
@@ -1145,6 +1584,14 @@ func DeleteHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		}
 	}
 
+	err = asg.DeleteExtension(args.id, nil, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
 	err = asg.DeleteAssignment(args.id, tx)
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
@@ -1226,122 +1673,3162 @@ func GetReportHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Para
 		schedulesID = append(schedulesID, val.Schedule.ID)
 	}
 
-	gps, err := cs.SelectGPBySchedule(schedulesID)
+	policies, err := grade.SelectPolicy(schedulesID)
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusInternalServerError))
 		return
 	}
 
-	if len(gps) < 1 {
+	// scores are only shown after the final grade is published
+	publications, err := grade.SelectPublishedPublication(schedulesID)
+	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
-			SetCode(http.StatusOK).
-			SetData(resp))
+			SetCode(http.StatusInternalServerError))
 		return
 	}
 
-	var gpsID []int64
-	scheduleGP := map[int64][]cs.GradeParameter{}
-	for _, gp := range gps {
-		gpsID = append(gpsID, gp.ID)
-		scheduleGP[gp.ScheduleID] = append(scheduleGP[gp.ScheduleID], gp)
+	var publicationsID []int64
+	for _, val := range publications {
+		publicationsID = append(publicationsID, val.ID)
 	}
-
-	assignments, err := asg.SelectByGP(gpsID, false)
+	snapshots, err := grade.SelectSnapshot(publicationsID, &sess.ID)
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusInternalServerError))
 		return
 	}
+	publicationSnapshot := map[int64]grade.Snapshot{}
+	for _, val := range snapshots {
+		publicationSnapshot[val.PublicationID] = val
+	}
 
-	var asgID []int64
-	gpAsg := map[int64][]asg.Assignment{}
-	for _, val := range assignments {
-		asgID = append(asgID, val.ID)
-		gpAsg[val.GradeParameterID] = append(gpAsg[val.GradeParameterID], val)
+	for _, c := range courses {
+		rep := getReportResponse{
+			CourseName: c.Course.Name,
+			ScheduleID: c.Schedule.ID,
+			Assignment: "-",
+			Attendance: "-",
+			Quiz:       "-",
+			Mid:        "-",
+			Final:      "-",
+			Total:      "-",
+			Letter:     "-",
+			GradePoint: "-",
+		}
+		policy := policies[c.Schedule.ID]
+		publication, ok := publications[c.Schedule.ID]
+		if !ok {
+			resp = append(resp, rep)
+			continue
+		}
+		snapshot, ok := publicationSnapshot[publication.ID]
+		if !ok {
+			resp = append(resp, rep)
+			continue
+		}
+
+		// every column comes from the snapshot so the report stays the one which was published
+		for _, param := range snapshot.Parameters {
+			score := policy.Format(param.Score)
+			switch param.Type {
+			case cs.GradeParameterAttendance:
+				rep.Attendance = score
+			case cs.GradeParameterAssignment:
+				rep.Assignment = score
+			case cs.GradeParameterQuiz:
+				rep.Quiz = score
+			case cs.GradeParameterMid:
+				rep.Mid = score
+			case cs.GradeParameterFinal:
+				rep.Final = score
+			}
+		}
+		rep.IsPublished = true
+		rep.Total = policy.Format(snapshot.Total)
+		rep.Letter = snapshot.Letter
+		rep.GradePoint = strconv.FormatFloat(snapshot.GradePoint, 'f', 2, 64)
+		resp = append(resp, rep)
 	}
 
-	if len(asgID) < 1 {
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// GetDetailAssignmentByAdmin returns score sheet of the assignment for every enrolled student
+func GetDetailAssignmentByAdmin(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleRead, auth.RoleXRead) {
 		template.RenderJSONResponse(w, new(template.Response).
-			SetCode(http.StatusOK).
-			SetData(resp))
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
 		return
 	}
 
-	submitted, err := asg.SelectSubmittedByUser(asgID, sess.ID)
+	params := detailAssignmentParams{
+		ScheduleID:   ps.ByName("schedule_id"),
+		AssignmentID: ps.ByName("assignment_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.ScheduleID, args.AssignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	studentsID, err := cs.SelectEnrolledStudentID(args.ScheduleID)
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusInternalServerError))
 		return
 	}
 
-	asgSubmit := map[int64]asg.UserAssignment{}
+	submitted, err := asg.SelectSubmittedByAssignment([]int64{assignment.ID})
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	submissions := map[int64]asg.UserAssignment{}
 	for _, val := range submitted {
-		asgSubmit[val.AssignmentID] = val
+		submissions[val.UserID] = val
 	}
 
-	attReport, err := att.CountByUserSchedule(sess.ID, schedulesID)
+	policy, err := grade.GetPolicy(args.ScheduleID)
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusInternalServerError))
 		return
 	}
 
-	for _, c := range courses {
-		rep := getReportResponse{
-			CourseName: c.Course.Name,
-			ScheduleID: c.Schedule.ID,
-			Assignment: "-",
-			Attendance: "-",
-			Quiz:       "-",
-			Mid:        "-",
-			Final:      "-",
-			Total:      "-",
+	praktikan := []userAssignment{}
+	if len(studentsID) > 0 {
+		users, err := usr.RequestID(studentsID, true)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
 		}
-		total := float64(0)
-		for _, gp := range scheduleGP[c.Schedule.ID] {
-			scoreFloat64 := float64(0)
-			if gp.Type == "ATTENDANCE" {
-				attendance := attReport[gp.ScheduleID]
-				if attendance.MeetingTotal > 0 {
-					scoreFloat64 = (float64(attendance.AttendanceTotal) / float64(attendance.MeetingTotal)) * float64(gp.Percentage) / 100
-				}
-				rep.Attendance = fmt.Sprintf("%.3g", scoreFloat64)
-			} else {
-				count := len(gpAsg[gp.ID])
-				for _, assignment := range gpAsg[gp.ID] {
-					submit, exist := asgSubmit[assignment.ID]
-					if !exist {
-						continue
-					}
-					if submit.Score.Valid {
-						scoreFloat64 += submit.Score.Float64
-					} else {
-						count--
-					}
-				}
-				if count > 0 {
-					scoreFloat64 = scoreFloat64 / float64(count)
+		userMap, err := handleShownStudents(assignment, args.ScheduleID, users)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		for _, val := range users {
+			student := userAssignment{
+				IdentityCode: userMap[val.ID].IdentityCode,
+				Name:         userMap[val.ID].Name,
+				Score:        "-",
+				SubmittedAt:  "-",
+			}
+			if submission, ok := submissions[val.ID]; ok {
+				if submission.Score.Valid {
+					student.Score = policy.Format(submission.Score.Float64)
 				}
-				total += (scoreFloat64 * float64(gp.Percentage) / 100)
-				switch gp.Type {
-				case "ASSIGNMENT":
-					rep.Assignment = fmt.Sprintf("%.3g", scoreFloat64)
-				case "QUIZ":
-					rep.Quiz = fmt.Sprintf("%.3g", scoreFloat64)
-				case "MID":
-					rep.Mid = fmt.Sprintf("%.3g", scoreFloat64)
-				case "FINAL":
-					rep.Final = fmt.Sprintf("%.3g", scoreFloat64)
+				student.Feedback = submission.Feedback.String
+				if assignment.Status == asg.StatusUploadRequired {
+					student.IsSubmitted = true
+					student.SubmittedAt = submission.CreatedAt.Format("Monday, 2 January 2006 15:04:05")
 				}
 			}
+			praktikan = append(praktikan, student)
 		}
-		rep.Total = fmt.Sprintf("%.3g", total)
-		resp = append(resp, rep)
+	}
+	// students are ordered by name, it would tell who they are
+	if assignment.IsIdentityHidden() {
+		sort.Slice(praktikan, func(i, j int) bool {
+			return praktikan[i].IdentityCode < praktikan[j].IdentityCode
+		})
 	}
 
+	status := "must_upload"
+	if assignment.Status == asg.StatusUploadNotRequired {
+		status = "upload_not_required"
+	}
 	template.RenderJSONResponse(w, new(template.Response).
 		SetCode(http.StatusOK).
-		SetData(resp))
+		SetData(detailAssignmentResponse{
+			ID:            assignment.ID,
+			Name:          assignment.Name,
+			Status:        status,
+			DueDate:       assignment.DueDate.Format("Monday, 2 January 2006 15:04:05"),
+			IsCreateScore: sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate),
+			IsAnonymous:   assignment.IsIdentityHidden(),
+			Praktikan:     praktikan,
+		}))
+	return
+}
+
+// UpdateScoreHandler sets score and feedback of a single student
+func UpdateScoreHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := updateScoreParams{
+		ScheduleID:   ps.ByName("schedule_id"),
+		AssignmentID: ps.ByName("assignment_id"),
+		IdentityCode: r.FormValue("identity_code"),
+		Score:        r.FormValue("score"),
+		Feedback:     r.FormValue("feedback"),
+		Reason:       r.FormValue("reason"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.ScheduleID, args.AssignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	previews, entries, err := handleScoreRows(assignment, args.ScheduleID, []scoreRow{args.Row})
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if len(entries) < 1 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(previews[0].Error))
+		return
+	}
+
+	err = handleScoreSave(assignment.ID, entries, scoreAudit{
+		Actor:  sess.ID,
+		Source: asg.ScoreSourceAPI,
+		Reason: args.Reason,
+	})
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Score has been saved"))
+	return
+}
+
+// CreateScoreHandler sets score of many students at once, nothing is saved when one of the score is invalid
+func CreateScoreHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := createScoreParams{
+		ScheduleID:   ps.ByName("schedule_id"),
+		AssignmentID: ps.ByName("assignment_id"),
+		Users:        r.FormValue("users"),
+		Reason:       r.FormValue("reason"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.ScheduleID, args.AssignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	handleScoreUpload(w, assignment, args.ScheduleID, args.Rows, false, scoreAudit{
+		Actor:  sess.ID,
+		Source: asg.ScoreSourceAPI,
+		Reason: args.Reason,
+	})
+	return
+}
+
+// UploadScoreHandler sets score of many students from CSV file with identity_code, score and feedback column,
+// set preview to true to see the validation result without saving
+func UploadScoreHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	// 2 MB is more than enough for a class score sheet
+	r.ParseMultipartForm(2 << 20)
+	params := uploadScoreParams{
+		ScheduleID:   ps.ByName("schedule_id"),
+		AssignmentID: ps.ByName("assignment_id"),
+		IsPreview:    r.FormValue("preview"),
+		Reason:       r.FormValue("reason"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.ScheduleID, args.AssignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("File is not exist"))
+		return
+	}
+	defer file.Close()
+
+	rows, err := validateScoreCSV(file)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	handleScoreUpload(w, assignment, args.ScheduleID, rows, args.IsPreview, scoreAudit{
+		Actor:  sess.ID,
+		Source: asg.ScoreSourceImport,
+		Reason: args.Reason,
+	})
+	return
+}
+
+// ReadExtensionHandler returns every deadline extension granted on the assignment
+func ReadExtensionHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	id, err := strconv.ParseInt(ps.ByName("id"), 10, 64)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid Request"))
+		return
+	}
+
+	assignment, scheduleID, status, err := handleAssignmentAccess(sess.ID, id)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(status).
+			AddError(err.Error()))
+		return
+	}
+
+	exts, err := asg.SelectExtension([]int64{id}, nil)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	resp, err := handleExtensionResponse(assignment, scheduleID, exts)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// CreateExtensionHandler grants new due date and close date to one or a group of students,
+// previous extension of the students is replaced
+func CreateExtensionHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := extensionParams{
+		id:            ps.ByName("id"),
+		identityCodes: r.FormValue("identity_code"),
+		dueDate:       r.FormValue("due_date"),
+		closeDate:     r.FormValue("close_date"),
+		reason:        r.FormValue("reason"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, scheduleID, status, err := handleAssignmentAccess(sess.ID, args.id)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(status).
+			AddError(err.Error()))
+		return
+	}
+
+	// a group shares one submission, so its members can not have their own deadline
+	if assignment.GroupMode != asg.GroupModeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Extension can not be granted on a group assignment, please update the due date of the assignment instead"))
+		return
+	}
+	if !args.dueDate.After(assignment.DueDate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Due date must be after the due date of the assignment"))
+		return
+	}
+	if assignment.PeerAssignedAt.Valid {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Submissions are already out for peer review"))
+		return
+	}
+	extended := assignment.Extend(&asg.Extension{DueDate: args.dueDate, CloseDate: args.closeDate})
+	if assignment.PeerReviewClose.Valid && !extended.Deadline().Before(assignment.PeerReviewClose.Time) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Extension must end before the peer review is closed"))
+		return
+	}
+
+	students, err := handleGradedStudents(assignment, scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	var usersID []int64
+	for _, val := range args.identityCodes {
+		student, ok := students[val]
+		if !ok {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusBadRequest).
+				AddError(fmt.Sprintf("Student %d is not enrolled in this schedule", val)))
+			return
+		}
+		usersID = append(usersID, student.ID)
+	}
+
+	tx := conn.DB.MustBegin()
+	for _, val := range usersID {
+		ext := asg.Extension{
+			AssignmentID: args.id,
+			UserID:       val,
+			DueDate:      args.dueDate,
+			CloseDate:    args.closeDate,
+			Reason:       args.reason,
+			GrantedBy:    sess.ID,
+		}
+		err = asg.UpsertExtension(ext, tx)
+		if err != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		// a late submission may not be late anymore with the extension
+		err = handleLatenessUpdate(assignment.Extend(&ext), val, tx)
+		if err != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Extension granted successfully"))
+	return
+}
+
+// DeleteExtensionHandler revokes the extension of a student
+func DeleteExtensionHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := deleteExtensionParams{
+		id:           ps.ByName("id"),
+		identityCode: ps.ByName("identity_code"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, scheduleID, status, err := handleAssignmentAccess(sess.ID, args.id)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(status).
+			AddError(err.Error()))
+		return
+	}
+
+	students, err := handleGradedStudents(assignment, scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	student, ok := students[args.identityCode]
+	if !ok {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Student is not enrolled in this schedule"))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = asg.DeleteExtension(args.id, &student.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Extension does not exist"))
+		return
+	}
+	// the submission is late again against the due date of the assignment
+	err = handleLatenessUpdate(assignment, student.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Extension revoked successfully"))
+	return
+}
+
+// UpdateGroupScoreHandler sets one score to every member of the group,
+// adjustments are added to the score of the given members
+func UpdateGroupScoreHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := groupScoreParams{
+		ScheduleID:   ps.ByName("schedule_id"),
+		AssignmentID: ps.ByName("assignment_id"),
+		GroupID:      ps.ByName("group_id"),
+		Score:        r.FormValue("score"),
+		Feedback:     r.FormValue("feedback"),
+		Adjustments:  r.FormValue("adjustments"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.ScheduleID, args.AssignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	if assignment.GroupMode == asg.GroupModeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Assignment is not a group assignment"))
+		return
+	}
+
+	group, err := asg.GetGroup(args.GroupID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	scope := assignment.GroupScope()
+	if group == nil || group.ScheduleID != args.ScheduleID || group.AssignmentID != scope {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Group does not exist on this assignment"))
+		return
+	}
+
+	code, err = handleGroupScore(assignment, *group, args, sess.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Group score has been saved"))
+	return
+}
+
+// ReadVersionHandler returns every submitted version of a student on the assignment
+func ReadVersionHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := studentParams{
+		scheduleID:   ps.ByName("schedule_id"),
+		assignmentID: ps.ByName("assignment_id"),
+		identityCode: ps.ByName("identity_code"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.scheduleID, args.assignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	students, err := handleGradedStudents(assignment, args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	student, ok := students[args.identityCode]
+	if !ok {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Student is not enrolled in this schedule"))
+		return
+	}
+
+	submit, err := asg.GetSubmittedByUser(assignment.ID, student.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	var pinned sql.NullInt64
+	if submit != nil {
+		pinned = submit.PinnedVersion
+	}
+
+	versions, err := asg.SelectVersion(assignment.ID, student.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	resp, err := handleVersionResponse(assignment, versions, pinned, students)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// PinVersionHandler chooses the version of a student which is graded,
+// empty version means the latest version is graded
+func PinVersionHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := pinVersionParams{
+		scheduleID:   ps.ByName("schedule_id"),
+		assignmentID: ps.ByName("assignment_id"),
+		identityCode: ps.ByName("identity_code"),
+		version:      r.FormValue("version"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.scheduleID, args.assignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	students, err := handleGradedStudents(assignment, args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	student, ok := students[args.identityCode]
+	if !ok {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Student is not enrolled in this schedule"))
+		return
+	}
+
+	submit, err := asg.GetSubmittedByUser(assignment.ID, student.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if submit == nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Student has not submitted this assignment"))
+		return
+	}
+
+	// late seconds follows the graded version so late penalty is charged on it
+	lateSeconds := submit.LateSeconds
+	if args.version.Valid {
+		version, err := asg.GetVersion(assignment.ID, student.ID, args.version.Int64)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		if version == nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusNotFound).
+				AddError("Version does not exist"))
+			return
+		}
+		lateSeconds = version.LateSeconds
+	} else {
+		versions, err := asg.SelectVersion(assignment.ID, student.ID)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		if len(versions) > 0 {
+			lateSeconds = versions[0].LateSeconds
+		}
+	}
+
+	err = asg.UpdatePinnedVersion(assignment.ID, student.ID, args.version, lateSeconds, nil)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	message := "Latest version will be graded"
+	if args.version.Valid {
+		message = fmt.Sprintf("Version %d will be graded", args.version.Int64)
+	}
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage(message))
+	return
+}
+
+// AttachRubricHandler attaches a rubric of the schedule to the assignment, empty rubric_id detaches it.
+// Rubric can not be changed after any submission has been graded with it
+func AttachRubricHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := attachRubricParams{
+		scheduleID:   ps.ByName("schedule_id"),
+		assignmentID: ps.ByName("assignment_id"),
+		rubricID:     r.FormValue("rubric_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.scheduleID, args.assignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	if args.rubricID.Valid {
+		rubric, err := rb.Get(args.rubricID.Int64)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		if rubric == nil || rubric.ScheduleID != args.scheduleID {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusNotFound).
+				AddError("Rubric does not exist"))
+			return
+		}
+	}
+
+	if assignment.RubricID != args.rubricID && rb.IsAssignmentScored(assignment.ID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Rubric can not be changed after submissions have been graded with it"))
+		return
+	}
+
+	err = asg.UpdateRubric(assignment.ID, args.rubricID, nil)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Rubric has been saved"))
+	return
+}
+
+// ReadRubricScoreHandler returns the rubric of the assignment filled for a student
+func ReadRubricScoreHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := studentParams{
+		scheduleID:   ps.ByName("schedule_id"),
+		assignmentID: ps.ByName("assignment_id"),
+		identityCode: ps.ByName("identity_code"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.scheduleID, args.assignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	if !assignment.RubricID.Valid {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Assignment does not use a rubric"))
+		return
+	}
+
+	students, err := handleGradedStudents(assignment, args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	student, ok := students[args.identityCode]
+	if !ok {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Student is not enrolled in this schedule"))
+		return
+	}
+
+	rubric, err := rb.Get(assignment.RubricID.Int64)
+	if err != nil || rubric == nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	resp, err := handleRubricScore(*rubric, assignment.ID, student.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// FillRubricHandler grades a student with the rubric of the assignment,
+// levels is a json list of criterion_id, level_id and comment. Score is computed from the chosen levels
+func FillRubricHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := fillRubricParams{
+		scheduleID:   ps.ByName("schedule_id"),
+		assignmentID: ps.ByName("assignment_id"),
+		identityCode: ps.ByName("identity_code"),
+		levels:       r.FormValue("levels"),
+		feedback:     r.FormValue("feedback"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.scheduleID, args.assignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	if !assignment.RubricID.Valid {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Assignment does not use a rubric"))
+		return
+	}
+
+	err = handleIndividualScore(assignment)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	students, err := handleGradedStudents(assignment, args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	student, ok := students[args.identityCode]
+	if !ok {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Student is not enrolled in this schedule"))
+		return
+	}
+
+	rubric, err := rb.Get(assignment.RubricID.Int64)
+	if err != nil || rubric == nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	scores, err := rubric.Fill(args.levels, args.comments)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+	_, score := rubric.Total(scores)
+
+	tx := conn.DB.MustBegin()
+	err = rb.ReplaceScore(assignment.ID, student.ID, scores, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	old, err := asg.SelectScoreByUser(assignment.ID, []int64{student.ID}, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = asg.UpsertScore(assignment.ID, student.ID, float32(score), args.feedback, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = asg.LogScore(assignment.ID, []int64{student.ID}, old, sess.ID, asg.ScoreSourceAPI, sql.NullString{}, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage(fmt.Sprintf("Score %g has been saved", score)))
+	return
+}
+
+// ReadFeedbackHandler returns the feedback thread on the submission of a student,
+// replies of the student are marked as read
+func ReadFeedbackHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := studentParams{
+		scheduleID:   ps.ByName("schedule_id"),
+		assignmentID: ps.ByName("assignment_id"),
+		identityCode: ps.ByName("identity_code"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.scheduleID, args.assignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	students, err := handleGradedStudents(assignment, args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	student, ok := students[args.identityCode]
+	if !ok {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Student is not enrolled in this schedule"))
+		return
+	}
+
+	feedbacks, err := asg.SelectFeedback(assignment.ID, student.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	// only messages sent by the student are marked, read receipt of assistant messages belongs to the student
+	err = asg.ReadFeedback(assignment.ID, student.ID, student.ID, nil)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	resp, err := handleFeedbackResponse(feedbacks, student)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if assignment.IsIdentityHidden() {
+		for i := range resp {
+			if !resp[i].IsAssistant {
+				resp[i].Sender = student.Name
+			}
+		}
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// CreateFeedbackHandler sends a feedback to a student with optional returned files,
+// file_id is a "~" separated list of files uploaded with feedback role
+func CreateFeedbackHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := feedbackParams{
+		scheduleID:   ps.ByName("schedule_id"),
+		assignmentID: ps.ByName("assignment_id"),
+		identityCode: ps.ByName("identity_code"),
+		message:      r.FormValue("message"),
+		fileID:       r.FormValue("file_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.scheduleID, args.assignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	students, err := handleGradedStudents(assignment, args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	student, ok := students[args.identityCode]
+	if !ok {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Student is not enrolled in this schedule"))
+		return
+	}
+
+	err = handleFeedbackFile(sess.ID, args.fileID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	err = handleFeedbackInsert(assignment, student.ID, sess.ID, student.ID, args.message, args.fileID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Feedback has been sent"))
+	return
+}
+
+// ReplyFeedbackHandler lets the student reply to the feedback thread of the submission,
+// the last assistant on the thread is notified
+func ReplyFeedbackHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	params := replyFeedbackParams{
+		id:      ps.ByName("id"),
+		message: r.FormValue("message"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, err := asg.GetByID(args.id)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Assignment does not exist"))
+		return
+	}
+
+	scheduleID, err := cs.GetScheduleIDByGP(assignment.GradeParameterID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if !cs.IsEnrolled(sess.ID, scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	feedbacks, err := asg.SelectFeedback(assignment.ID, sess.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	var assistantID int64
+	for _, val := range feedbacks {
+		if val.SenderID != sess.ID {
+			assistantID = val.SenderID
+		}
+	}
+	if assistantID == 0 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("There is no feedback to reply"))
+		return
+	}
+
+	err = handleFeedbackInsert(assignment, sess.ID, sess.ID, assistantID, args.message, nil)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Reply has been sent"))
+	return
+}
+
+// CreateSimilarityHandler starts the similarity checker on the uploaded files of the assignment,
+// the report is processed on background and can be read with ReadSimilarityHandler
+func CreateSimilarityHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := detailAssignmentParams{
+		ScheduleID:   ps.ByName("schedule_id"),
+		AssignmentID: ps.ByName("assignment_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.ScheduleID, args.AssignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = asg.ExpireSimilarityReport(assignment.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	reportID, err := asg.InsertSimilarityReport(assignment.ID, sess.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		if err.Error() == "No rows affected" {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusConflict).
+				AddError("Similarity check is still running"))
+			return
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	tx.Commit()
+
+	go handleSimilarity(assignment.ID, reportID)
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Similarity check has been started").
+		SetData(reportID))
+	return
+}
+
+// ReadSimilarityHandler returns the status and pairs of the latest similarity report of the assignment
+func ReadSimilarityHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := detailAssignmentParams{
+		ScheduleID:   ps.ByName("schedule_id"),
+		AssignmentID: ps.ByName("assignment_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.ScheduleID, args.AssignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	err = asg.ExpireSimilarityReport(assignment.ID, nil)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	report, err := asg.GetLatestSimilarityReport(assignment.ID)
+	if err == sql.ErrNoRows {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Similarity check has not been run"))
+		return
+	} else if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	resp, err := handleSimilarityResponse(assignment, args.ScheduleID, report)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// ReadSimilarityPairHandler returns the matching regions of two students on the latest similarity report
+func ReadSimilarityPairHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := similarityPairParams{
+		scheduleID:        ps.ByName("schedule_id"),
+		assignmentID:      ps.ByName("assignment_id"),
+		identityCode:      ps.ByName("identity_code"),
+		otherIdentityCode: ps.ByName("other_identity_code"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.scheduleID, args.assignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	students, err := handleGradedStudents(assignment, args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	student, ok := students[args.identityCode]
+	other, isOther := students[args.otherIdentityCode]
+	if !ok || !isOther {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Student is not enrolled in this schedule"))
+		return
+	}
+
+	report, err := asg.GetLatestSimilarityReport(assignment.ID)
+	if err == sql.ErrNoRows {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Similarity check has not been run"))
+		return
+	} else if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	pair, err := asg.GetSimilarityPair(report.ID, student.ID, other.ID)
+	if err == sql.ErrNoRows {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("No similarity found between the students"))
+		return
+	} else if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	var match similarityMatch
+	err = json.Unmarshal([]byte(pair.Matches), &match)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	// the pair is stored once, swap it so the requested student is always the user side
+	if pair.UserID != student.ID {
+		pair.ScoreUser, pair.ScoreOther = pair.ScoreOther, pair.ScoreUser
+		match.User, match.Other = match.Other, match.User
+	}
+	if match.User == nil {
+		match.User = []similarity.Region{}
+	}
+	if match.Other == nil {
+		match.Other = []similarity.Region{}
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(similarityDetailResponse{
+			similarityPairResponse: similarityPairResponse{
+				IdentityCode:      student.IdentityCode,
+				Name:              student.Name,
+				OtherIdentityCode: other.IdentityCode,
+				OtherName:         other.Name,
+				Score:             pair.Score,
+				ScoreUser:         pair.ScoreUser,
+				ScoreOther:        pair.ScoreOther,
+			},
+			RegionsUser:  match.User,
+			RegionsOther: match.Other,
+		}))
+	return
+}
+
+// ReadGradebookHandler returns scores of every student on every assessment of the schedule with the weighted total,
+// set format to csv or xlsx to download it as a spreadsheet
+func ReadGradebookHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := gradebookParams{
+		ScheduleID: ps.ByName("schedule_id"),
+		Format:     r.FormValue("format"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.ScheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	gradebook, _, err := handleGradebook(args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if args.Format == "json" {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusOK).
+			SetData(gradebook))
+		return
+	}
+
+	course, err := cs.GetByScheduleID(args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	// the sheet is built before the headers are sent so a failure can still be reported
+	name := fmt.Sprintf("%s %s", course.Course.Name, course.Schedule.Class)
+	var sheet bytes.Buffer
+	err = spreadsheet.Write(&sheet, args.Format, name, handleGradebookSheet(gradebook))
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	cntDisposition := fmt.Sprintf(`attachment; filename="gradebook_%s_%s.%s"`, time.Now().Format("20060102150405"), name, args.Format)
+	w.Header().Set("Content-Type", spreadsheet.ContentType(args.Format))
+	w.Header().Set("Content-Disposition", cntDisposition)
+	w.Header().Set("Cache-Control", "must-revalidate, post-check=0, pre-check=0")
+
+	sheet.WriteTo(w)
+	return
+}
+
+// ImportGradebookHandler updates scores from an exported gradebook which has been filled in, only changed scores
+// are saved and nothing is saved when one of them is invalid. Set preview to true to see the changes without saving
+func ImportGradebookHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	// 2 MB is more than enough for a class gradebook
+	r.ParseMultipartForm(2 << 20)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("File is not exist"))
+		return
+	}
+	defer file.Close()
+
+	params := importGradebookParams{
+		ScheduleID: ps.ByName("schedule_id"),
+		FileName:   header.Filename,
+		IsPreview:  r.FormValue("preview"),
+		Reason:     r.FormValue("reason"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.ScheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	sheet, err := spreadsheet.Read(file, header.Size, args.Format)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(fmt.Sprintf("Invalid %s file", args.Format)))
+		return
+	}
+
+	changes, entries, err := handleGradebookImport(args.ScheduleID, sheet)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	resp := gradebookPreviewResponse{
+		IsValid: true,
+		Total:   len(changes),
+		Changes: changes,
+	}
+	for _, val := range changes {
+		if !val.IsValid {
+			resp.IsValid = false
+			resp.Invalid++
+		}
+	}
+
+	if !resp.IsValid && !args.IsPreview {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(fmt.Sprintf("%d of %d scores are invalid", resp.Invalid, resp.Total)).
+			SetData(resp))
+		return
+	}
+
+	if resp.IsValid && !args.IsPreview && resp.Total > 0 {
+		err = handleGradebookSave(entries, scoreAudit{
+			Actor:  sess.ID,
+			Source: asg.ScoreSourceImport,
+			Reason: args.Reason,
+		})
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		resp.IsSaved = true
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// ReadScoreLogHandler returns the score history of the assignment from the newest change,
+// identity_code can be set to only return the history of a student
+func ReadScoreLogHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := scoreLogParams{
+		ScheduleID:   ps.ByName("schedule_id"),
+		AssignmentID: ps.ByName("assignment_id"),
+		IdentityCode: r.FormValue("identity_code"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.ScheduleID, args.AssignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	var userID *int64
+	if args.IdentityCode > 0 {
+		students, err := handleGradedStudents(assignment, args.ScheduleID)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		student, ok := students[args.IdentityCode]
+		if !ok {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusBadRequest).
+				AddError("Student is not enrolled in this schedule"))
+			return
+		}
+		userID = &student.ID
+	}
+
+	logs, err := asg.SelectScoreLog([]int64{assignment.ID}, userID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	resp, err := handleScoreLogResponse(logs, map[int64]string{assignment.ID: assignment.Name}, &assignment, args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// ReadStudentScoreLogHandler returns the score history of a student on every assignment of the schedule
+func ReadStudentScoreLogHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := studentScoreLogParams{
+		ScheduleID:   ps.ByName("schedule_id"),
+		IdentityCode: ps.ByName("identity_code"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.ScheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	students, err := handleEnrolledStudents(args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	student, ok := students[args.IdentityCode]
+	if !ok {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Student is not enrolled in this schedule"))
+		return
+	}
+
+	assignments, err := handleScheduleAssignment(args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	// scores of an anonymous assignment are not shown next to the name of the student
+	var asgID []int64
+	names := map[int64]string{}
+	for _, val := range assignments {
+		if val.IsIdentityHidden() {
+			continue
+		}
+		asgID = append(asgID, val.ID)
+		names[val.ID] = val.Name
+	}
+
+	logs, err := asg.SelectScoreLog(asgID, &student.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	resp, err := handleScoreLogResponse(logs, names, nil, args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// CreateRegradeHandler lets the student dispute the score of a scored submission with a reason and an optional attachment,
+// file_id is uploaded with the regrade role
+func CreateRegradeHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	params := createRegradeParams{
+		id:     ps.ByName("id"),
+		reason: r.FormValue("reason"),
+		fileID: r.FormValue("file_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, err := asg.GetByID(args.id)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Assignment does not exist"))
+		return
+	}
+	// an accepted request changes an individual score which a group assignment does not have
+	if handleIndividualScore(assignment) != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Group assignment can not be regraded, please contact the assistant"))
+		return
+	}
+
+	scheduleID, err := cs.GetScheduleIDByGP(assignment.GradeParameterID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if !cs.IsEnrolled(sess.ID, scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	isEnded, err := handleRegradeExpire(scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if isEnded {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("Regrade period has ended"))
+		return
+	}
+
+	submit, err := asg.GetSubmittedByUser(assignment.ID, sess.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if submit == nil || !submit.Score.Valid {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Submission has not been scored"))
+		return
+	}
+
+	if asg.IsRegradeOpen(assignment.ID, sess.ID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusConflict).
+			AddError("You already have an open regrade request on this assignment"))
+		return
+	}
+
+	code, err := handleRegradeInsert(assignment.ID, sess.ID, args.reason, args.fileID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Regrade request has been sent"))
+	return
+}
+
+// ReadRegradeHandler returns every regrade request of the student on the assignment
+func ReadRegradeHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	id, err := strconv.ParseInt(ps.ByName("id"), 10, 64)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid assignment ID"))
+		return
+	}
+
+	assignment, err := asg.GetByID(id)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Assignment does not exist"))
+		return
+	}
+
+	scheduleID, err := cs.GetScheduleIDByGP(assignment.GradeParameterID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if !cs.IsEnrolled(sess.ID, scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	_, err = handleRegradeExpire(scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	regrades, err := asg.SelectRegrade([]int64{assignment.ID}, &sess.ID, nil)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	resp, err := handleRegradeResponse(regrades, map[int64]asg.Assignment{assignment.ID: assignment}, scheduleID, false)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// ReadRegradeQueueHandler returns the open and escalated regrade requests of the schedule from the oldest one
+func ReadRegradeQueueHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := scoreParams{
+		ScheduleID: ps.ByName("schedule_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.ScheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	_, err = handleRegradeExpire(args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	assignments, err := handleScheduleAssignment(args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	var asgID []int64
+	asgMap := map[int64]asg.Assignment{}
+	for _, val := range assignments {
+		asgID = append(asgID, val.ID)
+		asgMap[val.ID] = val
+	}
+
+	regrades, err := asg.SelectRegrade(asgID, nil, []int8{asg.RegradeOpen, asg.RegradeEscalated})
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	resp, err := handleRegradeResponse(regrades, asgMap, args.ScheduleID, true)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// ResolveRegradeHandler resolves the regrade request, action is accept with the new score, reject with a response
+// or escalate to the schedule creator. Escalated request can only be resolved by the schedule creator
+func ResolveRegradeHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := resolveRegradeParams{
+		ScheduleID: ps.ByName("schedule_id"),
+		RegradeID:  ps.ByName("regrade_id"),
+		Action:     r.FormValue("action"),
+		Score:      r.FormValue("score"),
+		Response:   r.FormValue("response"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	regrade, err := asg.GetRegrade(args.RegradeID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if regrade == nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Regrade request does not exist"))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.ScheduleID, regrade.AssignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	isEnded, err := handleRegradeExpire(args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if isEnded || (regrade.Status != asg.RegradeOpen && regrade.Status != asg.RegradeEscalated) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Regrade request has been closed"))
+		return
+	}
+
+	code, err = handleRegradeResolve(assignment, *regrade, args, sess.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Regrade request has been updated"))
+	return
+}
+
+// ReadPeerReviewHandler returns the anonymous submissions the student has to review and,
+// once the review window is closed, the reviews the student received
+func ReadPeerReviewHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	id, err := strconv.ParseInt(ps.ByName("id"), 10, 64)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid assignment ID"))
+		return
+	}
+
+	now, err := publish.Now()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	assignment, err := asg.GetByID(id)
+	if err != nil || !assignment.IsPublished(now) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Assignment does not exist"))
+		return
+	}
+
+	scheduleID, err := cs.GetScheduleIDByGP(assignment.GradeParameterID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if !cs.IsEnrolled(sess.ID, scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	if !assignment.IsPeerReview() {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Assignment does not use peer review"))
+		return
+	}
+
+	// the cron assigns the reviews at the deadline, it is also done here in case the cron is disabled
+	err = asg.AssignPeer(assignment, now)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	var rubric *rb.Rubric
+	if assignment.RubricID.Valid {
+		rubric, err = rb.Get(assignment.RubricID.Int64)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	tasks, err := asg.SelectPeerReview(assignment.ID, &sess.ID, nil)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	var received []asg.PeerReview
+	if assignment.IsPeerReviewClosed(now) {
+		received, err = asg.SelectPeerReview(assignment.ID, nil, &sess.ID)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	resp, err := handlePeerResponse(assignment, rubric, tasks, received, now)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// SubmitPeerReviewHandler fills the rubric of a submission assigned to the student,
+// levels is a json list of criterion_id, level_id and comment. A review may be filled again while the window is open
+func SubmitPeerReviewHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	params := submitPeerReviewParams{
+		id:       ps.ByName("id"),
+		reviewID: ps.ByName("review_id"),
+		levels:   r.FormValue("levels"),
+		comment:  r.FormValue("comment"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	review, err := asg.GetPeerReview(args.reviewID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if review == nil || review.AssignmentID != args.id || review.ReviewerID != sess.ID {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Peer review does not exist"))
+		return
+	}
+
+	assignment, err := asg.GetByID(args.id)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Assignment does not exist"))
+		return
+	}
+	now, err := publish.Now()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if !assignment.IsPeerReviewOpen(now) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Peer review is closed"))
+		return
+	}
+	if !assignment.RubricID.Valid {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Assignment does not use a rubric"))
+		return
+	}
+
+	rubric, err := rb.Get(assignment.RubricID.Int64)
+	if err != nil || rubric == nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	scores, err := rubric.Fill(args.levels, args.comments)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+	_, score := rubric.Total(scores)
+
+	var reviewScores []asg.PeerReviewScore
+	for _, val := range scores {
+		reviewScores = append(reviewScores, asg.PeerReviewScore{
+			ReviewID:    review.ID,
+			CriterionID: val.CriterionID,
+			LevelID:     val.LevelID,
+			Points:      val.Points,
+			Comment:     val.Comment,
+		})
+	}
+
+	tx := conn.DB.MustBegin()
+	err = asg.SubmitPeerReview(review.ID, score, args.comment, reviewScores, tx)
+	if err != nil {
+		tx.Rollback()
+		if err.Error() == "No rows affected" {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusConflict).
+				AddError("Peer review has been excluded"))
+			return
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Peer review has been saved"))
+	return
+}
+
+// ReadPeerReviewAdminHandler returns every peer review of the assignment with the reviewers,
+// the outlier reviews and the peer score of every student
+func ReadPeerReviewAdminHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := detailAssignmentParams{
+		ScheduleID:   ps.ByName("schedule_id"),
+		AssignmentID: ps.ByName("assignment_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.ScheduleID, args.AssignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	if !assignment.IsPeerReview() {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Assignment does not use peer review"))
+		return
+	}
+
+	now, err := publish.Now()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	// the cron assigns the reviews at the deadline, it is also done here in case the cron is disabled
+	err = asg.AssignPeer(assignment, now)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	var rubric *rb.Rubric
+	if assignment.RubricID.Valid {
+		rubric, err = rb.Get(assignment.RubricID.Int64)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	reviews, err := asg.SelectPeerReview(assignment.ID, nil, nil)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	resp, err := handlePeerAdminResponse(assignment, args.ScheduleID, rubric, reviews, now)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// ModeratePeerReviewHandler excludes a submitted peer review from the peer score or includes it back
+func ModeratePeerReviewHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := moderatePeerReviewParams{
+		scheduleID:   ps.ByName("schedule_id"),
+		assignmentID: ps.ByName("assignment_id"),
+		reviewID:     ps.ByName("review_id"),
+		action:       r.FormValue("action"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.scheduleID, args.assignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	review, err := asg.GetPeerReview(args.reviewID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if review == nil || review.AssignmentID != assignment.ID {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Peer review does not exist"))
+		return
+	}
+
+	err = asg.ModeratePeerReview(review.ID, args.from, args.status, sess.ID, nil)
+	if err != nil {
+		if err.Error() == "No rows affected" {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusConflict).
+				AddError(fmt.Sprintf("Peer review is %s", asg.PeerStatuses[review.Status])))
+			return
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Peer review has been updated"))
+	return
+}
+
+// ApplyPeerScoreHandler combines the peer score into the score of every reviewed student
+// with the weight of the assignment, it can only be done after the review window is closed
+func ApplyPeerScoreHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := detailAssignmentParams{
+		ScheduleID:   ps.ByName("schedule_id"),
+		AssignmentID: ps.ByName("assignment_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.ScheduleID, args.AssignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	if !assignment.IsPeerReview() {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Assignment does not use peer review"))
+		return
+	}
+	now, err := publish.Now()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if !assignment.IsPeerReviewClosed(now) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Peer review has not been closed"))
+		return
+	}
+
+	count, err := handlePeerApply(assignment, sess.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage(fmt.Sprintf("Peer score has been applied to %d students", count)))
+	return
+}
+
+// RevealHandler shows the identities of the students on an anonymous assignment,
+// it can only be done after every student who needs a score has been scored
+func RevealHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := detailAssignmentParams{
+		ScheduleID:   ps.ByName("schedule_id"),
+		AssignmentID: ps.ByName("assignment_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.ScheduleID, args.AssignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	if !assignment.IsAnonymous() {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Assignment is not graded anonymously"))
+		return
+	}
+
+	unscored, err := handleUnscoredCount(assignment, args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if unscored > 0 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(fmt.Sprintf("%d students have not been scored", unscored)))
+		return
+	}
+
+	err = asg.Reveal(assignment.ID, nil)
+	if err != nil {
+		if err.Error() == "No rows affected" {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusConflict).
+				AddError("Identities have been revealed"))
+			return
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Identities have been revealed"))
+	return
+}
+
+// ReadStatisticHandler returns the submission and score statistics of every assignment and grade parameter
+// of the schedule, bins is the number of histogram bins. Other classes of the same course are only shown as aggregates
+func ReadStatisticHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := statisticParams{
+		ScheduleID: ps.ByName("schedule_id"),
+		Bins:       r.FormValue("bins"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.ScheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	resp, err := handleStatistic(args.ScheduleID, args.Bins)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// ReadAutograderTestHandler returns the test cases of the assignment which submissions are graded with
+func ReadAutograderTestHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := detailAssignmentParams{
+		ScheduleID:   ps.ByName("schedule_id"),
+		AssignmentID: ps.ByName("assignment_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.ScheduleID, args.AssignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	tests, err := asg.SelectAutograderTest(assignment.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	resp := []autograderTestResponse{}
+	for _, val := range tests {
+		resp = append(resp, autograderTestResponse{
+			ID:             val.ID,
+			Name:           val.Name,
+			Input:          val.Input,
+			ExpectedOutput: val.ExpectedOutput,
+			Timeout:        val.Timeout,
+			Points:         val.Points,
+		})
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// CreateAutograderTestHandler adds a test case to the assignment, timeout is in milliseconds
+// and points is the weight of the test on the suggested score
+func CreateAutograderTestHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := autograderTestParams{
+		scheduleID:     ps.ByName("schedule_id"),
+		assignmentID:   ps.ByName("assignment_id"),
+		name:           r.FormValue("name"),
+		input:          r.FormValue("input"),
+		expectedOutput: r.FormValue("expected_output"),
+		timeout:        r.FormValue("timeout"),
+		points:         r.FormValue("points"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.scheduleID, args.assignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	tests, err := asg.SelectAutograderTest(assignment.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if len(tests) >= asg.MaxAutograderTests {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(fmt.Sprintf("Assignment maximum has %d test cases", asg.MaxAutograderTests)))
+		return
+	}
+
+	testID, err := asg.InsertAutograderTest(asg.AutograderTest{
+		AssignmentID:   assignment.ID,
+		Name:           args.name,
+		Input:          args.input,
+		ExpectedOutput: args.expectedOutput,
+		Timeout:        args.timeout,
+		Points:         args.points,
+	}, nil)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Test case has been added").
+		SetData(testID))
+	return
+}
+
+// DeleteAutograderTestHandler removes a test case of the assignment, results of previous runs are kept
+func DeleteAutograderTestHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := deleteAutograderTestParams{
+		scheduleID:   ps.ByName("schedule_id"),
+		assignmentID: ps.ByName("assignment_id"),
+		testID:       ps.ByName("test_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.scheduleID, args.assignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	err = asg.DeleteAutograderTest(assignment.ID, args.testID, nil)
+	if err != nil {
+		if err.Error() == "No rows affected" {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusNotFound).
+				AddError("Test case does not exist"))
+			return
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Test case has been deleted"))
+	return
+}
+
+// CreateAutograderHandler starts the autograder on the submissions of the assignment,
+// the run is processed on background and can be read with ReadAutograderHandler
+func CreateAutograderHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := detailAssignmentParams{
+		ScheduleID:   ps.ByName("schedule_id"),
+		AssignmentID: ps.ByName("assignment_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.ScheduleID, args.AssignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	tests, err := asg.SelectAutograderTest(assignment.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if len(tests) < 1 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Assignment has no test case"))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = asg.ExpireAutograderRun(assignment.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	runID, err := asg.InsertAutograderRun(assignment.ID, sess.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		if err.Error() == "No rows affected" {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusConflict).
+				AddError("Autograder is still running"))
+			return
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	go handleAutograde(assignment.ID, runID)
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Autograder has been started").
+		SetData(runID))
+	return
+}
+
+// ReadAutograderHandler returns the status and the results of the latest autograder run of the assignment
+func ReadAutograderHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := detailAssignmentParams{
+		ScheduleID:   ps.ByName("schedule_id"),
+		AssignmentID: ps.ByName("assignment_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.ScheduleID, args.AssignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	err = asg.ExpireAutograderRun(assignment.ID, nil)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	run, err := asg.GetLatestAutograderRun(assignment.ID)
+	if err == sql.ErrNoRows {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Autograder has not been run"))
+		return
+	} else if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	resp, err := handleAutograderResponse(assignment, args.ScheduleID, run)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// ConfirmAutograderHandler saves the suggested scores of the latest autograder run as the scores of the students,
+// identity_codes is separated by ~ and is the anonymous code while the identities are hidden
+func ConfirmAutograderHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := confirmAutograderParams{
+		scheduleID:    ps.ByName("schedule_id"),
+		assignmentID:  ps.ByName("assignment_id"),
+		identityCodes: r.FormValue("identity_codes"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleScoreAccess(sess.ID, args.scheduleID, args.assignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	run, err := asg.GetLatestAutograderRun(assignment.ID)
+	if err == sql.ErrNoRows {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Autograder has not been run"))
+		return
+	} else if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if run.Status != asg.AutograderDone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusConflict).
+			AddError("Autograder has not finished"))
+		return
+	}
+
+	err = handleIndividualScore(assignment)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	students, err := handleGradedStudents(assignment, args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	var usersID []int64
+	for _, val := range args.identityCodes {
+		student, ok := students[val]
+		if !ok {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusBadRequest).
+				AddError(fmt.Sprintf("Student %d is not enrolled in this schedule", val)))
+			return
+		}
+		usersID = append(usersID, student.ID)
+	}
+
+	count, err := handleAutograderConfirm(assignment.ID, run, usersID, sess.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage(fmt.Sprintf("Suggested score has been confirmed for %d students", count)))
 	return
 }
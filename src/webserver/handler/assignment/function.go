@@ -1,27 +1,59 @@
 package assignment
 
 import (
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"html"
+	"io"
+	"math"
 	"net/http"
+	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/asepnur/meiko_course/src/util/alias"
 	"github.com/asepnur/meiko_course/src/util/conn"
 
 	asg "github.com/asepnur/meiko_course/src/module/assignment"
+	att "github.com/asepnur/meiko_course/src/module/attendance"
 	cs "github.com/asepnur/meiko_course/src/module/course"
 	fl "github.com/asepnur/meiko_course/src/module/file"
+	"github.com/asepnur/meiko_course/src/module/grade"
+	nt "github.com/asepnur/meiko_course/src/module/notification"
+	rb "github.com/asepnur/meiko_course/src/module/rubric"
+	usr "github.com/asepnur/meiko_course/src/module/user"
 	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/asepnur/meiko_course/src/util/publish"
+	"github.com/asepnur/meiko_course/src/util/sandbox"
+	"github.com/asepnur/meiko_course/src/util/similarity"
+	"github.com/asepnur/meiko_course/src/util/statistic"
+	"github.com/asepnur/meiko_course/src/webserver/template"
+	"github.com/jmoiron/sqlx"
 )
 
-func handleSubmitInsert(id, userID int64, desc sql.NullString, fileID []string) error {
+func handleSubmitInsert(id, userID int64, desc sql.NullString, lateSeconds int64, fileID []string) error {
 	tableID := strconv.FormatInt(id, 10)
+	hash, err := handleVersionHash(desc, fileID)
+	if err != nil {
+		return err
+	}
 
 	tx := conn.DB.MustBegin()
 
 	// update assignment
-	err := asg.InsertSubmit(id, userID, desc, tx)
+	err = asg.InsertSubmit(id, userID, desc, lateSeconds, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = asg.InsertVersion(id, userID, desc, lateSeconds, hash, userID, fileID, tx)
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -40,13 +72,18 @@ func handleSubmitInsert(id, userID int64, desc sql.NullString, fileID []string)
 	return nil
 }
 
-func handleSubmitUpdate(id, userID int64, desc sql.NullString, fileID []string) error {
+func handleSubmitUpdate(id, userID int64, desc sql.NullString, lateSeconds int64, fileID []string) error {
 	tableID := strconv.FormatInt(id, 10)
 	oldFile, err := fl.SelectIDByRelation(fl.TypAssignmentUpload, tableID, userID)
 	if err != nil {
 		return err
 	}
 
+	hash, err := handleVersionHash(desc, fileID)
+	if err != nil {
+		return err
+	}
+
 	var insert []string
 	for _, val := range fileID {
 		if !helper.IsStringInSlice(val, oldFile) {
@@ -64,7 +101,14 @@ func handleSubmitUpdate(id, userID int64, desc sql.NullString, fileID []string)
 	tx := conn.DB.MustBegin()
 
 	// update assignment
-	err = asg.UpdateSubmit(id, userID, desc, tx)
+	err = asg.UpdateSubmit(id, userID, desc, lateSeconds, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// previous files are kept on disk and referenced by older versions
+	err = asg.InsertVersion(id, userID, desc, lateSeconds, hash, userID, fileID, tx)
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -94,6 +138,117 @@ func handleSubmitUpdate(id, userID int64, desc sql.NullString, fileID []string)
 	return nil
 }
 
+// handleSubmitGroup saves the submission and a new version for every member of the group,
+// the submission is shared so every member gets the lateness of the assignment
+func handleSubmitGroup(assignment asg.Assignment, groupID, submittedBy int64, desc sql.NullString, fileID []string, now time.Time) error {
+	tableID := strconv.FormatInt(assignment.ID, 10)
+
+	members, err := asg.SelectGroupMember([]int64{groupID})
+	if err != nil {
+		return err
+	}
+	var usersID []int64
+	for _, val := range members {
+		usersID = append(usersID, val.UserID)
+	}
+
+	submitted, err := asg.SelectSubmittedByAssignment([]int64{assignment.ID})
+	if err != nil {
+		return err
+	}
+	isSubmitted := map[int64]bool{}
+	for _, val := range submitted {
+		isSubmitted[val.UserID] = true
+	}
+
+	oldFile, err := handleGroupFileID(tableID, usersID)
+	if err != nil {
+		return err
+	}
+
+	hash, err := handleVersionHash(desc, fileID)
+	if err != nil {
+		return err
+	}
+
+	lateSeconds := assignment.Lateness(now)
+	tx := conn.DB.MustBegin()
+	for _, val := range usersID {
+		if isSubmitted[val] {
+			err = asg.UpdateSubmit(assignment.ID, val, desc, lateSeconds, tx)
+		} else {
+			err = asg.InsertSubmit(assignment.ID, val, desc, lateSeconds, tx)
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		err = asg.InsertVersion(assignment.ID, val, desc, lateSeconds, hash, submittedBy, fileID, tx)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	err = asg.SetSubmissionGroup(assignment.ID, groupID, usersID, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// insert new file
+	for _, val := range fileID {
+		if !helper.IsStringInSlice(val, oldFile) {
+			if err = fl.UpdateRelation(val, fl.TypAssignmentUpload, tableID, tx); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+
+	// delete old file
+	for _, val := range oldFile {
+		if !helper.IsStringInSlice(val, fileID) {
+			if err = fl.Delete(val, tx); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// handleGroupFile returns files submitted by any member of the group
+func handleGroupFile(tableID string, usersID []int64) ([]fl.File, error) {
+	files, err := fl.SelectByRelation(fl.TypAssignmentUpload, []string{tableID}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	groupFiles := []fl.File{}
+	for _, val := range files {
+		if helper.Int64InSlice(val.UserID, usersID) {
+			groupFiles = append(groupFiles, val)
+		}
+	}
+	return groupFiles, nil
+}
+
+func handleGroupFileID(tableID string, usersID []int64) ([]string, error) {
+	files, err := handleGroupFile(tableID, usersID)
+	if err != nil {
+		return nil, err
+	}
+
+	var filesID []string
+	for _, val := range files {
+		filesID = append(filesID, val.ID)
+	}
+	return filesID, nil
+}
+
 func handleGradeBySchedule(scheduleID, userID int64) ([]getGradeResponse, int, error) {
 
 	resp := []getGradeResponse{}
@@ -122,10 +277,10 @@ func handleGradeBySchedule(scheduleID, userID int64) ([]getGradeResponse, int, e
 
 	var asgID []int64
 	var asgGP = make(map[int64]cs.GradeParameter)
-	var asgName = make(map[int64]string)
+	var asgMap = make(map[int64]asg.Assignment)
 	for _, assignment := range assignments {
 		asgID = append(asgID, assignment.ID)
-		asgName[assignment.ID] = assignment.Name
+		asgMap[assignment.ID] = assignment
 		for _, gp := range gps {
 			if assignment.GradeParameterID == gp.ID {
 				asgGP[assignment.ID] = gp
@@ -138,15 +293,2544 @@ func handleGradeBySchedule(scheduleID, userID int64) ([]getGradeResponse, int, e
 		return resp, http.StatusInternalServerError, err
 	}
 
+	policy, err := grade.GetPolicy(scheduleID)
+	if err != nil {
+		return resp, http.StatusInternalServerError, err
+	}
+
 	for _, val := range submitted {
 		if val.Score.Valid {
+			assignment := asgMap[val.AssignmentID]
 			resp = append(resp, getGradeResponse{
-				Name:  asgName[val.AssignmentID],
+				Name:  assignment.Name,
 				Type:  strings.ToLower(asgGP[val.AssignmentID].Type),
-				Score: fmt.Sprintf("%.3g", val.Score.Float64),
+				Score: policy.Format(grade.SubmissionScore(assignment, val, true).Final()),
 			})
 		}
 	}
 
 	return resp, http.StatusOK, nil
 }
+
+// handleScoreAccess returns the assignment when it belongs to the schedule and user is the assistant of the schedule
+func handleScoreAccess(userID, scheduleID, assignmentID int64) (asg.Assignment, int, error) {
+
+	assignment, err := asg.GetByID(assignmentID)
+	if err != nil {
+		return assignment, http.StatusNotFound, fmt.Errorf("Assignment does not exist")
+	}
+
+	schID, err := cs.GetScheduleIDByGP(assignment.GradeParameterID)
+	if err != nil {
+		return assignment, http.StatusInternalServerError, err
+	}
+
+	if schID != scheduleID {
+		return assignment, http.StatusBadRequest, fmt.Errorf("Assignment does not belong to the schedule")
+	}
+
+	if !cs.IsAssistant(userID, scheduleID) {
+		return assignment, http.StatusForbidden, fmt.Errorf("You don't have privilege")
+	}
+
+	return assignment, http.StatusOK, nil
+}
+
+// handleIndividualScore makes sure the score of every student of the assignment can be set on its own,
+// members of a group assignment are scored through the group score and their adjustment so an individual
+// score would be overwritten by the next group score or adjustment
+func handleIndividualScore(assignment asg.Assignment) error {
+	if assignment.GroupMode != asg.GroupModeNone {
+		return fmt.Errorf("Score of a group assignment has to be set on the group")
+	}
+	return nil
+}
+
+// handleScoreRows validates every score row against enrolled students of the schedule,
+// entries are returned only for valid rows so caller can decide whether to save or only preview
+func handleScoreRows(assignment asg.Assignment, scheduleID int64, rows []scoreRow) ([]scorePreview, []scoreEntry, error) {
+	previews := []scorePreview{}
+	entries := []scoreEntry{}
+
+	students, err := handleGradedStudents(assignment, scheduleID)
+	if err != nil {
+		return previews, entries, err
+	}
+
+	submitted, err := asg.SelectSubmittedByAssignment([]int64{assignment.ID})
+	if err != nil {
+		return previews, entries, err
+	}
+	submissions := map[int64]asg.UserAssignment{}
+	for _, val := range submitted {
+		submissions[val.UserID] = val
+	}
+
+	policy, err := grade.GetPolicy(scheduleID)
+	if err != nil {
+		return previews, entries, err
+	}
+
+	individual := handleIndividualScore(assignment)
+	scored := map[int64]bool{}
+	for _, row := range rows {
+		preview := scorePreview{
+			Line:         row.Line,
+			IdentityCode: row.IdentityCode,
+			Score:        row.Score,
+			Feedback:     row.Feedback,
+			OldScore:     "-",
+		}
+		if individual != nil {
+			preview.Error = individual.Error()
+			previews = append(previews, preview)
+			continue
+		}
+
+		identityCode, score, feedback, err := validateScoreRow(row)
+		if err != nil {
+			preview.Error = err.Error()
+			previews = append(previews, preview)
+			continue
+		}
+
+		student, ok := students[identityCode]
+		if !ok {
+			preview.Error = "Student is not enrolled in this schedule"
+			previews = append(previews, preview)
+			continue
+		}
+		preview.Name = student.Name
+
+		submission, isSubmitted := submissions[student.ID]
+		if isSubmitted && submission.Score.Valid {
+			preview.OldScore = policy.Format(submission.Score.Float64)
+		}
+
+		if scored[identityCode] {
+			preview.Error = "Duplicate identity code"
+			previews = append(previews, preview)
+			continue
+		}
+		scored[identityCode] = true
+
+		if assignment.Status == asg.StatusUploadRequired && !isSubmitted {
+			preview.Error = "Student has not submitted the assignment"
+			previews = append(previews, preview)
+			continue
+		}
+
+		preview.IsValid = true
+		previews = append(previews, preview)
+		entries = append(entries, scoreEntry{
+			UserID:   student.ID,
+			Score:    score,
+			Feedback: feedback,
+		})
+	}
+
+	return previews, entries, nil
+}
+
+// handleScoreSave saves all scores of the assignment in a single transaction, every changed score is logged
+func handleScoreSave(assignmentID int64, entries []scoreEntry, audit scoreAudit) error {
+	tx := conn.DB.MustBegin()
+	err := handleScoreWrite(assignmentID, entries, audit, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// handleScoreWrite saves the scores of the assignment and logs every changed score within tx
+func handleScoreWrite(assignmentID int64, entries []scoreEntry, audit scoreAudit, tx *sqlx.Tx) error {
+	var usersID []int64
+	for _, val := range entries {
+		usersID = append(usersID, val.UserID)
+	}
+
+	old, err := asg.SelectScoreByUser(assignmentID, usersID, tx)
+	if err != nil {
+		return err
+	}
+	for _, val := range entries {
+		err := asg.UpsertScore(assignmentID, val.UserID, val.Score, val.Feedback, tx)
+		if err != nil {
+			return err
+		}
+	}
+	return asg.LogScore(assignmentID, usersID, old, audit.Actor, audit.Source, audit.Reason, tx)
+}
+
+// handleScoreUpload validates the rows and saves them only when every row is valid and it is not a preview
+func handleScoreUpload(w http.ResponseWriter, assignment asg.Assignment, scheduleID int64, rows []scoreRow, isPreview bool, audit scoreAudit) {
+
+	previews, entries, err := handleScoreRows(assignment, scheduleID, rows)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	resp := scorePreviewResponse{
+		IsValid: len(entries) == len(rows),
+		Total:   len(rows),
+		Invalid: len(rows) - len(entries),
+		Rows:    previews,
+	}
+
+	if !resp.IsValid && !isPreview {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(fmt.Sprintf("%d of %d scores are invalid", resp.Invalid, resp.Total)).
+			SetData(resp))
+		return
+	}
+
+	if resp.IsValid && !isPreview {
+		err = handleScoreSave(assignment.ID, entries, audit)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		resp.IsSaved = true
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+}
+
+// handleLatenessUpdate recalculates the late seconds of every version of the user against the due date of
+// the assignment, which has to be extended for the user already, and sets the late seconds of the submission
+// from the graded version. Submission without any version is kept as it is
+func handleLatenessUpdate(assignment asg.Assignment, userID int64, tx *sqlx.Tx) error {
+	submit, err := asg.GetSubmittedByUser(assignment.ID, userID)
+	if err != nil {
+		return err
+	}
+	if submit == nil {
+		return nil
+	}
+	versions, err := asg.SelectVersion(assignment.ID, userID)
+	if err != nil {
+		return err
+	}
+	if len(versions) < 1 {
+		return nil
+	}
+
+	lateSeconds := assignment.Lateness(versions[0].CreatedAt)
+	for _, val := range versions {
+		late := assignment.Lateness(val.CreatedAt)
+		if submit.PinnedVersion.Valid && val.Version == submit.PinnedVersion.Int64 {
+			lateSeconds = late
+		}
+		if late == val.LateSeconds {
+			continue
+		}
+		err = asg.UpdateVersionLateness(val.ID, late, tx)
+		if err != nil {
+			return err
+		}
+	}
+	if lateSeconds == submit.LateSeconds {
+		return nil
+	}
+	return asg.UpdatePinnedVersion(assignment.ID, userID, submit.PinnedVersion, lateSeconds, tx)
+}
+
+// handleLateness formats the lateness of a submission into days, hours and minutes rounded up to a minute
+func handleLateness(lateSeconds int64) string {
+	if lateSeconds < 1 {
+		return "-"
+	}
+
+	minutes := (lateSeconds + 59) / 60
+	units := []struct {
+		name string
+		size int64
+	}{
+		{name: "day", size: 24 * 60},
+		{name: "hour", size: 60},
+		{name: "minute", size: 1},
+	}
+
+	var late []string
+	for _, unit := range units {
+		value := minutes / unit.size
+		minutes %= unit.size
+		if value < 1 {
+			continue
+		}
+		if value > 1 {
+			late = append(late, fmt.Sprintf("%d %ss", value, unit.name))
+			continue
+		}
+		late = append(late, fmt.Sprintf("%d %s", value, unit.name))
+	}
+	return strings.Join(late, " ")
+}
+
+// handleAssignmentAccess returns the assignment and its schedule when user is the assistant of the schedule
+func handleAssignmentAccess(userID, assignmentID int64) (asg.Assignment, int64, int, error) {
+
+	assignment, err := asg.GetByID(assignmentID)
+	if err != nil {
+		return assignment, 0, http.StatusNotFound, fmt.Errorf("Assignment does not exist")
+	}
+
+	scheduleID, err := cs.GetScheduleIDByGP(assignment.GradeParameterID)
+	if err != nil {
+		return assignment, 0, http.StatusInternalServerError, err
+	}
+
+	if !cs.IsAssistant(userID, scheduleID) {
+		return assignment, scheduleID, http.StatusForbidden, fmt.Errorf("You don't have privilege")
+	}
+
+	return assignment, scheduleID, http.StatusOK, nil
+}
+
+// handleEnrolledStudents returns enrolled students of the schedule keyed by identity code
+func handleEnrolledStudents(scheduleID int64) (map[int64]usr.UserReq, error) {
+	students := map[int64]usr.UserReq{}
+
+	studentsID, err := cs.SelectEnrolledStudentID(scheduleID)
+	if err != nil {
+		return students, err
+	}
+	if len(studentsID) < 1 {
+		return students, nil
+	}
+
+	users, err := usr.RequestID(studentsID, false)
+	if err != nil {
+		return students, err
+	}
+	for _, val := range users {
+		students[val.IdentityCode] = val
+	}
+	return students, nil
+}
+
+// handleGradedStudents returns enrolled students of the schedule keyed by the code assistants grade them with.
+// While the identities of an anonymous assignment are hidden it is the anonymous code and the name is replaced
+func handleGradedStudents(assignment asg.Assignment, scheduleID int64) (map[int64]usr.UserReq, error) {
+	students, err := handleEnrolledStudents(scheduleID)
+	if err != nil || !assignment.IsIdentityHidden() {
+		return students, err
+	}
+	return handleAnonymousStudents(assignment, students), nil
+}
+
+// handleUnscoredCount returns the number of enrolled students who still need a score on the assignment,
+// only students who have submitted need one when submission is required
+func handleUnscoredCount(assignment asg.Assignment, scheduleID int64) (int, error) {
+	studentsID, err := cs.SelectEnrolledStudentID(scheduleID)
+	if err != nil {
+		return 0, err
+	}
+	submitted, err := asg.SelectSubmittedByAssignment([]int64{assignment.ID})
+	if err != nil {
+		return 0, err
+	}
+	submissions := map[int64]asg.UserAssignment{}
+	for _, val := range submitted {
+		submissions[val.UserID] = val
+	}
+
+	var count int
+	for _, val := range studentsID {
+		submission, ok := submissions[val]
+		if !ok && assignment.Status == asg.StatusUploadRequired {
+			continue
+		}
+		if !ok || !submission.Score.Valid {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// handleShownStudents returns userID => the users as they are shown to assistants on the assignment.
+// While the identities of the assignment are hidden enrolled students get their anonymous code and
+// users who are no longer enrolled are only shown as anonymous
+func handleShownStudents(assignment asg.Assignment, scheduleID int64, users []usr.UserReq) (map[int64]usr.UserReq, error) {
+	userMap := map[int64]usr.UserReq{}
+	for _, val := range users {
+		userMap[val.ID] = val
+	}
+	if !assignment.IsIdentityHidden() {
+		return userMap, nil
+	}
+
+	students, err := handleGradedStudents(assignment, scheduleID)
+	if err != nil {
+		return userMap, err
+	}
+	for _, val := range users {
+		userMap[val.ID] = usr.UserReq{ID: val.ID, Name: "Anonymous"}
+	}
+	for _, val := range students {
+		if _, ok := userMap[val.ID]; ok {
+			userMap[val.ID] = val
+		}
+	}
+	return userMap, nil
+}
+
+// handleAnonymousStudents replaces the identity code and the name of the students with their anonymous code
+func handleAnonymousStudents(assignment asg.Assignment, students map[int64]usr.UserReq) map[int64]usr.UserReq {
+	var usersID []int64
+	for _, val := range students {
+		usersID = append(usersID, val.ID)
+	}
+	codes := assignment.AnonymousCodes(usersID)
+
+	anonymous := map[int64]usr.UserReq{}
+	for _, val := range students {
+		code := codes[val.ID]
+		anonymous[code] = usr.UserReq{
+			ID:           val.ID,
+			Name:         fmt.Sprintf("Anonymous %d", code),
+			IdentityCode: code,
+		}
+	}
+	return anonymous
+}
+
+// handleExtensionResponse builds the extension list including the student and the granting assistant,
+// students are shown the way they are graded so they stay anonymous while the identities are hidden
+func handleExtensionResponse(assignment asg.Assignment, scheduleID int64, exts []asg.Extension) ([]extensionResponse, error) {
+	resp := []extensionResponse{}
+	if len(exts) < 1 {
+		return resp, nil
+	}
+
+	var usersID, studentsID []int64
+	for _, val := range exts {
+		usersID = append(usersID, val.GrantedBy)
+		studentsID = append(studentsID, val.UserID)
+	}
+	users, err := usr.RequestID(usersID, false)
+	if err != nil {
+		return resp, err
+	}
+	userMap := map[int64]usr.UserReq{}
+	for _, val := range users {
+		userMap[val.ID] = val
+	}
+	students, err := usr.RequestID(studentsID, false)
+	if err != nil {
+		return resp, err
+	}
+	studentMap, err := handleShownStudents(assignment, scheduleID, students)
+	if err != nil {
+		return resp, err
+	}
+
+	for _, val := range exts {
+		closeDate := "-"
+		if val.CloseDate.Valid {
+			closeDate = val.CloseDate.Time.Format("Monday, 2 January 2006 15:04:05")
+		}
+		resp = append(resp, extensionResponse{
+			IdentityCode: studentMap[val.UserID].IdentityCode,
+			Name:         studentMap[val.UserID].Name,
+			DueDate:      val.DueDate.Format("Monday, 2 January 2006 15:04:05"),
+			CloseDate:    closeDate,
+			Reason:       val.Reason,
+			GrantedBy:    userMap[val.GrantedBy].Name,
+			UpdatedAt:    val.UpdatedAt.Format("Monday, 2 January 2006 15:04:05"),
+		})
+	}
+	return resp, nil
+}
+
+// handleGroupDetail returns the group with its member names and the files submitted by any member
+func handleGroupDetail(group asg.Group, tableID string) (*groupResponse, []fl.File, error) {
+	members, err := asg.SelectGroupMember([]int64{group.ID})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var usersID []int64
+	for _, val := range members {
+		usersID = append(usersID, val.UserID)
+	}
+
+	resp := &groupResponse{
+		ID:      group.ID,
+		Name:    group.Name,
+		Members: []string{},
+	}
+	if len(usersID) > 0 {
+		users, err := usr.RequestID(usersID, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, val := range users {
+			resp.Members = append(resp.Members, val.Name)
+		}
+	}
+
+	files, err := handleGroupFile(tableID, usersID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, files, nil
+}
+
+// handleGroupScore saves the group score to every member and applies the member adjustments in a single transaction
+func handleGroupScore(assignment asg.Assignment, group asg.Group, args groupScoreArgs, actor int64) (int, error) {
+	members, err := asg.SelectGroupMember([]int64{group.ID})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if len(members) < 1 {
+		return http.StatusBadRequest, fmt.Errorf("Group does not have any member")
+	}
+
+	var usersID []int64
+	for _, val := range members {
+		usersID = append(usersID, val.UserID)
+	}
+
+	if assignment.Status == asg.StatusUploadRequired {
+		submitted, err := asg.SelectSubmittedByAssignment([]int64{assignment.ID})
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		isSubmitted := false
+		for _, val := range submitted {
+			if helper.Int64InSlice(val.UserID, usersID) {
+				isSubmitted = true
+				break
+			}
+		}
+		if !isSubmitted {
+			return http.StatusBadRequest, fmt.Errorf("Group has not submitted the assignment")
+		}
+	}
+
+	users, err := usr.RequestID(usersID, false)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	memberMap := map[int64]int64{}
+	for _, val := range users {
+		memberMap[val.IdentityCode] = val.ID
+	}
+
+	adjustments := map[int64]float64{}
+	for _, val := range args.Adjustments {
+		userID, ok := memberMap[val.IdentityCode]
+		if !ok {
+			return http.StatusBadRequest, fmt.Errorf("Student %d is not a member of the group", val.IdentityCode)
+		}
+		adjustments[userID] = val.Adjustment
+	}
+
+	tx := conn.DB.MustBegin()
+	old, err := asg.SelectScoreByUser(assignment.ID, usersID, tx)
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	err = asg.UpsertGroupScore(assignment.ID, group.ID, usersID, args.Score, args.Feedback, tx)
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	for userID, val := range adjustments {
+		err = asg.UpdateAdjustment(assignment.ID, userID, val, tx)
+		if err != nil {
+			tx.Rollback()
+			return http.StatusInternalServerError, err
+		}
+	}
+
+	err = asg.LogScore(assignment.ID, usersID, old, actor, asg.ScoreSourceAPI, sql.NullString{}, tx)
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+// handleVersionHash returns sha256 of the description and the content of the files ordered by file id,
+// file id is used instead of the content when the file can not be read from disk
+func handleVersionHash(desc sql.NullString, fileID []string) (string, error) {
+	files, err := fl.SelectByID(fileID)
+	if err != nil {
+		return "", err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ID < files[j].ID
+	})
+
+	h := sha256.New()
+	io.WriteString(h, desc.String)
+	for _, val := range files {
+		fmt.Fprintf(h, "\x00%s.%s\x00", val.Name, val.Extension)
+
+		path := fmt.Sprintf("%s/assignment/%s.%s", alias.Dir["data"], val.ID, val.Extension)
+		f, err := os.Open(path)
+		if err != nil {
+			io.WriteString(h, val.ID)
+			continue
+		}
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// handleSubmitFile makes sure the submitted files follow the limits of the assignment, type of every file is
+// checked by its content instead of the extension. Files which are not attached to the submission yet must be
+// uploaded by the submitter and not used anywhere else
+func handleSubmitFile(assignment asg.Assignment, userID int64, attached, fileID []string) (int, error) {
+	if len(fileID) < 1 {
+		return http.StatusOK, nil
+	}
+	if assignment.MaxFile.Valid && int64(len(fileID)) > assignment.MaxFile.Int64 {
+		return http.StatusBadRequest, fmt.Errorf("Maximum %d files can be submitted", assignment.MaxFile.Int64)
+	}
+
+	types, err := fl.SelectTypeByID(assignment.ID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	files, err := fl.SelectByID(fileID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	fileMap := map[string]fl.File{}
+	for _, val := range files {
+		fileMap[val.ID] = val
+	}
+
+	checked := map[string]bool{}
+	for _, id := range fileID {
+		file, ok := fileMap[id]
+		if !ok {
+			return http.StatusBadRequest, fmt.Errorf("File %s does not exist", id)
+		}
+		name := fmt.Sprintf("%s.%s", file.Name, file.Extension)
+		if checked[id] {
+			return http.StatusBadRequest, fmt.Errorf("%s is attached more than once", name)
+		}
+		checked[id] = true
+
+		if !helper.IsStringInSlice(id, attached) {
+			if file.Type != fl.TypAssignmentUpload || file.UserID != userID || file.TableID.Valid {
+				return http.StatusForbidden, fmt.Errorf("%s does not belong to you", name)
+			}
+		}
+
+		if len(types) > 0 && !helper.IsStringInSlice(strings.ToLower(file.Extension), types) {
+			return http.StatusBadRequest, fmt.Errorf("%s is not allowed, allowed types are %s", name, strings.Join(types, ", "))
+		}
+
+		path := fmt.Sprintf("%s/assignment/%s.%s", alias.Dir["data"], file.ID, file.Extension)
+		f, err := os.Open(path)
+		if err != nil {
+			return http.StatusBadRequest, fmt.Errorf("%s has not been uploaded completely, please upload it again", name)
+		}
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return http.StatusInternalServerError, err
+		}
+		head := make([]byte, 512)
+		n, err := io.ReadFull(f, head)
+		f.Close()
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return http.StatusInternalServerError, err
+		}
+
+		if assignment.MaxSize.Valid && info.Size() > assignment.MaxSize.Int64<<20 {
+			return http.StatusBadRequest, fmt.Errorf("%s exceeds the maximum size of %d MB", name, assignment.MaxSize.Int64)
+		}
+		if !helper.IsFileSignature(file.Extension, head[:n]) {
+			return http.StatusBadRequest, fmt.Errorf("Content of %s does not match its type", name)
+		}
+	}
+	return http.StatusOK, nil
+}
+
+// handleVersionResponse builds the version list with the files and the submitter of each version,
+// pinned version is graded when it is valid otherwise the latest version. Students are the graded students
+// of the schedule, their anonymous name is shown while the identities of the assignment are hidden
+func handleVersionResponse(assignment asg.Assignment, versions []asg.Version, pinned sql.NullInt64, students map[int64]usr.UserReq) ([]versionResponse, error) {
+	resp := []versionResponse{}
+	if len(versions) < 1 {
+		return resp, nil
+	}
+
+	var versionsID, usersID []int64
+	for _, val := range versions {
+		versionsID = append(versionsID, val.ID)
+		usersID = append(usersID, val.SubmittedBy)
+	}
+
+	versionFiles, err := asg.SelectVersionFile(versionsID)
+	if err != nil {
+		return resp, err
+	}
+	var filesID []string
+	for _, val := range versionFiles {
+		filesID = append(filesID, val.FileID)
+	}
+	files, err := fl.SelectByID(filesID)
+	if err != nil {
+		return resp, err
+	}
+	fileMap := map[string]fl.File{}
+	for _, val := range files {
+		fileMap[val.ID] = val
+	}
+	filesByVersion := map[int64][]file{}
+	for _, val := range versionFiles {
+		f, ok := fileMap[val.FileID]
+		if !ok {
+			continue
+		}
+		filesByVersion[val.VersionID] = append(filesByVersion[val.VersionID], file{
+			ID:           f.ID,
+			Name:         fmt.Sprintf("%s.%s", f.Name, f.Extension),
+			URL:          fmt.Sprintf("/api/v1/file/assignment/%s.%s", f.ID, f.Extension),
+			URLThumbnail: helper.MimeToThumbnail(f.Mime),
+		})
+	}
+
+	users, err := usr.RequestID(usersID, false)
+	if err != nil {
+		return resp, err
+	}
+	userMap := map[int64]usr.UserReq{}
+	for _, val := range users {
+		userMap[val.ID] = val
+	}
+	if assignment.IsIdentityHidden() {
+		for _, val := range students {
+			userMap[val.ID] = val
+		}
+	}
+
+	// versions are ordered from the latest one
+	graded := versions[0].Version
+	if pinned.Valid {
+		graded = pinned.Int64
+	}
+
+	for _, val := range versions {
+		versionFile := filesByVersion[val.ID]
+		if versionFile == nil {
+			versionFile = []file{}
+		}
+		resp = append(resp, versionResponse{
+			Version:     val.Version,
+			Hash:        val.Hash,
+			Description: val.Description.String,
+			IsLate:      val.LateSeconds > 0,
+			Late:        handleLateness(val.LateSeconds),
+			Penalty:     assignment.Penalty(val.LateSeconds),
+			SubmittedBy: userMap[val.SubmittedBy].Name,
+			IsGraded:    val.Version == graded,
+			Files:       versionFile,
+			CreatedAt:   val.CreatedAt.Format("Monday, 2 January 2006 15:04:05"),
+		})
+	}
+	return resp, nil
+}
+
+// handleRubricScore returns the rubric filled with the chosen levels and comments of the user
+func handleRubricScore(rubric rb.Rubric, assignmentID, userID int64) (*rubricResponse, error) {
+	scores, err := rb.SelectScore(assignmentID, []int64{userID})
+	if err != nil {
+		return nil, err
+	}
+	return handleRubricFilled(rubric, scores), nil
+}
+
+// handleRubricFilled returns the rubric filled with the chosen levels and comments of the scores
+func handleRubricFilled(rubric rb.Rubric, scores []rb.Score) *rubricResponse {
+	scoreMap := map[int64]rb.Score{}
+	for _, val := range scores {
+		scoreMap[val.CriterionID] = val
+	}
+
+	points, score := rubric.Total(scores)
+	resp := &rubricResponse{
+		ID:        rubric.ID,
+		Name:      rubric.Name,
+		MaxPoints: rubric.MaxPoints(),
+		IsFilled:  len(scores) > 0,
+		Points:    points,
+		Score:     score,
+		Criteria:  []rubricCriterionResponse{},
+	}
+	for _, criterion := range rubric.Criteria {
+		levels := []rubricLevelResponse{}
+		for _, level := range criterion.Levels {
+			levels = append(levels, rubricLevelResponse{
+				ID:          level.ID,
+				Name:        level.Name,
+				Description: level.Description.String,
+				Points:      level.Points,
+			})
+		}
+		chosen := scoreMap[criterion.ID]
+		resp.Criteria = append(resp.Criteria, rubricCriterionResponse{
+			ID:          criterion.ID,
+			Name:        criterion.Name,
+			Description: criterion.Description.String,
+			MaxPoints:   criterion.MaxPoints(),
+			Levels:      levels,
+			LevelID:     chosen.LevelID,
+			Points:      chosen.Points,
+			Comment:     chosen.Comment.String,
+		})
+	}
+	return resp
+}
+
+// handleFeedbackResponse builds the feedback thread of the student with the returned files of every message,
+// messages of the student are sent by the given student so assistants see the anonymous name while it is hidden
+func handleFeedbackResponse(feedbacks []asg.Feedback, student usr.UserReq) ([]feedbackResponse, error) {
+	resp := []feedbackResponse{}
+	if len(feedbacks) < 1 {
+		return resp, nil
+	}
+
+	var tablesID []string
+	var usersID []int64
+	for _, val := range feedbacks {
+		tablesID = append(tablesID, strconv.FormatInt(val.ID, 10))
+		usersID = append(usersID, val.SenderID)
+	}
+
+	files, err := fl.SelectByRelation(fl.TypAssignmentReturn, tablesID, nil)
+	if err != nil {
+		return resp, err
+	}
+	fileMap := map[string][]file{}
+	for _, val := range files {
+		fileMap[val.TableID.String] = append(fileMap[val.TableID.String], file{
+			ID:           val.ID,
+			Name:         fmt.Sprintf("%s.%s", val.Name, val.Extension),
+			URL:          fmt.Sprintf("/api/v1/file/assignment/%s.%s", val.ID, val.Extension),
+			URLThumbnail: helper.MimeToThumbnail(val.Mime),
+		})
+	}
+
+	users, err := usr.RequestID(usersID, false)
+	if err != nil {
+		return resp, err
+	}
+	userMap := map[int64]usr.UserReq{}
+	for _, val := range users {
+		userMap[val.ID] = val
+	}
+
+	userMap[student.ID] = student
+
+	for _, val := range feedbacks {
+		rFile := fileMap[strconv.FormatInt(val.ID, 10)]
+		if rFile == nil {
+			rFile = []file{}
+		}
+		readAt := "-"
+		if val.ReadAt.Valid {
+			readAt = val.ReadAt.Time.Format("Monday, 2 January 2006 15:04:05")
+		}
+		resp = append(resp, feedbackResponse{
+			ID:          val.ID,
+			Sender:      userMap[val.SenderID].Name,
+			IsAssistant: val.SenderID != student.ID,
+			Message:     val.Message.String,
+			Files:       rFile,
+			IsRead:      val.ReadAt.Valid,
+			ReadAt:      readAt,
+			CreatedAt:   val.CreatedAt.Format("Monday, 2 January 2006 15:04:05"),
+		})
+	}
+	return resp, nil
+}
+
+// handleFeedbackFile makes sure every returned file is uploaded by the sender and has not been attached yet
+func handleFeedbackFile(senderID int64, fileID []string) error {
+	if len(fileID) < 1 {
+		return nil
+	}
+
+	files, err := fl.SelectByID(fileID)
+	if err != nil {
+		return err
+	}
+	if len(files) != len(fileID) {
+		return fmt.Errorf("File does not exist")
+	}
+	for _, val := range files {
+		if val.UserID != senderID || val.Type != fl.TypAssignmentReturn || val.TableID.Valid {
+			return fmt.Errorf("Invalid file %s.%s", val.Name, val.Extension)
+		}
+	}
+	return nil
+}
+
+// handleFeedbackInsert saves the message with its returned files and notifies the recipient
+func handleFeedbackInsert(assignment asg.Assignment, studentID, senderID, recipientID int64, message sql.NullString, fileID []string) error {
+	tx := conn.DB.MustBegin()
+	id, err := asg.InsertFeedback(assignment.ID, studentID, senderID, message, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	tableID := strconv.FormatInt(id, 10)
+	for _, val := range fileID {
+		err = fl.UpdateRelation(val, fl.TypAssignmentReturn, tableID, tx)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if recipientID > 0 {
+		err = nt.Insert(recipientID, "New feedback",
+			fmt.Sprintf("There is a new feedback on %s", assignment.Name),
+			"assignments", strconv.FormatInt(assignment.ID, 10), tx)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// handleSimilarity compares the uploaded files of every pair of users on the assignment and saves the result
+// to the report, it is run on background so any error is saved as the report message
+func handleSimilarity(assignmentID, reportID int64) {
+	defer func() {
+		if r := recover(); r != nil {
+			asg.FinishSimilarityReport(reportID, asg.SimilarityFailed,
+				sql.NullString{Valid: true, String: html.EscapeString(fmt.Sprint(r))}, nil)
+		}
+	}()
+
+	pairs, err := handleSimilarityPair(assignmentID, reportID)
+	if err != nil {
+		asg.FinishSimilarityReport(reportID, asg.SimilarityFailed,
+			sql.NullString{Valid: true, String: html.EscapeString(err.Error())}, nil)
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = asg.InsertSimilarityPair(pairs, tx)
+	if err != nil {
+		tx.Rollback()
+		asg.FinishSimilarityReport(reportID, asg.SimilarityFailed,
+			sql.NullString{Valid: true, String: html.EscapeString(err.Error())}, nil)
+		return
+	}
+	err = asg.FinishSimilarityReport(reportID, asg.SimilarityDone, sql.NullString{}, tx)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+	tx.Commit()
+}
+
+// handleSimilarityPair extracts the text of the graded files grouped by user and compares every pair,
+// members of the same group share their submission so they are not compared to each other
+func handleSimilarityPair(assignmentID, reportID int64) ([]asg.SimilarityPair, error) {
+	// the report is expired after MaxSimilarityReport so there is no point to keep comparing
+	deadline := time.Now().Add(asg.MaxSimilarityReport * time.Second)
+	pinned, err := asg.SelectPinnedFileID(assignmentID, nil)
+	if err != nil {
+		return nil, err
+	}
+	files, err := fl.SelectGradedUpload(assignmentID, nil, pinned)
+	if err != nil {
+		return nil, err
+	}
+
+	texts := map[int64]string{}
+	for _, val := range files {
+		if !similarity.IsSupported(val.Extension) {
+			continue
+		}
+		path := fmt.Sprintf("%s/assignment/%s.%s", alias.Dir["data"], val.ID, val.Extension)
+		f, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		text, err := similarity.Extract(f, val.Extension)
+		f.Close()
+		if err != nil {
+			continue
+		}
+		texts[val.UserID] += text + "\n"
+	}
+
+	submits, err := asg.SelectSubmittedByAssignment([]int64{assignmentID})
+	if err != nil {
+		return nil, err
+	}
+	groups := map[int64]int64{}
+	for _, val := range submits {
+		if val.GroupID.Valid {
+			groups[val.UserID] = val.GroupID.Int64
+		}
+	}
+
+	var usersID []int64
+	prints := map[int64][]similarity.Print{}
+	for userID, text := range texts {
+		usersID = append(usersID, userID)
+		prints[userID] = similarity.Fingerprint(text, similarity.DefaultK, similarity.DefaultW)
+	}
+	sort.Slice(usersID, func(i, j int) bool {
+		return usersID[i] < usersID[j]
+	})
+
+	pairs := []asg.SimilarityPair{}
+	for i, userID := range usersID {
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("Similarity check took too long")
+		}
+		for _, otherID := range usersID[i+1:] {
+			if groupID, ok := groups[userID]; ok && groupID == groups[otherID] {
+				continue
+			}
+
+			result := similarity.Compare(texts[userID], prints[userID], texts[otherID], prints[otherID])
+			if result.Score <= 0 {
+				continue
+			}
+			matches, err := json.Marshal(similarityMatch{
+				User:  result.RegionA,
+				Other: result.RegionB,
+			})
+			if err != nil {
+				return nil, err
+			}
+			pairs = append(pairs, asg.SimilarityPair{
+				ReportID:    reportID,
+				UserID:      userID,
+				OtherUserID: otherID,
+				Score:       result.Score,
+				ScoreUser:   result.ScoreA,
+				ScoreOther:  result.ScoreB,
+				Matches:     string(matches),
+			})
+		}
+	}
+	return pairs, nil
+}
+
+// handleSimilarityResponse builds the report status with its pairs, the most similar pair first.
+// Students are shown the way they are graded so they stay anonymous while the identities are hidden
+func handleSimilarityResponse(assignment asg.Assignment, scheduleID int64, report asg.SimilarityReport) (*similarityReportResponse, error) {
+	pairs, err := asg.SelectSimilarityPair(report.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	creator, err := usr.RequestID([]int64{report.CreatedBy}, false)
+	if err != nil {
+		return nil, err
+	}
+	createdBy := ""
+	if len(creator) > 0 {
+		createdBy = creator[0].Name
+	}
+
+	var usersID []int64
+	for _, val := range pairs {
+		usersID = append(usersID, val.UserID, val.OtherUserID)
+	}
+	users, err := usr.RequestID(usersID, false)
+	if err != nil {
+		return nil, err
+	}
+	userMap, err := handleShownStudents(assignment, scheduleID, users)
+	if err != nil {
+		return nil, err
+	}
+
+	finishedAt := "-"
+	if report.FinishedAt.Valid {
+		finishedAt = report.FinishedAt.Time.Format("Monday, 2 January 2006 15:04:05")
+	}
+	resp := &similarityReportResponse{
+		ID:         report.ID,
+		Status:     asg.SimilarityStatuses[report.Status],
+		Message:    report.Message.String,
+		CreatedBy:  createdBy,
+		CreatedAt:  report.CreatedAt.Format("Monday, 2 January 2006 15:04:05"),
+		FinishedAt: finishedAt,
+		Pairs:      []similarityPairResponse{},
+	}
+	for _, val := range pairs {
+		resp.Pairs = append(resp.Pairs, similarityPairResponse{
+			IdentityCode:      userMap[val.UserID].IdentityCode,
+			Name:              userMap[val.UserID].Name,
+			OtherIdentityCode: userMap[val.OtherUserID].IdentityCode,
+			OtherName:         userMap[val.OtherUserID].Name,
+			Score:             val.Score,
+			ScoreUser:         val.ScoreUser,
+			ScoreOther:        val.ScoreOther,
+		})
+	}
+	return resp, nil
+}
+
+// handleGradebook builds the students by assessments matrix of the schedule, every assignment of the schedule
+// is a column grouped by its grade parameter followed by the attendance and the weighted total
+func handleGradebook(scheduleID int64) (gradebookResponse, []asg.Assignment, error) {
+	resp := gradebookResponse{
+		Columns: []gradebookColumn{},
+		Rows:    []gradebookRow{},
+	}
+
+	gps, err := cs.SelectGPBySchedule([]int64{scheduleID})
+	if err != nil {
+		return resp, nil, err
+	}
+	var gpsID []int64
+	for _, gp := range gps {
+		gpsID = append(gpsID, gp.ID)
+	}
+
+	assignments, err := asg.SelectByGP(gpsID, true)
+	if err != nil {
+		return resp, nil, err
+	}
+	var asgID []int64
+	gpAsg := map[int64][]asg.Assignment{}
+	for _, val := range assignments {
+		asgID = append(asgID, val.ID)
+		gpAsg[val.GradeParameterID] = append(gpAsg[val.GradeParameterID], val)
+	}
+
+	// columns follow the grade parameter order so the sheet reads like the grade report
+	ordered := []asg.Assignment{}
+	isHidden := false
+	for _, gp := range gps {
+		for _, val := range gpAsg[gp.ID] {
+			ordered = append(ordered, val)
+			resp.Columns = append(resp.Columns, gradebookColumn{
+				AssignmentID: val.ID,
+				Name:         val.Name,
+				Type:         gp.Type,
+				Header:       fmt.Sprintf("%s %s (#%d)", gp.Type, html.UnescapeString(val.Name), val.ID),
+				IsHidden:     val.IsIdentityHidden(),
+			})
+			isHidden = isHidden || val.IsIdentityHidden()
+		}
+	}
+
+	submitted, err := asg.SelectSubmittedByAssignment(asgID)
+	if err != nil {
+		return resp, ordered, err
+	}
+	userSubmit := map[int64]map[int64]asg.UserAssignment{}
+	for _, val := range submitted {
+		if _, ok := userSubmit[val.UserID]; !ok {
+			userSubmit[val.UserID] = map[int64]asg.UserAssignment{}
+		}
+		userSubmit[val.UserID][val.AssignmentID] = val
+	}
+
+	policy, err := grade.GetPolicy(scheduleID)
+	if err != nil {
+		return resp, ordered, err
+	}
+
+	students, err := handleEnrolledStudents(scheduleID)
+	if err != nil {
+		return resp, ordered, err
+	}
+	var identities []int64
+	for identity := range students {
+		identities = append(identities, identity)
+	}
+	sort.Slice(identities, func(i, j int) bool {
+		return identities[i] < identities[j]
+	})
+
+	for _, identity := range identities {
+		student := students[identity]
+		report, err := att.CountByUserSchedule(student.ID, []int64{scheduleID})
+		if err != nil {
+			return resp, ordered, err
+		}
+
+		row := gradebookRow{
+			IdentityCode: identity,
+			Name:         student.Name,
+			Scores:       []string{},
+		}
+		for _, val := range ordered {
+			score := ""
+			if val.IsIdentityHidden() {
+				score = GradebookHidden
+			} else if submit, ok := userSubmit[student.ID][val.ID]; ok && submit.Score.Valid {
+				score = policy.Format(submit.Score.Float64)
+			}
+			row.Scores = append(row.Scores, score)
+		}
+
+		attendance := report[scheduleID]
+		params := grade.Parameters(gps, gpAsg, userSubmit[student.ID], attendance)
+		row.Attendance = policy.Format(grade.AttendanceScore(attendance.AttendanceTotal, attendance.MeetingTotal).Value)
+		row.Total = policy.Format(policy.Calculate(params).Total)
+		// the total would tell the hidden scores
+		if isHidden {
+			row.Total = GradebookHidden
+		}
+		resp.Rows = append(resp.Rows, row)
+	}
+	return resp, ordered, nil
+}
+
+// handleGradebookSheet converts the gradebook into spreadsheet rows with a header line
+func handleGradebookSheet(gradebook gradebookResponse) [][]string {
+	header := []string{"Identity Code", "Name"}
+	for _, val := range gradebook.Columns {
+		header = append(header, val.Header)
+	}
+	header = append(header, "Attendance", "Total")
+
+	rows := [][]string{header}
+	for _, val := range gradebook.Rows {
+		row := []string{strconv.FormatInt(val.IdentityCode, 10), html.UnescapeString(val.Name)}
+		row = append(row, val.Scores...)
+		row = append(row, val.Attendance, val.Total)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// handleGradebookImport compares the imported sheet with the current gradebook, every changed score is validated
+// the same way as scores set through the score API. Empty cell and unchanged score are skipped
+func handleGradebookImport(scheduleID int64, sheet [][]string) ([]gradebookChange, map[int64][]scoreEntry, error) {
+	changes := []gradebookChange{}
+	entries := map[int64][]scoreEntry{}
+
+	if len(sheet) < 1 {
+		return changes, entries, fmt.Errorf("Sheet is empty")
+	}
+	columns, err := validateGradebookHeader(sheet[0])
+	if err != nil {
+		return changes, entries, err
+	}
+
+	gradebook, assignments, err := handleGradebook(scheduleID)
+	if err != nil {
+		return changes, entries, err
+	}
+	asgMap := map[int64]asg.Assignment{}
+	asgIndex := map[int64]int{}
+	for i, val := range assignments {
+		asgMap[val.ID] = val
+		asgIndex[val.ID] = i
+	}
+	for i, id := range columns {
+		if _, ok := asgMap[id]; !ok {
+			return changes, entries, fmt.Errorf("Column %s is not an assessment of this schedule", sheet[0][i])
+		}
+	}
+	current := map[string]gradebookRow{}
+	for _, val := range gradebook.Rows {
+		current[strconv.FormatInt(val.IdentityCode, 10)] = val
+	}
+
+	policy, err := grade.GetPolicy(scheduleID)
+	if err != nil {
+		return changes, entries, err
+	}
+
+	// keep the column order of the sheet so the preview follows the sheet
+	var indexes []int
+	for i := range columns {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	asgRows := map[int64][]scoreRow{}
+	asgChanges := map[int64][]int{}
+	for line, cells := range sheet[1:] {
+		if len(cells) < 1 || helper.IsEmpty(cells[0]) {
+			continue
+		}
+		identity := helper.Trim(cells[0])
+		for _, i := range indexes {
+			if i >= len(cells) || helper.IsEmpty(cells[i]) {
+				continue
+			}
+			assignment := asgMap[columns[i]]
+			value := helper.Trim(cells[i])
+			if assignment.IsIdentityHidden() && value == GradebookHidden {
+				continue
+			}
+
+			old := ""
+			if row, ok := current[identity]; ok {
+				old = row.Scores[asgIndex[assignment.ID]]
+			}
+			if score, err := strconv.ParseFloat(value, 64); err == nil && old != "" && policy.Format(score) == old {
+				continue
+			}
+
+			if old == "" {
+				old = "-"
+			}
+			change := gradebookChange{
+				Line:         line + 2,
+				IdentityCode: identity,
+				AssignmentID: assignment.ID,
+				Assignment:   assignment.Name,
+				OldScore:     old,
+				Score:        value,
+			}
+			// students of an anonymous assignment are only scored by their anonymous code
+			if assignment.IsIdentityHidden() {
+				change.Error = "Assignment is anonymous, score it by the anonymous code"
+				changes = append(changes, change)
+				continue
+			}
+
+			asgChanges[assignment.ID] = append(asgChanges[assignment.ID], len(changes))
+			asgRows[assignment.ID] = append(asgRows[assignment.ID], scoreRow{
+				Line:         line + 2,
+				IdentityCode: identity,
+				Score:        value,
+			})
+			changes = append(changes, change)
+		}
+	}
+
+	for id, rows := range asgRows {
+		previews, valid, err := handleScoreRows(asgMap[id], scheduleID, rows)
+		if err != nil {
+			return changes, entries, err
+		}
+		for i, val := range previews {
+			change := &changes[asgChanges[id][i]]
+			change.Name = val.Name
+			change.IsValid = val.IsValid
+			change.Error = val.Error
+		}
+		entries[id] = valid
+	}
+	return changes, entries, nil
+}
+
+// handleGradebookSave saves every imported score in a single transaction, feedback of the submission is kept
+func handleGradebookSave(entries map[int64][]scoreEntry, audit scoreAudit) error {
+	var asgID []int64
+	for id := range entries {
+		asgID = append(asgID, id)
+	}
+	tx := conn.DB.MustBegin()
+	for id, val := range entries {
+		var usersID []int64
+		for _, entry := range val {
+			usersID = append(usersID, entry.UserID)
+		}
+		old, err := asg.SelectScoreByUser(id, usersID, tx)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		for _, entry := range val {
+			err := asg.UpsertScore(id, entry.UserID, entry.Score, entry.Feedback, tx)
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+
+		err = asg.LogScore(id, usersID, old, audit.Actor, audit.Source, audit.Reason, tx)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// handleScoreLogResponse resolves the student and the actor of every score log, names is the assignment name by id.
+// Set shown to show the students of an assignment the way they are graded, see handleShownStudents
+func handleScoreLogResponse(logs []asg.ScoreLog, names map[int64]string, shown *asg.Assignment, scheduleID int64) ([]scoreLogResponse, error) {
+	resp := []scoreLogResponse{}
+	if len(logs) < 1 {
+		return resp, nil
+	}
+
+	var usersID []int64
+	isExist := map[int64]bool{}
+	for _, val := range logs {
+		for _, id := range []int64{val.UserID, val.Actor} {
+			if !isExist[id] {
+				isExist[id] = true
+				usersID = append(usersID, id)
+			}
+		}
+	}
+	users, err := usr.RequestID(usersID, false)
+	if err != nil {
+		return resp, err
+	}
+	userMap := map[int64]usr.UserReq{}
+	for _, val := range users {
+		userMap[val.ID] = val
+	}
+	studentMap := userMap
+	if shown != nil {
+		var students []usr.UserReq
+		for _, val := range logs {
+			students = append(students, userMap[val.UserID])
+		}
+		studentMap, err = handleShownStudents(*shown, scheduleID, students)
+		if err != nil {
+			return resp, err
+		}
+	}
+
+	score := func(val sql.NullFloat64) string {
+		if !val.Valid {
+			return "-"
+		}
+		return fmt.Sprintf("%g", val.Float64)
+	}
+	for _, val := range logs {
+		resp = append(resp, scoreLogResponse{
+			ID:           val.ID,
+			AssignmentID: val.AssignmentID,
+			Assignment:   names[val.AssignmentID],
+			IdentityCode: studentMap[val.UserID].IdentityCode,
+			Name:         studentMap[val.UserID].Name,
+			OldScore:     score(val.OldScore),
+			NewScore:     score(val.NewScore),
+			Actor:        userMap[val.Actor].Name,
+			Source:       asg.ScoreSources[val.Source],
+			Reason:       val.Reason.String,
+			CreatedAt:    val.CreatedAt.Format("Monday, 2 January 2006 15:04:05"),
+		})
+	}
+	return resp, nil
+}
+
+// handleScheduleAssignment returns every assignment of the schedule
+func handleScheduleAssignment(scheduleID int64) ([]asg.Assignment, error) {
+	gps, err := cs.SelectGPBySchedule([]int64{scheduleID})
+	if err != nil {
+		return nil, err
+	}
+	var gpsID []int64
+	for _, val := range gps {
+		gpsID = append(gpsID, val.ID)
+	}
+	return asg.SelectByGP(gpsID, false)
+}
+
+// handleRegradeExpire closes unresolved regrade requests of the schedule when the regrade period after
+// the final grade is published has ended, it returns whether the period has ended
+func handleRegradeExpire(scheduleID int64) (bool, error) {
+	publication, err := grade.GetPublication(scheduleID)
+	if err != nil {
+		return false, err
+	}
+	if publication == nil || publication.Status != grade.StatusPublished {
+		return false, nil
+	}
+
+	policy, err := grade.GetPolicy(scheduleID)
+	if err != nil {
+		return false, err
+	}
+	// the publication time is set by the database so it is compared with the database clock
+	now, err := publish.Now()
+	if err != nil {
+		return false, err
+	}
+	if now.Before(publication.CreatedAt.AddDate(0, 0, int(policy.RegradeDays))) {
+		return false, nil
+	}
+
+	assignments, err := handleScheduleAssignment(scheduleID)
+	if err != nil {
+		return true, err
+	}
+	var asgID []int64
+	for _, val := range assignments {
+		asgID = append(asgID, val.ID)
+	}
+	return true, asg.CloseRegrade(asgID, nil)
+}
+
+// handleRegradeInsert creates the regrade request with its attachment, the attachment must be uploaded
+// by the student and has not been attached yet
+func handleRegradeInsert(assignmentID, userID int64, reason, fileID string) (int, error) {
+	if len(fileID) > 0 {
+		files, err := fl.SelectByID([]string{fileID})
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		if len(files) < 1 {
+			return http.StatusBadRequest, fmt.Errorf("File does not exist")
+		}
+		if files[0].UserID != userID || files[0].Type != fl.TypRegrade || files[0].TableID.Valid {
+			return http.StatusBadRequest, fmt.Errorf("Invalid file %s.%s", files[0].Name, files[0].Extension)
+		}
+	}
+
+	tx := conn.DB.MustBegin()
+	id, err := asg.InsertRegrade(assignmentID, userID, reason, tx)
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+	if len(fileID) > 0 {
+		err = fl.UpdateRelation(fileID, fl.TypRegrade, strconv.FormatInt(id, 10), tx)
+		if err != nil {
+			tx.Rollback()
+			return http.StatusInternalServerError, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+// handleRegradeResolve accepts, rejects or escalates the regrade request and notifies the student or the schedule creator.
+// Accepted request changes the score through the score log with the response as the reason
+func handleRegradeResolve(assignment asg.Assignment, regrade asg.Regrade, args resolveRegradeArgs, actorID int64) (int, error) {
+	course, err := cs.GetByScheduleID(args.ScheduleID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	isCreator := course.Schedule.CreatedBy == actorID
+	if regrade.Status == asg.RegradeEscalated && !isCreator {
+		return http.StatusForbidden, fmt.Errorf("Escalated request can only be resolved by the schedule creator")
+	}
+
+	var status int8
+	var recipientID int64
+	var message string
+	switch args.Action {
+	case "accept":
+		status = asg.RegradeAccepted
+		recipientID = regrade.UserID
+		message = fmt.Sprintf("Your regrade request on %s has been accepted", assignment.Name)
+	case "reject":
+		status = asg.RegradeRejected
+		recipientID = regrade.UserID
+		message = fmt.Sprintf("Your regrade request on %s has been rejected", assignment.Name)
+	case "escalate":
+		if regrade.Status != asg.RegradeOpen {
+			return http.StatusBadRequest, fmt.Errorf("Regrade request has been escalated")
+		}
+		if isCreator {
+			return http.StatusBadRequest, fmt.Errorf("You are the creator of the schedule")
+		}
+		status = asg.RegradeEscalated
+		recipientID = course.Schedule.CreatedBy
+		message = fmt.Sprintf("A regrade request on %s has been escalated to you", assignment.Name)
+	}
+
+	if status == asg.RegradeAccepted {
+		err = handleIndividualScore(assignment)
+		if err != nil {
+			return http.StatusBadRequest, err
+		}
+	}
+
+	tx := conn.DB.MustBegin()
+	if status == asg.RegradeAccepted {
+		submit, err := asg.GetSubmittedByUser(assignment.ID, regrade.UserID)
+		if err != nil {
+			tx.Rollback()
+			return http.StatusInternalServerError, err
+		}
+		if submit == nil {
+			tx.Rollback()
+			return http.StatusBadRequest, fmt.Errorf("Submission does not exist")
+		}
+
+		reason := args.Response
+		if !reason.Valid {
+			reason = sql.NullString{Valid: true, String: regrade.Reason}
+		}
+		entry := scoreEntry{
+			UserID: regrade.UserID,
+			Score:  args.Score,
+		}
+		err = handleScoreWrite(assignment.ID, []scoreEntry{entry}, scoreAudit{
+			Actor:  actorID,
+			Source: asg.ScoreSourceRegrade,
+			Reason: reason,
+		}, tx)
+		if err != nil {
+			tx.Rollback()
+			return http.StatusInternalServerError, err
+		}
+	}
+
+	// the request may have been resolved by another assistant since it was read
+	err = asg.UpdateRegrade(regrade.ID, []int8{regrade.Status}, status, actorID, args.Response, tx)
+	if err != nil {
+		tx.Rollback()
+		if err.Error() == "No rows affected" {
+			return http.StatusConflict, fmt.Errorf("Regrade request has been changed, please reload it")
+		}
+		return http.StatusInternalServerError, err
+	}
+
+	err = nt.Insert(recipientID, "Regrade request", message,
+		"assignments", strconv.FormatInt(assignment.ID, 10), tx)
+	if err != nil {
+		tx.Rollback()
+		return http.StatusInternalServerError, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+// handleRegradeResponse builds the regrade requests with their attachments and the current score of the submissions,
+// assignments is keyed by assignment id. Set isShown when the list is read by assistants so the students of
+// an assignment whose identities are hidden are shown with their anonymous code
+func handleRegradeResponse(regrades []asg.Regrade, assignments map[int64]asg.Assignment, scheduleID int64, isShown bool) ([]regradeResponse, error) {
+	resp := []regradeResponse{}
+	if len(regrades) < 1 {
+		return resp, nil
+	}
+
+	var tablesID []string
+	var usersID, asgID []int64
+	isExist := map[int64]bool{}
+	for _, val := range regrades {
+		tablesID = append(tablesID, strconv.FormatInt(val.ID, 10))
+		asgID = append(asgID, val.AssignmentID)
+		ids := []int64{val.UserID}
+		if val.HandledBy.Valid {
+			ids = append(ids, val.HandledBy.Int64)
+		}
+		for _, id := range ids {
+			if !isExist[id] {
+				isExist[id] = true
+				usersID = append(usersID, id)
+			}
+		}
+	}
+
+	files, err := fl.SelectByRelation(fl.TypRegrade, tablesID, nil)
+	if err != nil {
+		return resp, err
+	}
+	fileMap := map[string][]file{}
+	for _, val := range files {
+		fileMap[val.TableID.String] = append(fileMap[val.TableID.String], file{
+			ID:           val.ID,
+			Name:         fmt.Sprintf("%s.%s", val.Name, val.Extension),
+			URL:          fmt.Sprintf("/api/v1/file/assignment/%s.%s", val.ID, val.Extension),
+			URLThumbnail: helper.MimeToThumbnail(val.Mime),
+		})
+	}
+
+	submitted, err := asg.SelectSubmittedByAssignment(asgID)
+	if err != nil {
+		return resp, err
+	}
+	scores := map[int64]map[int64]sql.NullFloat64{}
+	for _, val := range submitted {
+		if scores[val.AssignmentID] == nil {
+			scores[val.AssignmentID] = map[int64]sql.NullFloat64{}
+		}
+		scores[val.AssignmentID][val.UserID] = val.Score
+	}
+
+	users, err := usr.RequestID(usersID, false)
+	if err != nil {
+		return resp, err
+	}
+	userMap := map[int64]usr.UserReq{}
+	for _, val := range users {
+		userMap[val.ID] = val
+	}
+
+	// assignment id => userID => the student as shown on the assignment
+	shown := map[int64]map[int64]usr.UserReq{}
+	if isShown {
+		for id, assignment := range assignments {
+			if !assignment.IsIdentityHidden() {
+				continue
+			}
+			shown[id], err = handleShownStudents(assignment, scheduleID, users)
+			if err != nil {
+				return resp, err
+			}
+		}
+	}
+
+	for _, val := range regrades {
+		student := userMap[val.UserID]
+		if students, ok := shown[val.AssignmentID]; ok {
+			student = students[val.UserID]
+		}
+		rFile := fileMap[strconv.FormatInt(val.ID, 10)]
+		if rFile == nil {
+			rFile = []file{}
+		}
+		score := "-"
+		if s := scores[val.AssignmentID][val.UserID]; s.Valid {
+			score = fmt.Sprintf("%g", s.Float64)
+		}
+		handledBy := "-"
+		if val.HandledBy.Valid {
+			handledBy = userMap[val.HandledBy.Int64].Name
+		}
+		resp = append(resp, regradeResponse{
+			ID:           val.ID,
+			AssignmentID: val.AssignmentID,
+			Assignment:   assignments[val.AssignmentID].Name,
+			IdentityCode: student.IdentityCode,
+			Name:         student.Name,
+			Reason:       val.Reason,
+			Score:        score,
+			Status:       asg.RegradeStatuses[val.Status],
+			Response:     val.Response.String,
+			HandledBy:    handledBy,
+			Files:        rFile,
+			CreatedAt:    val.CreatedAt.Format("Monday, 2 January 2006 15:04:05"),
+			UpdatedAt:    val.UpdatedAt.Format("Monday, 2 January 2006 15:04:05"),
+		})
+	}
+	return resp, nil
+}
+
+// handlePeerRubric returns the filled rubric of every peer review
+func handlePeerRubric(rubric *rb.Rubric, reviews []asg.PeerReview) (map[int64]*rubricResponse, error) {
+	resp := map[int64]*rubricResponse{}
+	if rubric == nil || len(reviews) < 1 {
+		return resp, nil
+	}
+
+	var reviewsID []int64
+	for _, val := range reviews {
+		reviewsID = append(reviewsID, val.ID)
+	}
+	scores, err := asg.SelectPeerReviewScore(reviewsID)
+	if err != nil {
+		return resp, err
+	}
+	reviewScores := map[int64][]rb.Score{}
+	for _, val := range scores {
+		reviewScores[val.ReviewID] = append(reviewScores[val.ReviewID], rb.Score{
+			CriterionID: val.CriterionID,
+			LevelID:     val.LevelID,
+			Points:      val.Points,
+			Comment:     val.Comment,
+		})
+	}
+
+	for _, val := range reviews {
+		resp[val.ID] = handleRubricFilled(*rubric, reviewScores[val.ID])
+	}
+	return resp, nil
+}
+
+// handlePeerResponse builds the reviews the student has to fill and the reviews the student received at now,
+// names of the students are never returned so the reviews stay anonymous
+func handlePeerResponse(assignment asg.Assignment, rubric *rb.Rubric, tasks, received []asg.PeerReview, now time.Time) (*peerResponse, error) {
+	resp := &peerResponse{
+		IsOpen:    assignment.IsPeerReviewOpen(now),
+		CloseDate: assignment.PeerReviewClose.Time.Format("Monday, 2 January 2006 15:04:05"),
+		Weight:    assignment.PeerWeight,
+		PeerScore: "-",
+		Reviews:   []peerTaskResponse{},
+		Received:  []peerReceivedResponse{},
+	}
+
+	rubrics, err := handlePeerRubric(rubric, append(tasks, received...))
+	if err != nil {
+		return nil, err
+	}
+
+	submitted, err := asg.SelectSubmittedByAssignment([]int64{assignment.ID})
+	if err != nil {
+		return nil, err
+	}
+	submitMap := map[int64]asg.UserAssignment{}
+	for _, val := range submitted {
+		submitMap[val.UserID] = val
+	}
+
+	files, err := fl.SelectByRelation(fl.TypAssignmentUpload, []string{strconv.FormatInt(assignment.ID, 10)}, nil)
+	if err != nil {
+		return nil, err
+	}
+	userFiles := map[int64][]file{}
+	for _, val := range files {
+		userFiles[val.UserID] = append(userFiles[val.UserID], file{
+			ID:           val.ID,
+			Name:         fmt.Sprintf("file-%d.%s", len(userFiles[val.UserID])+1, val.Extension),
+			URL:          fmt.Sprintf("/api/v1/file/assignment/%s.%s", val.ID, val.Extension),
+			URLThumbnail: helper.MimeToThumbnail(val.Mime),
+		})
+	}
+
+	for _, val := range tasks {
+		taskFiles := userFiles[val.UserID]
+		if taskFiles == nil {
+			taskFiles = []file{}
+		}
+		resp.Reviews = append(resp.Reviews, peerTaskResponse{
+			ID:          val.ID,
+			Status:      asg.PeerStatuses[val.Status],
+			Description: submitMap[val.UserID].Description.String,
+			Files:       taskFiles,
+			Comment:     val.Comment.String,
+			Rubric:      rubrics[val.ID],
+		})
+	}
+
+	for _, val := range received {
+		if val.Status != asg.PeerSubmitted {
+			continue
+		}
+		resp.Received = append(resp.Received, peerReceivedResponse{
+			Score:   val.Score.Float64,
+			Comment: val.Comment.String,
+			Rubric:  rubrics[val.ID],
+		})
+	}
+	for _, val := range asg.PeerScores(received) {
+		resp.PeerScore = fmt.Sprintf("%g", val)
+	}
+	return resp, nil
+}
+
+// handlePeerAdminResponse builds every peer review of the assignment at now with the reviewer and the reviewed student,
+// both are shown with their anonymous code while the identities are hidden
+func handlePeerAdminResponse(assignment asg.Assignment, scheduleID int64, rubric *rb.Rubric, reviews []asg.PeerReview, now time.Time) (*peerAdminResponse, error) {
+	resp := &peerAdminResponse{
+		IsOpen:    assignment.IsPeerReviewOpen(now),
+		IsClosed:  assignment.IsPeerReviewClosed(now),
+		CloseDate: assignment.PeerReviewClose.Time.Format("Monday, 2 January 2006 15:04:05"),
+		Reviewers: assignment.PeerReviewers,
+		Weight:    assignment.PeerWeight,
+		Scores:    []peerScoreResponse{},
+		Reviews:   []peerReviewAdminResponse{},
+	}
+	if len(reviews) < 1 {
+		return resp, nil
+	}
+
+	rubrics, err := handlePeerRubric(rubric, reviews)
+	if err != nil {
+		return nil, err
+	}
+
+	var usersID []int64
+	for _, val := range reviews {
+		usersID = append(usersID, val.ReviewerID, val.UserID)
+		if val.ModeratedBy.Valid {
+			usersID = append(usersID, val.ModeratedBy.Int64)
+		}
+	}
+	users, err := usr.RequestID(usersID, false)
+	if err != nil {
+		return nil, err
+	}
+	userMap := map[int64]usr.UserReq{}
+	for _, val := range users {
+		userMap[val.ID] = val
+	}
+	students, err := handleShownStudents(assignment, scheduleID, users)
+	if err != nil {
+		return nil, err
+	}
+
+	outliers := asg.PeerOutliers(reviews)
+	for _, val := range reviews {
+		score := "-"
+		if val.Score.Valid {
+			score = fmt.Sprintf("%g", val.Score.Float64)
+		}
+		moderatedBy := ""
+		if val.ModeratedBy.Valid {
+			moderatedBy = userMap[val.ModeratedBy.Int64].Name
+		}
+		resp.Reviews = append(resp.Reviews, peerReviewAdminResponse{
+			ID:                   val.ID,
+			ReviewerIdentityCode: students[val.ReviewerID].IdentityCode,
+			ReviewerName:         students[val.ReviewerID].Name,
+			IdentityCode:         students[val.UserID].IdentityCode,
+			Name:                 students[val.UserID].Name,
+			Status:               asg.PeerStatuses[val.Status],
+			Score:                score,
+			Comment:              val.Comment.String,
+			IsOutlier:            outliers[val.ID],
+			ModeratedBy:          moderatedBy,
+			UpdatedAt:            val.UpdatedAt.Format("Monday, 2 January 2006 15:04:05"),
+			Rubric:               rubrics[val.ID],
+		})
+	}
+
+	for userID, val := range asg.PeerScores(reviews) {
+		resp.Scores = append(resp.Scores, peerScoreResponse{
+			IdentityCode: students[userID].IdentityCode,
+			Name:         students[userID].Name,
+			PeerScore:    val,
+		})
+	}
+	sort.Slice(resp.Scores, func(i, j int) bool {
+		return resp.Scores[i].IdentityCode < resp.Scores[j].IdentityCode
+	})
+	return resp, nil
+}
+
+// handlePeerApply combines the peer score into the score of every reviewed student with the weight
+// of the assignment. The part graded by assistants is taken from the score before any peer score was
+// combined, so applying it again does not count the peer score twice. Students who have not been graded
+// by an assistant are skipped unless the peer score is the whole score
+func handlePeerApply(assignment asg.Assignment, actorID int64) (int, error) {
+	reviews, err := asg.SelectPeerReview(assignment.ID, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	peerScores := asg.PeerScores(reviews)
+	if len(peerScores) < 1 {
+		return 0, nil
+	}
+
+	var usersID []int64
+	for userID := range peerScores {
+		usersID = append(usersID, userID)
+	}
+	current, err := asg.SelectScoreByUser(assignment.ID, usersID, nil)
+	if err != nil {
+		return 0, err
+	}
+	logs, err := asg.SelectScoreLog([]int64{assignment.ID}, nil)
+	if err != nil {
+		return 0, err
+	}
+	userLogs := map[int64][]asg.ScoreLog{}
+	for _, val := range logs {
+		userLogs[val.UserID] = append(userLogs[val.UserID], val)
+	}
+	var entries []scoreEntry
+	for _, userID := range usersID {
+		peer := peerScores[userID]
+		score := peer
+		if assignment.PeerWeight < asg.MaxPeerWeight {
+			staff := asg.StaffScore(userLogs[userID], current[userID])
+			if !staff.Valid {
+				continue
+			}
+			score = staff.Float64*(asg.MaxPeerWeight-assignment.PeerWeight)/asg.MaxPeerWeight +
+				peer*assignment.PeerWeight/asg.MaxPeerWeight
+		}
+		entries = append(entries, scoreEntry{
+			UserID: userID,
+			Score:  float32(math.Round(score*100) / 100),
+		})
+	}
+	if len(entries) < 1 {
+		return 0, nil
+	}
+
+	err = handleScoreSave(assignment.ID, entries, scoreAudit{
+		Actor:  actorID,
+		Source: asg.ScoreSourcePeer,
+		Reason: sql.NullString{Valid: true, String: "Peer review"},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// handleScheduleScores collects the scores of the enrolled students of the schedule. Score of a grade parameter
+// and the total follow the grade policy of the schedule, a grade parameter is only counted for a student
+// once one of its assignments has been scored so unscored assignments do not count as zero
+func handleScheduleScores(scheduleID int64) (scheduleScores, error) {
+	scores := scheduleScores{
+		submitted:  map[int64]int{},
+		late:       map[int64]int{},
+		scores:     map[int64][]float64{},
+		parameters: map[int64][]float64{},
+	}
+
+	gps, err := cs.SelectGPBySchedule([]int64{scheduleID})
+	if err != nil {
+		return scores, err
+	}
+	scores.gps = gps
+	var gpsID []int64
+	for _, gp := range gps {
+		gpsID = append(gpsID, gp.ID)
+	}
+
+	assignments, err := asg.SelectByGP(gpsID, true)
+	if err != nil {
+		return scores, err
+	}
+	var asgID []int64
+	gpAsg := map[int64][]asg.Assignment{}
+	for _, val := range assignments {
+		asgID = append(asgID, val.ID)
+		gpAsg[val.GradeParameterID] = append(gpAsg[val.GradeParameterID], val)
+	}
+	for _, gp := range gps {
+		scores.assignments = append(scores.assignments, gpAsg[gp.ID]...)
+	}
+
+	studentsID, err := cs.SelectEnrolledStudentID(scheduleID)
+	if err != nil {
+		return scores, err
+	}
+	scores.enrolled = len(studentsID)
+
+	submitted, err := asg.SelectSubmittedByAssignment(asgID)
+	if err != nil {
+		return scores, err
+	}
+	userSubmit := map[int64]map[int64]asg.UserAssignment{}
+	for _, val := range submitted {
+		if _, ok := userSubmit[val.UserID]; !ok {
+			userSubmit[val.UserID] = map[int64]asg.UserAssignment{}
+		}
+		userSubmit[val.UserID][val.AssignmentID] = val
+	}
+
+	policy, err := grade.GetPolicy(scheduleID)
+	if err != nil {
+		return scores, err
+	}
+
+	for _, studentID := range studentsID {
+		for _, val := range scores.assignments {
+			submit, ok := userSubmit[studentID][val.ID]
+			if ok || val.Status == asg.StatusUploadNotRequired {
+				scores.submitted[val.ID]++
+			}
+			if ok && val.Status == asg.StatusUploadRequired && submit.LateSeconds > 0 {
+				scores.late[val.ID]++
+			}
+			if ok && submit.Score.Valid {
+				scores.scores[val.ID] = append(scores.scores[val.ID], submit.Score.Float64)
+			}
+		}
+
+		report, err := att.CountByUserSchedule(studentID, []int64{scheduleID})
+		if err != nil {
+			return scores, err
+		}
+		params := grade.Parameters(gps, gpAsg, userSubmit[studentID], report[scheduleID])
+		for _, param := range params {
+			isGraded := param.Type == cs.GradeParameterAttendance
+			for _, val := range param.Scores {
+				isGraded = isGraded || val.IsGraded
+			}
+			if isGraded {
+				scores.parameters[param.ID] = append(scores.parameters[param.ID], policy.Round(policy.Average(param.Scores)))
+			}
+		}
+		scores.totals = append(scores.totals, policy.Calculate(params).Total)
+	}
+	return scores, nil
+}
+
+// handleSubmissionRate returns the percentage of expected submissions which have been submitted
+func handleSubmissionRate(scores scheduleScores) float64 {
+	var submitted int
+	for _, val := range scores.assignments {
+		submitted += scores.submitted[val.ID]
+	}
+	return handlePercentage(submitted, scores.enrolled*len(scores.assignments))
+}
+
+func handlePercentage(count, total int) float64 {
+	if total < 1 {
+		return 0
+	}
+	return math.Round(float64(count)/float64(total)*10000) / 100
+}
+
+// handleSummary returns the descriptive statistics of the scores rounded to two decimals
+func handleSummary(values []float64) scoreSummary {
+	summary := statistic.Summarize(values)
+	round := func(value float64) float64 {
+		return math.Round(value*100) / 100
+	}
+	return scoreSummary{
+		Count:  summary.Count,
+		Mean:   round(summary.Mean),
+		Median: round(summary.Median),
+		StdDev: round(summary.StdDev),
+		Min:    round(summary.Min),
+		Max:    round(summary.Max),
+	}
+}
+
+// handleHistogram returns the histogram of the scores from zero to the maximum score,
+// scores above the maximum score are counted on the last bin
+func handleHistogram(values []float64, bins int) []histogramBin {
+	resp := []histogramBin{}
+	for _, val := range statistic.Histogram(values, 0, asg.MaxScore, bins) {
+		resp = append(resp, histogramBin{
+			From:  math.Round(val.From*100) / 100,
+			To:    math.Round(val.To*100) / 100,
+			Count: val.Count,
+		})
+	}
+	return resp
+}
+
+// handleStatistic returns the statistics of every assignment and grade parameter of the schedule
+// and compares it with the other classes of the same course on the same semester
+func handleStatistic(scheduleID int64, bins int) (statisticResponse, error) {
+	resp := statisticResponse{
+		ScheduleID:  scheduleID,
+		Bins:        bins,
+		Assignments: []assignmentStatistic{},
+		Parameters:  []parameterStatistic{},
+		Classes:     []classStatistic{},
+	}
+
+	scores, err := handleScheduleScores(scheduleID)
+	if err != nil {
+		return resp, err
+	}
+	resp.Enrolled = scores.enrolled
+	resp.Total = handleSummary(scores.totals)
+	resp.Histogram = handleHistogram(scores.totals, bins)
+
+	gpType := map[int64]string{}
+	for _, gp := range scores.gps {
+		gpType[gp.ID] = gp.Type
+		resp.Parameters = append(resp.Parameters, parameterStatistic{
+			ID:         gp.ID,
+			Type:       gp.Type,
+			Percentage: gp.Percentage,
+			Score:      handleSummary(scores.parameters[gp.ID]),
+			Histogram:  handleHistogram(scores.parameters[gp.ID], bins),
+		})
+	}
+
+	for _, val := range scores.assignments {
+		status := "must_upload"
+		if val.Status == asg.StatusUploadNotRequired {
+			status = "upload_not_required"
+		}
+		resp.Assignments = append(resp.Assignments, assignmentStatistic{
+			ID:             val.ID,
+			Name:           val.Name,
+			Type:           gpType[val.GradeParameterID],
+			Status:         status,
+			Enrolled:       scores.enrolled,
+			Submitted:      scores.submitted[val.ID],
+			SubmissionRate: handlePercentage(scores.submitted[val.ID], scores.enrolled),
+			OnTime:         scores.submitted[val.ID] - scores.late[val.ID],
+			Late:           scores.late[val.ID],
+			Score:          handleSummary(scores.scores[val.ID]),
+			Histogram:      handleHistogram(scores.scores[val.ID], bins),
+		})
+	}
+
+	schedules, err := cs.SelectSiblingSchedule(scheduleID)
+	if err != nil {
+		return resp, err
+	}
+	for _, schedule := range schedules {
+		classScores := scores
+		if schedule.ID != scheduleID {
+			classScores, err = handleScheduleScores(schedule.ID)
+			if err != nil {
+				return resp, err
+			}
+		}
+
+		class := classStatistic{
+			ScheduleID:     schedule.ID,
+			Class:          schedule.Class,
+			Enrolled:       classScores.enrolled,
+			SubmissionRate: handleSubmissionRate(classScores),
+			Total:          handleSummary(classScores.totals),
+			Parameters:     []parameterSummary{},
+		}
+		// classes are compared by grade parameter type since every class has its own grade parameters
+		for _, gp := range classScores.gps {
+			class.Parameters = append(class.Parameters, parameterSummary{
+				Type:  gp.Type,
+				Score: handleSummary(classScores.parameters[gp.ID]),
+			})
+		}
+		resp.Classes = append(resp.Classes, class)
+	}
+	return resp, nil
+}
+
+// handleAutograde compiles and runs the uploaded source files of every user who has submitted the assignment
+// against its tests and saves the results to the run, it is run on background so any error is saved as the run message
+func handleAutograde(assignmentID, runID int64) {
+	defer func() {
+		if r := recover(); r != nil {
+			asg.FinishAutograderRun(runID, asg.AutograderFailed,
+				sql.NullString{Valid: true, String: html.EscapeString(fmt.Sprint(r))}, nil)
+		}
+	}()
+
+	results, err := handleAutogradeResult(assignmentID, runID)
+	if err != nil {
+		asg.FinishAutograderRun(runID, asg.AutograderFailed,
+			sql.NullString{Valid: true, String: html.EscapeString(err.Error())}, nil)
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = asg.InsertAutograderResult(results, tx)
+	if err != nil {
+		tx.Rollback()
+		asg.FinishAutograderRun(runID, asg.AutograderFailed,
+			sql.NullString{Valid: true, String: html.EscapeString(err.Error())}, nil)
+		return
+	}
+	err = asg.FinishAutograderRun(runID, asg.AutograderDone, sql.NullString{}, tx)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+	tx.Commit()
+}
+
+// handleAutogradeResult runs the tests on the graded files of every user who has submitted, users without files
+// of a supported language get a result without a suggested score
+func handleAutogradeResult(assignmentID, runID int64) ([]asg.AutograderResult, error) {
+	// the run is expired after MaxAutograderRun so there is no point to keep grading
+	deadline := time.Now().Add(asg.MaxAutograderRun * time.Second)
+	tests, err := asg.SelectAutograderTest(assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(tests) < 1 {
+		return nil, fmt.Errorf("Assignment has no test case")
+	}
+
+	pinned, err := asg.SelectPinnedFileID(assignmentID, nil)
+	if err != nil {
+		return nil, err
+	}
+	files, err := fl.SelectGradedUpload(assignmentID, nil, pinned)
+	if err != nil {
+		return nil, err
+	}
+	userFiles := map[int64][]fl.File{}
+	for _, val := range files {
+		userFiles[val.UserID] = append(userFiles[val.UserID], val)
+	}
+
+	submits, err := asg.SelectSubmittedByAssignment([]int64{assignmentID})
+	if err != nil {
+		return nil, err
+	}
+	var usersID []int64
+	for _, val := range submits {
+		if !helper.Int64InSlice(val.UserID, usersID) {
+			usersID = append(usersID, val.UserID)
+		}
+	}
+	sort.Slice(usersID, func(i, j int) bool {
+		return usersID[i] < usersID[j]
+	})
+
+	results := []asg.AutograderResult{}
+	for _, userID := range usersID {
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("Autograder run took too long")
+		}
+		result, err := handleAutogradeUser(userFiles[userID], tests)
+		if err != nil {
+			return nil, err
+		}
+		result.RunID = runID
+		result.UserID = userID
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// handleAutogradeUser copies the source files of the language most of the files are written in to a new sandbox,
+// compiles them and runs every test. The suggested score is the share of the points of the passed tests
+func handleAutogradeUser(files []fl.File, tests []asg.AutograderTest) (asg.AutograderResult, error) {
+	result := asg.AutograderResult{
+		Status: asg.AutograderNoSource,
+		Total:  len(tests),
+		Tests:  "[]",
+	}
+
+	counts := map[string]int{}
+	for _, val := range files {
+		if _, ok := sandbox.Languages[strings.ToLower(val.Extension)]; ok {
+			counts[strings.ToLower(val.Extension)]++
+		}
+	}
+	ext := ""
+	for key, val := range counts {
+		if val > counts[ext] || (val == counts[ext] && key < ext) {
+			ext = key
+		}
+	}
+	if ext == "" {
+		return result, nil
+	}
+	lang := sandbox.Languages[ext]
+	result.Language = sql.NullString{Valid: true, String: lang.Extension}
+
+	box, err := sandbox.New()
+	if err != nil {
+		return result, err
+	}
+	defer box.Close()
+
+	var names []string
+	for _, val := range files {
+		if strings.ToLower(val.Extension) != ext {
+			continue
+		}
+		// the name is saved without its extension, python looks for main.py so the original name is kept
+		name := fmt.Sprintf("%s.%s", html.UnescapeString(val.Name), ext)
+		f, err := os.Open(fmt.Sprintf("%s/assignment/%s.%s", alias.Dir["data"], val.ID, val.Extension))
+		if err != nil {
+			return result, err
+		}
+		name, err = box.Write(name, f)
+		f.Close()
+		if err != nil {
+			return result, err
+		}
+		names = append(names, name)
+	}
+
+	compiled, err := box.Compile(lang, names)
+	if err != nil {
+		return result, err
+	}
+	if compiled.Status != sandbox.StatusOK {
+		result.Status = asg.AutograderCompileError
+		result.CompileOutput = sql.NullString{Valid: true, String: helper.Trim(compiled.Stderr + compiled.Stdout)}
+		result.SuggestedScore = sql.NullFloat64{Valid: true, Float64: 0}
+		return result, nil
+	}
+
+	var points, total float64
+	testResults := []autograderTestResult{}
+	for _, test := range tests {
+		run, err := box.Run(lang, names, test.Input, sandbox.Limit{
+			Time:   time.Duration(test.Timeout) * time.Millisecond,
+			Memory: sandbox.DefaultMemory,
+			Output: sandbox.DefaultOutput,
+		})
+		if err != nil {
+			return result, err
+		}
+
+		passed := run.Status == sandbox.StatusOK && sandbox.IsSameOutput(test.ExpectedOutput, run.Stdout)
+		total += test.Points
+		if passed {
+			points += test.Points
+			result.Passed++
+		}
+		testResults = append(testResults, autograderTestResult{
+			TestID:   test.ID,
+			Name:     test.Name,
+			Status:   sandbox.Statuses[run.Status],
+			IsPassed: passed,
+			Points:   test.Points,
+			Output:   handleTruncate(run.Stdout, asg.MaxAutograderOutput),
+			Error:    handleTruncate(run.Stderr, asg.MaxAutograderOutput),
+			Duration: int64(run.Duration / time.Millisecond),
+		})
+	}
+
+	tb, err := json.Marshal(testResults)
+	if err != nil {
+		return result, err
+	}
+	result.Status = asg.AutograderGraded
+	result.Tests = string(tb)
+	result.SuggestedScore = sql.NullFloat64{Valid: true, Float64: math.Round(points/total*asg.MaxScore*100) / 100}
+	return result, nil
+}
+
+// handleTruncate cuts the text to at most max bytes without splitting a character
+func handleTruncate(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+	text = text[:max]
+	for len(text) > 0 && !utf8.ValidString(text) {
+		text = text[:len(text)-1]
+	}
+	return text
+}
+
+// handleAutograderResponse builds the run status with the results of every user, the current score
+// is shown next to the suggested score so the assistant can compare them before confirming
+func handleAutograderResponse(assignment asg.Assignment, scheduleID int64, run asg.AutograderRun) (*autograderRunResponse, error) {
+	results, err := asg.SelectAutograderResult(run.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	usersID := []int64{run.CreatedBy}
+	for _, val := range results {
+		usersID = append(usersID, val.UserID)
+	}
+	users, err := usr.RequestID(usersID, false)
+	if err != nil {
+		return nil, err
+	}
+	userMap, err := handleShownStudents(assignment, scheduleID, users)
+	if err != nil {
+		return nil, err
+	}
+	for _, val := range users {
+		if val.ID == run.CreatedBy {
+			userMap[val.ID] = val
+		}
+	}
+	scores, err := asg.SelectScoreByUser(assignment.ID, usersID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	finishedAt := "-"
+	if run.FinishedAt.Valid {
+		finishedAt = run.FinishedAt.Time.Format("Monday, 2 January 2006 15:04:05")
+	}
+	resp := &autograderRunResponse{
+		ID:         run.ID,
+		Status:     asg.AutograderStatuses[run.Status],
+		Message:    run.Message.String,
+		CreatedBy:  userMap[run.CreatedBy].Name,
+		CreatedAt:  run.CreatedAt.Format("Monday, 2 January 2006 15:04:05"),
+		FinishedAt: finishedAt,
+		Results:    []autograderResultResponse{},
+	}
+	for _, val := range results {
+		tests := []autograderTestResult{}
+		err = json.Unmarshal([]byte(val.Tests), &tests)
+		if err != nil {
+			return nil, err
+		}
+
+		suggested := "-"
+		if val.SuggestedScore.Valid {
+			suggested = strconv.FormatFloat(val.SuggestedScore.Float64, 'f', 2, 64)
+		}
+		score := "-"
+		if scores[val.UserID].Valid {
+			score = strconv.FormatFloat(scores[val.UserID].Float64, 'f', 2, 64)
+		}
+		confirmedAt := "-"
+		if val.ConfirmedAt.Valid {
+			confirmedAt = val.ConfirmedAt.Time.Format("Monday, 2 January 2006 15:04:05")
+		}
+		resp.Results = append(resp.Results, autograderResultResponse{
+			IdentityCode:   userMap[val.UserID].IdentityCode,
+			Name:           userMap[val.UserID].Name,
+			Status:         asg.AutograderResultStatuses[val.Status],
+			Language:       val.Language.String,
+			CompileOutput:  val.CompileOutput.String,
+			Passed:         val.Passed,
+			Total:          val.Total,
+			SuggestedScore: suggested,
+			Score:          score,
+			IsConfirmed:    val.ConfirmedAt.Valid,
+			ConfirmedAt:    confirmedAt,
+			Tests:          tests,
+		})
+	}
+	// identities must not leak through the order while they are hidden
+	sort.Slice(resp.Results, func(i, j int) bool {
+		return resp.Results[i].IdentityCode < resp.Results[j].IdentityCode
+	})
+	return resp, nil
+}
+
+// handleAutograderConfirm saves the suggested scores of the users as their score and marks them as confirmed,
+// the feedback of the users is kept. It returns the number of confirmed scores
+func handleAutograderConfirm(assignmentID int64, run asg.AutograderRun, usersID []int64, actorID int64) (int, error) {
+	results, err := asg.SelectAutograderResult(run.ID, usersID)
+	if err != nil {
+		return 0, err
+	}
+	var entries []scoreEntry
+	var confirmedID []int64
+	for _, val := range results {
+		if !val.SuggestedScore.Valid || val.ConfirmedAt.Valid {
+			continue
+		}
+		entries = append(entries, scoreEntry{
+			UserID: val.UserID,
+			Score:  float32(val.SuggestedScore.Float64),
+		})
+		confirmedID = append(confirmedID, val.UserID)
+	}
+	if len(entries) < 1 {
+		return 0, nil
+	}
+
+	tx := conn.DB.MustBegin()
+	err = handleScoreWrite(assignmentID, entries, scoreAudit{
+		Actor:  actorID,
+		Source: asg.ScoreSourceAutograder,
+		Reason: sql.NullString{Valid: true, String: "Autograder"},
+	}, tx)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	err = asg.ConfirmAutograderResult(run.ID, confirmedID, actorID, tx)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
@@ -0,0 +1,254 @@
+package assignment
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+	"time"
+
+	asg "github.com/asepnur/meiko_course/src/module/assignment"
+	usr "github.com/asepnur/meiko_course/src/module/user"
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestHandleLatenessUpdate(t *testing.T) {
+	due := time.Date(2017, 9, 1, 8, 0, 0, 0, time.Local)
+	// the extension moves the due date 2 hours later
+	extended := asg.Assignment{ID: 10, DueDate: due}.Extend(&asg.Extension{DueDate: due.Add(2 * time.Hour)})
+
+	type mock struct {
+		submit   []driver.Value
+		versions [][]driver.Value
+		updates  []string
+	}
+	tests := []struct {
+		name string
+		mock mock
+	}{
+		{
+			name: "Not submitted",
+			mock: mock{},
+		},
+		{
+			name: "Latest version is graded",
+			mock: mock{
+				submit: []driver.Value{10800, nil},
+				versions: [][]driver.Value{
+					{22, 2, 10800, due.Add(3 * time.Hour)},
+					{21, 1, 3600, due.Add(time.Hour)},
+				},
+				updates: []string{
+					`UPDATE\s+submission_versions\s+SET\s+late_seconds = \(3600\)\s+WHERE\s+id = \(22\)`,
+					`UPDATE\s+submission_versions\s+SET\s+late_seconds = \(0\)\s+WHERE\s+id = \(21\)`,
+					`(?s)UPDATE\s+p_users_assignments\s+SET\s+pinned_version = \(NULL\),\s+late_seconds = \(3600\).+users_id = \(1\)`,
+				},
+			},
+		},
+		{
+			name: "Pinned version is graded",
+			mock: mock{
+				submit: []driver.Value{3600, 1},
+				versions: [][]driver.Value{
+					{22, 2, 0, due.Add(2 * time.Hour)},
+					{21, 1, 3600, due.Add(time.Hour)},
+				},
+				updates: []string{
+					`UPDATE\s+submission_versions\s+SET\s+late_seconds = \(0\)\s+WHERE\s+id = \(21\)`,
+					`(?s)UPDATE\s+p_users_assignments\s+SET\s+pinned_version = \(1\),\s+late_seconds = \(0\).+users_id = \(1\)`,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := conn.InitDBMock()
+			if err != nil {
+				t.Fatalf("InitDBMock() error = %v", err)
+			}
+
+			submit := sqlmock.NewRows([]string{"late_seconds", "pinned_version"})
+			if tt.mock.submit != nil {
+				submit.AddRow(tt.mock.submit...)
+			}
+			mock.ExpectQuery(`(?s)SELECT.+FROM\s+p_users_assignments`).WillReturnRows(submit)
+			if tt.mock.submit != nil {
+				versions := sqlmock.NewRows([]string{"id", "version", "late_seconds", "created_at"})
+				for _, val := range tt.mock.versions {
+					versions.AddRow(val...)
+				}
+				mock.ExpectQuery(`(?s)SELECT.+FROM\s+submission_versions`).WillReturnRows(versions)
+			}
+			for _, val := range tt.mock.updates {
+				mock.ExpectExec(val).WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			if err := handleLatenessUpdate(extended, 1, nil); err != nil {
+				t.Errorf("handleLatenessUpdate() error = %v", err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("handleLatenessUpdate() %v", err)
+			}
+		})
+	}
+}
+
+func TestHandleSubmitGroup(t *testing.T) {
+	now := time.Date(2017, 9, 1, 9, 30, 0, 0, time.Local)
+	assignment := asg.Assignment{
+		ID:        10,
+		DueDate:   now.Add(-90 * time.Minute),
+		GroupMode: asg.GroupModeSchedule,
+	}
+
+	mock, err := conn.InitDBMock()
+	if err != nil {
+		t.Fatalf("InitDBMock() error = %v", err)
+	}
+	mock.ExpectQuery(`(?s)SELECT.+FROM\s+p_users_assignment_groups`).
+		WillReturnRows(sqlmock.NewRows([]string{"assignment_groups_id", "users_id"}).AddRow(7, 1).AddRow(7, 2))
+	mock.ExpectQuery(`(?s)SELECT.+FROM\s+p_users_assignments`).
+		WillReturnRows(sqlmock.NewRows([]string{"assignments_id", "users_id", "late_seconds"}).AddRow(10, 1, 0))
+	mock.ExpectQuery(`(?s)SELECT.+FROM\s+files`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin()
+	// every member shares the lateness of the group submission
+	mock.ExpectExec(`(?s)UPDATE\s+p_users_assignments.+late_seconds = IF\(pinned_version IS NULL, \(5400\), late_seconds\).+users_id = \(1\)`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`(?s)INSERT INTO\s+submission_versions.+\(5400\)`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`(?s)INSERT INTO\s+p_users_assignments.+\(10\),\s+\(2\),\s+\(NULL\),\s+\(5400\)`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`(?s)INSERT INTO\s+submission_versions.+\(5400\)`).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec(`(?s)UPDATE\s+p_users_assignments\s+SET\s+assignment_groups_id = \(7\).+users_id IN \(1, 2\)`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	if err := handleSubmitGroup(assignment, 7, 1, sql.NullString{}, nil, now); err != nil {
+		t.Errorf("handleSubmitGroup() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("handleSubmitGroup() %v", err)
+	}
+}
+
+func TestHandleAnonymousStudents(t *testing.T) {
+	assignment := asg.Assignment{ID: 10}
+	assignment.AnonymousKey.Valid = true
+	assignment.AnonymousKey.String = "8f14e45fceea167a"
+
+	students := map[int64]usr.UserReq{
+		140810140016: {ID: 1, Name: "Risal Falah", IdentityCode: 140810140016},
+		140810140022: {ID: 2, Name: "Asep Nur", IdentityCode: 140810140022},
+		140810140031: {ID: 3, Name: "Dinda Putri", IdentityCode: 140810140031},
+	}
+
+	anonymous := handleAnonymousStudents(assignment, students)
+	if len(anonymous) != len(students) {
+		t.Fatalf("handleAnonymousStudents() returns %d students, want %d", len(anonymous), len(students))
+	}
+
+	shown := map[int64]bool{}
+	for code, val := range anonymous {
+		if _, ok := students[code]; ok {
+			t.Errorf("handleAnonymousStudents() exposes identity code %d", code)
+		}
+		if code < asg.AnonymousCodeMin || code > asg.AnonymousCodeMax {
+			t.Errorf("handleAnonymousStudents() code %d is out of range", code)
+		}
+		if val.IdentityCode != code || val.Name != fmt.Sprintf("Anonymous %d", code) {
+			t.Errorf("handleAnonymousStudents() = %+v, want identity and name of code %d", val, code)
+		}
+		shown[val.ID] = true
+	}
+	for _, val := range students {
+		if !shown[val.ID] {
+			t.Errorf("handleAnonymousStudents() user %d is missing", val.ID)
+		}
+	}
+
+	// codes have to stay the same between requests
+	again := handleAnonymousStudents(assignment, students)
+	for code, val := range anonymous {
+		if again[code].ID != val.ID {
+			t.Errorf("handleAnonymousStudents() code %d changes from user %d to %d", code, val.ID, again[code].ID)
+		}
+	}
+}
+
+func TestHandleRegradeExpire(t *testing.T) {
+	published := time.Date(2017, 12, 20, 10, 0, 0, 0, time.Local)
+
+	type mock struct {
+		publication []driver.Value
+		now         time.Time
+	}
+	tests := []struct {
+		name  string
+		mock  mock
+		ended bool
+	}{
+		{
+			name:  "Grade is not published",
+			mock:  mock{},
+			ended: false,
+		},
+		{
+			name: "Regrade period is running",
+			mock: mock{
+				publication: []driver.Value{1, 5, 1, published},
+				now:         published.AddDate(0, 0, 6),
+			},
+			ended: false,
+		},
+		{
+			name: "Regrade period has ended",
+			mock: mock{
+				publication: []driver.Value{1, 5, 1, published},
+				now:         published.AddDate(0, 0, 7),
+			},
+			ended: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := conn.InitDBMock()
+			if err != nil {
+				t.Fatalf("InitDBMock() error = %v", err)
+			}
+
+			publication := sqlmock.NewRows([]string{"id", "schedules_id", "status", "created_at"})
+			if tt.mock.publication != nil {
+				publication.AddRow(tt.mock.publication...)
+			}
+			mock.ExpectQuery(`(?s)SELECT.+FROM\s+grade_publications`).WillReturnRows(publication)
+			if tt.mock.publication != nil {
+				mock.ExpectQuery(`(?s)SELECT.+FROM\s+grade_policies`).
+					WillReturnRows(sqlmock.NewRows([]string{"schedules_id", "regrade_days"}).AddRow(5, 7))
+				mock.ExpectQuery(`SELECT NOW\(\)`).
+					WillReturnRows(sqlmock.NewRows([]string{"NOW()"}).AddRow(tt.mock.now))
+			}
+			if tt.ended {
+				mock.ExpectQuery(`(?s)SELECT.+FROM\s+grade_parameters`).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "schedules_id"}).AddRow(3, 5))
+				mock.ExpectQuery(`(?s)SELECT.+FROM\s+assignments`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10).AddRow(11))
+				mock.ExpectExec(`(?s)UPDATE\s+regrade_requests.+assignments_id IN \(10, 11\)`).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			}
+
+			ended, err := handleRegradeExpire(5)
+			if err != nil {
+				t.Errorf("handleRegradeExpire() error = %v", err)
+			}
+			if ended != tt.ended {
+				t.Errorf("handleRegradeExpire() = %v, want %v", ended, tt.ended)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("handleRegradeExpire() %v", err)
+			}
+		})
+	}
+}
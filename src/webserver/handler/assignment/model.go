@@ -2,11 +2,19 @@ package assignment
 
 import (
 	"database/sql"
+	"regexp"
 	"time"
 
+	asg "github.com/asepnur/meiko_course/src/module/assignment"
+	cs "github.com/asepnur/meiko_course/src/module/course"
 	fs "github.com/asepnur/meiko_course/src/module/file"
+	"github.com/asepnur/meiko_course/src/util/similarity"
+	"github.com/go-sql-driver/mysql"
 )
 
+// GradebookHidden is shown instead of the score of an assignment whose identities are hidden and instead of the total
+const GradebookHidden = "hidden"
+
 type getParams struct {
 	scheduleID string
 	filter     string
@@ -37,19 +45,32 @@ type getDetailArgs struct {
 }
 
 type getDetailResponse struct {
-	ID                   int64  `json:"id"`
-	Name                 string `json:"name"`
-	Description          string `json:"description"`
-	DueDate              string `json:"due_date"`
-	Score                string `json:"score"`
-	Status               string `json:"status"`
-	CreatedAt            string `json:"created_at"`
-	UpdatedAt            string `json:"updated_at"`
-	AssignmentFile       []file `json:"assignment_file"`
-	IsAllowUpload        bool   `json:"is_allow_upload"`
-	SubmittedDescription string `json:"submitted_description"`
-	SubmittedFile        []file `json:"submitted_file"`
-	SubmittedDate        string `json:"submitted_date"`
+	ID                   int64              `json:"id"`
+	Name                 string             `json:"name"`
+	Description          string             `json:"description"`
+	DueDate              string             `json:"due_date"`
+	CloseDate            string             `json:"close_date"`
+	LatePolicy           string             `json:"late_policy"`
+	Late                 string             `json:"late"`
+	Score                string             `json:"score"`
+	Status               string             `json:"status"`
+	CreatedAt            string             `json:"created_at"`
+	UpdatedAt            string             `json:"updated_at"`
+	AssignmentFile       []file             `json:"assignment_file"`
+	IsAllowUpload        bool               `json:"is_allow_upload"`
+	SubmittedDescription string             `json:"submitted_description"`
+	SubmittedFile        []file             `json:"submitted_file"`
+	SubmittedDate        string             `json:"submitted_date"`
+	Feedback             string             `json:"feedback"`
+	Group                *groupResponse     `json:"group,omitempty"`
+	Rubric               *rubricResponse    `json:"rubric,omitempty"`
+	Feedbacks            []feedbackResponse `json:"feedbacks"`
+}
+
+type groupResponse struct {
+	ID      int64    `json:"id"`
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
 }
 
 type submitParams struct {
@@ -101,6 +122,17 @@ type createParams struct {
 	maxSizeFile      string
 	allowedTypesFile string
 	maxFile          string
+	latePolicy       string
+	gracePeriod      string
+	latePenalty      string
+	closeDate        string
+	groupMode        string
+	publishAt        string
+	unpublishAt      string
+	peerReviewers    string
+	peerReviewClose  string
+	peerWeight       string
+	anonymous        string
 }
 
 type createArgs struct {
@@ -113,6 +145,11 @@ type createArgs struct {
 	maxSizeFile      int64
 	allowedTypesFile []string
 	maxFile          int64
+	late             latePolicy
+	groupMode        int8
+	publish          publishWindow
+	peer             peerReview
+	anonymous        bool
 }
 type updateParams struct {
 	ID               string
@@ -125,6 +162,17 @@ type updateParams struct {
 	maxSizeFile      string
 	allowedTypesFile string
 	maxFile          string
+	latePolicy       string
+	gracePeriod      string
+	latePenalty      string
+	closeDate        string
+	groupMode        string
+	publishAt        string
+	unpublishAt      string
+	peerReviewers    string
+	peerReviewClose  string
+	peerWeight       string
+	anonymous        string
 }
 type updateArgs struct {
 	ID               int64
@@ -137,6 +185,29 @@ type updateArgs struct {
 	maxSizeFile      int64
 	allowedTypesFile []string
 	maxFile          int64
+	late             latePolicy
+	groupMode        int8
+	publish          publishWindow
+	peer             peerReview
+	anonymous        bool
+}
+
+type latePolicy struct {
+	policy      int8
+	gracePeriod int64
+	penalty     float64
+	closeDate   mysql.NullTime
+}
+
+type publishWindow struct {
+	publishAt   mysql.NullTime
+	unpublishAt mysql.NullTime
+}
+
+type peerReview struct {
+	reviewers int8
+	closeDate mysql.NullTime
+	weight    float64
 }
 
 type deleteParams struct {
@@ -155,14 +226,17 @@ type availableArgs struct {
 }
 
 type getReportResponse struct {
-	ScheduleID int64  `json:"schedule_id"`
-	CourseName string `json:"course_name"`
-	Attendance string `json:"attendance"`
-	Assignment string `json:"assignment"`
-	Quiz       string `json:"quiz"`
-	Mid        string `json:"mid"`
-	Final      string `json:"final"`
-	Total      string `json:"total"`
+	ScheduleID  int64  `json:"schedule_id"`
+	CourseName  string `json:"course_name"`
+	Attendance  string `json:"attendance"`
+	Assignment  string `json:"assignment"`
+	Quiz        string `json:"quiz"`
+	Mid         string `json:"mid"`
+	Final       string `json:"final"`
+	Total       string `json:"total"`
+	Letter      string `json:"letter"`
+	GradePoint  string `json:"grade_point"`
+	IsPublished bool   `json:"is_published"`
 }
 
 type getGradeResponse struct {
@@ -198,11 +272,14 @@ type detailResponse struct {
 	Type             string `json:"type"`
 }
 type detAsgUser struct {
-	ID          int64  `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	UploadedAt  string `json:"uploaded_at"`
-	Link        string `json:"url"`
+	ID          int64   `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	UploadedAt  string  `json:"uploaded_at"`
+	Link        string  `json:"url"`
+	IsLate      bool    `json:"is_late"`
+	Late        string  `json:"late"`
+	Penalty     float64 `json:"penalty"`
 }
 type respDetAsgUser struct {
 	TotalPage   int          `json:"total_page"`
@@ -210,20 +287,35 @@ type respDetAsgUser struct {
 	ID          int64        `json:"id"`
 	Name        string       `json:"name"`
 	DueDate     string       `json:"due_date"`
+	CloseDate   string       `json:"close_date"`
+	LatePolicy  string       `json:"late_policy"`
 	Status      string       `json:"status"`
+	IsAnonymous bool         `json:"is_anonymous"`
 	DetAsgUser  []detAsgUser `json:"users"`
 }
 type respDetailUpdate struct {
-	ID               int64     `json:"id"`
-	Name             string    `json:"name"`
-	Description      string    `json:"description"`
-	DueDate          time.Time `json:"due_date"`
-	GradeParameterID int64     `json:"grade_parameter_id"`
-	Status           int8      `json:"status"`
-	MaxSize          int8      `json:"max_size"`
-	MaxFile          int8      `json:"max_file"`
-	Type             []string  `json:"types"`
-	FilesID          []file    `json:"files"`
+	ID               int64      `json:"id"`
+	Name             string     `json:"name"`
+	Description      string     `json:"description"`
+	DueDate          time.Time  `json:"due_date"`
+	GradeParameterID int64      `json:"grade_parameter_id"`
+	Status           int8       `json:"status"`
+	MaxSize          int8       `json:"max_size"`
+	MaxFile          int8       `json:"max_file"`
+	LatePolicy       string     `json:"late_policy"`
+	GracePeriod      int64      `json:"grace_period"`
+	LatePenalty      float64    `json:"late_penalty"`
+	CloseDate        *time.Time `json:"close_date"`
+	GroupMode        string     `json:"group_mode"`
+	PublishAt        *time.Time `json:"publish_at"`
+	UnpublishAt      *time.Time `json:"unpublish_at"`
+	PeerReviewers    int8       `json:"peer_reviewers"`
+	PeerReviewClose  *time.Time `json:"peer_review_close"`
+	PeerWeight       float64    `json:"peer_weight"`
+	IsAnonymous      bool       `json:"is_anonymous"`
+	IsRevealed       bool       `json:"is_revealed"`
+	Type             []string   `json:"types"`
+	FilesID          []file     `json:"files"`
 }
 
 type file struct {
@@ -311,15 +403,17 @@ type listAssignmentsArgs struct {
 
 type updateScoreParams struct {
 	Score        string
-	UserID       string
+	Feedback     string
+	IdentityCode string
 	ScheduleID   string
 	AssignmentID string
+	Reason       string
 }
 type updateScoreArgs struct {
-	Score        float32
-	UserID       int64
+	Row          scoreRow
 	ScheduleID   int64
 	AssignmentID int64
+	Reason       sql.NullString
 }
 type detailAssignmentParams struct {
 	ScheduleID   string
@@ -331,37 +425,94 @@ type detailAssignmentArgs struct {
 }
 
 type userAssignment struct {
-	UserID int64   `json:"user_id"`
-	Name   string  `json:"name"`
-	Grade  float32 `json:"grade"`
+	IdentityCode int64  `json:"identity_code"`
+	Name         string `json:"name"`
+	Score        string `json:"score"`
+	Feedback     string `json:"feedback"`
+	IsSubmitted  bool   `json:"is_submitted"`
+	SubmittedAt  string `json:"submitted_at"`
 }
 
 type detailAssignmentResponse struct {
-	Name          string         `json:"name"`
-	Description   sql.NullString `json:"description"`
-	DueDate       time.Time      `json:"due_date"`
-	IsCreateScore bool           `json:"is_create_score"`
-	Praktikan     []userAssignment
+	ID            int64            `json:"id"`
+	Name          string           `json:"name"`
+	Status        string           `json:"status"`
+	DueDate       string           `json:"due_date"`
+	IsCreateScore bool             `json:"is_create_score"`
+	IsAnonymous   bool             `json:"is_anonymous"`
+	Praktikan     []userAssignment `json:"students"`
 }
 type createScoreParams struct {
 	ScheduleID   string
 	AssignmentID string
-	Name         string
-	Description  string
 	Users        string
+	Reason       string
 }
 type createScoreArgs struct {
 	ScheduleID   int64
 	AssignmentID int64
-	Name         string
-	Description  sql.NullString
-	IdentityCode []int64
-	Score        []float32
+	Rows         []scoreRow
+	Reason       sql.NullString
+}
+type uploadScoreParams struct {
+	ScheduleID   string
+	AssignmentID string
+	IsPreview    string
+	Reason       string
+}
+type uploadScoreArgs struct {
+	ScheduleID   int64
+	AssignmentID int64
+	IsPreview    bool
+	Reason       sql.NullString
 }
 type student struct {
 	IdentityCode int64   `json:"identity_code"`
-	Name         string  `json:"name"`
-	Score        float32 `json:"score"`
+	Score        float64 `json:"score"`
+	Feedback     string  `json:"feedback"`
+}
+
+// scoreRow is a single score input before it is checked against the schedule, line is the CSV line number
+type scoreRow struct {
+	Line         int
+	IdentityCode string
+	Score        string
+	Feedback     string
+	// HasFeedback is false when the row has no feedback column, the current feedback is kept
+	HasFeedback bool
+}
+
+// scoreAudit is who changes the score, where the change comes from and why, it is written to the score log
+type scoreAudit struct {
+	Actor  int64
+	Source int8
+	Reason sql.NullString
+}
+
+// scoreEntry is a validated score which is ready to be saved, null feedback keeps the current feedback
+type scoreEntry struct {
+	UserID   int64
+	Score    float32
+	Feedback sql.NullString
+}
+
+type scorePreview struct {
+	Line         int    `json:"line"`
+	IdentityCode string `json:"identity_code"`
+	Name         string `json:"name"`
+	OldScore     string `json:"old_score"`
+	Score        string `json:"score"`
+	Feedback     string `json:"feedback"`
+	IsValid      bool   `json:"is_valid"`
+	Error        string `json:"error,omitempty"`
+}
+
+type scorePreviewResponse struct {
+	IsValid bool           `json:"is_valid"`
+	IsSaved bool           `json:"is_saved"`
+	Total   int            `json:"total"`
+	Invalid int            `json:"invalid"`
+	Rows    []scorePreview `json:"rows"`
 }
 type listAssignmentResponse struct {
 	ID          int64  `json:"id"`
@@ -391,3 +542,665 @@ type respGP struct {
 	ID   int64  `json:"id"`
 	Name string `json:"name"`
 }
+
+type extensionParams struct {
+	id            string
+	identityCodes string
+	dueDate       string
+	closeDate     string
+	reason        string
+}
+
+type extensionArgs struct {
+	id            int64
+	identityCodes []int64
+	dueDate       time.Time
+	closeDate     mysql.NullTime
+	reason        string
+}
+
+type deleteExtensionParams struct {
+	id           string
+	identityCode string
+}
+
+type deleteExtensionArgs struct {
+	id           int64
+	identityCode int64
+}
+
+type extensionResponse struct {
+	IdentityCode int64  `json:"identity_code"`
+	Name         string `json:"name"`
+	DueDate      string `json:"due_date"`
+	CloseDate    string `json:"close_date"`
+	Reason       string `json:"reason"`
+	GrantedBy    string `json:"granted_by"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+type groupScoreParams struct {
+	ScheduleID   string
+	AssignmentID string
+	GroupID      string
+	Score        string
+	Feedback     string
+	Adjustments  string
+}
+
+type groupScoreArgs struct {
+	ScheduleID   int64
+	AssignmentID int64
+	GroupID      int64
+	Score        float32
+	Feedback     sql.NullString
+	Adjustments  []adjustment
+}
+
+type adjustment struct {
+	IdentityCode int64   `json:"identity_code"`
+	Adjustment   float64 `json:"adjustment"`
+}
+
+type studentParams struct {
+	scheduleID   string
+	assignmentID string
+	identityCode string
+}
+
+type studentArgs struct {
+	scheduleID   int64
+	assignmentID int64
+	identityCode int64
+}
+
+type pinVersionParams struct {
+	scheduleID   string
+	assignmentID string
+	identityCode string
+	version      string
+}
+
+type pinVersionArgs struct {
+	scheduleID   int64
+	assignmentID int64
+	identityCode int64
+	version      sql.NullInt64
+}
+
+type versionResponse struct {
+	Version     int64   `json:"version"`
+	Hash        string  `json:"hash"`
+	Description string  `json:"description"`
+	IsLate      bool    `json:"is_late"`
+	Late        string  `json:"late"`
+	Penalty     float64 `json:"penalty"`
+	SubmittedBy string  `json:"submitted_by"`
+	IsGraded    bool    `json:"is_graded"`
+	Files       []file  `json:"files"`
+	CreatedAt   string  `json:"created_at"`
+}
+
+type attachRubricParams struct {
+	scheduleID   string
+	assignmentID string
+	rubricID     string
+}
+
+type attachRubricArgs struct {
+	scheduleID   int64
+	assignmentID int64
+	rubricID     sql.NullInt64
+}
+
+type fillRubricParams struct {
+	scheduleID   string
+	assignmentID string
+	identityCode string
+	levels       string
+	feedback     string
+}
+
+type fillRubricArgs struct {
+	scheduleID   int64
+	assignmentID int64
+	identityCode int64
+	levels       map[int64]int64
+	comments     map[int64]string
+	feedback     sql.NullString
+}
+
+type rubricLevel struct {
+	CriterionID int64  `json:"criterion_id"`
+	LevelID     int64  `json:"level_id"`
+	Comment     string `json:"comment"`
+}
+
+type rubricResponse struct {
+	ID        int64                     `json:"id"`
+	Name      string                    `json:"name"`
+	MaxPoints float64                   `json:"max_points"`
+	IsFilled  bool                      `json:"is_filled"`
+	Points    float64                   `json:"points"`
+	Score     float64                   `json:"score"`
+	Criteria  []rubricCriterionResponse `json:"criteria"`
+}
+
+type rubricCriterionResponse struct {
+	ID          int64                 `json:"id"`
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	MaxPoints   float64               `json:"max_points"`
+	Levels      []rubricLevelResponse `json:"levels"`
+	LevelID     int64                 `json:"level_id,omitempty"`
+	Points      float64               `json:"points"`
+	Comment     string                `json:"comment"`
+}
+
+type rubricLevelResponse struct {
+	ID          int64   `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Points      float64 `json:"points"`
+}
+
+type feedbackParams struct {
+	scheduleID   string
+	assignmentID string
+	identityCode string
+	message      string
+	fileID       string
+}
+
+type feedbackArgs struct {
+	scheduleID   int64
+	assignmentID int64
+	identityCode int64
+	message      sql.NullString
+	fileID       []string
+}
+
+type replyFeedbackParams struct {
+	id      string
+	message string
+}
+
+type replyFeedbackArgs struct {
+	id      int64
+	message sql.NullString
+}
+
+type feedbackResponse struct {
+	ID          int64  `json:"id"`
+	Sender      string `json:"sender"`
+	IsAssistant bool   `json:"is_assistant"`
+	Message     string `json:"message"`
+	Files       []file `json:"files"`
+	IsRead      bool   `json:"is_read"`
+	ReadAt      string `json:"read_at"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type similarityPairParams struct {
+	scheduleID        string
+	assignmentID      string
+	identityCode      string
+	otherIdentityCode string
+}
+
+type similarityPairArgs struct {
+	scheduleID        int64
+	assignmentID      int64
+	identityCode      int64
+	otherIdentityCode int64
+}
+
+type similarityMatch struct {
+	User  []similarity.Region `json:"user"`
+	Other []similarity.Region `json:"other"`
+}
+
+type similarityReportResponse struct {
+	ID         int64                    `json:"id"`
+	Status     string                   `json:"status"`
+	Message    string                   `json:"message"`
+	CreatedBy  string                   `json:"created_by"`
+	CreatedAt  string                   `json:"created_at"`
+	FinishedAt string                   `json:"finished_at"`
+	Pairs      []similarityPairResponse `json:"pairs"`
+}
+
+type similarityPairResponse struct {
+	IdentityCode      int64   `json:"identity_code"`
+	Name              string  `json:"name"`
+	OtherIdentityCode int64   `json:"other_identity_code"`
+	OtherName         string  `json:"other_name"`
+	Score             float64 `json:"score"`
+	ScoreUser         float64 `json:"score_user"`
+	ScoreOther        float64 `json:"score_other"`
+}
+
+type similarityDetailResponse struct {
+	similarityPairResponse
+	RegionsUser  []similarity.Region `json:"regions_user"`
+	RegionsOther []similarity.Region `json:"regions_other"`
+}
+
+type gradebookParams struct {
+	ScheduleID string
+	Format     string
+}
+
+type gradebookArgs struct {
+	ScheduleID int64
+	Format     string
+}
+
+type importGradebookParams struct {
+	ScheduleID string
+	FileName   string
+	IsPreview  string
+	Reason     string
+}
+
+type importGradebookArgs struct {
+	ScheduleID int64
+	Format     string
+	IsPreview  bool
+	Reason     sql.NullString
+}
+
+// gradebookHeader matches the assignment id at the end of an assessment column header
+var gradebookHeader = regexp.MustCompile(`\(#(\d+)\)$`)
+
+// gradebookColumn is an assessment column of the gradebook, header is used to match the column on import
+type gradebookColumn struct {
+	AssignmentID int64  `json:"assignment_id"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	Header       string `json:"header"`
+	IsHidden     bool   `json:"is_hidden"`
+}
+
+// gradebookRow holds scores of a student in the same order as the columns, ungraded score is empty
+// and the score of a hidden column is GradebookHidden
+type gradebookRow struct {
+	IdentityCode int64    `json:"identity_code"`
+	Name         string   `json:"name"`
+	Scores       []string `json:"scores"`
+	Attendance   string   `json:"attendance"`
+	Total        string   `json:"total"`
+}
+
+type gradebookResponse struct {
+	Columns []gradebookColumn `json:"columns"`
+	Rows    []gradebookRow    `json:"rows"`
+}
+
+// gradebookChange is a single score which differs from the current gradebook, line is the line of the sheet
+type gradebookChange struct {
+	Line         int    `json:"line"`
+	IdentityCode string `json:"identity_code"`
+	Name         string `json:"name"`
+	AssignmentID int64  `json:"assignment_id"`
+	Assignment   string `json:"assignment"`
+	OldScore     string `json:"old_score"`
+	Score        string `json:"score"`
+	IsValid      bool   `json:"is_valid"`
+	Error        string `json:"error,omitempty"`
+}
+
+type gradebookPreviewResponse struct {
+	IsValid bool              `json:"is_valid"`
+	IsSaved bool              `json:"is_saved"`
+	Total   int               `json:"total"`
+	Invalid int               `json:"invalid"`
+	Changes []gradebookChange `json:"changes"`
+}
+
+type scoreLogParams struct {
+	ScheduleID   string
+	AssignmentID string
+	IdentityCode string
+}
+
+type scoreLogArgs struct {
+	ScheduleID   int64
+	AssignmentID int64
+	IdentityCode int64
+}
+
+type studentScoreLogParams struct {
+	ScheduleID   string
+	IdentityCode string
+}
+
+type studentScoreLogArgs struct {
+	ScheduleID   int64
+	IdentityCode int64
+}
+
+// scoreLogResponse is a score change, score which is not set yet is shown as "-"
+type scoreLogResponse struct {
+	ID           int64  `json:"id"`
+	AssignmentID int64  `json:"assignment_id"`
+	Assignment   string `json:"assignment"`
+	IdentityCode int64  `json:"identity_code"`
+	Name         string `json:"name"`
+	OldScore     string `json:"old_score"`
+	NewScore     string `json:"new_score"`
+	Actor        string `json:"actor"`
+	Source       string `json:"source"`
+	Reason       string `json:"reason"`
+	CreatedAt    string `json:"created_at"`
+}
+
+type createRegradeParams struct {
+	id     string
+	reason string
+	fileID string
+}
+
+type createRegradeArgs struct {
+	id     int64
+	reason string
+	fileID string
+}
+
+type resolveRegradeParams struct {
+	ScheduleID string
+	RegradeID  string
+	Action     string
+	Score      string
+	Response   string
+}
+
+type resolveRegradeArgs struct {
+	ScheduleID int64
+	RegradeID  int64
+	Action     string
+	Score      float32
+	Response   sql.NullString
+}
+
+// regradeResponse is a regrade request with the current score of the submission
+type regradeResponse struct {
+	ID           int64  `json:"id"`
+	AssignmentID int64  `json:"assignment_id"`
+	Assignment   string `json:"assignment"`
+	IdentityCode int64  `json:"identity_code"`
+	Name         string `json:"name"`
+	Reason       string `json:"reason"`
+	Score        string `json:"score"`
+	Status       string `json:"status"`
+	Response     string `json:"response"`
+	HandledBy    string `json:"handled_by"`
+	Files        []file `json:"files"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+type submitPeerReviewParams struct {
+	id       string
+	reviewID string
+	levels   string
+	comment  string
+}
+
+type submitPeerReviewArgs struct {
+	id       int64
+	reviewID int64
+	levels   map[int64]int64
+	comments map[int64]string
+	comment  sql.NullString
+}
+
+type moderatePeerReviewParams struct {
+	scheduleID   string
+	assignmentID string
+	reviewID     string
+	action       string
+}
+
+type moderatePeerReviewArgs struct {
+	scheduleID   int64
+	assignmentID int64
+	reviewID     int64
+	from         int8
+	status       int8
+}
+
+// peerTaskResponse is a submission the student has to review, it does not tell whose submission it is
+type peerTaskResponse struct {
+	ID          int64           `json:"id"`
+	Status      string          `json:"status"`
+	Description string          `json:"description"`
+	Files       []file          `json:"files"`
+	Comment     string          `json:"comment"`
+	Rubric      *rubricResponse `json:"rubric"`
+}
+
+// peerReceivedResponse is a review on the submission of the student, it does not tell who the reviewer is
+type peerReceivedResponse struct {
+	Score   float64         `json:"score"`
+	Comment string          `json:"comment"`
+	Rubric  *rubricResponse `json:"rubric"`
+}
+
+type peerResponse struct {
+	IsOpen    bool                   `json:"is_open"`
+	CloseDate string                 `json:"close_date"`
+	Weight    float64                `json:"weight"`
+	PeerScore string                 `json:"peer_score"`
+	Reviews   []peerTaskResponse     `json:"reviews"`
+	Received  []peerReceivedResponse `json:"received"`
+}
+
+type peerReviewAdminResponse struct {
+	ID                   int64           `json:"id"`
+	ReviewerIdentityCode int64           `json:"reviewer_identity_code"`
+	ReviewerName         string          `json:"reviewer_name"`
+	IdentityCode         int64           `json:"identity_code"`
+	Name                 string          `json:"name"`
+	Status               string          `json:"status"`
+	Score                string          `json:"score"`
+	Comment              string          `json:"comment"`
+	IsOutlier            bool            `json:"is_outlier"`
+	ModeratedBy          string          `json:"moderated_by"`
+	UpdatedAt            string          `json:"updated_at"`
+	Rubric               *rubricResponse `json:"rubric"`
+}
+
+type peerScoreResponse struct {
+	IdentityCode int64   `json:"identity_code"`
+	Name         string  `json:"name"`
+	PeerScore    float64 `json:"peer_score"`
+}
+
+type peerAdminResponse struct {
+	IsOpen    bool                      `json:"is_open"`
+	IsClosed  bool                      `json:"is_closed"`
+	CloseDate string                    `json:"close_date"`
+	Reviewers int8                      `json:"reviewers"`
+	Weight    float64                   `json:"weight"`
+	Scores    []peerScoreResponse       `json:"scores"`
+	Reviews   []peerReviewAdminResponse `json:"reviews"`
+}
+
+type statisticParams struct {
+	ScheduleID string
+	Bins       string
+}
+
+type statisticArgs struct {
+	ScheduleID int64
+	Bins       int
+}
+
+// scheduleScores is the scores of the enrolled students of a schedule which the statistics are computed from,
+// assignments are ordered by grade parameter and scores of every assignment and grade parameter are keyed by id
+type scheduleScores struct {
+	enrolled    int
+	gps         []cs.GradeParameter
+	assignments []asg.Assignment
+	submitted   map[int64]int
+	late        map[int64]int
+	scores      map[int64][]float64
+	parameters  map[int64][]float64
+	totals      []float64
+}
+
+// scoreSummary is the descriptive statistics of scores, StdDev is the population standard deviation
+type scoreSummary struct {
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	StdDev float64 `json:"std_dev"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+}
+
+type histogramBin struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Count int     `json:"count"`
+}
+
+type assignmentStatistic struct {
+	ID             int64          `json:"id"`
+	Name           string         `json:"name"`
+	Type           string         `json:"type"`
+	Status         string         `json:"status"`
+	Enrolled       int            `json:"enrolled"`
+	Submitted      int            `json:"submitted"`
+	SubmissionRate float64        `json:"submission_rate"`
+	OnTime         int            `json:"on_time"`
+	Late           int            `json:"late"`
+	Score          scoreSummary   `json:"score"`
+	Histogram      []histogramBin `json:"histogram"`
+}
+
+type parameterStatistic struct {
+	ID         int64          `json:"id"`
+	Type       string         `json:"type"`
+	Percentage float32        `json:"percentage"`
+	Score      scoreSummary   `json:"score"`
+	Histogram  []histogramBin `json:"histogram"`
+}
+
+type parameterSummary struct {
+	Type  string       `json:"type"`
+	Score scoreSummary `json:"score"`
+}
+
+// classStatistic is a class of the same course on the same semester, it only contains aggregates
+type classStatistic struct {
+	ScheduleID     int64              `json:"schedule_id"`
+	Class          string             `json:"class"`
+	Enrolled       int                `json:"enrolled"`
+	SubmissionRate float64            `json:"submission_rate"`
+	Total          scoreSummary       `json:"total"`
+	Parameters     []parameterSummary `json:"grade_parameters"`
+}
+
+type statisticResponse struct {
+	ScheduleID  int64                 `json:"schedule_id"`
+	Bins        int                   `json:"bins"`
+	Enrolled    int                   `json:"enrolled"`
+	Assignments []assignmentStatistic `json:"assignments"`
+	Parameters  []parameterStatistic  `json:"grade_parameters"`
+	Total       scoreSummary          `json:"total"`
+	Histogram   []histogramBin        `json:"histogram"`
+	Classes     []classStatistic      `json:"classes"`
+}
+
+type autograderTestParams struct {
+	scheduleID     string
+	assignmentID   string
+	name           string
+	input          string
+	expectedOutput string
+	timeout        string
+	points         string
+}
+
+type autograderTestArgs struct {
+	scheduleID     int64
+	assignmentID   int64
+	name           string
+	input          string
+	expectedOutput string
+	timeout        int64
+	points         float64
+}
+
+type deleteAutograderTestParams struct {
+	scheduleID   string
+	assignmentID string
+	testID       string
+}
+
+type deleteAutograderTestArgs struct {
+	scheduleID   int64
+	assignmentID int64
+	testID       int64
+}
+
+type confirmAutograderParams struct {
+	scheduleID    string
+	assignmentID  string
+	identityCodes string
+}
+
+type confirmAutograderArgs struct {
+	scheduleID    int64
+	assignmentID  int64
+	identityCodes []int64
+}
+
+type autograderTestResponse struct {
+	ID             int64   `json:"id"`
+	Name           string  `json:"name"`
+	Input          string  `json:"input"`
+	ExpectedOutput string  `json:"expected_output"`
+	Timeout        int64   `json:"timeout"`
+	Points         float64 `json:"points"`
+}
+
+// autograderTestResult is the result of a submission on a test, it is saved as json on the autograder result.
+// Output and Error are the beginning of stdout and stderr of the program
+type autograderTestResult struct {
+	TestID   int64   `json:"test_id"`
+	Name     string  `json:"name"`
+	Status   string  `json:"status"`
+	IsPassed bool    `json:"is_passed"`
+	Points   float64 `json:"points"`
+	Output   string  `json:"output"`
+	Error    string  `json:"error"`
+	Duration int64   `json:"duration"`
+}
+
+type autograderResultResponse struct {
+	IdentityCode   int64                  `json:"identity_code"`
+	Name           string                 `json:"name"`
+	Status         string                 `json:"status"`
+	Language       string                 `json:"language"`
+	CompileOutput  string                 `json:"compile_output"`
+	Passed         int                    `json:"passed"`
+	Total          int                    `json:"total"`
+	SuggestedScore string                 `json:"suggested_score"`
+	Score          string                 `json:"score"`
+	IsConfirmed    bool                   `json:"is_confirmed"`
+	ConfirmedAt    string                 `json:"confirmed_at"`
+	Tests          []autograderTestResult `json:"tests"`
+}
+
+type autograderRunResponse struct {
+	ID         int64                      `json:"id"`
+	Status     string                     `json:"status"`
+	Message    string                     `json:"message"`
+	CreatedBy  string                     `json:"created_by"`
+	CreatedAt  string                     `json:"created_at"`
+	FinishedAt string                     `json:"finished_at"`
+	Results    []autograderResultResponse `json:"results"`
+}
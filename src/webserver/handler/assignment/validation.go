@@ -2,16 +2,22 @@ package assignment
 
 import (
 	"database/sql"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"html"
+	"io"
+	"math"
 	"strconv"
 	"strings"
 	"time"
 
 	asg "github.com/asepnur/meiko_course/src/module/assignment"
 	fl "github.com/asepnur/meiko_course/src/module/file"
+	rb "github.com/asepnur/meiko_course/src/module/rubric"
 	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/asepnur/meiko_course/src/util/spreadsheet"
+	"github.com/go-sql-driver/mysql"
 )
 
 func (params getParams) validate() (getArgs, error) {
@@ -113,6 +119,17 @@ func (params createParams) validate() (createArgs, error) {
 		allowedTypesFile: params.allowedTypesFile,
 		maxSizeFile:      params.maxSizeFile,
 		maxFile:          params.maxFile,
+		latePolicy:       params.latePolicy,
+		gracePeriod:      params.gracePeriod,
+		latePenalty:      params.latePenalty,
+		closeDate:        params.closeDate,
+		groupMode:        params.groupMode,
+		publishAt:        helper.Trim(params.publishAt),
+		unpublishAt:      helper.Trim(params.unpublishAt),
+		peerReviewers:    helper.Trim(params.peerReviewers),
+		peerReviewClose:  helper.Trim(params.peerReviewClose),
+		peerWeight:       helper.Trim(params.peerWeight),
+		anonymous:        helper.Trim(params.anonymous),
 	}
 	var filesID []string
 	if len(params.filesID) > 0 {
@@ -186,7 +203,7 @@ func (params createParams) validate() (createArgs, error) {
 				}
 			}
 			if count == 0 {
-				return args, fmt.Errorf(fmt.Sprintf("%s Denied type", val))
+				return args, fmt.Errorf("%s Denied type", val)
 			}
 		}
 		if helper.IsEmpty(params.maxFile) {
@@ -207,6 +224,36 @@ func (params createParams) validate() (createArgs, error) {
 	if helper.IsEmpty(params.dueDate) {
 		return args, fmt.Errorf("Due date can not be empty")
 	}
+	dueDate, err := time.Parse(`2006-01-02 15:04:05`, params.dueDate)
+	if err != nil {
+		return args, fmt.Errorf("Invalid due date")
+	}
+	late, err := validateLatePolicy(params.latePolicy, params.gracePeriod, params.latePenalty, params.closeDate, dueDate)
+	if err != nil {
+		return args, err
+	}
+	groupMode, err := validateGroupMode(params.groupMode)
+	if err != nil {
+		return args, err
+	}
+	publish, err := validatePublish(params.publishAt, params.unpublishAt, dueDate)
+	if err != nil {
+		return args, err
+	}
+	deadline := asg.Assignment{
+		DueDate:     dueDate,
+		LatePolicy:  late.policy,
+		GracePeriod: late.gracePeriod,
+		CloseDate:   late.closeDate,
+	}.Deadline()
+	peer, err := validatePeerReview(params.peerReviewers, params.peerReviewClose, params.peerWeight, deadline, int8(status), groupMode)
+	if err != nil {
+		return args, err
+	}
+	anonymous, err := validateAnonymous(params.anonymous)
+	if err != nil {
+		return args, err
+	}
 
 	return createArgs{
 		filesID:          filesID,
@@ -218,6 +265,11 @@ func (params createParams) validate() (createArgs, error) {
 		maxSizeFile:      size,
 		allowedTypesFile: allowedTypes,
 		maxFile:          maxFile,
+		late:             late,
+		groupMode:        groupMode,
+		publish:          publish,
+		peer:             peer,
+		anonymous:        anonymous,
 	}, nil
 }
 
@@ -234,6 +286,17 @@ func (params updateParams) validate() (updateArgs, error) {
 		allowedTypesFile: params.allowedTypesFile,
 		maxSizeFile:      params.maxSizeFile,
 		maxFile:          params.maxFile,
+		latePolicy:       params.latePolicy,
+		gracePeriod:      params.gracePeriod,
+		latePenalty:      params.latePenalty,
+		closeDate:        params.closeDate,
+		groupMode:        params.groupMode,
+		publishAt:        helper.Trim(params.publishAt),
+		unpublishAt:      helper.Trim(params.unpublishAt),
+		peerReviewers:    helper.Trim(params.peerReviewers),
+		peerReviewClose:  helper.Trim(params.peerReviewClose),
+		peerWeight:       helper.Trim(params.peerWeight),
+		anonymous:        helper.Trim(params.anonymous),
 	}
 	if helper.IsEmpty(params.ID) {
 		return args, fmt.Errorf("ID can not be empty")
@@ -314,7 +377,7 @@ func (params updateParams) validate() (updateArgs, error) {
 				}
 			}
 			if count == 0 {
-				return args, fmt.Errorf(fmt.Sprintf("%s Denied type", val))
+				return args, fmt.Errorf("%s Denied type", val)
 			}
 		}
 		if helper.IsEmpty(params.maxFile) {
@@ -334,7 +397,33 @@ func (params updateParams) validate() (updateArgs, error) {
 	layout := `2006-01-02 15:04:05`
 	dueDate, err := time.Parse(layout, params.dueDate)
 	if err != nil {
-		return args, fmt.Errorf(err.Error())
+		return args, err
+	}
+	late, err := validateLatePolicy(params.latePolicy, params.gracePeriod, params.latePenalty, params.closeDate, dueDate)
+	if err != nil {
+		return args, err
+	}
+	groupMode, err := validateGroupMode(params.groupMode)
+	if err != nil {
+		return args, err
+	}
+	publish, err := validatePublish(params.publishAt, params.unpublishAt, dueDate)
+	if err != nil {
+		return args, err
+	}
+	deadline := asg.Assignment{
+		DueDate:     dueDate,
+		LatePolicy:  late.policy,
+		GracePeriod: late.gracePeriod,
+		CloseDate:   late.closeDate,
+	}.Deadline()
+	peer, err := validatePeerReview(params.peerReviewers, params.peerReviewClose, params.peerWeight, deadline, int8(status), groupMode)
+	if err != nil {
+		return args, err
+	}
+	anonymous, err := validateAnonymous(params.anonymous)
+	if err != nil {
+		return args, err
 	}
 
 	return updateArgs{
@@ -348,6 +437,11 @@ func (params updateParams) validate() (updateArgs, error) {
 		maxSizeFile:      size,
 		allowedTypesFile: allowedTypes,
 		maxFile:          maxFile,
+		late:             late,
+		groupMode:        groupMode,
+		publish:          publish,
+		peer:             peer,
+		anonymous:        anonymous,
 	}, nil
 }
 
@@ -594,7 +688,7 @@ func (params updateScoreParams) validate() (updateScoreArgs, error) {
 	}
 	scheduleID, err := strconv.ParseInt(params.ScheduleID, 10, 64)
 	if err != nil {
-		return args, err
+		return args, fmt.Errorf("Invalid schedule ID")
 	}
 	// Assignment ID validation
 	if helper.IsEmpty(params.AssignmentID) {
@@ -602,29 +696,31 @@ func (params updateScoreParams) validate() (updateScoreArgs, error) {
 	}
 	assignmentID, err := strconv.ParseInt(params.AssignmentID, 10, 64)
 	if err != nil {
-		return args, err
-	}
-	// Users ID validation
-	if helper.IsEmpty(params.UserID) {
-		return args, fmt.Errorf("User ID can not be empty")
+		return args, fmt.Errorf("Invalid assignment ID")
 	}
-	userID, err := strconv.ParseInt(params.UserID, 10, 64)
-	if err != nil {
-		return args, err
+	// Identity code validation
+	if helper.IsEmpty(params.IdentityCode) {
+		return args, fmt.Errorf("Identity code can not be empty")
 	}
 	// Score Validation
 	if helper.IsEmpty(params.Score) {
 		return args, fmt.Errorf("Score can not be empty")
 	}
-	score, err := strconv.ParseFloat(params.Score, 64)
+	reason, err := validateScoreReason(params.Reason)
 	if err != nil {
 		return args, err
 	}
 	return updateScoreArgs{
 		ScheduleID:   scheduleID,
 		AssignmentID: assignmentID,
-		UserID:       userID,
-		Score:        float32(score),
+		Row: scoreRow{
+			Line:         1,
+			IdentityCode: params.IdentityCode,
+			Score:        params.Score,
+			Feedback:     params.Feedback,
+			HasFeedback:  true,
+		},
+		Reason: reason,
 	}, nil
 }
 
@@ -654,11 +750,6 @@ func (params detailAssignmentParams) validate() (detailAssignmentArgs, error) {
 
 func (params createScoreParams) validate() (createScoreArgs, error) {
 	args := createScoreArgs{}
-	params = createScoreParams{
-		ScheduleID:   params.ScheduleID,
-		AssignmentID: params.AssignmentID,
-		Users:        params.Users,
-	}
 
 	//Schedule ID validation
 	if helper.IsEmpty(params.ScheduleID) {
@@ -667,7 +758,7 @@ func (params createScoreParams) validate() (createScoreArgs, error) {
 
 	scheduleID, err := strconv.ParseInt(params.ScheduleID, 10, 64)
 	if err != nil {
-		return args, err
+		return args, fmt.Errorf("Invalid schedule ID")
 	}
 
 	// Assignment ID validation
@@ -676,31 +767,167 @@ func (params createScoreParams) validate() (createScoreArgs, error) {
 	}
 	assignmentID, err := strconv.ParseInt(params.AssignmentID, 10, 64)
 	if err != nil {
-		return args, err
+		return args, fmt.Errorf("Invalid assignment ID")
 	}
+
 	// Users validation
-	var users []int64
-	var score []float32
-	if !helper.IsEmpty(params.Users) {
-		var std []student
-		err := json.Unmarshal([]byte(params.Users), &std)
+	if helper.IsEmpty(params.Users) {
+		return args, fmt.Errorf("Users can not be empty")
+	}
+	var std []student
+	err = json.Unmarshal([]byte(params.Users), &std)
+	if err != nil {
+		return args, fmt.Errorf("Invalid users format")
+	}
+	if len(std) < 1 {
+		return args, fmt.Errorf("Users can not be empty")
+	}
+
+	var rows []scoreRow
+	for i, val := range std {
+		rows = append(rows, scoreRow{
+			Line:         i + 1,
+			IdentityCode: strconv.FormatInt(val.IdentityCode, 10),
+			Score:        strconv.FormatFloat(val.Score, 'f', -1, 64),
+			Feedback:     val.Feedback,
+			HasFeedback:  true,
+		})
+	}
+
+	reason, err := validateScoreReason(params.Reason)
+	if err != nil {
+		return args, err
+	}
+
+	return createScoreArgs{
+		ScheduleID:   scheduleID,
+		AssignmentID: assignmentID,
+		Rows:         rows,
+		Reason:       reason,
+	}, nil
+
+}
 
+func (params uploadScoreParams) validate() (uploadScoreArgs, error) {
+	var args uploadScoreArgs
+
+	scheduleID, err := strconv.ParseInt(params.ScheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule ID")
+	}
+
+	assignmentID, err := strconv.ParseInt(params.AssignmentID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid assignment ID")
+	}
+
+	var isPreview bool
+	if !helper.IsEmpty(params.IsPreview) {
+		isPreview, err = strconv.ParseBool(params.IsPreview)
 		if err != nil {
-			return args, err
-		}
-		for _, val := range std {
-			users = append(users, val.IdentityCode)
-			score = append(score, val.Score)
+			return args, fmt.Errorf("Invalid preview")
 		}
 	}
 
-	return createScoreArgs{
+	reason, err := validateScoreReason(params.Reason)
+	if err != nil {
+		return args, err
+	}
+
+	return uploadScoreArgs{
 		ScheduleID:   scheduleID,
 		AssignmentID: assignmentID,
-		IdentityCode: users,
-		Score:        score,
+		IsPreview:    isPreview,
+		Reason:       reason,
 	}, nil
+}
+
+// validateScoreReason checks the optional reason of a score change which is kept on the score log
+func validateScoreReason(reason string) (sql.NullString, error) {
+	var result sql.NullString
+	reason = html.EscapeString(helper.Trim(reason))
+	if len(reason) > asg.MaxReason {
+		return result, fmt.Errorf("Reason maximum consist of %d character", asg.MaxReason)
+	}
+	if !helper.IsEmpty(reason) {
+		result = sql.NullString{Valid: true, String: reason}
+	}
+	return result, nil
+}
+
+// validateScoreCSV reads score rows with identity_code, score and optional feedback column,
+// the first line is skipped when it is a header
+func validateScoreCSV(r io.Reader) ([]scoreRow, error) {
+	var rows []scoreRow
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return rows, fmt.Errorf("Invalid CSV file")
+	}
+
+	for i, val := range records {
+		if len(val) < 1 || (len(val) == 1 && helper.IsEmpty(val[0])) {
+			continue
+		}
+		if i == 0 {
+			if _, err := strconv.ParseInt(helper.Trim(val[0]), 10, 64); err != nil {
+				continue
+			}
+		}
+
+		row := scoreRow{
+			Line:         i + 1,
+			IdentityCode: helper.Trim(val[0]),
+		}
+		if len(val) > 1 {
+			row.Score = helper.Trim(val[1])
+		}
+		if len(val) > 2 {
+			row.Feedback = val[2]
+			row.HasFeedback = true
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) < 1 {
+		return rows, fmt.Errorf("CSV file does not contain any score")
+	}
+	return rows, nil
+}
+
+// validateScoreRow checks the format of a single score row
+func validateScoreRow(row scoreRow) (int64, float32, sql.NullString, error) {
+	var feedback sql.NullString
+
+	identityCode, err := strconv.ParseInt(row.IdentityCode, 10, 64)
+	if err != nil {
+		return 0, 0, feedback, fmt.Errorf("Invalid identity code")
+	}
+
+	if helper.IsEmpty(row.Score) {
+		return 0, 0, feedback, fmt.Errorf("Score can not be empty")
+	}
+	score, err := strconv.ParseFloat(row.Score, 32)
+	if err != nil {
+		return 0, 0, feedback, fmt.Errorf("Invalid score")
+	}
+	if math.IsNaN(score) || score < asg.MinScore || score > asg.MaxScore {
+		return 0, 0, feedback, fmt.Errorf("Score must be between %d and %d", asg.MinScore, asg.MaxScore)
+	}
+
+	// empty feedback clears the current one
+	fb := html.EscapeString(helper.Trim(row.Feedback))
+	if len(fb) > asg.MaxFeedback {
+		return 0, 0, feedback, fmt.Errorf("Feedback maximum consist of %d character", asg.MaxFeedback)
+	}
+	if row.HasFeedback {
+		feedback = sql.NullString{Valid: true, String: fb}
+	}
 
+	return identityCode, float32(score), feedback, nil
 }
 
 func (params scoreParams) validate() (scoreArgs, error) {
@@ -719,3 +946,989 @@ func (params scoreParams) validate() (scoreArgs, error) {
 		ScheduleID: id,
 	}, nil
 }
+
+func validateLatePolicy(policy, gracePeriod, penalty, closeDate string, dueDate time.Time) (latePolicy, error) {
+	late := latePolicy{
+		policy: asg.LatePolicyCutoff,
+	}
+	policy = strings.ToLower(helper.Trim(policy))
+	if helper.IsEmpty(policy) {
+		return late, nil
+	}
+
+	isValid := false
+	for key, val := range asg.LatePolicies {
+		if val == policy {
+			late.policy = key
+			isValid = true
+			break
+		}
+	}
+	if !isValid {
+		return late, fmt.Errorf("Late policy must be cutoff, grace or penalty")
+	}
+
+	switch late.policy {
+	case asg.LatePolicyGrace:
+		if helper.IsEmpty(gracePeriod) {
+			return late, fmt.Errorf("Grace period can not be empty")
+		}
+		grace, err := strconv.ParseInt(gracePeriod, 10, 64)
+		if err != nil {
+			return late, fmt.Errorf("Grace period must be a number of minutes")
+		}
+		if grace < 1 || grace > asg.MaxGracePeriod {
+			return late, fmt.Errorf("Grace period must be between 1 and %d minutes", asg.MaxGracePeriod)
+		}
+		late.gracePeriod = grace
+	case asg.LatePolicyPenalty:
+		if helper.IsEmpty(penalty) {
+			return late, fmt.Errorf("Late penalty can not be empty")
+		}
+		pct, err := strconv.ParseFloat(penalty, 64)
+		if err != nil || math.IsNaN(pct) {
+			return late, fmt.Errorf("Invalid late penalty")
+		}
+		if pct <= 0 || pct > asg.MaxLatePenalty {
+			return late, fmt.Errorf("Late penalty must be above 0 and not more than %d percent per day", asg.MaxLatePenalty)
+		}
+		late.penalty = pct
+
+		if helper.IsEmpty(closeDate) {
+			return late, fmt.Errorf("Close date can not be empty")
+		}
+		t, err := time.Parse(`2006-01-02 15:04:05`, closeDate)
+		if err != nil {
+			return late, fmt.Errorf("Invalid close date")
+		}
+		if !t.After(dueDate) {
+			return late, fmt.Errorf("Close date must be after due date")
+		}
+		late.closeDate = mysql.NullTime{Valid: true, Time: t}
+	}
+	return late, nil
+}
+
+func validatePublish(publishAt, unpublishAt string, dueDate time.Time) (publishWindow, error) {
+	var publish publishWindow
+	if !helper.IsEmpty(publishAt) {
+		t, err := time.Parse(`2006-01-02 15:04:05`, publishAt)
+		if err != nil {
+			return publish, fmt.Errorf("Invalid publish date")
+		}
+		if !t.Before(dueDate) {
+			return publish, fmt.Errorf("Publish date must be before due date")
+		}
+		publish.publishAt = mysql.NullTime{Valid: true, Time: t}
+	}
+	if !helper.IsEmpty(unpublishAt) {
+		t, err := time.Parse(`2006-01-02 15:04:05`, unpublishAt)
+		if err != nil {
+			return publish, fmt.Errorf("Invalid unpublish date")
+		}
+		if publish.publishAt.Valid && !t.After(publish.publishAt.Time) {
+			return publish, fmt.Errorf("Unpublish date must be after publish date")
+		}
+		publish.unpublishAt = mysql.NullTime{Valid: true, Time: t}
+	}
+	return publish, nil
+}
+
+func validatePeerReview(reviewers, closeDate, weight string, deadline time.Time, status, groupMode int8) (peerReview, error) {
+	var peer peerReview
+	if helper.IsEmpty(reviewers) {
+		return peer, nil
+	}
+	n, err := strconv.ParseInt(reviewers, 10, 8)
+	if err != nil || n < 0 || n > asg.MaxPeerReviewers {
+		return peer, fmt.Errorf("Peer reviewers must be between 0 and %d", asg.MaxPeerReviewers)
+	}
+	if n == 0 {
+		return peer, nil
+	}
+	if status != asg.StatusUploadRequired {
+		return peer, fmt.Errorf("Peer review requires the assignment to be uploaded")
+	}
+	if groupMode != asg.GroupModeNone {
+		return peer, fmt.Errorf("Peer review is only available on individual assignment")
+	}
+	peer.reviewers = int8(n)
+
+	if helper.IsEmpty(closeDate) {
+		return peer, fmt.Errorf("Peer review close date can not be empty")
+	}
+	t, err := time.Parse(`2006-01-02 15:04:05`, closeDate)
+	if err != nil {
+		return peer, fmt.Errorf("Invalid peer review close date")
+	}
+	if !t.After(deadline) {
+		return peer, fmt.Errorf("Peer review close date must be after the submission deadline")
+	}
+	peer.closeDate = mysql.NullTime{Valid: true, Time: t}
+
+	if !helper.IsEmpty(weight) {
+		w, err := strconv.ParseFloat(weight, 64)
+		if err != nil || math.IsNaN(w) || w < 0 || w > asg.MaxPeerWeight {
+			return peer, fmt.Errorf("Peer weight must be between 0 and %d percent", asg.MaxPeerWeight)
+		}
+		peer.weight = w
+	}
+	return peer, nil
+}
+
+// validateAnonymous validates the anonymous grading mode, empty means the assignment is not anonymous
+func validateAnonymous(anonymous string) (bool, error) {
+	if helper.IsEmpty(anonymous) {
+		return false, nil
+	}
+	isAnonymous, err := strconv.ParseBool(anonymous)
+	if err != nil {
+		return false, fmt.Errorf("Anonymous must be true or false")
+	}
+	return isAnonymous, nil
+}
+
+func (params extensionParams) validate() (extensionArgs, error) {
+	var args extensionArgs
+	params = extensionParams{
+		id:            params.id,
+		identityCodes: helper.Trim(params.identityCodes),
+		dueDate:       helper.Trim(params.dueDate),
+		closeDate:     helper.Trim(params.closeDate),
+		reason:        html.EscapeString(helper.Trim(params.reason)),
+	}
+
+	if helper.IsEmpty(params.id) {
+		return args, fmt.Errorf("ID can not be empty")
+	}
+	id, err := strconv.ParseInt(params.id, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Can not convert ID to int64")
+	}
+
+	if helper.IsEmpty(params.identityCodes) {
+		return args, fmt.Errorf("Identity code can not be empty")
+	}
+	var identityCodes []int64
+	for _, val := range strings.Split(params.identityCodes, "~") {
+		identityCode, err := strconv.ParseInt(helper.Trim(val), 10, 64)
+		if err != nil {
+			return args, fmt.Errorf("Invalid identity code %s", val)
+		}
+		if helper.Int64InSlice(identityCode, identityCodes) {
+			continue
+		}
+		identityCodes = append(identityCodes, identityCode)
+	}
+
+	if helper.IsEmpty(params.dueDate) {
+		return args, fmt.Errorf("Due date can not be empty")
+	}
+	dueDate, err := time.Parse(`2006-01-02 15:04:05`, params.dueDate)
+	if err != nil {
+		return args, fmt.Errorf("Invalid due date")
+	}
+
+	var closeDate mysql.NullTime
+	if !helper.IsEmpty(params.closeDate) {
+		t, err := time.Parse(`2006-01-02 15:04:05`, params.closeDate)
+		if err != nil {
+			return args, fmt.Errorf("Invalid close date")
+		}
+		if !t.After(dueDate) {
+			return args, fmt.Errorf("Close date must be after due date")
+		}
+		closeDate = mysql.NullTime{Valid: true, Time: t}
+	}
+
+	if helper.IsEmpty(params.reason) {
+		return args, fmt.Errorf("Reason can not be empty")
+	}
+	if len(params.reason) > asg.MaxReason {
+		return args, fmt.Errorf("Reason maximum consist of %d character", asg.MaxReason)
+	}
+
+	return extensionArgs{
+		id:            id,
+		identityCodes: identityCodes,
+		dueDate:       dueDate,
+		closeDate:     closeDate,
+		reason:        params.reason,
+	}, nil
+}
+
+func (params deleteExtensionParams) validate() (deleteExtensionArgs, error) {
+	var args deleteExtensionArgs
+	if helper.IsEmpty(params.id) {
+		return args, fmt.Errorf("ID can not be empty")
+	}
+	id, err := strconv.ParseInt(params.id, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Can not convert ID to int64")
+	}
+
+	if helper.IsEmpty(params.identityCode) {
+		return args, fmt.Errorf("Identity code can not be empty")
+	}
+	identityCode, err := strconv.ParseInt(params.identityCode, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid identity code")
+	}
+
+	return deleteExtensionArgs{
+		id:           id,
+		identityCode: identityCode,
+	}, nil
+}
+
+func validateGroupMode(mode string) (int8, error) {
+	mode = strings.ToLower(helper.Trim(mode))
+	if helper.IsEmpty(mode) {
+		return asg.GroupModeNone, nil
+	}
+	for key, val := range asg.GroupModes {
+		if val == mode {
+			return key, nil
+		}
+	}
+	return asg.GroupModeNone, fmt.Errorf("Group mode must be individual, assignment or schedule")
+}
+
+func (params groupScoreParams) validate() (groupScoreArgs, error) {
+	var args groupScoreArgs
+	if helper.IsEmpty(params.ScheduleID) {
+		return args, fmt.Errorf("Schedule ID can not be empty")
+	}
+	scheduleID, err := strconv.ParseInt(params.ScheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule ID")
+	}
+
+	if helper.IsEmpty(params.AssignmentID) {
+		return args, fmt.Errorf("Assignment ID can not be empty")
+	}
+	assignmentID, err := strconv.ParseInt(params.AssignmentID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid assignment ID")
+	}
+
+	if helper.IsEmpty(params.GroupID) {
+		return args, fmt.Errorf("Group ID can not be empty")
+	}
+	groupID, err := strconv.ParseInt(params.GroupID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid group ID")
+	}
+
+	if helper.IsEmpty(params.Score) {
+		return args, fmt.Errorf("Score can not be empty")
+	}
+	score, err := strconv.ParseFloat(params.Score, 32)
+	if err != nil {
+		return args, fmt.Errorf("Invalid score")
+	}
+	if math.IsNaN(score) || score < asg.MinScore || score > asg.MaxScore {
+		return args, fmt.Errorf("Score must be between %d and %d", asg.MinScore, asg.MaxScore)
+	}
+
+	// empty feedback clears the current one
+	fb := html.EscapeString(helper.Trim(params.Feedback))
+	if len(fb) > asg.MaxFeedback {
+		return args, fmt.Errorf("Feedback maximum consist of %d character", asg.MaxFeedback)
+	}
+	feedback := sql.NullString{Valid: true, String: fb}
+
+	adjustments := []adjustment{}
+	if !helper.IsEmpty(params.Adjustments) {
+		err = json.Unmarshal([]byte(params.Adjustments), &adjustments)
+		if err != nil {
+			return args, fmt.Errorf("Invalid adjustments format")
+		}
+	}
+	var identityCodes []int64
+	for _, val := range adjustments {
+		if math.IsNaN(val.Adjustment) || math.Abs(val.Adjustment) > asg.MaxAdjustment {
+			return args, fmt.Errorf("Adjustment must be between -%d and %d", asg.MaxAdjustment, asg.MaxAdjustment)
+		}
+		if helper.Int64InSlice(val.IdentityCode, identityCodes) {
+			return args, fmt.Errorf("Duplicate adjustment for identity code %d", val.IdentityCode)
+		}
+		identityCodes = append(identityCodes, val.IdentityCode)
+	}
+
+	return groupScoreArgs{
+		ScheduleID:   scheduleID,
+		AssignmentID: assignmentID,
+		GroupID:      groupID,
+		Score:        float32(score),
+		Feedback:     feedback,
+		Adjustments:  adjustments,
+	}, nil
+}
+
+func (params studentParams) validate() (studentArgs, error) {
+	var args studentArgs
+	if helper.IsEmpty(params.scheduleID) {
+		return args, fmt.Errorf("Schedule ID can not be empty")
+	}
+	scheduleID, err := strconv.ParseInt(params.scheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule ID")
+	}
+
+	if helper.IsEmpty(params.assignmentID) {
+		return args, fmt.Errorf("Assignment ID can not be empty")
+	}
+	assignmentID, err := strconv.ParseInt(params.assignmentID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid assignment ID")
+	}
+
+	if helper.IsEmpty(params.identityCode) {
+		return args, fmt.Errorf("Identity code can not be empty")
+	}
+	identityCode, err := strconv.ParseInt(params.identityCode, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid identity code")
+	}
+
+	return studentArgs{
+		scheduleID:   scheduleID,
+		assignmentID: assignmentID,
+		identityCode: identityCode,
+	}, nil
+}
+
+func (params pinVersionParams) validate() (pinVersionArgs, error) {
+	var args pinVersionArgs
+	v, err := studentParams{
+		scheduleID:   params.scheduleID,
+		assignmentID: params.assignmentID,
+		identityCode: params.identityCode,
+	}.validate()
+	if err != nil {
+		return args, err
+	}
+
+	// empty version unpins the submission so the latest version is graded
+	var version sql.NullInt64
+	params.version = helper.Trim(params.version)
+	if !helper.IsEmpty(params.version) {
+		val, err := strconv.ParseInt(params.version, 10, 64)
+		if err != nil || val < 1 {
+			return args, fmt.Errorf("Invalid version")
+		}
+		version = sql.NullInt64{Valid: true, Int64: val}
+	}
+
+	return pinVersionArgs{
+		scheduleID:   v.scheduleID,
+		assignmentID: v.assignmentID,
+		identityCode: v.identityCode,
+		version:      version,
+	}, nil
+}
+
+func (params attachRubricParams) validate() (attachRubricArgs, error) {
+	var args attachRubricArgs
+	scheduleID, err := strconv.ParseInt(params.scheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule ID")
+	}
+
+	assignmentID, err := strconv.ParseInt(params.assignmentID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid assignment ID")
+	}
+
+	// empty rubric id detaches the rubric
+	var rubricID sql.NullInt64
+	params.rubricID = helper.Trim(params.rubricID)
+	if !helper.IsEmpty(params.rubricID) {
+		id, err := strconv.ParseInt(params.rubricID, 10, 64)
+		if err != nil {
+			return args, fmt.Errorf("Invalid rubric ID")
+		}
+		rubricID = sql.NullInt64{Valid: true, Int64: id}
+	}
+
+	return attachRubricArgs{
+		scheduleID:   scheduleID,
+		assignmentID: assignmentID,
+		rubricID:     rubricID,
+	}, nil
+}
+
+func (params fillRubricParams) validate() (fillRubricArgs, error) {
+	var args fillRubricArgs
+	student, err := studentParams{
+		scheduleID:   params.scheduleID,
+		assignmentID: params.assignmentID,
+		identityCode: params.identityCode,
+	}.validate()
+	if err != nil {
+		return args, err
+	}
+
+	levels, comments, err := validateRubricLevels(params.levels)
+	if err != nil {
+		return args, err
+	}
+
+	// empty feedback clears the current one
+	fb := html.EscapeString(helper.Trim(params.feedback))
+	if len(fb) > asg.MaxFeedback {
+		return args, fmt.Errorf("Feedback maximum consist of %d character", asg.MaxFeedback)
+	}
+	feedback := sql.NullString{Valid: true, String: fb}
+
+	return fillRubricArgs{
+		scheduleID:   student.scheduleID,
+		assignmentID: student.assignmentID,
+		identityCode: student.identityCode,
+		levels:       levels,
+		comments:     comments,
+		feedback:     feedback,
+	}, nil
+}
+
+// validateRubricLevels parses the chosen level and comment of every criterion
+func validateRubricLevels(param string) (map[int64]int64, map[int64]string, error) {
+	levels := map[int64]int64{}
+	comments := map[int64]string{}
+	if helper.IsEmpty(param) {
+		return levels, comments, fmt.Errorf("Levels can not be empty")
+	}
+	var reqs []rubricLevel
+	err := json.Unmarshal([]byte(param), &reqs)
+	if err != nil {
+		return levels, comments, fmt.Errorf("Invalid levels format")
+	}
+
+	for _, val := range reqs {
+		if _, ok := levels[val.CriterionID]; ok {
+			return levels, comments, fmt.Errorf("Duplicate level for criterion %d", val.CriterionID)
+		}
+		comment := html.EscapeString(helper.Trim(val.Comment))
+		if len(comment) > rb.MaxComment {
+			return levels, comments, fmt.Errorf("Comment maximum consist of %d character", rb.MaxComment)
+		}
+		levels[val.CriterionID] = val.LevelID
+		comments[val.CriterionID] = comment
+	}
+	return levels, comments, nil
+}
+
+func (params feedbackParams) validate() (feedbackArgs, error) {
+	var args feedbackArgs
+	student, err := studentParams{
+		scheduleID:   params.scheduleID,
+		assignmentID: params.assignmentID,
+		identityCode: params.identityCode,
+	}.validate()
+	if err != nil {
+		return args, err
+	}
+
+	message, err := validateFeedbackMessage(params.message)
+	if err != nil {
+		return args, err
+	}
+
+	var fileID []string
+	params.fileID = helper.Trim(params.fileID)
+	if !helper.IsEmpty(params.fileID) {
+		for _, val := range strings.Split(params.fileID, "~") {
+			if !helper.IsValidFileID(val) {
+				return args, fmt.Errorf("Invalid file ID")
+			}
+			fileID = append(fileID, val)
+		}
+	}
+
+	if !message.Valid && len(fileID) < 1 {
+		return args, fmt.Errorf("Message or file must be filled")
+	}
+
+	return feedbackArgs{
+		scheduleID:   student.scheduleID,
+		assignmentID: student.assignmentID,
+		identityCode: student.identityCode,
+		message:      message,
+		fileID:       fileID,
+	}, nil
+}
+
+func (params replyFeedbackParams) validate() (replyFeedbackArgs, error) {
+	var args replyFeedbackArgs
+	id, err := strconv.ParseInt(params.id, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid assignment ID")
+	}
+
+	message, err := validateFeedbackMessage(params.message)
+	if err != nil {
+		return args, err
+	}
+	if !message.Valid {
+		return args, fmt.Errorf("Message can not be empty")
+	}
+
+	return replyFeedbackArgs{
+		id:      id,
+		message: message,
+	}, nil
+}
+
+func validateFeedbackMessage(message string) (sql.NullString, error) {
+	message = html.EscapeString(helper.Trim(message))
+	if helper.IsEmpty(message) {
+		return sql.NullString{}, nil
+	}
+	if len(message) > asg.MaxFeedback {
+		return sql.NullString{}, fmt.Errorf("Message maximum consist of %d character", asg.MaxFeedback)
+	}
+	return sql.NullString{Valid: true, String: message}, nil
+}
+
+func (params similarityPairParams) validate() (similarityPairArgs, error) {
+	var args similarityPairArgs
+	v, err := studentParams{
+		scheduleID:   params.scheduleID,
+		assignmentID: params.assignmentID,
+		identityCode: params.identityCode,
+	}.validate()
+	if err != nil {
+		return args, err
+	}
+
+	if helper.IsEmpty(params.otherIdentityCode) {
+		return args, fmt.Errorf("Other identity code can not be empty")
+	}
+	otherIdentityCode, err := strconv.ParseInt(params.otherIdentityCode, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid other identity code")
+	}
+	if otherIdentityCode == v.identityCode {
+		return args, fmt.Errorf("Identity code must be different")
+	}
+
+	return similarityPairArgs{
+		scheduleID:        v.scheduleID,
+		assignmentID:      v.assignmentID,
+		identityCode:      v.identityCode,
+		otherIdentityCode: otherIdentityCode,
+	}, nil
+}
+
+func (params gradebookParams) validate() (gradebookArgs, error) {
+	var args gradebookArgs
+
+	scheduleID, err := strconv.ParseInt(params.ScheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule ID")
+	}
+
+	format := strings.ToLower(helper.Trim(params.Format))
+	if helper.IsEmpty(format) {
+		format = "json"
+	}
+	if format != "json" && !spreadsheet.IsValidFormat(format) {
+		return args, fmt.Errorf("Format must be json, csv or xlsx")
+	}
+
+	return gradebookArgs{
+		ScheduleID: scheduleID,
+		Format:     format,
+	}, nil
+}
+
+func (params importGradebookParams) validate() (importGradebookArgs, error) {
+	var args importGradebookArgs
+
+	scheduleID, err := strconv.ParseInt(params.ScheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule ID")
+	}
+
+	_, ext, err := helper.ExtractExtension(params.FileName)
+	if err != nil || !spreadsheet.IsValidFormat(strings.ToLower(ext)) {
+		return args, fmt.Errorf("File must be a csv or xlsx file")
+	}
+
+	var isPreview bool
+	if !helper.IsEmpty(params.IsPreview) {
+		isPreview, err = strconv.ParseBool(params.IsPreview)
+		if err != nil {
+			return args, fmt.Errorf("Invalid preview")
+		}
+	}
+
+	reason, err := validateScoreReason(params.Reason)
+	if err != nil {
+		return args, err
+	}
+
+	return importGradebookArgs{
+		ScheduleID: scheduleID,
+		Format:     strings.ToLower(ext),
+		IsPreview:  isPreview,
+		Reason:     reason,
+	}, nil
+}
+
+// validateGradebookHeader finds the assessment columns of an imported gradebook by the assignment id
+// at the end of the header, e.g. "ASSIGNMENT Essay (#12)". Other columns are ignored
+func validateGradebookHeader(header []string) (map[int]int64, error) {
+	columns := map[int]int64{}
+	if len(header) < 1 || !strings.EqualFold(helper.Trim(header[0]), "Identity Code") {
+		return columns, fmt.Errorf("First column of the sheet must be Identity Code")
+	}
+
+	used := map[int64]bool{}
+	for i, val := range header {
+		match := gradebookHeader.FindStringSubmatch(helper.Trim(val))
+		if len(match) < 2 {
+			continue
+		}
+		id, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return columns, fmt.Errorf("Invalid column %s", val)
+		}
+		if used[id] {
+			return columns, fmt.Errorf("Column %s appears more than once", val)
+		}
+		used[id] = true
+		columns[i] = id
+	}
+
+	if len(columns) < 1 {
+		return columns, fmt.Errorf("Sheet does not contain any assessment column")
+	}
+	return columns, nil
+}
+
+func (params scoreLogParams) validate() (scoreLogArgs, error) {
+	var args scoreLogArgs
+
+	scheduleID, err := strconv.ParseInt(params.ScheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule ID")
+	}
+
+	assignmentID, err := strconv.ParseInt(params.AssignmentID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid assignment ID")
+	}
+
+	// identity code is optional, zero returns the log of every student
+	var identityCode int64
+	if !helper.IsEmpty(params.IdentityCode) {
+		identityCode, err = strconv.ParseInt(params.IdentityCode, 10, 64)
+		if err != nil || identityCode < 1 {
+			return args, fmt.Errorf("Invalid identity code")
+		}
+	}
+
+	return scoreLogArgs{
+		ScheduleID:   scheduleID,
+		AssignmentID: assignmentID,
+		IdentityCode: identityCode,
+	}, nil
+}
+
+func (params studentScoreLogParams) validate() (studentScoreLogArgs, error) {
+	var args studentScoreLogArgs
+
+	scheduleID, err := strconv.ParseInt(params.ScheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule ID")
+	}
+
+	identityCode, err := strconv.ParseInt(params.IdentityCode, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid identity code")
+	}
+
+	return studentScoreLogArgs{
+		ScheduleID:   scheduleID,
+		IdentityCode: identityCode,
+	}, nil
+}
+
+func (params createRegradeParams) validate() (createRegradeArgs, error) {
+	var args createRegradeArgs
+	id, err := strconv.ParseInt(params.id, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid assignment ID")
+	}
+
+	reason := html.EscapeString(helper.Trim(params.reason))
+	if helper.IsEmpty(reason) {
+		return args, fmt.Errorf("Reason can not be empty")
+	}
+	if len(reason) > asg.MaxReason {
+		return args, fmt.Errorf("Reason maximum consist of %d character", asg.MaxReason)
+	}
+
+	// attachment is optional, only a single file is accepted
+	fileID := helper.Trim(params.fileID)
+	if !helper.IsEmpty(fileID) && !helper.IsValidFileID(fileID) {
+		return args, fmt.Errorf("Invalid file ID")
+	}
+
+	return createRegradeArgs{
+		id:     id,
+		reason: reason,
+		fileID: fileID,
+	}, nil
+}
+
+func (params resolveRegradeParams) validate() (resolveRegradeArgs, error) {
+	var args resolveRegradeArgs
+
+	scheduleID, err := strconv.ParseInt(params.ScheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule ID")
+	}
+
+	regradeID, err := strconv.ParseInt(params.RegradeID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid regrade ID")
+	}
+
+	response := html.EscapeString(helper.Trim(params.Response))
+	if len(response) > asg.MaxReason {
+		return args, fmt.Errorf("Response maximum consist of %d character", asg.MaxReason)
+	}
+
+	var score float64
+	action := strings.ToLower(helper.Trim(params.Action))
+	switch action {
+	case "accept":
+		if helper.IsEmpty(params.Score) {
+			return args, fmt.Errorf("Score can not be empty")
+		}
+		score, err = strconv.ParseFloat(params.Score, 32)
+		if err != nil {
+			return args, fmt.Errorf("Invalid score")
+		}
+		if math.IsNaN(score) || score < asg.MinScore || score > asg.MaxScore {
+			return args, fmt.Errorf("Score must be between %d and %d", asg.MinScore, asg.MaxScore)
+		}
+	case "reject":
+		if helper.IsEmpty(response) {
+			return args, fmt.Errorf("Response can not be empty when rejecting")
+		}
+	case "escalate":
+	default:
+		return args, fmt.Errorf("Action must be accept, reject or escalate")
+	}
+
+	return resolveRegradeArgs{
+		ScheduleID: scheduleID,
+		RegradeID:  regradeID,
+		Action:     action,
+		Score:      float32(score),
+		Response:   sql.NullString{Valid: !helper.IsEmpty(response), String: response},
+	}, nil
+}
+
+func (params submitPeerReviewParams) validate() (submitPeerReviewArgs, error) {
+	var args submitPeerReviewArgs
+	id, err := strconv.ParseInt(params.id, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid assignment ID")
+	}
+
+	reviewID, err := strconv.ParseInt(params.reviewID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid review ID")
+	}
+
+	levels, comments, err := validateRubricLevels(params.levels)
+	if err != nil {
+		return args, err
+	}
+
+	var comment sql.NullString
+	c := html.EscapeString(helper.Trim(params.comment))
+	if len(c) > rb.MaxComment {
+		return args, fmt.Errorf("Comment maximum consist of %d character", rb.MaxComment)
+	}
+	if !helper.IsEmpty(c) {
+		comment = sql.NullString{Valid: true, String: c}
+	}
+
+	return submitPeerReviewArgs{
+		id:       id,
+		reviewID: reviewID,
+		levels:   levels,
+		comments: comments,
+		comment:  comment,
+	}, nil
+}
+
+func (params moderatePeerReviewParams) validate() (moderatePeerReviewArgs, error) {
+	var args moderatePeerReviewArgs
+	assignment, err := detailAssignmentParams{
+		ScheduleID:   params.scheduleID,
+		AssignmentID: params.assignmentID,
+	}.validate()
+	if err != nil {
+		return args, err
+	}
+
+	reviewID, err := strconv.ParseInt(params.reviewID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid review ID")
+	}
+
+	var from, status int8
+	switch strings.ToLower(helper.Trim(params.action)) {
+	case "exclude":
+		from, status = asg.PeerSubmitted, asg.PeerExcluded
+	case "include":
+		from, status = asg.PeerExcluded, asg.PeerSubmitted
+	default:
+		return args, fmt.Errorf("Action must be exclude or include")
+	}
+
+	return moderatePeerReviewArgs{
+		scheduleID:   assignment.ScheduleID,
+		assignmentID: assignment.AssignmentID,
+		reviewID:     reviewID,
+		from:         from,
+		status:       status,
+	}, nil
+}
+
+func (params statisticParams) validate() (statisticArgs, error) {
+	var args statisticArgs
+
+	scheduleID, err := strconv.ParseInt(params.ScheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule ID")
+	}
+
+	bins := asg.DefaultHistogramBins
+	if !helper.IsEmpty(params.Bins) {
+		bins, err = strconv.Atoi(helper.Trim(params.Bins))
+		if err != nil || bins < 1 || bins > asg.MaxHistogramBins {
+			return args, fmt.Errorf("Bins must be between 1 and %d", asg.MaxHistogramBins)
+		}
+	}
+
+	return statisticArgs{
+		ScheduleID: scheduleID,
+		Bins:       bins,
+	}, nil
+}
+
+func (params autograderTestParams) validate() (autograderTestArgs, error) {
+	var args autograderTestArgs
+	assignment, err := detailAssignmentParams{
+		ScheduleID:   params.scheduleID,
+		AssignmentID: params.assignmentID,
+	}.validate()
+	if err != nil {
+		return args, err
+	}
+
+	name := html.EscapeString(helper.Trim(params.name))
+	if helper.IsEmpty(name) {
+		return args, fmt.Errorf("Name can not be empty")
+	}
+	if len(name) > asg.MaxAutograderTestName {
+		return args, fmt.Errorf("Name maximum consist of %d character", asg.MaxAutograderTestName)
+	}
+
+	// input and expected output are compared byte by byte with the program so they are not escaped
+	if len(params.input) > asg.MaxAutograderText {
+		return args, fmt.Errorf("Input maximum consist of %d character", asg.MaxAutograderText)
+	}
+	if len(params.expectedOutput) > asg.MaxAutograderText {
+		return args, fmt.Errorf("Expected output maximum consist of %d character", asg.MaxAutograderText)
+	}
+
+	if helper.IsEmpty(params.timeout) {
+		return args, fmt.Errorf("Timeout can not be empty")
+	}
+	timeout, err := strconv.ParseInt(helper.Trim(params.timeout), 10, 64)
+	if err != nil || timeout < 1 || timeout > asg.MaxAutograderTimeout {
+		return args, fmt.Errorf("Timeout must be between 1 and %d milliseconds", asg.MaxAutograderTimeout)
+	}
+
+	points := float64(1)
+	if !helper.IsEmpty(params.points) {
+		points, err = strconv.ParseFloat(helper.Trim(params.points), 64)
+		if err != nil || math.IsNaN(points) || points <= 0 || points > asg.MaxAutograderPoints {
+			return args, fmt.Errorf("Points must be more than 0 and at most %d", asg.MaxAutograderPoints)
+		}
+	}
+
+	return autograderTestArgs{
+		scheduleID:     assignment.ScheduleID,
+		assignmentID:   assignment.AssignmentID,
+		name:           name,
+		input:          params.input,
+		expectedOutput: params.expectedOutput,
+		timeout:        timeout,
+		points:         points,
+	}, nil
+}
+
+func (params deleteAutograderTestParams) validate() (deleteAutograderTestArgs, error) {
+	var args deleteAutograderTestArgs
+	assignment, err := detailAssignmentParams{
+		ScheduleID:   params.scheduleID,
+		AssignmentID: params.assignmentID,
+	}.validate()
+	if err != nil {
+		return args, err
+	}
+
+	testID, err := strconv.ParseInt(params.testID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid test ID")
+	}
+
+	return deleteAutograderTestArgs{
+		scheduleID:   assignment.ScheduleID,
+		assignmentID: assignment.AssignmentID,
+		testID:       testID,
+	}, nil
+}
+
+func (params confirmAutograderParams) validate() (confirmAutograderArgs, error) {
+	var args confirmAutograderArgs
+	assignment, err := detailAssignmentParams{
+		ScheduleID:   params.scheduleID,
+		AssignmentID: params.assignmentID,
+	}.validate()
+	if err != nil {
+		return args, err
+	}
+
+	identityCodes := helper.Trim(params.identityCodes)
+	if helper.IsEmpty(identityCodes) {
+		return args, fmt.Errorf("Identity codes can not be empty")
+	}
+	var codes []int64
+	for _, val := range strings.Split(identityCodes, "~") {
+		code, err := strconv.ParseInt(helper.Trim(val), 10, 64)
+		if err != nil {
+			return args, fmt.Errorf("Invalid identity code %s", val)
+		}
+		if helper.Int64InSlice(code, codes) {
+			return args, fmt.Errorf("Duplicate identity code %d", code)
+		}
+		codes = append(codes, code)
+	}
+
+	return confirmAutograderArgs{
+		scheduleID:    assignment.ScheduleID,
+		assignmentID:  assignment.AssignmentID,
+		identityCodes: codes,
+	}, nil
+}
@@ -4,11 +4,14 @@ import (
 	"database/sql"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
+	asg "github.com/asepnur/meiko_course/src/module/assignment"
 	"github.com/asepnur/meiko_course/src/module/bot"
 	cs "github.com/asepnur/meiko_course/src/module/course"
 	fl "github.com/asepnur/meiko_course/src/module/file"
+	"github.com/asepnur/meiko_course/src/module/grade"
 	"github.com/asepnur/meiko_course/src/util/helper"
 )
 
@@ -253,11 +256,35 @@ func handleGrade(text string, userID int64) ([]map[string]interface{}, error) {
 		return args, err
 	}
 
+	var schedulesID []int64
 	for _, val := range grades {
+		if !helper.Int64InSlice(val.ScheduleID, schedulesID) {
+			schedulesID = append(schedulesID, val.ScheduleID)
+		}
+	}
+
+	policies, err := grade.SelectPolicy(schedulesID)
+	if err != nil {
+		return args, err
+	}
+
+	for _, val := range grades {
+		assignmentID, err := strconv.ParseInt(val.AssignmentID, 10, 64)
+		if err != nil {
+			return args, err
+		}
+		assignment, err := asg.GetByID(assignmentID)
+		if err != nil {
+			return args, err
+		}
+		score := grade.Score{
+			Value:   float64(val.Score),
+			Penalty: assignment.Penalty(val.LateSeconds),
+		}
 		args = append(args, map[string]interface{}{
 			"url":         "/api/v1/assignment/" + val.AssignmentID,
 			"name":        val.Name,
-			"score":       fmt.Sprintf("%.3g", val.Score),
+			"score":       policies[val.ScheduleID].Format(score.Final()),
 			"scored_time": val.UpdatedAt.Unix(),
 			"course_name": val.CourseName,
 		})
@@ -1,6 +1,7 @@
 package course
 
 import (
+	"bytes"
 	"database/sql"
 	"fmt"
 	"net/http"
@@ -20,6 +21,7 @@ import (
 	usr "github.com/asepnur/meiko_course/src/module/user"
 
 	"github.com/asepnur/meiko_course/src/util/auth"
+	"github.com/asepnur/meiko_course/src/util/spreadsheet"
 	"github.com/asepnur/meiko_course/src/webserver/template"
 	"github.com/julienschmidt/httprouter"
 )
@@ -424,6 +426,8 @@ func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		PlaceID:        r.FormValue("place"),
 		IsUpdate:       r.FormValue("is_update"),
 		GradeParameter: r.FormValue("grade_parameter"),
+		IsOverride:     r.FormValue("is_override"),
+		OverrideReason: r.FormValue("override_reason"),
 	}
 
 	args, err := params.validate()
@@ -498,6 +502,26 @@ func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		return
 	}
 
+	// percentage can not be changed once scores exist unless admin overrides it
+	changes := handleGPChanges(gpsOld, gpsInsert, gpsUpdate, gpsDelete)
+	var gpsOldID []int64
+	for _, val := range gpsOld {
+		gpsOldID = append(gpsOldID, val.ID)
+	}
+	isScored := len(changes) > 0 && ag.IsScoredByGradeParameter(gpsOldID)
+	if isScored && !args.IsOverride {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusConflict).
+			AddError("Percentage can not be changed because scores exist"))
+		return
+	}
+	if isScored && !sess.IsHasRoles(auth.ModuleSchedule, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("Only admin can override percentage after scores exist"))
+		return
+	}
+
 	// is exist course and place
 	scExist := cs.IsExistScheduleID(args.ScheduleID)
 	if !scExist {
@@ -590,6 +614,30 @@ func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		}
 	}
 
+	// changed parameter set has to be published again
+	if len(changes) > 0 {
+		err = cs.UpdateGPStatus(args.ScheduleID, cs.GradeParameterDraft, tx)
+		if err != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	// record every override
+	if isScored {
+		for _, val := range changes {
+			err = cs.InsertGPOverride(args.ScheduleID, val.Type, val.OldPercentage, val.NewPercentage, args.OverrideReason, sess.ID, tx)
+			if err != nil {
+				tx.Rollback()
+				template.RenderJSONResponse(w, new(template.Response).
+					SetCode(http.StatusInternalServerError))
+				return
+			}
+		}
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
@@ -1376,3 +1424,68 @@ func ExchangeByScheduleHandler(w http.ResponseWriter, r *http.Request, ps httpro
 		SetCode(http.StatusOK))
 	return
 }
+
+// ExportRosterHandler is used by assistant to export roster of the schedule as csv or xlsx
+func ExportRosterHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleSchedule, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := exportRosterParams{
+		scheduleID: ps.ByName("schedule_id"),
+		format:     r.FormValue("format"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You are not authorized"))
+		return
+	}
+
+	course, err := cs.GetByScheduleID(args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid Request"))
+		return
+	}
+
+	rows, err := handleRoster(args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	// the sheet is built before the headers are sent so a failure can still be reported
+	name := fmt.Sprintf("%s %s", course.Course.Name, course.Schedule.Class)
+	var sheet bytes.Buffer
+	err = spreadsheet.Write(&sheet, args.format, name, rows)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	cntDisposition := fmt.Sprintf(`attachment; filename="roster_%s_%s.%s"`, time.Now().Format("20060102150405"), name, args.format)
+	w.Header().Set("Content-Type", spreadsheet.ContentType(args.format))
+	w.Header().Set("Content-Disposition", cntDisposition)
+	w.Header().Set("Cache-Control", "must-revalidate, post-check=0, pre-check=0")
+
+	sheet.WriteTo(w)
+	return
+}
@@ -1,9 +1,15 @@
 package course
 
 import (
+	"database/sql"
 	"fmt"
+	"strconv"
 
+	ag "github.com/asepnur/meiko_course/src/module/assignment"
+	att "github.com/asepnur/meiko_course/src/module/attendance"
 	cs "github.com/asepnur/meiko_course/src/module/course"
+	"github.com/asepnur/meiko_course/src/module/grade"
+	usr "github.com/asepnur/meiko_course/src/module/user"
 	"github.com/asepnur/meiko_course/src/util/helper"
 )
 
@@ -148,3 +154,133 @@ func getAll(userID int64) ([]getResponse, error) {
 // 		return courses, err
 // 	}
 // }
+
+// handleRoster builds roster rows of the schedule, each student has the attendance,
+// average score of every grade parameter and the weighted total
+func handleRoster(scheduleID int64) ([][]string, error) {
+
+	header := []string{"Identity Code", "Name", "Email", "Attendance", "Attendance (%)"}
+
+	gps, err := cs.SelectGPBySchedule([]int64{scheduleID})
+	if err != nil {
+		return nil, err
+	}
+
+	var gpsID []int64
+	for _, gp := range gps {
+		gpsID = append(gpsID, gp.ID)
+		header = append(header, fmt.Sprintf("%s (%g%%)", gp.Type, gp.Percentage))
+	}
+	header = append(header, "Total")
+	rows := [][]string{header}
+
+	studentsID, err := cs.SelectEnrolledStudentID(scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(studentsID) < 1 {
+		return rows, nil
+	}
+
+	users, err := usr.RequestID(studentsID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments := []ag.Assignment{}
+	if len(gpsID) > 0 {
+		assignments, err = ag.SelectByGP(gpsID, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var asgID []int64
+	gpAsg := map[int64][]ag.Assignment{}
+	for _, val := range assignments {
+		asgID = append(asgID, val.ID)
+		gpAsg[val.GradeParameterID] = append(gpAsg[val.GradeParameterID], val)
+	}
+
+	submitted, err := ag.SelectSubmittedByAssignment(asgID)
+	if err != nil {
+		return nil, err
+	}
+
+	// user id => assignment id => submission
+	userSubmit := map[int64]map[int64]ag.UserAssignment{}
+	for _, val := range submitted {
+		if _, ok := userSubmit[val.UserID]; !ok {
+			userSubmit[val.UserID] = map[int64]ag.UserAssignment{}
+		}
+		userSubmit[val.UserID][val.AssignmentID] = val
+	}
+
+	policy, err := grade.GetPolicy(scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		report, err := att.CountByUserSchedule(user.ID, []int64{scheduleID})
+		if err != nil {
+			return nil, err
+		}
+
+		attendance := report[scheduleID]
+		row := []string{
+			strconv.FormatInt(user.IdentityCode, 10),
+			user.Name,
+			user.Email,
+			strconv.Itoa(attendance.AttendanceTotal),
+			policy.Format(grade.AttendanceScore(attendance.AttendanceTotal, attendance.MeetingTotal).Value),
+		}
+
+		params := grade.Parameters(gps, gpAsg, userSubmit[user.ID], attendance)
+		result := policy.Calculate(params)
+		for _, gp := range params {
+			row = append(row, policy.Format(result.Parameters[gp.ID]))
+		}
+		row = append(row, policy.Format(result.Total))
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// handleGPChanges returns the grade parameters whose percentage is changed, inserted or deleted
+func handleGPChanges(gpsOld []cs.GradeParameter, gpsInsert, gpsUpdate []gradeParameter, gpsDelete []cs.GradeParameter) []cs.GradeParameterOverride {
+	changes := []cs.GradeParameterOverride{}
+
+	oldPercentage := map[string]float32{}
+	for _, val := range gpsOld {
+		oldPercentage[val.Type] = val.Percentage
+	}
+
+	for _, val := range gpsUpdate {
+		if oldPercentage[val.Type] == val.Percentage {
+			continue
+		}
+		changes = append(changes, cs.GradeParameterOverride{
+			Type:          val.Type,
+			OldPercentage: sql.NullFloat64{Valid: true, Float64: float64(oldPercentage[val.Type])},
+			NewPercentage: sql.NullFloat64{Valid: true, Float64: float64(val.Percentage)},
+		})
+	}
+
+	for _, val := range gpsInsert {
+		changes = append(changes, cs.GradeParameterOverride{
+			Type:          val.Type,
+			NewPercentage: sql.NullFloat64{Valid: true, Float64: float64(val.Percentage)},
+		})
+	}
+
+	for _, val := range gpsDelete {
+		changes = append(changes, cs.GradeParameterOverride{
+			Type:          val.Type,
+			OldPercentage: sql.NullFloat64{Valid: true, Float64: float64(val.Percentage)},
+		})
+	}
+	return changes
+}
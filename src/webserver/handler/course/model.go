@@ -134,6 +134,8 @@ type updateParams struct {
 	PlaceID        string
 	IsUpdate       string
 	GradeParameter string
+	IsOverride     string
+	OverrideReason string
 }
 
 type updateArgs struct {
@@ -152,6 +154,8 @@ type updateArgs struct {
 	PlaceID        string
 	IsUpdate       bool
 	GradeParameter []gradeParameter
+	IsOverride     bool
+	OverrideReason string
 }
 
 type summaryResponse struct {
@@ -366,3 +370,13 @@ type courseSchedule struct {
 	Course   course   `json:"course"`
 	Schedule schedule `json:"schedule"`
 }
+
+type exportRosterParams struct {
+	scheduleID string
+	format     string
+}
+
+type exportRosterArgs struct {
+	scheduleID int64
+	format     string
+}
@@ -10,6 +10,7 @@ import (
 
 	cs "github.com/asepnur/meiko_course/src/module/course"
 	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/asepnur/meiko_course/src/util/spreadsheet"
 )
 
 func (params readParams) validate() (readArgs, error) {
@@ -185,31 +186,23 @@ func (params createParams) validate() (createArgs, error) {
 			return args, fmt.Errorf("Invalid grade parameter")
 		}
 
-		var percentage float32
-		gpType := []string{
-			cs.GradeParameterAssignment,
-			cs.GradeParameterAttendance,
-			cs.GradeParameterFinal,
-			cs.GradeParameterMid,
-			cs.GradeParameterQuiz,
-		}
-
+		var gpsValidate []cs.GradeParameter
 		for _, val := range gp {
-			percentage += val.Percentage
 			// check status change
 			if val.StatusChange != cs.GradeParameterStatusChange && val.StatusChange != cs.GradeParameterStatusUnchange {
 				return args, fmt.Errorf("Invalid grade parameter")
 			}
-			// validate type
-			if !helper.IsStringInSlice(val.Type, gpType) {
-				return args, fmt.Errorf("Invalid grade parameter")
-			}
+			gpsValidate = append(gpsValidate, cs.GradeParameter{
+				Type:       val.Type,
+				Percentage: val.Percentage,
+			})
 			gps = append(gps, val)
 		}
 
-		// validate total percentage should be 100
-		if helper.Float32Round(percentage) != 100 {
-			return args, fmt.Errorf("Total percentage must be 100%%")
+		// validate type should be unique and total percentage should be 100
+		err = cs.ValidateGradeParameters(gpsValidate)
+		if err != nil {
+			return args, err
 		}
 	}
 
@@ -278,6 +271,8 @@ func (params updateParams) validate() (updateArgs, error) {
 		PlaceID:        html.EscapeString(strings.ToUpper(helper.Trim(params.PlaceID))),
 		IsUpdate:       params.IsUpdate,
 		GradeParameter: params.GradeParameter,
+		IsOverride:     params.IsOverride,
+		OverrideReason: html.EscapeString(helper.Trim(params.OverrideReason)),
 	}
 
 	// Course Validation
@@ -430,37 +425,37 @@ func (params updateParams) validate() (updateArgs, error) {
 			return args, fmt.Errorf("Invalid grade parameter")
 		}
 
-		var percentage float32
-		gpType := []string{
-			cs.GradeParameterAssignment,
-			cs.GradeParameterAttendance,
-			cs.GradeParameterFinal,
-			cs.GradeParameterMid,
-			cs.GradeParameterQuiz,
-		}
-
-		var gpChoosen []string
+		var gpsValidate []cs.GradeParameter
 		for _, val := range gp {
-			percentage += val.Percentage
 			// check status change
 			if val.StatusChange != cs.GradeParameterStatusChange && val.StatusChange != cs.GradeParameterStatusUnchange {
 				return args, fmt.Errorf("Invalid grade parameter")
 			}
-			// validate type
-			if !helper.IsStringInSlice(val.Type, gpType) {
-				return args, fmt.Errorf("Invalid grade parameter")
-			}
-			if !helper.IsStringInSlice(val.Type, gpChoosen) {
-				return args, fmt.Errorf("Invalid grade parameter should be unique")
-			}
+			gpsValidate = append(gpsValidate, cs.GradeParameter{
+				Type:       val.Type,
+				Percentage: val.Percentage,
+			})
 			gps = append(gps, val)
 		}
 
-		// validate total percentage should be 100
-		if helper.Float32Round(percentage) != 100 {
-			return args, fmt.Errorf("Total percentage must be 100%%")
+		// validate type should be unique and total percentage should be 100
+		err = cs.ValidateGradeParameters(gpsValidate)
+		if err != nil {
+			return args, err
+		}
+	}
+
+	// override is used by admin to change percentage after scores exist
+	var isOverride bool
+	if !helper.IsEmpty(params.IsOverride) {
+		isOverride, err = strconv.ParseBool(params.IsOverride)
+		if err != nil {
+			return args, fmt.Errorf("Invalid override")
 		}
 	}
+	if isOverride && helper.IsEmpty(params.OverrideReason) {
+		return args, fmt.Errorf("Override reason can not be empty")
+	}
 
 	return updateArgs{
 		ID:             params.ID,
@@ -478,6 +473,8 @@ func (params updateParams) validate() (updateArgs, error) {
 		PlaceID:        params.PlaceID,
 		IsUpdate:       isUpdate,
 		GradeParameter: gps,
+		IsOverride:     isOverride,
+		OverrideReason: params.OverrideReason,
 	}, nil
 }
 
@@ -644,3 +641,24 @@ func (params exchangeByScheduleParams) validate() (exchangeByScheduleArgs, error
 		role:       params.role,
 	}, nil
 }
+
+func (params exportRosterParams) validate() (exportRosterArgs, error) {
+	var args exportRosterArgs
+	scheduleID, err := strconv.ParseInt(params.scheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("schedule id must be numeric")
+	}
+
+	format := strings.ToLower(params.format)
+	if helper.IsEmpty(format) {
+		format = spreadsheet.FormatCSV
+	}
+	if !spreadsheet.IsValidFormat(format) {
+		return args, fmt.Errorf("Format must be csv or xlsx")
+	}
+
+	return exportRosterArgs{
+		scheduleID: scheduleID,
+		format:     format,
+	}, nil
+}
@@ -20,6 +20,7 @@ import (
 	tt "github.com/asepnur/meiko_course/src/module/tutorial"
 	"github.com/asepnur/meiko_course/src/util/auth"
 	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/asepnur/meiko_course/src/util/publish"
 	"github.com/asepnur/meiko_course/src/webserver/template"
 	"github.com/disintegration/imaging"
 	"github.com/julienschmidt/httprouter"
@@ -146,7 +147,7 @@ func GetFileHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params
 	filename := html.EscapeString(ps.ByName("filename"))
 
 	switch payload {
-	case "assignment", "tutorial":
+	case "assignment", "tutorial", "forum":
 		err = handleSingleWithMeta(payload, filename, w)
 	case "profile", "default", "information":
 		err = handleSingleWithoutMeta(payload, filename, w)
@@ -216,19 +217,44 @@ func UploadFileHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Par
 			typ = fl.TypAssignment
 			isHasAccess = sess.IsHasRoles(auth.ModuleAssignment, auth.RoleXCreate, auth.RoleCreate, auth.RoleXUpdate, auth.RoleUpdate)
 		} else if args.role == "student" {
-			// please verify file size
 			typ = fl.TypAssignmentUpload
 			gpid := cs.GetGradeParametersID(args.id)
 			scheduleID, _ := cs.GetScheduleIDByGP(gpid)
 			if cs.IsEnrolled(sess.ID, scheduleID) {
 				isHasAccess = true
 			}
+		} else if args.role == "feedback" {
+			// file returned with the feedback of the assistant, e.g. annotated submission
+			typ = fl.TypAssignmentReturn
+			gpid := cs.GetGradeParametersID(args.id)
+			scheduleID, _ := cs.GetScheduleIDByGP(gpid)
+			isHasAccess = sess.IsHasRoles(auth.ModuleAssignment, auth.RoleXUpdate, auth.RoleUpdate) && cs.IsAssistant(sess.ID, scheduleID)
+		} else if args.role == "regrade" {
+			// attachment of the regrade request of the student
+			typ = fl.TypRegrade
+			gpid := cs.GetGradeParametersID(args.id)
+			scheduleID, _ := cs.GetScheduleIDByGP(gpid)
+			isHasAccess = cs.IsEnrolled(sess.ID, scheduleID)
 		}
 	case "tutorial":
 		if args.role == "assistant" {
 			typ = fl.TypTutorial
 			isHasAccess = sess.IsHasRoles(auth.ModuleTutorial, auth.RoleXCreate, auth.RoleCreate, auth.RoleXUpdate, auth.RoleUpdate)
 		}
+	case "information":
+		if args.role == "assistant" {
+			typ = fl.TypInf
+			isHasAccess = sess.IsHasRoles(auth.ModuleInformation, auth.RoleXCreate, auth.RoleCreate, auth.RoleXUpdate, auth.RoleUpdate)
+		}
+	case "forum":
+		// id is the schedule id of the discussion
+		switch args.role {
+		case "thread":
+			typ = fl.TypForumThread
+		case "reply":
+			typ = fl.TypForumReply
+		}
+		isHasAccess = len(typ) > 0 && (cs.IsEnrolled(sess.ID, args.id) || cs.IsAssistant(sess.ID, args.id))
 	}
 
 	if !isHasAccess {
@@ -252,7 +278,7 @@ func UploadFileHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Par
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(statusCode).
-			AddError("File is not exist"))
+			AddError(err.Error()))
 		return
 	}
 
@@ -278,6 +304,7 @@ func RouterFileHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Par
 		id:      r.FormValue("id"),
 		payload: r.FormValue("payload"),
 		role:    r.FormValue("role"),
+		filter:  r.FormValue("filter"),
 	}
 
 	args, err := params.validate()
@@ -300,17 +327,21 @@ func RouterFileHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Par
 				return
 			}
 		case "student":
-			if !cs.IsEnrolled(sess.ID, tutorial.ScheduleID) {
+			now, err := publish.Now()
+			if err != nil || !cs.IsEnrolled(sess.ID, tutorial.ScheduleID) || !tutorial.IsPublished(now) {
 				http.Redirect(w, r, fl.NotFoundURL, http.StatusSeeOther)
 				return
 			}
+		default:
+			http.Redirect(w, r, fl.NotFoundURL, http.StatusSeeOther)
+			return
 		}
 		isHasAccess = true
 		typ = fl.TypTutorial
 	case "assignment":
 		if args.role == "assistant" {
 			if sess.IsHasRoles(auth.ModuleAssignment, auth.RoleXRead, auth.RoleRead) {
-				err = handleUserAssignment(sess.ID, args.id, w)
+				err = handleUserAssignment(sess.ID, args.id, args.filter, w)
 				if err != nil {
 					http.Redirect(w, r, fl.NotFoundURL, http.StatusSeeOther)
 				}
@@ -338,6 +369,12 @@ func RouterFileHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Par
 // UploadInformationImageHandler func ...
 func UploadInformationImageHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleInformation, auth.RoleXCreate, auth.RoleCreate, auth.RoleXUpdate, auth.RoleUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
 
 	// get uploaded file
 	r.ParseMultipartForm(2 * MB)
@@ -388,8 +425,9 @@ func UploadInformationImageHandler(w http.ResponseWriter, r *http.Request, ps ht
 	// generate file id
 	t := time.Now().UnixNano()
 	rand.Seed(t)
-	mImgID := fmt.Sprintf("%d.%06d.1", t, rand.Intn(999999))
-	tImgID := fmt.Sprintf("%d.%06d.2", t, rand.Intn(999999))
+	imgID := fmt.Sprintf("%d.%06d", t, rand.Intn(999999))
+	mImgID := imgID + ".1"
+	tImgID := imgID + ".2"
 
 	go func() {
 		// resize image
@@ -397,8 +435,8 @@ func UploadInformationImageHandler(w http.ResponseWriter, r *http.Request, ps ht
 		tImg := imaging.Thumbnail(img, 128, 128, imaging.Lanczos)
 
 		// save image to storage
-		imaging.Save(mImg, "files/var/www/meiko/data/information/"+mImgID+".jpg")
-		imaging.Save(tImg, "files/var/www/meiko/data/information/"+tImgID+".jpg")
+		imaging.Save(mImg, alias.Dir["data"]+"/information/"+mImgID+".jpg")
+		imaging.Save(tImg, alias.Dir["data"]+"/information/"+tImgID+".jpg")
 	}()
 
 	// begin transaction to db
@@ -410,7 +448,7 @@ func UploadInformationImageHandler(w http.ResponseWriter, r *http.Request, ps ht
 	}
 
 	// insert main image
-	err = fl.Insert(mImgID, args.FileName, args.Mime, args.Extension, sess.ID, fl.TypProfPict, tx)
+	err = fl.Insert(mImgID, args.FileName, MimeJPEG, ExtJPEG, sess.ID, fl.TypInfPict, tx)
 	if err != nil {
 		tx.Rollback()
 		template.RenderJSONResponse(w, new(template.Response).
@@ -419,7 +457,7 @@ func UploadInformationImageHandler(w http.ResponseWriter, r *http.Request, ps ht
 	}
 
 	// insert thumbnail image
-	err = fl.Insert(tImgID, args.FileName, args.Mime, args.Extension, sess.ID, fl.TypProfPictThumb, tx)
+	err = fl.Insert(tImgID, args.FileName, MimeJPEG, ExtJPEG, sess.ID, fl.TypInfPictThumb, tx)
 	if err != nil {
 		tx.Rollback()
 		template.RenderJSONResponse(w, new(template.Response).
@@ -437,7 +475,11 @@ func UploadInformationImageHandler(w http.ResponseWriter, r *http.Request, ps ht
 
 	template.RenderJSONResponse(w, new(template.Response).
 		SetCode(http.StatusOK).
-		SetMessage("Status OK"))
+		SetData(fileResponse{
+			ID:           mImgID,
+			Name:         header.Filename,
+			URLThumbnail: fmt.Sprintf("/api/v1/file/information/%s.jpg", tImgID),
+		}))
 	return
 }
 
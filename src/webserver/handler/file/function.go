@@ -8,7 +8,9 @@ import (
 	"mime/multipart"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	asg "github.com/asepnur/meiko_course/src/module/assignment"
@@ -17,6 +19,7 @@ import (
 	usr "github.com/asepnur/meiko_course/src/module/user"
 	"github.com/asepnur/meiko_course/src/util/alias"
 	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/asepnur/meiko_course/src/util/spreadsheet"
 )
 
 func handleSingleWithMeta(payload, filename string, w http.ResponseWriter) error {
@@ -99,7 +102,10 @@ func handleSingleWithoutMeta(payload, filename string, w http.ResponseWriter) er
 	return nil
 }
 
-func handleUserAssignment(userID, assignmentID int64, w http.ResponseWriter) error {
+// handleUserAssignment streams the graded files of the assignment as a zip, files of every student are put
+// in a folder named by the identity code and manifest.csv lists every student with the files which could not be found.
+// Set filter to only download ungraded or late submissions
+func handleUserAssignment(userID, assignmentID int64, filter string, w http.ResponseWriter) error {
 
 	assignment, err := asg.GetByID(assignmentID)
 	if err != nil {
@@ -115,25 +121,22 @@ func handleUserAssignment(userID, assignmentID int64, w http.ResponseWriter) err
 		return fmt.Errorf("You are not authorized")
 	}
 
-	tableID := strconv.FormatInt(assignment.ID, 10)
-	files, err := fl.SelectByRelation(fl.TypAssignmentUpload, []string{tableID}, nil)
+	pinned, err := asg.SelectPinnedFileID(assignment.ID, nil)
 	if err != nil {
 		return err
 	}
-
-	studentsID := []int64{}
-	for _, val := range files {
-		studentsID = append(studentsID, val.UserID)
+	files, err := fl.SelectGradedUpload(assignment.ID, nil, pinned)
+	if err != nil {
+		return err
 	}
 
-	users, err := usr.RequestID(studentsID, false)
+	entries, err := handleSubmissionEntries(assignment, scheduleID, files, filter)
 	if err != nil {
 		return err
 	}
-
-	studentMap := map[int64]int64{}
-	for _, val := range users {
-		studentMap[val.ID] = val.IdentityCode
+	userFiles := map[int64][]fl.File{}
+	for _, val := range files {
+		userFiles[val.UserID] = append(userFiles[val.UserID], val)
 	}
 
 	cntDisposition := fmt.Sprintf(`attachment; filename="%s_%s.zip"`, time.Now().Format("20060102150405"), assignment.Name)
@@ -145,40 +148,185 @@ func handleUserAssignment(userID, assignmentID int64, w http.ResponseWriter) err
 	w.Header().Set("Content-Transfer-Encoding", "binary")
 
 	zw := zip.NewWriter(w)
-	defer zw.Close()
 
-	for _, val := range files {
-		path := fmt.Sprintf("%s/assignment/%s.%s", alias.Dir["data"], val.ID, val.Extension)
-		file, err := os.Open(path)
-		if err != nil {
-			continue
+	// while the identities are hidden the original file name is dropped since it often contains the student name
+	isHidden := assignment.IsIdentityHidden()
+	for i := range entries {
+		entry := &entries[i]
+		used := map[string]int{}
+		for k, val := range userFiles[entry.UserID] {
+			name := val.Name
+			if isHidden {
+				name = fmt.Sprintf("file-%d", k+1)
+			}
+			full := fmt.Sprintf("%s.%s", name, val.Extension)
+			used[full]++
+			if used[full] > 1 {
+				full = fmt.Sprintf("%s (%d).%s", name, used[full], val.Extension)
+			}
+			name = full
+
+			path := fmt.Sprintf("%s/assignment/%s.%s", alias.Dir["data"], val.ID, val.Extension)
+			err = handleZipFile(zw, fmt.Sprintf("%s/%s", entry.Folder, name), path)
+			if os.IsNotExist(err) {
+				entry.Missing = append(entry.Missing, name)
+				continue
+			} else if err != nil {
+				return err
+			}
+			entry.Files = append(entry.Files, name)
 		}
-		defer file.Close()
+	}
 
-		info, err := file.Stat()
-		if err != nil {
-			return err
+	mw, err := zw.Create("manifest.csv")
+	if err != nil {
+		return err
+	}
+	err = spreadsheet.WriteCSV(mw, handleManifest(entries))
+	if err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// handleZipFile copies the file on path into the archive as name, the file is closed once it has been written.
+// An error which satisfies os.IsNotExist is returned when the file is missing from the disk
+func handleZipFile(zw *zip.Writer, name, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	hdr, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	hdr.Method = zip.Deflate
+	hdr.Name = name
+
+	writter, err := zw.CreateHeader(hdr)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(writter, file)
+	return err
+}
+
+// handleSubmissionEntries returns every student who has submitted or uploaded a file on the assignment which
+// matches the filter, ordered by folder. While the identities are hidden the folder is the anonymous code
+func handleSubmissionEntries(assignment asg.Assignment, scheduleID int64, files []fl.File, filter string) ([]submissionEntry, error) {
+	submitted, err := asg.SelectSubmittedByAssignment([]int64{assignment.ID})
+	if err != nil {
+		return nil, err
+	}
+	submissions := map[int64]asg.UserAssignment{}
+	var usersID []int64
+	for _, val := range submitted {
+		submissions[val.UserID] = val
+		usersID = append(usersID, val.UserID)
+	}
+	// score, feedback and pin writes update the submission so the time comes from the graded version
+	versions, err := asg.SelectGradedVersion(assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	submittedAt := map[int64]time.Time{}
+	for _, val := range versions {
+		submittedAt[val.UserID] = val.CreatedAt
+	}
+	for _, val := range files {
+		if !helper.Int64InSlice(val.UserID, usersID) {
+			usersID = append(usersID, val.UserID)
 		}
+	}
+
+	entries := []submissionEntry{}
+	if len(usersID) < 1 {
+		return entries, nil
+	}
+	users, err := usr.RequestID(usersID, false)
+	if err != nil {
+		return nil, err
+	}
 
-		hdr, err := zip.FileInfoHeader(info)
+	isHidden := assignment.IsIdentityHidden()
+	codes := map[int64]int64{}
+	if isHidden {
+		enrolledID, err := cs.SelectEnrolledStudentID(scheduleID)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		codes = assignment.AnonymousCodes(enrolledID)
+	}
 
-		hdr.Method = zip.Deflate
-		hdr.Name = fmt.Sprintf("%d_%s.%s", studentMap[val.UserID], val.Name, val.Extension)
-		writter, err := zw.CreateHeader(hdr)
-		if err != nil {
-			return err
+	for _, val := range users {
+		submission, ok := submissions[val.ID]
+		if filter == FilterUngraded && ok && submission.Score.Valid {
+			continue
+		}
+		if filter == FilterLate && (!ok || submission.LateSeconds <= 0) {
+			continue
 		}
 
-		_, err = io.Copy(writter, file)
-		if err != nil {
-			return err
+		entry := submissionEntry{
+			UserID:      val.ID,
+			Folder:      strconv.FormatInt(val.IdentityCode, 10),
+			Name:        val.Name,
+			SubmittedAt: "-",
+			Score:       "-",
+		}
+		if isHidden {
+			entry.Name = "Anonymous"
+			entry.Folder = fmt.Sprintf("anonymous-%d", len(entries)+1)
+			if code, isCode := codes[val.ID]; isCode {
+				entry.Folder = strconv.FormatInt(code, 10)
+			}
+		}
+		if ok {
+			if t, isSubmitted := submittedAt[val.ID]; isSubmitted {
+				entry.SubmittedAt = t.Format("2006-01-02 15:04:05")
+			}
+			entry.IsLate = submission.LateSeconds > 0
+			if submission.Score.Valid {
+				entry.Score = strconv.FormatFloat(submission.Score.Float64, 'f', 2, 64)
+			}
 		}
+		entries = append(entries, entry)
 	}
 
-	return nil
+	// identities must not leak through the order while they are hidden
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Folder < entries[j].Folder
+	})
+	return entries, nil
+}
+
+// handleManifest builds the rows of manifest.csv, files which could not be found on the disk are listed
+// on missing_files so they are not mistaken for files which were never uploaded
+func handleManifest(entries []submissionEntry) [][]string {
+	rows := [][]string{{"identity_code", "name", "submitted_at", "late", "score", "files", "missing_files"}}
+	for _, val := range entries {
+		late := "no"
+		if val.IsLate {
+			late = "yes"
+		}
+		rows = append(rows, []string{
+			val.Folder,
+			val.Name,
+			val.SubmittedAt,
+			late,
+			val.Score,
+			strings.Join(val.Files, "; "),
+			strings.Join(val.Missing, "; "),
+		})
+	}
+	return rows
 }
 
 func handleUpload(file multipart.File, header *multipart.FileHeader, userID int64, typ string, payload string) (fileResponse, int, error) {
@@ -194,10 +342,23 @@ func handleUpload(file multipart.File, header *multipart.FileHeader, userID int6
 		return resp, http.StatusBadRequest, err
 	}
 
+	// the content type sent by the client can not be trusted, it is sniffed from the content instead
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return resp, http.StatusBadRequest, fmt.Errorf("File can not be read")
+	}
+	head = head[:n]
+
+	mime := http.DetectContentType(head)
+	if office, ok := officeMimes[strings.ToLower(ext)]; ok && mime == "application/zip" {
+		mime = office
+	}
+
 	params := metaParams{
 		fileName:  fn,
 		extension: ext,
-		mime:      header.Header.Get("Content-Type"),
+		mime:      mime,
 	}
 
 	args, err := params.validate()
@@ -205,29 +366,39 @@ func handleUpload(file multipart.File, header *multipart.FileHeader, userID int6
 		return resp, http.StatusBadRequest, fmt.Errorf("Invalid Request")
 	}
 
+	// submission files are checked again against the assignment limits when they are submitted
+	if typ == fl.TypAssignmentUpload && !helper.IsFileSignature(args.extension, head) {
+		return resp, http.StatusBadRequest, fmt.Errorf("Content of %s does not match its type", header.Filename)
+	}
+
 	// get filename
 	t := time.Now().UnixNano()
 	rand.Seed(t)
 	fileID := fmt.Sprintf("%d.%06d", t, rand.Intn(999999))
 
-	// save file
-	go func() {
-		path := fmt.Sprintf("%s/%s/%s.%s", alias.Dir["data"], payload, fileID, args.extension)
-		f, _ := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0666)
-		defer f.Close()
-
-		file.Seek(0, 0)
-		io.Copy(f, file)
-	}()
+	// save file before the metadata so a stored file always exists on disk
+	path := fmt.Sprintf("%s/%s/%s.%s", alias.Dir["data"], payload, fileID, args.extension)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0666)
+	if err != nil {
+		return resp, http.StatusInternalServerError, fmt.Errorf("Internal Error")
+	}
+	file.Seek(0, 0)
+	_, err = io.Copy(f, file)
+	f.Close()
+	if err != nil {
+		os.Remove(path)
+		return resp, http.StatusInternalServerError, fmt.Errorf("Internal Error")
+	}
 
 	err = fl.Insert(fileID, args.fileName, args.mime, args.extension, userID, typ, nil)
 	if err != nil {
+		os.Remove(path)
 		return resp, http.StatusInternalServerError, fmt.Errorf("Internal Error")
 	}
 
 	return fileResponse{
 		ID:           fileID,
 		Name:         header.Filename,
-		URLThumbnail: helper.MimeToThumbnail(params.mime),
+		URLThumbnail: helper.MimeToThumbnail(args.mime),
 	}, http.StatusOK, nil
 }
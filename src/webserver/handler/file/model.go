@@ -14,8 +14,20 @@ const (
 	ExtJPEG  = "jpg"
 	MimeZIP  = "zip"
 	MimeRAR  = ""
+
+	// FilterUngraded only downloads submissions which have not been scored
+	FilterUngraded = "ungraded"
+	// FilterLate only downloads submissions which were submitted late
+	FilterLate = "late"
 )
 
+// officeMimes is used for office documents which are sniffed as zip archives
+var officeMimes = map[string]string{
+	"docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	"xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
 type uploadImageMapper struct {
 	fn        string
 	multiple  bool
@@ -78,12 +90,27 @@ type routerParams struct {
 	payload string
 	role    string
 	id      string
+	filter  string
 }
 
 type routerArgs struct {
 	payload string
 	role    string
 	id      int64
+	filter  string
+}
+
+// submissionEntry is a student on the bulk download, Folder is the identity code
+// or the anonymous code of the student which the files are put in
+type submissionEntry struct {
+	UserID      int64
+	Folder      string
+	Name        string
+	SubmittedAt string
+	IsLate      bool
+	Score       string
+	Files       []string
+	Missing     []string
 }
 
 type uploadFileParams struct {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"html"
 	"strconv"
+	"strings"
 
 	"github.com/asepnur/meiko_course/src/util/helper"
 )
@@ -68,10 +69,14 @@ func (params metaParams) validate() (metaArgs, error) {
 	}
 
 	// validate mime and extension
+	extension := strings.ToLower(params.extension)
+	if helper.IsEmpty(extension) || len(extension) > 5 {
+		return args, fmt.Errorf("Invalid extension")
+	}
 
 	return metaArgs{
 		fileName:  params.fileName,
-		extension: params.extension,
+		extension: extension,
 		mime:      params.mime,
 	}, nil
 }
@@ -94,9 +99,15 @@ func (params routerParams) validate() (routerArgs, error) {
 		return args, err
 	}
 
+	filter := strings.ToLower(helper.Trim(params.filter))
+	if filter != "" && filter != FilterUngraded && filter != FilterLate {
+		return args, fmt.Errorf("Filter must be %s or %s", FilterUngraded, FilterLate)
+	}
+
 	return routerArgs{
 		payload: params.payload,
 		role:    params.role,
 		id:      id,
+		filter:  filter,
 	}, nil
 }
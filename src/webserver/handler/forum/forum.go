@@ -0,0 +1,742 @@
+package forum
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	fl "github.com/asepnur/meiko_course/src/module/file"
+	frm "github.com/asepnur/meiko_course/src/module/forum"
+	"github.com/asepnur/meiko_course/src/util/auth"
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/webserver/template"
+	"github.com/julienschmidt/httprouter"
+)
+
+// ReadHandler lists threads of a schedule, optionally only threads related to a tutorial, assignment or meeting
+func ReadHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := readParams{
+		scheduleID: r.FormValue("schedule_id"),
+		relation:   r.FormValue("relation"),
+		relationID: r.FormValue("relation_id"),
+		page:       r.FormValue("pg"),
+		total:      r.FormValue("ttl"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	isMember, _ := handleAccess(sess.ID, args.scheduleID)
+	if !isMember {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You are not authorized"))
+		return
+	}
+
+	offset := (args.page - 1) * args.total
+	threads, count, err := frm.SelectThreadByPage(args.scheduleID, args.tableName, args.tableID, args.total, offset, true)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	respThread, err := handleThreadResponse(threads, args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	totalPage := count / args.total
+	if count%args.total > 0 {
+		totalPage++
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(readResponse{
+			Page:      args.page,
+			TotalPage: totalPage,
+			Threads:   respThread,
+		}))
+	return
+}
+
+// DetailHandler returns the thread with its replies
+func DetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := detailParams{
+		id:    ps.ByName("thread_id"),
+		page:  r.FormValue("pg"),
+		total: r.FormValue("ttl"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	thread, err := frm.GetThreadByID(args.id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusNoContent))
+			return
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	isMember, isAssistant := handleAccess(sess.ID, thread.ScheduleID)
+	if !isMember {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You are not authorized"))
+		return
+	}
+
+	offset := (args.page - 1) * args.total
+	replies, count, err := frm.SelectReplyByPage(thread.ID, args.total, offset, true)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	respThread, err := handleThreadResponse([]frm.Thread{thread}, thread.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	respReply, err := handleReplyResponse(replies, thread)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	totalPage := count / args.total
+	if count%args.total > 0 {
+		totalPage++
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(detailResponse{
+			Thread:      respThread[0],
+			CanModerate: isAssistant,
+			Page:        args.page,
+			TotalPage:   totalPage,
+			Replies:     respReply,
+		}))
+	return
+}
+
+// CreateHandler ...
+func CreateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := createParams{
+		scheduleID: r.FormValue("schedule_id"),
+		title:      r.FormValue("title"),
+		content:    r.FormValue("content"),
+		relation:   r.FormValue("relation"),
+		relationID: r.FormValue("relation_id"),
+		filesID:    r.FormValue("files_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	isMember, _ := handleAccess(sess.ID, args.scheduleID)
+	if !isMember {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You are not authorized"))
+		return
+	}
+
+	if args.tableName.Valid && !handleRelation(args.tableName.String, args.tableID.Int64, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid relation"))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	id, err := frm.InsertThread(args.title, args.content, args.scheduleID, sess.ID, args.tableName, args.tableID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	tableID := strconv.FormatInt(id, 10)
+	for _, fileID := range args.filesID {
+		if fl.UpdateRelation(fileID, fl.TypForumThread, tableID, tx) != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusBadRequest).
+				AddError("Wrong File ID"))
+			return
+		}
+	}
+
+	tx.Commit()
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(map[string]int64{"id": id}).
+		SetMessage("Thread created successfully"))
+	return
+}
+
+// UpdateHandler is used by the author to edit the thread, assistants are allowed to edit for moderation
+func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := updateParams{
+		id:      ps.ByName("thread_id"),
+		title:   r.FormValue("title"),
+		content: r.FormValue("content"),
+		filesID: r.FormValue("files_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	thread, err := frm.GetThreadByID(args.id)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid Request"))
+		return
+	}
+
+	isMember, isAssistant := handleAccess(sess.ID, thread.ScheduleID)
+	if !isAssistant && (!isMember || thread.UserID != sess.ID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	if !isAssistant && thread.IsLocked == frm.StatusLocked {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("Thread is locked"))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = frm.UpdateThread(thread.ID, args.title, args.content, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if fl.ReplaceRelation(fl.TypForumThread, args.filesID, strconv.FormatInt(thread.ID, 10), tx) != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Wrong File ID"))
+		return
+	}
+
+	tx.Commit()
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Thread updated successfully"))
+	return
+}
+
+// DeleteHandler is used by the author or assistants to remove the thread
+func DeleteHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := idParams{
+		id: ps.ByName("thread_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	thread, err := frm.GetThreadByID(args.id)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid Request"))
+		return
+	}
+
+	isMember, isAssistant := handleAccess(sess.ID, thread.ScheduleID)
+	if !isAssistant && (!isMember || thread.UserID != sess.ID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = frm.DeleteThread(thread.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = fl.DeleteByRelation(fl.TypForumThread, strconv.FormatInt(thread.ID, 10), tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	tx.Commit()
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Success"))
+	return
+}
+
+// PinHandler is used by assistants to pin or unpin the thread
+func PinHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := statusParams{
+		id:     ps.ByName("thread_id"),
+		status: r.FormValue("is_pinned"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	thread, err := frm.GetThreadByID(args.id)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid Request"))
+		return
+	}
+
+	_, isAssistant := handleAccess(sess.ID, thread.ScheduleID)
+	if !isAssistant {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	var isPinned int8 = frm.StatusUnpinned
+	if args.status {
+		isPinned = frm.StatusPinned
+	}
+
+	if thread.IsPinned != isPinned {
+		err = frm.UpdateThreadPinned(thread.ID, isPinned, nil)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Success"))
+	return
+}
+
+// LockHandler is used by assistants to lock or unlock the thread, locked thread can not be replied
+func LockHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := statusParams{
+		id:     ps.ByName("thread_id"),
+		status: r.FormValue("is_locked"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	thread, err := frm.GetThreadByID(args.id)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid Request"))
+		return
+	}
+
+	_, isAssistant := handleAccess(sess.ID, thread.ScheduleID)
+	if !isAssistant {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	var isLocked int8 = frm.StatusUnlocked
+	if args.status {
+		isLocked = frm.StatusLocked
+	}
+
+	if thread.IsLocked != isLocked {
+		err = frm.UpdateThreadLocked(thread.ID, isLocked, nil)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Success"))
+	return
+}
+
+// AcceptHandler is used by the thread author or assistants to mark a reply as the accepted answer
+func AcceptHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := acceptParams{
+		id:      ps.ByName("thread_id"),
+		replyID: r.FormValue("reply_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	thread, err := frm.GetThreadByID(args.id)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid Request"))
+		return
+	}
+
+	isMember, isAssistant := handleAccess(sess.ID, thread.ScheduleID)
+	if !isAssistant && (!isMember || thread.UserID != sess.ID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	if args.replyID.Valid {
+		reply, err := frm.GetReplyByID(args.replyID.Int64)
+		if err != nil || reply.ThreadID != thread.ID {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusBadRequest).
+				AddError("Invalid reply"))
+			return
+		}
+	}
+
+	if thread.AcceptedReplyID != args.replyID {
+		err = frm.UpdateThreadAccepted(thread.ID, args.replyID, nil)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Success"))
+	return
+}
+
+// CreateReplyHandler ...
+func CreateReplyHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := replyParams{
+		threadID: ps.ByName("thread_id"),
+		content:  r.FormValue("content"),
+		filesID:  r.FormValue("files_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	thread, err := frm.GetThreadByID(args.threadID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid Request"))
+		return
+	}
+
+	isMember, isAssistant := handleAccess(sess.ID, thread.ScheduleID)
+	if !isMember {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You are not authorized"))
+		return
+	}
+
+	if !isAssistant && thread.IsLocked == frm.StatusLocked {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("Thread is locked"))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	id, err := frm.InsertReply(thread.ID, sess.ID, args.content, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	tableID := strconv.FormatInt(id, 10)
+	for _, fileID := range args.filesID {
+		if fl.UpdateRelation(fileID, fl.TypForumReply, tableID, tx) != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusBadRequest).
+				AddError("Wrong File ID"))
+			return
+		}
+	}
+
+	err = frm.TouchThread(thread.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	tx.Commit()
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(map[string]int64{"id": id}).
+		SetMessage("Reply created successfully"))
+	return
+}
+
+// UpdateReplyHandler is used by the author to edit the reply, assistants are allowed to edit for moderation
+func UpdateReplyHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := replyParams{
+		threadID: ps.ByName("thread_id"),
+		replyID:  ps.ByName("reply_id"),
+		content:  r.FormValue("content"),
+		filesID:  r.FormValue("files_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	thread, err := frm.GetThreadByID(args.threadID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid Request"))
+		return
+	}
+
+	reply, err := frm.GetReplyByID(args.replyID)
+	if err != nil || reply.ThreadID != thread.ID {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid Request"))
+		return
+	}
+
+	isMember, isAssistant := handleAccess(sess.ID, thread.ScheduleID)
+	if !isAssistant && (!isMember || reply.UserID != sess.ID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	if !isAssistant && thread.IsLocked == frm.StatusLocked {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("Thread is locked"))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = frm.UpdateReply(reply.ID, args.content, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if fl.ReplaceRelation(fl.TypForumReply, args.filesID, strconv.FormatInt(reply.ID, 10), tx) != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Wrong File ID"))
+		return
+	}
+
+	tx.Commit()
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Reply updated successfully"))
+	return
+}
+
+// DeleteReplyHandler is used by the author or assistants to remove the reply
+func DeleteReplyHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	threadParams := idParams{id: ps.ByName("thread_id")}
+	threadArgs, err := threadParams.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	replyParams := idParams{id: ps.ByName("reply_id")}
+	replyArgs, err := replyParams.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	thread, err := frm.GetThreadByID(threadArgs.id)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid Request"))
+		return
+	}
+
+	reply, err := frm.GetReplyByID(replyArgs.id)
+	if err != nil || reply.ThreadID != thread.ID {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid Request"))
+		return
+	}
+
+	isMember, isAssistant := handleAccess(sess.ID, thread.ScheduleID)
+	if !isAssistant && (!isMember || reply.UserID != sess.ID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = frm.DeleteReply(reply.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = fl.DeleteByRelation(fl.TypForumReply, strconv.FormatInt(reply.ID, 10), tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	// deleted reply can not be the accepted answer anymore
+	if thread.AcceptedReplyID.Valid && thread.AcceptedReplyID.Int64 == reply.ID {
+		err = frm.UpdateThreadAccepted(thread.ID, sql.NullInt64{}, tx)
+		if err != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	tx.Commit()
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Success"))
+	return
+}
@@ -0,0 +1,234 @@
+package forum
+
+import (
+	"fmt"
+	"strconv"
+
+	asg "github.com/asepnur/meiko_course/src/module/assignment"
+	att "github.com/asepnur/meiko_course/src/module/attendance"
+	cs "github.com/asepnur/meiko_course/src/module/course"
+	fl "github.com/asepnur/meiko_course/src/module/file"
+	frm "github.com/asepnur/meiko_course/src/module/forum"
+	tt "github.com/asepnur/meiko_course/src/module/tutorial"
+	usr "github.com/asepnur/meiko_course/src/module/user"
+	"github.com/asepnur/meiko_course/src/util/helper"
+)
+
+// handleAccess returns whether user is a member (student or assistant) and whether user is an assistant of the schedule
+func handleAccess(userID, scheduleID int64) (bool, bool) {
+	if cs.IsAssistant(userID, scheduleID) {
+		return true, true
+	}
+	return cs.IsEnrolled(userID, scheduleID), false
+}
+
+// handleRelation checks whether the related tutorial, assignment or meeting belongs to the schedule
+func handleRelation(tableName string, tableID, scheduleID int64) bool {
+	switch tableName {
+	case frm.TableTutorial:
+		tutorial, err := tt.GetByID(tableID)
+		if err != nil {
+			return false
+		}
+		return tutorial.ScheduleID == scheduleID
+	case frm.TableAssignment:
+		assignment, err := asg.GetByID(tableID)
+		if err != nil {
+			return false
+		}
+		schID, err := cs.GetScheduleIDByGP(assignment.GradeParameterID)
+		if err != nil {
+			return false
+		}
+		return schID == scheduleID
+	case frm.TableMeeting:
+		meeting, err := att.GetMeetingByID(uint64(tableID))
+		if err != nil {
+			return false
+		}
+		return meeting.ScheduleID == scheduleID
+	}
+	return false
+}
+
+// handleRelationPayload converts the related table name into relation payload
+func handleRelationPayload(tableName string) string {
+	switch tableName {
+	case frm.TableTutorial:
+		return "tutorial"
+	case frm.TableAssignment:
+		return "assignment"
+	case frm.TableMeeting:
+		return "meeting"
+	}
+	return ""
+}
+
+// handleFiles returns attachments grouped by table id
+func handleFiles(typ string, tablesID []int64) (map[int64][]fileResponse, error) {
+	res := map[int64][]fileResponse{}
+	if len(tablesID) < 1 {
+		return res, nil
+	}
+
+	files, err := fl.SelectByRelation(typ, helper.Int64ToStringSlice(tablesID), nil)
+	if err != nil {
+		return res, err
+	}
+
+	for _, val := range files {
+		id, err := strconv.ParseInt(val.TableID.String, 10, 64)
+		if err != nil {
+			continue
+		}
+		res[id] = append(res[id], fileResponse{
+			ID:   val.ID,
+			Name: fmt.Sprintf("%s.%s", val.Name, val.Extension),
+			URL:  fmt.Sprintf("/api/v1/file/forum/%s.%s", val.ID, val.Extension),
+		})
+	}
+	return res, nil
+}
+
+// handleUsers returns the authors of threads or replies, assistants are flagged so students know the official answer
+func handleUsers(usersID []int64, scheduleID int64) (map[int64]userResponse, error) {
+	res := map[int64]userResponse{}
+	if len(usersID) < 1 {
+		return res, nil
+	}
+
+	assistantsID, err := cs.SelectAssistantID(scheduleID)
+	if err != nil {
+		return res, err
+	}
+
+	users, err := usr.RequestID(usersID, false)
+	if err != nil {
+		return res, err
+	}
+
+	for _, val := range users {
+		res[val.ID] = userResponse{
+			ID:          val.ID,
+			Name:        val.Name,
+			IsAssistant: helper.Int64InSlice(val.ID, assistantsID),
+		}
+	}
+	return res, nil
+}
+
+// handleThreadResponse builds thread response including the author, attachments and total reply
+func handleThreadResponse(threads []frm.Thread, scheduleID int64) ([]threadResponse, error) {
+	resp := []threadResponse{}
+	if len(threads) < 1 {
+		return resp, nil
+	}
+
+	var threadsID, usersID []int64
+	for _, val := range threads {
+		threadsID = append(threadsID, val.ID)
+		if !helper.Int64InSlice(val.UserID, usersID) {
+			usersID = append(usersID, val.UserID)
+		}
+	}
+
+	users, err := handleUsers(usersID, scheduleID)
+	if err != nil {
+		return resp, err
+	}
+
+	files, err := handleFiles(fl.TypForumThread, threadsID)
+	if err != nil {
+		return resp, err
+	}
+
+	totalReply, err := frm.CountReplyByThreadID(threadsID)
+	if err != nil {
+		return resp, err
+	}
+
+	for _, val := range threads {
+		var relation *relationResponse
+		if val.TableName.Valid && val.TableID.Valid {
+			relation = &relationResponse{
+				Type: handleRelationPayload(val.TableName.String),
+				ID:   val.TableID.Int64,
+			}
+		}
+
+		threadFiles := files[val.ID]
+		if threadFiles == nil {
+			threadFiles = []fileResponse{}
+		}
+
+		author, ok := users[val.UserID]
+		if !ok {
+			author = userResponse{ID: val.UserID}
+		}
+
+		resp = append(resp, threadResponse{
+			ID:              val.ID,
+			Title:           val.Title,
+			Content:         val.Content,
+			Author:          author,
+			Relation:        relation,
+			AcceptedReplyID: val.AcceptedReplyID.Int64,
+			IsPinned:        val.IsPinned == frm.StatusPinned,
+			IsLocked:        val.IsLocked == frm.StatusLocked,
+			TotalReply:      totalReply[val.ID],
+			Files:           threadFiles,
+			Time:            val.CreatedAt.Unix(),
+			UpdatedAt:       val.UpdatedAt.Unix(),
+		})
+	}
+	return resp, nil
+}
+
+// handleReplyResponse builds reply response including the author and attachments
+func handleReplyResponse(replies []frm.Reply, thread frm.Thread) ([]replyResponse, error) {
+	resp := []replyResponse{}
+	if len(replies) < 1 {
+		return resp, nil
+	}
+
+	var repliesID, usersID []int64
+	for _, val := range replies {
+		repliesID = append(repliesID, val.ID)
+		if !helper.Int64InSlice(val.UserID, usersID) {
+			usersID = append(usersID, val.UserID)
+		}
+	}
+
+	users, err := handleUsers(usersID, thread.ScheduleID)
+	if err != nil {
+		return resp, err
+	}
+
+	files, err := handleFiles(fl.TypForumReply, repliesID)
+	if err != nil {
+		return resp, err
+	}
+
+	for _, val := range replies {
+		replyFiles := files[val.ID]
+		if replyFiles == nil {
+			replyFiles = []fileResponse{}
+		}
+
+		author, ok := users[val.UserID]
+		if !ok {
+			author = userResponse{ID: val.UserID}
+		}
+
+		resp = append(resp, replyResponse{
+			ID:         val.ID,
+			Content:    val.Content,
+			Author:     author,
+			IsAccepted: thread.AcceptedReplyID.Valid && thread.AcceptedReplyID.Int64 == val.ID,
+			Files:      replyFiles,
+			Time:       val.CreatedAt.Unix(),
+			UpdatedAt:  val.UpdatedAt.Unix(),
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,161 @@
+package forum
+
+import "database/sql"
+
+type readParams struct {
+	scheduleID string
+	relation   string
+	relationID string
+	page       string
+	total      string
+}
+
+type readArgs struct {
+	scheduleID int64
+	tableName  string
+	tableID    int64
+	page       int
+	total      int
+}
+
+type detailParams struct {
+	id    string
+	page  string
+	total string
+}
+
+type detailArgs struct {
+	id    int64
+	page  int
+	total int
+}
+
+type createParams struct {
+	scheduleID string
+	title      string
+	content    string
+	relation   string
+	relationID string
+	filesID    string
+}
+
+type createArgs struct {
+	scheduleID int64
+	title      string
+	content    string
+	tableName  sql.NullString
+	tableID    sql.NullInt64
+	filesID    []string
+}
+
+type updateParams struct {
+	id      string
+	title   string
+	content string
+	filesID string
+}
+
+type updateArgs struct {
+	id      int64
+	title   string
+	content string
+	filesID []string
+}
+
+type idParams struct {
+	id string
+}
+
+type idArgs struct {
+	id int64
+}
+
+type statusParams struct {
+	id     string
+	status string
+}
+
+type statusArgs struct {
+	id     int64
+	status bool
+}
+
+type acceptParams struct {
+	id      string
+	replyID string
+}
+
+type acceptArgs struct {
+	id      int64
+	replyID sql.NullInt64
+}
+
+type replyParams struct {
+	threadID string
+	replyID  string
+	content  string
+	filesID  string
+}
+
+type replyArgs struct {
+	threadID int64
+	replyID  int64
+	content  string
+	filesID  []string
+}
+
+type fileResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type userResponse struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	IsAssistant bool   `json:"is_assistant"`
+}
+
+type relationResponse struct {
+	Type string `json:"type"`
+	ID   int64  `json:"id"`
+}
+
+type threadResponse struct {
+	ID              int64             `json:"id"`
+	Title           string            `json:"title"`
+	Content         string            `json:"content"`
+	Author          userResponse      `json:"author"`
+	Relation        *relationResponse `json:"relation"`
+	AcceptedReplyID int64             `json:"accepted_reply_id,omitempty"`
+	IsPinned        bool              `json:"is_pinned"`
+	IsLocked        bool              `json:"is_locked"`
+	TotalReply      int               `json:"total_reply"`
+	Files           []fileResponse    `json:"files"`
+	Time            int64             `json:"time"`
+	UpdatedAt       int64             `json:"updated_at"`
+}
+
+type replyResponse struct {
+	ID         int64          `json:"id"`
+	Content    string         `json:"content"`
+	Author     userResponse   `json:"author"`
+	IsAccepted bool           `json:"is_accepted"`
+	Files      []fileResponse `json:"files"`
+	Time       int64          `json:"time"`
+	UpdatedAt  int64          `json:"updated_at"`
+}
+
+type readResponse struct {
+	Page      int              `json:"page"`
+	TotalPage int              `json:"total_page"`
+	Threads   []threadResponse `json:"threads"`
+}
+
+type detailResponse struct {
+	Thread      threadResponse  `json:"thread"`
+	CanModerate bool            `json:"can_moderate"`
+	Page        int             `json:"page"`
+	TotalPage   int             `json:"total_page"`
+	Replies     []replyResponse `json:"replies"`
+}
@@ -0,0 +1,312 @@
+package forum
+
+import (
+	"database/sql"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	frm "github.com/asepnur/meiko_course/src/module/forum"
+	"github.com/asepnur/meiko_course/src/util/helper"
+)
+
+func (params readParams) validate() (readArgs, error) {
+	var args readArgs
+
+	if helper.IsEmpty(params.scheduleID) || helper.IsEmpty(params.page) || helper.IsEmpty(params.total) {
+		return args, fmt.Errorf("Invalid request")
+	}
+
+	scheduleID, err := strconv.ParseInt(params.scheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid request")
+	}
+
+	page, total, err := validatePage(params.page, params.total)
+	if err != nil {
+		return args, err
+	}
+
+	tableName, tableID, err := validateRelation(params.relation, params.relationID)
+	if err != nil {
+		return args, err
+	}
+
+	return readArgs{
+		scheduleID: scheduleID,
+		tableName:  tableName.String,
+		tableID:    tableID.Int64,
+		page:       page,
+		total:      total,
+	}, nil
+}
+
+func (params detailParams) validate() (detailArgs, error) {
+	var args detailArgs
+
+	id, err := strconv.ParseInt(params.id, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid request")
+	}
+
+	page, total, err := validatePage(params.page, params.total)
+	if err != nil {
+		return args, err
+	}
+
+	return detailArgs{
+		id:    id,
+		page:  page,
+		total: total,
+	}, nil
+}
+
+func (params createParams) validate() (createArgs, error) {
+	var args createArgs
+
+	scheduleID, err := strconv.ParseInt(params.scheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule id")
+	}
+
+	title, err := validateTitle(params.title)
+	if err != nil {
+		return args, err
+	}
+
+	content, err := validateContent(params.content)
+	if err != nil {
+		return args, err
+	}
+
+	tableName, tableID, err := validateRelation(params.relation, params.relationID)
+	if err != nil {
+		return args, err
+	}
+
+	filesID, err := validateFilesID(params.filesID)
+	if err != nil {
+		return args, err
+	}
+
+	return createArgs{
+		scheduleID: scheduleID,
+		title:      title,
+		content:    content,
+		tableName:  tableName,
+		tableID:    tableID,
+		filesID:    filesID,
+	}, nil
+}
+
+func (params updateParams) validate() (updateArgs, error) {
+	var args updateArgs
+
+	id, err := strconv.ParseInt(params.id, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid request")
+	}
+
+	title, err := validateTitle(params.title)
+	if err != nil {
+		return args, err
+	}
+
+	content, err := validateContent(params.content)
+	if err != nil {
+		return args, err
+	}
+
+	filesID, err := validateFilesID(params.filesID)
+	if err != nil {
+		return args, err
+	}
+
+	return updateArgs{
+		id:      id,
+		title:   title,
+		content: content,
+		filesID: filesID,
+	}, nil
+}
+
+func (params idParams) validate() (idArgs, error) {
+	var args idArgs
+	id, err := strconv.ParseInt(params.id, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid request")
+	}
+	return idArgs{id: id}, nil
+}
+
+func (params statusParams) validate() (statusArgs, error) {
+	var args statusArgs
+	id, err := strconv.ParseInt(params.id, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid request")
+	}
+
+	status, err := strconv.ParseBool(params.status)
+	if err != nil {
+		return args, fmt.Errorf("Invalid status")
+	}
+
+	return statusArgs{
+		id:     id,
+		status: status,
+	}, nil
+}
+
+func (params acceptParams) validate() (acceptArgs, error) {
+	var args acceptArgs
+	id, err := strconv.ParseInt(params.id, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid request")
+	}
+
+	// empty reply id is used to unmark the accepted answer
+	var replyID sql.NullInt64
+	if !helper.IsEmpty(params.replyID) {
+		rID, err := strconv.ParseInt(params.replyID, 10, 64)
+		if err != nil {
+			return args, fmt.Errorf("Invalid reply id")
+		}
+		replyID = sql.NullInt64{Valid: true, Int64: rID}
+	}
+
+	return acceptArgs{
+		id:      id,
+		replyID: replyID,
+	}, nil
+}
+
+func (params replyParams) validate() (replyArgs, error) {
+	var args replyArgs
+
+	threadID, err := strconv.ParseInt(params.threadID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid request")
+	}
+
+	var replyID int64
+	if !helper.IsEmpty(params.replyID) {
+		replyID, err = strconv.ParseInt(params.replyID, 10, 64)
+		if err != nil {
+			return args, fmt.Errorf("Invalid request")
+		}
+	}
+
+	content, err := validateContent(params.content)
+	if err != nil {
+		return args, err
+	}
+
+	filesID, err := validateFilesID(params.filesID)
+	if err != nil {
+		return args, err
+	}
+
+	return replyArgs{
+		threadID: threadID,
+		replyID:  replyID,
+		content:  content,
+		filesID:  filesID,
+	}, nil
+}
+
+func validatePage(pg, ttl string) (int, int, error) {
+	if helper.IsEmpty(pg) || helper.IsEmpty(ttl) {
+		return 0, 0, fmt.Errorf("Invalid request")
+	}
+
+	page, err := strconv.ParseUint(pg, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Invalid request")
+	}
+
+	total, err := strconv.ParseUint(ttl, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Invalid request")
+	}
+
+	// should be positive number
+	if page < 1 || total < 1 {
+		return 0, 0, fmt.Errorf("Invalid request")
+	}
+
+	if total > 100 {
+		return 0, 0, fmt.Errorf("Max total should be less than or equal to 100")
+	}
+
+	return int(page), int(total), nil
+}
+
+func validateTitle(title string) (string, error) {
+	title = html.EscapeString(helper.Trim(title))
+	if helper.IsEmpty(title) {
+		return title, fmt.Errorf("Title cannot be empty")
+	}
+
+	if len(title) > frm.MaxTitle {
+		return title, fmt.Errorf("Title maximum consist of %d character", frm.MaxTitle)
+	}
+	return title, nil
+}
+
+func validateContent(content string) (string, error) {
+	content = html.EscapeString(helper.Trim(content))
+	if helper.IsEmpty(content) {
+		return content, fmt.Errorf("Content cannot be empty")
+	}
+
+	if len(content) > frm.MaxContent {
+		return content, fmt.Errorf("Content maximum consist of %d character", frm.MaxContent)
+	}
+	return content, nil
+}
+
+// validateRelation converts relation payload into the related table name
+func validateRelation(relation, relationID string) (sql.NullString, sql.NullInt64, error) {
+	var tableName sql.NullString
+	var tableID sql.NullInt64
+
+	if helper.IsEmpty(relation) {
+		return tableName, tableID, nil
+	}
+
+	switch relation {
+	case "tutorial":
+		tableName.String = frm.TableTutorial
+	case "assignment":
+		tableName.String = frm.TableAssignment
+	case "meeting":
+		tableName.String = frm.TableMeeting
+	default:
+		return tableName, tableID, fmt.Errorf("Invalid relation")
+	}
+
+	id, err := strconv.ParseInt(relationID, 10, 64)
+	if err != nil {
+		return tableName, tableID, fmt.Errorf("Invalid relation id")
+	}
+
+	tableName.Valid = true
+	tableID = sql.NullInt64{Valid: true, Int64: id}
+	return tableName, tableID, nil
+}
+
+func validateFilesID(filesID string) ([]string, error) {
+	var ids []string
+	if helper.IsEmpty(filesID) {
+		return ids, nil
+	}
+
+	ids = strings.Split(filesID, "~")
+	for _, val := range ids {
+		if !helper.IsValidFileID(val) {
+			return ids, fmt.Errorf("Invalid fileID format")
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,214 @@
+package grade
+
+import (
+	"strconv"
+
+	asg "github.com/asepnur/meiko_course/src/module/assignment"
+	att "github.com/asepnur/meiko_course/src/module/attendance"
+	cs "github.com/asepnur/meiko_course/src/module/course"
+	gd "github.com/asepnur/meiko_course/src/module/grade"
+	usr "github.com/asepnur/meiko_course/src/module/user"
+)
+
+func handlePolicyResponse(policy gd.Policy) policyResponse {
+	missing := "zero"
+	if policy.Missing == gd.MissingExcluded {
+		missing = "excluded"
+	}
+
+	rounding := "half_up"
+	switch policy.Rounding {
+	case gd.RoundDown:
+		rounding = "down"
+	case gd.RoundUp:
+		rounding = "up"
+	}
+
+	return policyResponse{
+		ScheduleID:  policy.ScheduleID,
+		Missing:     missing,
+		DropLowest:  policy.DropLowest,
+		IsCapped:    policy.IsCapped == gd.StatusCapped,
+		Rounding:    rounding,
+		Decimals:    policy.Decimals,
+		RegradeDays: policy.RegradeDays,
+	}
+}
+
+// handleResults computes the current final grade of every enrolled student of the schedule
+func handleResults(scheduleID int64, policy gd.Policy) ([]studentResult, error) {
+	results := []studentResult{}
+
+	studentsID, err := cs.SelectEnrolledStudentID(scheduleID)
+	if err != nil {
+		return results, err
+	}
+	if len(studentsID) < 1 {
+		return results, nil
+	}
+
+	users, err := usr.RequestID(studentsID, true)
+	if err != nil {
+		return results, err
+	}
+
+	gps, err := cs.SelectGPBySchedule([]int64{scheduleID})
+	if err != nil {
+		return results, err
+	}
+
+	var gpsID []int64
+	for _, gp := range gps {
+		gpsID = append(gpsID, gp.ID)
+	}
+
+	assignments := []asg.Assignment{}
+	if len(gpsID) > 0 {
+		assignments, err = asg.SelectByGP(gpsID, false)
+		if err != nil {
+			return results, err
+		}
+	}
+
+	var asgID []int64
+	gpAsg := map[int64][]asg.Assignment{}
+	for _, val := range assignments {
+		asgID = append(asgID, val.ID)
+		gpAsg[val.GradeParameterID] = append(gpAsg[val.GradeParameterID], val)
+	}
+
+	submitted := []asg.UserAssignment{}
+	if len(asgID) > 0 {
+		submitted, err = asg.SelectSubmittedByAssignment(asgID)
+		if err != nil {
+			return results, err
+		}
+	}
+
+	// user id => assignment id => submission
+	userSubmit := map[int64]map[int64]asg.UserAssignment{}
+	for _, val := range submitted {
+		if _, ok := userSubmit[val.UserID]; !ok {
+			userSubmit[val.UserID] = map[int64]asg.UserAssignment{}
+		}
+		userSubmit[val.UserID][val.AssignmentID] = val
+	}
+
+	for _, user := range users {
+		report, err := att.CountByUserSchedule(user.ID, []int64{scheduleID})
+		if err != nil {
+			return results, err
+		}
+
+		params := gd.Parameters(gps, gpAsg, userSubmit[user.ID], report[scheduleID])
+		results = append(results, studentResult{
+			user:   user,
+			params: params,
+			result: policy.Calculate(params),
+		})
+	}
+	return results, nil
+}
+
+// handleSnapshots converts the computed results into snapshots of the publication
+func handleSnapshots(publicationID int64, results []studentResult, scales []gd.Scale) []gd.Snapshot {
+	snapshots := []gd.Snapshot{}
+	for _, val := range results {
+		scale, _ := gd.Letter(scales, val.result.Total)
+		snapshot := gd.Snapshot{
+			PublicationID: publicationID,
+			UserID:        val.user.ID,
+			Total:         val.result.Total,
+			Letter:        scale.Letter,
+			GradePoint:    scale.GradePoint,
+		}
+		for _, param := range val.params {
+			snapshot.Parameters = append(snapshot.Parameters, gd.SnapshotParameter{
+				PublicationID:    publicationID,
+				UserID:           val.user.ID,
+				GradeParameterID: param.ID,
+				Type:             param.Type,
+				Score:            val.result.Parameters[param.ID],
+			})
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots
+}
+
+// handleStudentResponse builds the student list of the snapshots ordered by the user order
+func handleStudentResponse(snapshots []gd.Snapshot, policy gd.Policy) ([]studentResponse, error) {
+	resp := []studentResponse{}
+	if len(snapshots) < 1 {
+		return resp, nil
+	}
+
+	var usersID []int64
+	userSnapshot := map[int64]gd.Snapshot{}
+	for _, val := range snapshots {
+		usersID = append(usersID, val.UserID)
+		userSnapshot[val.UserID] = val
+	}
+
+	users, err := usr.RequestID(usersID, true)
+	if err != nil {
+		return resp, err
+	}
+
+	for _, user := range users {
+		snapshot, ok := userSnapshot[user.ID]
+		if !ok {
+			continue
+		}
+		resp = append(resp, studentResponse{
+			IdentityCode: user.IdentityCode,
+			Name:         user.Name,
+			Total:        policy.Format(snapshot.Total),
+			Letter:       snapshot.Letter,
+			GradePoint:   strconv.FormatFloat(snapshot.GradePoint, 'f', 2, 64),
+		})
+	}
+	return resp, nil
+}
+
+// handleOverrideResponse builds override history including the name of the admin
+func handleOverrideResponse(overrides []cs.GradeParameterOverride) ([]overrideResponse, error) {
+	resp := []overrideResponse{}
+	if len(overrides) < 1 {
+		return resp, nil
+	}
+
+	var usersID []int64
+	for _, val := range overrides {
+		usersID = append(usersID, val.UserID)
+	}
+	users, err := usr.RequestID(usersID, false)
+	if err != nil {
+		return resp, err
+	}
+	names := map[int64]string{}
+	for _, val := range users {
+		names[val.ID] = val.Name
+	}
+
+	for _, val := range overrides {
+		oldPercentage := "-"
+		if val.OldPercentage.Valid {
+			oldPercentage = strconv.FormatFloat(val.OldPercentage.Float64, 'f', -1, 64)
+		}
+		newPercentage := "-"
+		if val.NewPercentage.Valid {
+			newPercentage = strconv.FormatFloat(val.NewPercentage.Float64, 'f', -1, 64)
+		}
+		resp = append(resp, overrideResponse{
+			Type:          val.Type,
+			OldPercentage: oldPercentage,
+			NewPercentage: newPercentage,
+			Reason:        val.Reason,
+			UserID:        val.UserID,
+			Name:          names[val.UserID],
+			Time:          val.CreatedAt.Unix(),
+		})
+	}
+	return resp, nil
+}
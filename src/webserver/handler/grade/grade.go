@@ -0,0 +1,620 @@
+package grade
+
+import (
+	"net/http"
+
+	asg "github.com/asepnur/meiko_course/src/module/assignment"
+	cs "github.com/asepnur/meiko_course/src/module/course"
+	gd "github.com/asepnur/meiko_course/src/module/grade"
+	"github.com/asepnur/meiko_course/src/util/auth"
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/webserver/template"
+	"github.com/julienschmidt/httprouter"
+)
+
+// ReadPolicyHandler returns the grade policy of the schedule
+func ReadPolicyHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleSchedule, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := scheduleParams{
+		scheduleID: ps.ByName("schedule_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	policy, err := gd.GetPolicy(args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(handlePolicyResponse(policy)))
+	return
+}
+
+// UpdatePolicyHandler sets how missing scores, lowest scores, capping and rounding are handled on the schedule,
+// regrade_days is how many days regrade requests stay open after the final grade is published
+func UpdatePolicyHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleSchedule, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := policyParams{
+		scheduleID:  ps.ByName("schedule_id"),
+		missing:     r.FormValue("missing"),
+		dropLowest:  r.FormValue("drop_lowest"),
+		isCapped:    r.FormValue("is_capped"),
+		rounding:    r.FormValue("rounding"),
+		decimals:    r.FormValue("decimals"),
+		regradeDays: r.FormValue("regrade_days"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	policy := gd.Policy{
+		ScheduleID:  args.scheduleID,
+		Missing:     args.missing,
+		DropLowest:  args.dropLowest,
+		IsCapped:    args.isCapped,
+		Rounding:    args.rounding,
+		Decimals:    args.decimals,
+		RegradeDays: args.regradeDays,
+	}
+	err = gd.UpsertPolicy(policy, nil)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Grade policy has been updated").
+		SetData(handlePolicyResponse(policy)))
+	return
+}
+
+// ReadScaleHandler returns the letter grade scale of the schedule
+func ReadScaleHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleSchedule, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := scheduleParams{
+		scheduleID: ps.ByName("schedule_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	scales, err := gd.SelectScale(args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	resp := []scale{}
+	for _, val := range scales {
+		resp = append(resp, scale{
+			Letter:     val.Letter,
+			MinScore:   val.MinScore,
+			GradePoint: val.GradePoint,
+		})
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// UpdateScaleHandler replaces the letter grade scale of the schedule, published grade is not affected until it is published again
+func UpdateScaleHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleSchedule, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := scaleParams{
+		scheduleID: ps.ByName("schedule_id"),
+		scales:     r.FormValue("scales"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = gd.ReplaceScale(args.scheduleID, args.scales, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	tx.Commit()
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Grade scale has been updated"))
+	return
+}
+
+// ReadPublicationHandler returns the published final grade, or a preview of it when it has not been published
+func ReadPublicationHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleSchedule, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := scheduleParams{
+		scheduleID: ps.ByName("schedule_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	policy, err := gd.GetPolicy(args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	publication, err := gd.GetPublication(args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	resp := publicationResponse{}
+	var snapshots []gd.Snapshot
+	if publication != nil && publication.Status == gd.StatusPublished {
+		resp.IsPublished = true
+		resp.PublishedBy = publication.PublishedBy
+		resp.PublishedAt = publication.CreatedAt.Unix()
+		snapshots, err = gd.SelectSnapshot([]int64{publication.ID}, nil)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	} else {
+		if publication != nil {
+			resp.Reason = publication.Reason.String
+		}
+
+		scales, err := gd.SelectScale(args.scheduleID)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+
+		results, err := handleResults(args.scheduleID, policy)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		snapshots = handleSnapshots(0, results, scales)
+	}
+
+	resp.Students, err = handleStudentResponse(snapshots, policy)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// PublishHandler freezes the current final grade of every student into a snapshot which is shown to the students
+func PublishHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleSchedule, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := scheduleParams{
+		scheduleID: ps.ByName("schedule_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	publication, err := gd.GetPublication(args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if publication != nil && publication.Status == gd.StatusPublished {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Final grade has been published, unpublish it first"))
+		return
+	}
+
+	status, err := cs.GetGPStatus(args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if status != cs.GradeParameterPublished {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Grade parameter must be published before publishing final grade"))
+		return
+	}
+
+	gps, err := cs.SelectGPBySchedule([]int64{args.scheduleID})
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = cs.ValidateGradeParameters(gps)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	policy, err := gd.GetPolicy(args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	scales, err := gd.SelectScale(args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	results, err := handleResults(args.scheduleID, policy)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	publicationID, err := gd.InsertPublication(args.scheduleID, sess.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = gd.InsertSnapshot(handleSnapshots(publicationID, results, scales), tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	tx.Commit()
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Final grade has been published"))
+	return
+}
+
+// UnpublishHandler hides the published final grade from the students, reason is required
+func UnpublishHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleSchedule, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := unpublishParams{
+		scheduleID: ps.ByName("schedule_id"),
+		reason:     r.FormValue("reason"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	publication, err := gd.GetPublication(args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if publication == nil || publication.Status != gd.StatusPublished {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Final grade has not been published"))
+		return
+	}
+
+	err = gd.UnpublishPublication(publication.ID, sess.ID, args.reason, nil)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Final grade has been unpublished"))
+	return
+}
+
+// ReadParameterHandler returns grade parameters of the schedule with its draft or published status and override history
+func ReadParameterHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleSchedule, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := scheduleParams{
+		scheduleID: ps.ByName("schedule_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	status, err := cs.GetGPStatus(args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	gps, err := cs.SelectGPBySchedule([]int64{args.scheduleID})
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	overrides, err := cs.SelectGPOverride(args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	resp := parameterResponse{
+		Status:     "draft",
+		IsValid:    true,
+		Parameters: []parameter{},
+	}
+	if status == cs.GradeParameterPublished {
+		resp.Status = "published"
+	}
+
+	var gpsID []int64
+	for _, val := range gps {
+		gpsID = append(gpsID, val.ID)
+		resp.Total += float64(val.Percentage)
+		resp.Parameters = append(resp.Parameters, parameter{
+			Type:       val.Type,
+			Percentage: val.Percentage,
+		})
+	}
+	resp.IsScored = asg.IsScoredByGradeParameter(gpsID)
+
+	err = cs.ValidateGradeParameters(gps)
+	if err != nil {
+		resp.IsValid = false
+		resp.Error = err.Error()
+	}
+
+	resp.Overrides, err = handleOverrideResponse(overrides)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// PublishParameterHandler marks the grade parameters as published, active parameters must total exactly 100
+func PublishParameterHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleSchedule, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := scheduleParams{
+		scheduleID: ps.ByName("schedule_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	gps, err := cs.SelectGPBySchedule([]int64{args.scheduleID})
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = cs.ValidateGradeParameters(gps)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	err = cs.UpdateGPStatus(args.scheduleID, cs.GradeParameterPublished, nil)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Grade parameter has been published"))
+	return
+}
@@ -0,0 +1,117 @@
+package grade
+
+import (
+	gd "github.com/asepnur/meiko_course/src/module/grade"
+	usr "github.com/asepnur/meiko_course/src/module/user"
+)
+
+type policyParams struct {
+	scheduleID  string
+	missing     string
+	dropLowest  string
+	isCapped    string
+	rounding    string
+	decimals    string
+	regradeDays string
+}
+
+type policyArgs struct {
+	scheduleID  int64
+	missing     int8
+	dropLowest  int8
+	isCapped    int8
+	rounding    int8
+	decimals    int8
+	regradeDays int16
+}
+
+type scheduleParams struct {
+	scheduleID string
+}
+
+type scheduleArgs struct {
+	scheduleID int64
+}
+
+type policyResponse struct {
+	ScheduleID  int64  `json:"schedule_id"`
+	Missing     string `json:"missing"`
+	DropLowest  int8   `json:"drop_lowest"`
+	IsCapped    bool   `json:"is_capped"`
+	Rounding    string `json:"rounding"`
+	Decimals    int8   `json:"decimals"`
+	RegradeDays int16  `json:"regrade_days"`
+}
+
+type scaleParams struct {
+	scheduleID string
+	scales     string
+}
+
+type scaleArgs struct {
+	scheduleID int64
+	scales     []gd.Scale
+}
+
+type scale struct {
+	Letter     string  `json:"letter"`
+	MinScore   float64 `json:"min_score"`
+	GradePoint float64 `json:"grade_point"`
+}
+
+type unpublishParams struct {
+	scheduleID string
+	reason     string
+}
+
+type unpublishArgs struct {
+	scheduleID int64
+	reason     string
+}
+
+type studentResult struct {
+	user   usr.UserReq
+	params []gd.Parameter
+	result gd.Result
+}
+
+type publicationResponse struct {
+	IsPublished bool              `json:"is_published"`
+	PublishedBy int64             `json:"published_by,omitempty"`
+	PublishedAt int64             `json:"published_at,omitempty"`
+	Reason      string            `json:"reason,omitempty"`
+	Students    []studentResponse `json:"students"`
+}
+
+type studentResponse struct {
+	IdentityCode int64  `json:"identity_code"`
+	Name         string `json:"name"`
+	Total        string `json:"total"`
+	Letter       string `json:"letter"`
+	GradePoint   string `json:"grade_point"`
+}
+
+type parameterResponse struct {
+	Status     string             `json:"status"`
+	IsValid    bool               `json:"is_valid"`
+	Error      string             `json:"error,omitempty"`
+	IsScored   bool               `json:"is_scored"`
+	Total      float64            `json:"total"`
+	Parameters []parameter        `json:"parameters"`
+	Overrides  []overrideResponse `json:"overrides"`
+}
+
+type parameter struct {
+	Type       string  `json:"type"`
+	Percentage float32 `json:"percentage"`
+}
+
+type overrideResponse struct {
+	Type          string `json:"type"`
+	OldPercentage string `json:"old_percentage"`
+	NewPercentage string `json:"new_percentage"`
+	Reason        string `json:"reason"`
+	UserID        int64  `json:"user_id"`
+	Name          string `json:"name"`
+	Time          int64  `json:"time"`
+}
@@ -0,0 +1,155 @@
+package grade
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	gd "github.com/asepnur/meiko_course/src/module/grade"
+	"github.com/asepnur/meiko_course/src/util/helper"
+)
+
+func (params scheduleParams) validate() (scheduleArgs, error) {
+	var args scheduleArgs
+	scheduleID, err := strconv.ParseInt(params.scheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule id")
+	}
+	return scheduleArgs{scheduleID: scheduleID}, nil
+}
+
+func (params policyParams) validate() (policyArgs, error) {
+	var args policyArgs
+	scheduleID, err := strconv.ParseInt(params.scheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule id")
+	}
+
+	var missing int8
+	switch params.missing {
+	case "zero":
+		missing = gd.MissingZero
+	case "excluded":
+		missing = gd.MissingExcluded
+	default:
+		return args, fmt.Errorf("Missing must be zero or excluded")
+	}
+
+	var dropLowest int64
+	if !helper.IsEmpty(params.dropLowest) {
+		dropLowest, err = strconv.ParseInt(params.dropLowest, 10, 8)
+		if err != nil || dropLowest < 0 || dropLowest > gd.MaxDropLowest {
+			return args, fmt.Errorf("Drop lowest must be between 0 and %d", gd.MaxDropLowest)
+		}
+	}
+
+	var isCapped int8 = gd.StatusUncapped
+	if !helper.IsEmpty(params.isCapped) {
+		capped, err := strconv.ParseBool(params.isCapped)
+		if err != nil {
+			return args, fmt.Errorf("Invalid is capped")
+		}
+		if capped {
+			isCapped = gd.StatusCapped
+		}
+	}
+
+	var rounding int8
+	switch params.rounding {
+	case "half_up", "":
+		rounding = gd.RoundHalfUp
+	case "down":
+		rounding = gd.RoundDown
+	case "up":
+		rounding = gd.RoundUp
+	default:
+		return args, fmt.Errorf("Rounding must be half_up, down or up")
+	}
+
+	var decimals int64 = gd.DefaultDecimals
+	if !helper.IsEmpty(params.decimals) {
+		decimals, err = strconv.ParseInt(params.decimals, 10, 8)
+		if err != nil || decimals < 0 || decimals > gd.MaxDecimals {
+			return args, fmt.Errorf("Decimals must be between 0 and %d", gd.MaxDecimals)
+		}
+	}
+
+	var regradeDays int64 = gd.DefaultRegradeDays
+	if !helper.IsEmpty(params.regradeDays) {
+		regradeDays, err = strconv.ParseInt(params.regradeDays, 10, 16)
+		if err != nil || regradeDays < 0 || regradeDays > gd.MaxRegradeDays {
+			return args, fmt.Errorf("Regrade days must be between 0 and %d", gd.MaxRegradeDays)
+		}
+	}
+
+	return policyArgs{
+		scheduleID:  scheduleID,
+		missing:     missing,
+		dropLowest:  int8(dropLowest),
+		isCapped:    isCapped,
+		rounding:    rounding,
+		decimals:    int8(decimals),
+		regradeDays: int16(regradeDays),
+	}, nil
+}
+
+func (params scaleParams) validate() (scaleArgs, error) {
+	var args scaleArgs
+	scheduleID, err := strconv.ParseInt(params.scheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule id")
+	}
+
+	if helper.IsEmpty(params.scales) {
+		return args, fmt.Errorf("Scales can not be empty")
+	}
+
+	var input []scale
+	err = json.Unmarshal([]byte(params.scales), &input)
+	if err != nil {
+		return args, fmt.Errorf("Invalid scales format")
+	}
+
+	var scales []gd.Scale
+	for _, val := range input {
+		scales = append(scales, gd.Scale{
+			ScheduleID: scheduleID,
+			Letter:     strings.ToUpper(helper.Trim(val.Letter)),
+			MinScore:   val.MinScore,
+			GradePoint: val.GradePoint,
+		})
+	}
+
+	err = gd.ValidateScales(scales)
+	if err != nil {
+		return args, err
+	}
+
+	return scaleArgs{
+		scheduleID: scheduleID,
+		scales:     scales,
+	}, nil
+}
+
+func (params unpublishParams) validate() (unpublishArgs, error) {
+	var args unpublishArgs
+	scheduleID, err := strconv.ParseInt(params.scheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule id")
+	}
+
+	reason := html.EscapeString(helper.Trim(params.reason))
+	if helper.IsEmpty(reason) {
+		return args, fmt.Errorf("Reason can not be empty")
+	}
+	if len(reason) > gd.MaxReason {
+		return args, fmt.Errorf("Reason maximum consist of %d character", gd.MaxReason)
+	}
+
+	return unpublishArgs{
+		scheduleID: scheduleID,
+		reason:     reason,
+	}, nil
+}
@@ -0,0 +1,156 @@
+package group
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	asg "github.com/asepnur/meiko_course/src/module/assignment"
+	cs "github.com/asepnur/meiko_course/src/module/course"
+	usr "github.com/asepnur/meiko_course/src/module/user"
+)
+
+// handleAssignment makes sure the assignment belongs to the schedule when the groups are defined for an assignment
+func handleAssignment(scheduleID int64, assignmentID sql.NullInt64) (int, error) {
+	if !assignmentID.Valid {
+		return http.StatusOK, nil
+	}
+
+	assignment, err := asg.GetByID(assignmentID.Int64)
+	if err != nil {
+		return http.StatusNotFound, fmt.Errorf("Assignment does not exist")
+	}
+
+	schID, err := cs.GetScheduleIDByGP(assignment.GradeParameterID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if schID != scheduleID {
+		return http.StatusBadRequest, fmt.Errorf("Assignment does not belong to the schedule")
+	}
+	return http.StatusOK, nil
+}
+
+// handleMembers converts identity codes into user id, every member must be enrolled in the schedule
+// and must not be a member of another group in the same scope
+func handleMembers(scheduleID int64, assignmentID sql.NullInt64, groupID int64, identityCodes []int64) ([]int64, int, error) {
+	studentsID, err := cs.SelectEnrolledStudentID(scheduleID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	students := map[int64]int64{}
+	if len(studentsID) > 0 {
+		users, err := usr.RequestID(studentsID, false)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		for _, val := range users {
+			students[val.IdentityCode] = val.ID
+		}
+	}
+
+	var usersID []int64
+	for _, val := range identityCodes {
+		userID, ok := students[val]
+		if !ok {
+			return nil, http.StatusBadRequest, fmt.Errorf("Student %d is not enrolled in this schedule", val)
+		}
+		usersID = append(usersID, userID)
+	}
+
+	groups, err := asg.SelectGroup(scheduleID, assignmentID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	var groupsID []int64
+	groupName := map[int64]string{}
+	for _, val := range groups {
+		if val.ID == groupID {
+			continue
+		}
+		groupsID = append(groupsID, val.ID)
+		groupName[val.ID] = val.Name
+	}
+
+	members, err := asg.SelectGroupMember(groupsID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	for _, val := range members {
+		for i, userID := range usersID {
+			if val.UserID == userID {
+				return nil, http.StatusBadRequest, fmt.Errorf("Student %d is already a member of %s", identityCodes[i], groupName[val.GroupID])
+			}
+		}
+	}
+
+	return usersID, http.StatusOK, nil
+}
+
+// handleGroup returns the group when it belongs to the schedule
+func handleGroup(scheduleID, groupID int64) (*asg.Group, int, error) {
+	group, err := asg.GetGroup(groupID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if group == nil || group.ScheduleID != scheduleID {
+		return nil, http.StatusNotFound, fmt.Errorf("Group does not exist")
+	}
+	return group, http.StatusOK, nil
+}
+
+// handleGroupResponse builds the group list with the members of every group
+func handleGroupResponse(groups []asg.Group) ([]groupResponse, error) {
+	resp := []groupResponse{}
+	if len(groups) < 1 {
+		return resp, nil
+	}
+
+	var groupsID []int64
+	for _, val := range groups {
+		groupsID = append(groupsID, val.ID)
+	}
+
+	members, err := asg.SelectGroupMember(groupsID)
+	if err != nil {
+		return resp, err
+	}
+
+	var usersID []int64
+	groupMembers := map[int64][]int64{}
+	for _, val := range members {
+		usersID = append(usersID, val.UserID)
+		groupMembers[val.GroupID] = append(groupMembers[val.GroupID], val.UserID)
+	}
+
+	userMap := map[int64]usr.UserReq{}
+	if len(usersID) > 0 {
+		users, err := usr.RequestID(usersID, false)
+		if err != nil {
+			return resp, err
+		}
+		for _, val := range users {
+			userMap[val.ID] = val
+		}
+	}
+
+	for _, group := range groups {
+		rMembers := []memberResponse{}
+		for _, userID := range groupMembers[group.ID] {
+			rMembers = append(rMembers, memberResponse{
+				IdentityCode: userMap[userID].IdentityCode,
+				Name:         userMap[userID].Name,
+			})
+		}
+		resp = append(resp, groupResponse{
+			ID:           group.ID,
+			Name:         group.Name,
+			AssignmentID: group.AssignmentID.Int64,
+			IsSubmitted:  asg.IsGroupSubmitted(group.ID),
+			Members:      rMembers,
+		})
+	}
+	return resp, nil
+}
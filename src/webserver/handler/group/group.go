@@ -0,0 +1,318 @@
+package group
+
+import (
+	"net/http"
+
+	asg "github.com/asepnur/meiko_course/src/module/assignment"
+	cs "github.com/asepnur/meiko_course/src/module/course"
+	"github.com/asepnur/meiko_course/src/util/auth"
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/asepnur/meiko_course/src/webserver/template"
+	"github.com/julienschmidt/httprouter"
+)
+
+// ReadHandler returns groups of the schedule, or groups of a single assignment when assignment_id is set
+func ReadHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := readParams{
+		scheduleID:   ps.ByName("schedule_id"),
+		assignmentID: r.FormValue("assignment_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	code, err := handleAssignment(args.scheduleID, args.assignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	groups, err := asg.SelectGroup(args.scheduleID, args.assignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	resp, err := handleGroupResponse(groups)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// CreateHandler creates a group of the schedule, the group only belongs to the assignment when assignment_id is set
+func CreateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleCreate, auth.RoleXCreate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := createParams{
+		scheduleID:    ps.ByName("schedule_id"),
+		assignmentID:  r.FormValue("assignment_id"),
+		name:          r.FormValue("name"),
+		identityCodes: r.FormValue("identity_code"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	code, err := handleAssignment(args.scheduleID, args.assignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	usersID, code, err := handleMembers(args.scheduleID, args.assignmentID, 0, args.identityCodes)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	id, err := asg.InsertGroup(args.scheduleID, args.assignmentID, args.name, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = asg.ReplaceGroupMember(id, usersID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Group created successfully"))
+	return
+}
+
+// UpdateHandler renames the group and replaces its members, members can not be changed after the group has submitted
+func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := updateParams{
+		scheduleID:    ps.ByName("schedule_id"),
+		groupID:       ps.ByName("group_id"),
+		name:          r.FormValue("name"),
+		identityCodes: r.FormValue("identity_code"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	group, code, err := handleGroup(args.scheduleID, args.groupID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	usersID, code, err := handleMembers(args.scheduleID, group.AssignmentID, group.ID, args.identityCodes)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	members, err := asg.SelectGroupMember([]int64{group.ID})
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	isChanged := len(members) != len(usersID)
+	for _, val := range members {
+		if !helper.Int64InSlice(val.UserID, usersID) {
+			isChanged = true
+			break
+		}
+	}
+
+	if isChanged && asg.IsGroupSubmitted(group.ID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Members of a group which has submitted can not be changed"))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = asg.UpdateGroup(group.ID, args.name, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if isChanged {
+		err = asg.ReplaceGroupMember(group.ID, usersID, tx)
+		if err != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Group updated successfully"))
+	return
+}
+
+// DeleteHandler deletes the group when it has never submitted
+func DeleteHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleDelete, auth.RoleXDelete) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := deleteParams{
+		scheduleID: ps.ByName("schedule_id"),
+		groupID:    ps.ByName("group_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	group, code, err := handleGroup(args.scheduleID, args.groupID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	if asg.IsGroupSubmitted(group.ID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Group which has submitted can not be deleted"))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = asg.DeleteGroup(group.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Group deleted successfully"))
+	return
+}
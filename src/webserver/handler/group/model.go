@@ -0,0 +1,66 @@
+package group
+
+import (
+	"database/sql"
+)
+
+type readParams struct {
+	scheduleID   string
+	assignmentID string
+}
+
+type readArgs struct {
+	scheduleID   int64
+	assignmentID sql.NullInt64
+}
+
+type createParams struct {
+	scheduleID    string
+	assignmentID  string
+	name          string
+	identityCodes string
+}
+
+type createArgs struct {
+	scheduleID    int64
+	assignmentID  sql.NullInt64
+	name          string
+	identityCodes []int64
+}
+
+type updateParams struct {
+	scheduleID    string
+	groupID       string
+	name          string
+	identityCodes string
+}
+
+type updateArgs struct {
+	scheduleID    int64
+	groupID       int64
+	name          string
+	identityCodes []int64
+}
+
+type deleteParams struct {
+	scheduleID string
+	groupID    string
+}
+
+type deleteArgs struct {
+	scheduleID int64
+	groupID    int64
+}
+
+type groupResponse struct {
+	ID           int64            `json:"id"`
+	Name         string           `json:"name"`
+	AssignmentID int64            `json:"assignment_id,omitempty"`
+	IsSubmitted  bool             `json:"is_submitted"`
+	Members      []memberResponse `json:"members"`
+}
+
+type memberResponse struct {
+	IdentityCode int64  `json:"identity_code"`
+	Name         string `json:"name"`
+}
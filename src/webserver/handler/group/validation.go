@@ -0,0 +1,150 @@
+package group
+
+import (
+	"database/sql"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	asg "github.com/asepnur/meiko_course/src/module/assignment"
+	"github.com/asepnur/meiko_course/src/util/helper"
+)
+
+func (params readParams) validate() (readArgs, error) {
+	var args readArgs
+	scheduleID, err := strconv.ParseInt(params.scheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule id")
+	}
+
+	assignmentID, err := validateAssignmentID(params.assignmentID)
+	if err != nil {
+		return args, err
+	}
+
+	return readArgs{
+		scheduleID:   scheduleID,
+		assignmentID: assignmentID,
+	}, nil
+}
+
+func (params createParams) validate() (createArgs, error) {
+	var args createArgs
+	scheduleID, err := strconv.ParseInt(params.scheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule id")
+	}
+
+	assignmentID, err := validateAssignmentID(params.assignmentID)
+	if err != nil {
+		return args, err
+	}
+
+	name, err := validateName(params.name)
+	if err != nil {
+		return args, err
+	}
+
+	identityCodes, err := validateIdentityCodes(params.identityCodes)
+	if err != nil {
+		return args, err
+	}
+
+	return createArgs{
+		scheduleID:    scheduleID,
+		assignmentID:  assignmentID,
+		name:          name,
+		identityCodes: identityCodes,
+	}, nil
+}
+
+func (params updateParams) validate() (updateArgs, error) {
+	var args updateArgs
+	scheduleID, err := strconv.ParseInt(params.scheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule id")
+	}
+
+	groupID, err := strconv.ParseInt(params.groupID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid group id")
+	}
+
+	name, err := validateName(params.name)
+	if err != nil {
+		return args, err
+	}
+
+	identityCodes, err := validateIdentityCodes(params.identityCodes)
+	if err != nil {
+		return args, err
+	}
+
+	return updateArgs{
+		scheduleID:    scheduleID,
+		groupID:       groupID,
+		name:          name,
+		identityCodes: identityCodes,
+	}, nil
+}
+
+func (params deleteParams) validate() (deleteArgs, error) {
+	var args deleteArgs
+	scheduleID, err := strconv.ParseInt(params.scheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule id")
+	}
+
+	groupID, err := strconv.ParseInt(params.groupID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid group id")
+	}
+
+	return deleteArgs{
+		scheduleID: scheduleID,
+		groupID:    groupID,
+	}, nil
+}
+
+func validateAssignmentID(assignmentID string) (sql.NullInt64, error) {
+	if helper.IsEmpty(assignmentID) {
+		return sql.NullInt64{}, nil
+	}
+	id, err := strconv.ParseInt(assignmentID, 10, 64)
+	if err != nil {
+		return sql.NullInt64{}, fmt.Errorf("Invalid assignment id")
+	}
+	return sql.NullInt64{Valid: true, Int64: id}, nil
+}
+
+func validateName(name string) (string, error) {
+	name = html.EscapeString(helper.Trim(name))
+	if helper.IsEmpty(name) {
+		return name, fmt.Errorf("Name can not be empty")
+	}
+	if len(name) > asg.MaxGroupName {
+		return name, fmt.Errorf("Name maximum consist of %d character", asg.MaxGroupName)
+	}
+	return name, nil
+}
+
+func validateIdentityCodes(identityCodes string) ([]int64, error) {
+	identityCodes = helper.Trim(identityCodes)
+	if helper.IsEmpty(identityCodes) {
+		return nil, fmt.Errorf("Group must have at least one member")
+	}
+
+	var codes []int64
+	for _, val := range strings.Split(identityCodes, "~") {
+		code, err := strconv.ParseInt(helper.Trim(val), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid identity code %s", val)
+		}
+		if helper.Int64InSlice(code, codes) {
+			return nil, fmt.Errorf("Duplicate identity code %d", code)
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
@@ -0,0 +1,127 @@
+package group
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func Test_createParams_validate(t *testing.T) {
+	type fields struct {
+		scheduleID    string
+		assignmentID  string
+		name          string
+		identityCodes string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		want    createArgs
+		wantErr bool
+	}{
+		{
+			name:    "All empty",
+			fields:  fields{},
+			want:    createArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Invalid assignment id",
+			fields: fields{
+				scheduleID:    "1",
+				assignmentID:  "abc",
+				name:          "Group 1",
+				identityCodes: "140810140060",
+			},
+			want:    createArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Empty name",
+			fields: fields{
+				scheduleID:    "1",
+				name:          "   ",
+				identityCodes: "140810140060",
+			},
+			want:    createArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Name overlength",
+			fields: fields{
+				scheduleID:    "1",
+				name:          "uvuvwevwevwe onyetenyevwe ughemubwem ughemubwem ossasossas",
+				identityCodes: "140810140060",
+			},
+			want:    createArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Without member",
+			fields: fields{
+				scheduleID: "1",
+				name:       "Group 1",
+			},
+			want:    createArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Duplicate member",
+			fields: fields{
+				scheduleID:    "1",
+				name:          "Group 1",
+				identityCodes: "140810140060~140810140060",
+			},
+			want:    createArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Schedule group",
+			fields: fields{
+				scheduleID:    "1",
+				name:          "Group <1>",
+				identityCodes: "140810140060~140810140061",
+			},
+			want: createArgs{
+				scheduleID:    1,
+				name:          "Group &lt;1&gt;",
+				identityCodes: []int64{140810140060, 140810140061},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Assignment group",
+			fields: fields{
+				scheduleID:    "1",
+				assignmentID:  "2",
+				name:          "Group 1",
+				identityCodes: "140810140060",
+			},
+			want: createArgs{
+				scheduleID:    1,
+				assignmentID:  sql.NullInt64{Valid: true, Int64: 2},
+				name:          "Group 1",
+				identityCodes: []int64{140810140060},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := createParams{
+				scheduleID:    tt.fields.scheduleID,
+				assignmentID:  tt.fields.assignmentID,
+				name:          tt.fields.name,
+				identityCodes: tt.fields.identityCodes,
+			}
+			got, err := params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("createParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("createParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
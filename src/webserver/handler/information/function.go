@@ -0,0 +1,144 @@
+package information
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	cs "github.com/asepnur/meiko_course/src/module/course"
+	fl "github.com/asepnur/meiko_course/src/module/file"
+	inf "github.com/asepnur/meiko_course/src/module/information"
+	"github.com/asepnur/meiko_course/src/util/auth"
+)
+
+// handleAccess checks whether user may manage informations of the schedule,
+// global informations (without schedule) require the X role
+func handleAccess(sess *auth.User, scheduleID sql.NullInt64, xRole, role string) bool {
+	if !scheduleID.Valid {
+		return sess.IsHasRoles(auth.ModuleInformation, xRole)
+	}
+	return sess.IsHasRoles(auth.ModuleInformation, xRole, role) && cs.IsAssistant(sess.ID, scheduleID.Int64)
+}
+
+// handleThumbnailID returns thumbnail id of main image id, image is uploaded as <id>.1 and <id>.2
+func handleThumbnailID(imageID string) string {
+	return strings.TrimSuffix(imageID, ".1") + ".2"
+}
+
+// handleImageRelation relates the main image and its thumbnail to the information
+func handleImageRelation(imageID, tableID string, tx *sqlx.Tx) error {
+	if !strings.HasSuffix(imageID, ".1") {
+		return fmt.Errorf("Invalid image id")
+	}
+
+	err := fl.UpdateRelation(imageID, fl.TypInfPict, tableID, tx)
+	if err != nil {
+		return err
+	}
+
+	return fl.UpdateRelation(handleThumbnailID(imageID), fl.TypInfPictThumb, tableID, tx)
+}
+
+// handleResponse builds response of informations including the files, images and course name
+func handleResponse(informations []inf.Information) ([]readInformation, error) {
+
+	resp := []readInformation{}
+	if len(informations) < 1 {
+		return resp, nil
+	}
+
+	var tablesID []string
+	var schedulesID []int64
+	for _, val := range informations {
+		tablesID = append(tablesID, strconv.FormatInt(val.ID, 10))
+		if val.ScheduleID.Valid {
+			schedulesID = append(schedulesID, val.ScheduleID.Int64)
+		}
+	}
+
+	files, err := fl.SelectByRelation(fl.TypInf, tablesID, nil)
+	if err != nil {
+		return resp, err
+	}
+
+	mapFiles := map[string][]fileResponse{}
+	for _, val := range files {
+		mapFiles[val.TableID.String] = append(mapFiles[val.TableID.String], fileResponse{
+			ID:   val.ID,
+			Name: fmt.Sprintf("%s.%s", val.Name, val.Extension),
+			URL:  fmt.Sprintf("/api/v1/file/information/%s.%s", val.ID, val.Extension),
+		})
+	}
+
+	images, err := fl.SelectByRelation(fl.TypInfPict, tablesID, nil)
+	if err != nil {
+		return resp, err
+	}
+
+	mapImages := map[string]fl.File{}
+	for _, val := range images {
+		mapImages[val.TableID.String] = val
+	}
+
+	courseName := map[int64]string{}
+	if len(schedulesID) > 0 {
+		courses, err := cs.SelectJoinScheduleCourse(schedulesID)
+		if err != nil {
+			return resp, err
+		}
+		for _, val := range courses {
+			courseName[val.ID] = val.Name
+		}
+	}
+
+	for _, val := range informations {
+		id := strconv.FormatInt(val.ID, 10)
+
+		desc := "-"
+		if val.Description.Valid {
+			desc = val.Description.String
+		}
+
+		imageURL := fl.NoImgAvailable
+		thumbURL := fl.NoImgAvailable
+		if img, ok := mapImages[id]; ok {
+			imageURL = fmt.Sprintf("/api/v1/file/information/%s.%s", img.ID, img.Extension)
+			thumbURL = fmt.Sprintf("/api/v1/file/information/%s.%s", handleThumbnailID(img.ID), img.Extension)
+		}
+
+		filesResp := mapFiles[id]
+		if filesResp == nil {
+			filesResp = []fileResponse{}
+		}
+
+		var publishAt, unpublishAt *time.Time
+		if val.PublishAt.Valid {
+			publishAt = &val.PublishAt.Time
+		}
+		if val.UnpublishAt.Valid {
+			unpublishAt = &val.UnpublishAt.Time
+		}
+
+		resp = append(resp, readInformation{
+			ID:          val.ID,
+			Title:       val.Title,
+			Description: desc,
+			ScheduleID:  val.ScheduleID.Int64,
+			CourseName:  courseName[val.ScheduleID.Int64],
+			Status:      val.Status,
+			IsPinned:    val.IsPinned == inf.StatusPinned,
+			ImageURL:    imageURL,
+			ThumbURL:    thumbURL,
+			Files:       filesResp,
+			Time:        val.CreatedAt.Unix(),
+			UpdatedAt:   val.UpdatedAt.Unix(),
+			PublishAt:   publishAt,
+			UnpublishAt: unpublishAt,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,547 @@
+package information
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	cs "github.com/asepnur/meiko_course/src/module/course"
+	fl "github.com/asepnur/meiko_course/src/module/file"
+	inf "github.com/asepnur/meiko_course/src/module/information"
+	"github.com/asepnur/meiko_course/src/util/auth"
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/util/publish"
+	"github.com/asepnur/meiko_course/src/webserver/template"
+	"github.com/julienschmidt/httprouter"
+)
+
+// ReadHandler is used by assistant for listing informations of a schedule, global informations are listed when schedule_id is empty
+func ReadHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := readParams{
+		scheduleID: r.FormValue("schedule_id"),
+		page:       r.FormValue("pg"),
+		total:      r.FormValue("ttl"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid Request"))
+		return
+	}
+
+	if args.total > 100 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Max total should be less than or equal to 100"))
+		return
+	}
+
+	if !handleAccess(sess, args.scheduleID, auth.RoleXRead, auth.RoleRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You are not authorized"))
+		return
+	}
+
+	offset := (args.page - 1) * args.total
+	informations, count, err := inf.SelectByPage(args.scheduleID, args.total, offset, true)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	respInformation, err := handleResponse(informations)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	totalPage := count / args.total
+	if count%args.total > 0 {
+		totalPage++
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(readResponse{
+			Page:         args.page,
+			TotalPage:    totalPage,
+			Informations: respInformation,
+		}))
+	return
+}
+
+// ReadDetailHandler ...
+func ReadDetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := readDetailParams{
+		id: ps.ByName("information_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid Request"))
+		return
+	}
+
+	information, err := inf.GetByID(args.id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusNoContent))
+			return
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if !handleAccess(sess, information.ScheduleID, auth.RoleXRead, auth.RoleRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You are not authorized"))
+		return
+	}
+
+	resp, err := handleResponse([]inf.Information{information})
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp[0]))
+	return
+}
+
+// CreateHandler ...
+func CreateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := createParams{
+		title:       r.FormValue("title"),
+		description: r.FormValue("description"),
+		scheduleID:  r.FormValue("schedule_id"),
+		status:      r.FormValue("status"),
+		filesID:     r.FormValue("files_id"),
+		imageID:     r.FormValue("image_id"),
+		publishAt:   r.FormValue("publish_at"),
+		unpublishAt: r.FormValue("unpublish_at"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !handleAccess(sess, args.scheduleID, auth.RoleXCreate, auth.RoleCreate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	id, err := inf.Insert(args.title, args.description, args.scheduleID, args.status, sess.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = inf.UpdatePublish(id, args.publishAt, args.unpublishAt, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	tableID := strconv.FormatInt(id, 10)
+	for _, fileID := range args.filesID {
+		if fl.UpdateRelation(fileID, fl.TypInf, tableID, tx) != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusBadRequest).
+				AddError("Wrong File ID"))
+			return
+		}
+	}
+
+	if len(args.imageID) > 0 {
+		if handleImageRelation(args.imageID, tableID, tx) != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusBadRequest).
+				AddError("Wrong Image ID"))
+			return
+		}
+	}
+
+	tx.Commit()
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Information created successfully"))
+	return
+}
+
+// UpdateHandler ...
+func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := updateParams{
+		id:          ps.ByName("information_id"),
+		title:       r.FormValue("title"),
+		description: r.FormValue("description"),
+		status:      r.FormValue("status"),
+		filesID:     r.FormValue("files_id"),
+		imageID:     r.FormValue("image_id"),
+		publishAt:   r.FormValue("publish_at"),
+		unpublishAt: r.FormValue("unpublish_at"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	information, err := inf.GetByID(args.id)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid Request"))
+		return
+	}
+
+	if !handleAccess(sess, information.ScheduleID, auth.RoleXUpdate, auth.RoleUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	tableID := strconv.FormatInt(information.ID, 10)
+	image, err := fl.GetByRelation(fl.TypInfPict, tableID)
+	if err != nil && err != sql.ErrNoRows {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = inf.Update(information.ID, args.title, args.description, args.status, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = inf.UpdatePublish(information.ID, args.publishAt, args.unpublishAt, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if fl.ReplaceRelation(fl.TypInf, args.filesID, tableID, tx) != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Wrong File ID"))
+		return
+	}
+
+	if image.ID != args.imageID {
+		err = fl.DeleteByRelation(fl.TypInfPict, tableID, tx)
+		if err == nil {
+			err = fl.DeleteByRelation(fl.TypInfPictThumb, tableID, tx)
+		}
+		if err != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+
+		if len(args.imageID) > 0 {
+			if handleImageRelation(args.imageID, tableID, tx) != nil {
+				tx.Rollback()
+				template.RenderJSONResponse(w, new(template.Response).
+					SetCode(http.StatusBadRequest).
+					AddError("Wrong Image ID"))
+				return
+			}
+		}
+	}
+
+	tx.Commit()
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Information updated successfully"))
+	return
+}
+
+// DeleteHandler ...
+func DeleteHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := deleteParams{
+		id: ps.ByName("information_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid Request"))
+		return
+	}
+
+	information, err := inf.GetByID(args.id)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid Request"))
+		return
+	}
+
+	if !handleAccess(sess, information.ScheduleID, auth.RoleXDelete, auth.RoleDelete) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	tableID := strconv.FormatInt(information.ID, 10)
+
+	tx := conn.DB.MustBegin()
+	err = inf.Delete(information.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	for _, typ := range []string{fl.TypInf, fl.TypInfPict, fl.TypInfPictThumb} {
+		err = fl.DeleteByRelation(typ, tableID, tx)
+		if err != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	tx.Commit()
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Success"))
+	return
+}
+
+// PinHandler is used for pin or unpin information, pinned informations are always listed first
+func PinHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := pinParams{
+		id:       ps.ByName("information_id"),
+		isPinned: r.FormValue("is_pinned"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	information, err := inf.GetByID(args.id)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid Request"))
+		return
+	}
+
+	if !handleAccess(sess, information.ScheduleID, auth.RoleXUpdate, auth.RoleUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	err = inf.UpdatePinned(information.ID, args.isPinned, nil)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Success"))
+	return
+}
+
+// GetHandler is used by student for listing informations of enrolled schedules including the global informations
+func GetHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := readParams{
+		scheduleID: r.FormValue("schedule_id"),
+		page:       r.FormValue("pg"),
+		total:      r.FormValue("ttl"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid Request"))
+		return
+	}
+
+	if args.total > 100 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Max total should be less than or equal to 100"))
+		return
+	}
+
+	var schedulesID []int64
+	if args.scheduleID.Valid {
+		if !cs.IsEnrolled(sess.ID, args.scheduleID.Int64) {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusForbidden).
+				AddError("You are not authorized"))
+			return
+		}
+		schedulesID = []int64{args.scheduleID.Int64}
+	} else {
+		schedulesID, err = cs.SelectScheduleIDByUserID(sess.ID, cs.PStatusStudent)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	offset := (args.page - 1) * args.total
+	informations, count, err := inf.SelectBySchedule(schedulesID, args.total, offset, true)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	respInformation, err := handleResponse(informations)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	totalPage := count / args.total
+	if count%args.total > 0 {
+		totalPage++
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(readResponse{
+			Page:         args.page,
+			TotalPage:    totalPage,
+			Informations: respInformation,
+		}))
+	return
+}
+
+// GetDetailHandler ...
+func GetDetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := readDetailParams{
+		id: ps.ByName("information_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid Request"))
+		return
+	}
+
+	information, err := inf.GetByID(args.id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusNoContent))
+			return
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if information.ScheduleID.Valid && !cs.IsEnrolled(sess.ID, information.ScheduleID.Int64) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You are not authorized"))
+		return
+	}
+
+	now, err := publish.Now()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if !information.IsPublished(now) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNoContent))
+		return
+	}
+
+	resp, err := handleResponse([]inf.Information{information})
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp[0]))
+	return
+}
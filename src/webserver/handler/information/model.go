@@ -0,0 +1,119 @@
+package information
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+type readParams struct {
+	scheduleID string
+	page       string
+	total      string
+}
+
+type readArgs struct {
+	scheduleID sql.NullInt64
+	page       int
+	total      int
+}
+
+type readDetailParams struct {
+	id string
+}
+
+type readDetailArgs struct {
+	id int64
+}
+
+type createParams struct {
+	title       string
+	description string
+	scheduleID  string
+	status      string
+	filesID     string
+	imageID     string
+	publishAt   string
+	unpublishAt string
+}
+
+type createArgs struct {
+	title       string
+	description sql.NullString
+	scheduleID  sql.NullInt64
+	status      int8
+	filesID     []string
+	imageID     string
+	publishAt   mysql.NullTime
+	unpublishAt mysql.NullTime
+}
+
+type updateParams struct {
+	id          string
+	title       string
+	description string
+	status      string
+	filesID     string
+	imageID     string
+	publishAt   string
+	unpublishAt string
+}
+
+type updateArgs struct {
+	id          int64
+	title       string
+	description sql.NullString
+	status      int8
+	filesID     []string
+	imageID     string
+	publishAt   mysql.NullTime
+	unpublishAt mysql.NullTime
+}
+
+type deleteParams struct {
+	id string
+}
+
+type deleteArgs struct {
+	id int64
+}
+
+type pinParams struct {
+	id       string
+	isPinned string
+}
+
+type pinArgs struct {
+	id       int64
+	isPinned int8
+}
+
+type fileResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type readInformation struct {
+	ID          int64          `json:"id"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	ScheduleID  int64          `json:"schedule_id,omitempty"`
+	CourseName  string         `json:"course_name,omitempty"`
+	Status      int8           `json:"status"`
+	IsPinned    bool           `json:"is_pinned"`
+	ImageURL    string         `json:"image_url"`
+	ThumbURL    string         `json:"image_thumbnail_url"`
+	Files       []fileResponse `json:"files"`
+	Time        int64          `json:"time"`
+	UpdatedAt   int64          `json:"updated_at"`
+	PublishAt   *time.Time     `json:"publish_at,omitempty"`
+	UnpublishAt *time.Time     `json:"unpublish_at,omitempty"`
+}
+
+type readResponse struct {
+	Page         int               `json:"page"`
+	TotalPage    int               `json:"total_page"`
+	Informations []readInformation `json:"informations"`
+}
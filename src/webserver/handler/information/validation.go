@@ -0,0 +1,284 @@
+package information
+
+import (
+	"database/sql"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	inf "github.com/asepnur/meiko_course/src/module/information"
+	"github.com/asepnur/meiko_course/src/util/alias"
+	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/go-sql-driver/mysql"
+)
+
+func (params readParams) validate() (readArgs, error) {
+	var args readArgs
+
+	if helper.IsEmpty(params.page) || helper.IsEmpty(params.total) {
+		return args, fmt.Errorf("Invalid request")
+	}
+
+	var scheduleID sql.NullInt64
+	if !helper.IsEmpty(params.scheduleID) {
+		id, err := strconv.ParseInt(params.scheduleID, 10, 64)
+		if err != nil {
+			return args, fmt.Errorf("Invalid request")
+		}
+		scheduleID = sql.NullInt64{Valid: true, Int64: id}
+	}
+
+	page, err := strconv.ParseUint(params.page, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid request")
+	}
+
+	total, err := strconv.ParseUint(params.total, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid request")
+	}
+
+	// should be positive number
+	if page < 1 || total < 1 {
+		return args, fmt.Errorf("Invalid request")
+	}
+
+	return readArgs{
+		scheduleID: scheduleID,
+		page:       int(page),
+		total:      int(total),
+	}, nil
+}
+
+func (params readDetailParams) validate() (readDetailArgs, error) {
+
+	var args readDetailArgs
+	id, err := strconv.ParseInt(params.id, 10, 64)
+	if err != nil {
+		return args, err
+	}
+
+	return readDetailArgs{id: id}, nil
+}
+
+func (params createParams) validate() (createArgs, error) {
+
+	var args createArgs
+	params = createParams{
+		title:       html.EscapeString(helper.Trim(params.title)),
+		description: html.EscapeString(helper.Trim(params.description)),
+		scheduleID:  params.scheduleID,
+		status:      params.status,
+		filesID:     params.filesID,
+		imageID:     params.imageID,
+		publishAt:   helper.Trim(params.publishAt),
+		unpublishAt: helper.Trim(params.unpublishAt),
+	}
+
+	title, desc, err := validateContent(params.title, params.description)
+	if err != nil {
+		return args, err
+	}
+
+	var scheduleID sql.NullInt64
+	if !helper.IsEmpty(params.scheduleID) {
+		id, err := strconv.ParseInt(params.scheduleID, 10, 64)
+		if err != nil {
+			return args, fmt.Errorf("Invalid schedule id")
+		}
+		scheduleID = sql.NullInt64{Valid: true, Int64: id}
+	}
+
+	status, err := validateStatus(params.status)
+	if err != nil {
+		return args, err
+	}
+
+	filesID, err := validateFilesID(params.filesID)
+	if err != nil {
+		return args, err
+	}
+
+	if !helper.IsEmpty(params.imageID) && !helper.IsValidFileID(params.imageID) {
+		return args, fmt.Errorf("Invalid image id format")
+	}
+
+	publishAt, unpublishAt, err := validatePublish(params.publishAt, params.unpublishAt)
+	if err != nil {
+		return args, err
+	}
+
+	return createArgs{
+		title:       title,
+		description: desc,
+		scheduleID:  scheduleID,
+		status:      status,
+		filesID:     filesID,
+		imageID:     params.imageID,
+		publishAt:   publishAt,
+		unpublishAt: unpublishAt,
+	}, nil
+}
+
+func (params updateParams) validate() (updateArgs, error) {
+
+	var args updateArgs
+	params = updateParams{
+		id:          params.id,
+		title:       html.EscapeString(helper.Trim(params.title)),
+		description: html.EscapeString(helper.Trim(params.description)),
+		status:      params.status,
+		filesID:     params.filesID,
+		imageID:     params.imageID,
+		publishAt:   helper.Trim(params.publishAt),
+		unpublishAt: helper.Trim(params.unpublishAt),
+	}
+
+	id, err := strconv.ParseInt(params.id, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid Request")
+	}
+
+	title, desc, err := validateContent(params.title, params.description)
+	if err != nil {
+		return args, err
+	}
+
+	status, err := validateStatus(params.status)
+	if err != nil {
+		return args, err
+	}
+
+	filesID, err := validateFilesID(params.filesID)
+	if err != nil {
+		return args, err
+	}
+
+	if !helper.IsEmpty(params.imageID) && !helper.IsValidFileID(params.imageID) {
+		return args, fmt.Errorf("Invalid image id format")
+	}
+
+	publishAt, unpublishAt, err := validatePublish(params.publishAt, params.unpublishAt)
+	if err != nil {
+		return args, err
+	}
+
+	return updateArgs{
+		id:          id,
+		title:       title,
+		description: desc,
+		status:      status,
+		filesID:     filesID,
+		imageID:     params.imageID,
+		publishAt:   publishAt,
+		unpublishAt: unpublishAt,
+	}, nil
+}
+
+func (params deleteParams) validate() (deleteArgs, error) {
+	var args deleteArgs
+	id, err := strconv.ParseInt(params.id, 10, 64)
+	if err != nil {
+		return args, err
+	}
+
+	return deleteArgs{id: id}, nil
+}
+
+func (params pinParams) validate() (pinArgs, error) {
+	var args pinArgs
+	id, err := strconv.ParseInt(params.id, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid Request")
+	}
+
+	isPinned, err := strconv.ParseBool(params.isPinned)
+	if err != nil {
+		return args, fmt.Errorf("Invalid pinned value")
+	}
+
+	var pinned int8 = inf.StatusUnpinned
+	if isPinned {
+		pinned = inf.StatusPinned
+	}
+
+	return pinArgs{
+		id:       id,
+		isPinned: pinned,
+	}, nil
+}
+
+func validateContent(title, description string) (string, sql.NullString, error) {
+	var desc sql.NullString
+
+	if helper.IsEmpty(title) {
+		return title, desc, fmt.Errorf("Title cannot be empty")
+	}
+
+	if len(title) > inf.MaxTitle {
+		return title, desc, fmt.Errorf("Title maximum consist of %d character", inf.MaxTitle)
+	}
+
+	if len(description) > inf.MaxDesc {
+		return title, desc, fmt.Errorf("Description maximum consist of %d character", inf.MaxDesc)
+	}
+
+	if !helper.IsEmpty(description) {
+		desc = sql.NullString{
+			Valid:  true,
+			String: description,
+		}
+	}
+	return title, desc, nil
+}
+
+func validateStatus(status string) (int8, error) {
+	if helper.IsEmpty(status) {
+		return alias.InformationStatusGeneral, nil
+	}
+
+	s, err := strconv.ParseInt(status, 10, 8)
+	if err != nil || (s != alias.InformationStatusGeneral && s != alias.InformationStatusMaterial) {
+		return 0, fmt.Errorf("Invalid status")
+	}
+	return int8(s), nil
+}
+
+func validateFilesID(filesID string) ([]string, error) {
+	var ids []string
+	if helper.IsEmpty(filesID) {
+		return ids, nil
+	}
+
+	ids = strings.Split(filesID, "~")
+	for _, val := range ids {
+		if !helper.IsValidFileID(val) {
+			return ids, fmt.Errorf("Invalid fileID format")
+		}
+	}
+	return ids, nil
+}
+
+func validatePublish(publishAt, unpublishAt string) (mysql.NullTime, mysql.NullTime, error) {
+	var publish, unpublish mysql.NullTime
+	if !helper.IsEmpty(publishAt) {
+		t, err := time.Parse(`2006-01-02 15:04:05`, publishAt)
+		if err != nil {
+			return publish, unpublish, fmt.Errorf("Invalid publish date")
+		}
+		publish = mysql.NullTime{Valid: true, Time: t}
+	}
+	if !helper.IsEmpty(unpublishAt) {
+		t, err := time.Parse(`2006-01-02 15:04:05`, unpublishAt)
+		if err != nil {
+			return publish, unpublish, fmt.Errorf("Invalid unpublish date")
+		}
+		if publish.Valid && !t.After(publish.Time) {
+			return publish, unpublish, fmt.Errorf("Unpublish date must be after publish date")
+		}
+		unpublish = mysql.NullTime{Valid: true, Time: t}
+	}
+	return publish, unpublish, nil
+}
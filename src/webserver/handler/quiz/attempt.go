@@ -0,0 +1,343 @@
+package quiz
+
+import (
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	qz "github.com/asepnur/meiko_course/src/module/quiz"
+	"github.com/asepnur/meiko_course/src/util/auth"
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/asepnur/meiko_course/src/webserver/template"
+	"github.com/julienschmidt/httprouter"
+)
+
+// ReadStudentHandler returns the quiz with the attempts of the user, attempt which time has run out is submitted first
+func ReadStudentHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	params := studentQuizParams{
+		assignmentID: ps.ByName("assignment_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, quiz, deadline, code, err := handleStudentAccess(sess.ID, args.assignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	now := time.Now()
+	attempts, err := qz.SelectAttempt(assignment.ID, sess.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	attempts, err = handleExpire(*quiz, attempts, deadline, now)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	questionCount := quiz.QuestionCount
+	if questionCount < 1 {
+		count, err := qz.CountQuestion([]int64{quiz.BankID})
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		questionCount = count[quiz.BankID]
+	}
+
+	isOpen := !now.After(deadline)
+	resp := studentQuizResponse{
+		ID:            assignment.ID,
+		Name:          assignment.Name,
+		Description:   assignment.Description.String,
+		DueDate:       deadline.Format("Monday, 2 January 2006 15:04:05"),
+		TimeLimit:     quiz.TimeLimit,
+		MaxAttempts:   quiz.MaxAttempts,
+		QuestionCount: questionCount,
+		IsOpen:        isOpen,
+		CanAttempt:    isOpen && (quiz.MaxAttempts < 1 || len(attempts) < quiz.MaxAttempts),
+		Attempts:      []attemptResponse{},
+	}
+	for _, val := range attempts {
+		attempt, err := handleAttemptResponse(*quiz, val, deadline, now, false)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		if val.Status == qz.AttemptInProgress {
+			resp.CanAttempt = isOpen
+		}
+		resp.Attempts = append(resp.Attempts, attempt)
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// StartHandler starts a new attempt with questions drawn from the bank,
+// the running attempt is returned instead when the user has not submitted it
+func StartHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	params := studentQuizParams{
+		assignmentID: ps.ByName("assignment_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, quiz, deadline, code, err := handleStudentAccess(sess.ID, args.assignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	now := time.Now()
+	attempts, err := qz.SelectAttempt(assignment.ID, sess.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	attempts, err = handleExpire(*quiz, attempts, deadline, now)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	for _, val := range attempts {
+		if val.Status != qz.AttemptInProgress {
+			continue
+		}
+		resp, err := handleAttemptResponse(*quiz, val, deadline, now, true)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusOK).
+			SetData(resp))
+		return
+	}
+
+	if now.After(deadline) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("Quiz has been closed"))
+		return
+	}
+	if quiz.MaxAttempts > 0 && len(attempts) >= quiz.MaxAttempts {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You have used all attempts"))
+		return
+	}
+
+	questions, err := qz.SelectQuestion(quiz.BankID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if len(questions) < 1 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Quiz does not have any question"))
+		return
+	}
+
+	rnd := rand.New(rand.NewSource(now.UnixNano()))
+	var answers []qz.Answer
+	for _, val := range qz.Draw(questions, quiz.QuestionCount, quiz.IsShuffled, rnd) {
+		order := qz.OptionOrder(val, quiz.IsOptionShuffled, rnd)
+		answers = append(answers, qz.Answer{
+			QuestionID:  val.ID,
+			OptionOrder: strings.Join(helper.Int64ToStringSlice(order), "~"),
+		})
+	}
+
+	tx := conn.DB.MustBegin()
+	id, err := qz.InsertAttempt(assignment.ID, sess.ID, answers, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	attempt, err := qz.GetAttempt(id)
+	if err != nil || attempt == nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	resp, err := handleAttemptResponse(*quiz, *attempt, deadline, now, true)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// ReadAttemptHandler returns the attempt with its questions and saved answers,
+// points and comments are shown after the attempt has been submitted
+func ReadAttemptHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	params := attemptParams{
+		assignmentID: ps.ByName("assignment_id"),
+		attemptID:    ps.ByName("attempt_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, quiz, deadline, code, err := handleStudentAccess(sess.ID, args.assignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	attempt, code, err := handleAttempt(sess.ID, assignment.ID, args.attemptID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	now := time.Now()
+	attempts, err := handleExpire(*quiz, []qz.Attempt{*attempt}, deadline, now)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	attempt, err = qz.GetAttempt(attempts[0].ID)
+	if err != nil || attempt == nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	resp, err := handleAttemptResponse(*quiz, *attempt, deadline, now, true)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// SaveAnswerHandler saves answers of the running attempt, answers is a json list of question id with its answer.
+// Choice answer is the option id, options of multiple answer question are separated by "~"
+func SaveAnswerHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	params := saveAnswerParams{
+		assignmentID: ps.ByName("assignment_id"),
+		attemptID:    ps.ByName("attempt_id"),
+		answers:      r.FormValue("answers"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	code, err := handleSave(sess.ID, args, false)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Answers have been saved"))
+	return
+}
+
+// SubmitHandler saves the last answers and submits the attempt, answers are graded right away
+// except short answers which do not match any accepted answer
+func SubmitHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	params := saveAnswerParams{
+		assignmentID: ps.ByName("assignment_id"),
+		attemptID:    ps.ByName("attempt_id"),
+		answers:      r.FormValue("answers"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	code, err := handleSave(sess.ID, args, true)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Quiz has been submitted"))
+	return
+}
@@ -0,0 +1,524 @@
+package quiz
+
+import (
+	"net/http"
+
+	cs "github.com/asepnur/meiko_course/src/module/course"
+	qz "github.com/asepnur/meiko_course/src/module/quiz"
+	"github.com/asepnur/meiko_course/src/util/auth"
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/webserver/template"
+	"github.com/julienschmidt/httprouter"
+)
+
+// ReadBankHandler returns question banks of the schedule
+func ReadBankHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := readBankParams{
+		scheduleID: ps.ByName("schedule_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	banks, err := qz.SelectBank(args.scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	var banksID []int64
+	for _, val := range banks {
+		banksID = append(banksID, val.ID)
+	}
+	count, err := qz.CountQuestion(banksID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	resp := []bankResponse{}
+	for _, val := range banks {
+		resp = append(resp, bankResponse{
+			ID:            val.ID,
+			Name:          val.Name,
+			QuestionCount: count[val.ID],
+			IsUsed:        qz.IsBankUsed(val.ID),
+			CreatedAt:     val.CreatedAt.Format("Monday, 2 January 2006 15:04:05"),
+		})
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// CreateBankHandler creates an empty question bank of the schedule
+func CreateBankHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleCreate, auth.RoleXCreate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := createBankParams{
+		scheduleID: ps.ByName("schedule_id"),
+		name:       r.FormValue("name"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	id, err := qz.InsertBank(args.scheduleID, args.name, sess.ID, nil)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Question bank created successfully").
+		SetData(id))
+	return
+}
+
+// UpdateBankHandler renames the question bank
+func UpdateBankHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := updateBankParams{
+		scheduleID: ps.ByName("schedule_id"),
+		bankID:     ps.ByName("bank_id"),
+		name:       r.FormValue("name"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	_, code, err := handleBank(args.scheduleID, args.bankID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	err = qz.UpdateBank(args.bankID, args.name, nil)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Question bank updated successfully"))
+	return
+}
+
+// DeleteBankHandler deletes the question bank with its questions, bank which is used by a quiz can not be deleted
+func DeleteBankHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleDelete, auth.RoleXDelete) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := bankParams{
+		scheduleID: ps.ByName("schedule_id"),
+		bankID:     ps.ByName("bank_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	_, code, err := handleBank(args.scheduleID, args.bankID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	if qz.IsBankUsed(args.bankID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Question bank is used by a quiz"))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = qz.DeleteBank(args.bankID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Question bank deleted successfully"))
+	return
+}
+
+// ReadQuestionHandler returns active questions of the bank with their answers
+func ReadQuestionHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := bankParams{
+		scheduleID: ps.ByName("schedule_id"),
+		bankID:     ps.ByName("bank_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	_, code, err := handleBank(args.scheduleID, args.bankID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	questions, err := qz.SelectQuestion(args.bankID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	resp := []questionResponse{}
+	for _, question := range questions {
+		options := []optionResponse{}
+		for _, val := range question.Options {
+			options = append(options, optionResponse{
+				ID:        val.ID,
+				Text:      val.Text,
+				IsCorrect: val.IsCorrect,
+			})
+		}
+		resp = append(resp, questionResponse{
+			ID:         question.ID,
+			Type:       qz.Types[question.Type],
+			Question:   question.Question,
+			Points:     question.Points,
+			Answer:     question.Answer.String,
+			Tolerance:  question.Tolerance,
+			IsAnswered: qz.IsQuestionAnswered(question.ID),
+			Options:    options,
+		})
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// CreateQuestionHandler adds a question to the bank, options is a json list of options with the correct ones marked
+func CreateQuestionHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleCreate, auth.RoleXCreate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := createQuestionParams{
+		scheduleID: ps.ByName("schedule_id"),
+		bankID:     ps.ByName("bank_id"),
+		typ:        r.FormValue("type"),
+		question:   r.FormValue("question"),
+		points:     r.FormValue("points"),
+		answer:     r.FormValue("answer"),
+		tolerance:  r.FormValue("tolerance"),
+		options:    r.FormValue("options"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	_, code, err := handleBank(args.scheduleID, args.bankID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	id, err := qz.InsertQuestion(args.question, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Question created successfully").
+		SetData(id))
+	return
+}
+
+// UpdateQuestionHandler replaces the question, question which has been drawn on an attempt can not be changed
+func UpdateQuestionHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := updateQuestionParams{
+		scheduleID: ps.ByName("schedule_id"),
+		bankID:     ps.ByName("bank_id"),
+		questionID: ps.ByName("question_id"),
+		typ:        r.FormValue("type"),
+		question:   r.FormValue("question"),
+		points:     r.FormValue("points"),
+		answer:     r.FormValue("answer"),
+		tolerance:  r.FormValue("tolerance"),
+		options:    r.FormValue("options"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	_, code, err := handleBank(args.scheduleID, args.bankID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	question, err := qz.GetQuestion(args.questionID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if question == nil || question.BankID != args.bankID {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Question does not exist"))
+		return
+	}
+
+	if qz.IsQuestionAnswered(question.ID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Question can not be changed after it has been answered, delete it and create a new one"))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = qz.UpdateQuestion(args.question, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Question updated successfully"))
+	return
+}
+
+// DeleteQuestionHandler removes the question from the bank, past attempts still show it
+func DeleteQuestionHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleDelete, auth.RoleXDelete) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := deleteQuestionParams{
+		scheduleID: ps.ByName("schedule_id"),
+		bankID:     ps.ByName("bank_id"),
+		questionID: ps.ByName("question_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsAssistant(sess.ID, args.scheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	question, err := qz.GetQuestion(args.questionID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	bank, _, err := handleBank(args.scheduleID, args.bankID)
+	if err != nil || question == nil || question.BankID != bank.ID {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Question does not exist"))
+		return
+	}
+
+	err = qz.DeleteQuestion(question.ID, nil)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Question deleted successfully"))
+	return
+}
@@ -0,0 +1,406 @@
+package quiz
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	asg "github.com/asepnur/meiko_course/src/module/assignment"
+	cs "github.com/asepnur/meiko_course/src/module/course"
+	qz "github.com/asepnur/meiko_course/src/module/quiz"
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/util/publish"
+	"github.com/jmoiron/sqlx"
+)
+
+// handleBank returns the question bank when it belongs to the schedule
+func handleBank(scheduleID, bankID int64) (*qz.Bank, int, error) {
+	bank, err := qz.GetBank(bankID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if bank == nil || bank.ScheduleID != scheduleID {
+		return nil, http.StatusNotFound, fmt.Errorf("Question bank does not exist")
+	}
+	return bank, http.StatusOK, nil
+}
+
+// handleQuizAccess makes sure the assignment belongs to the schedule and the user is an assistant of the schedule
+func handleQuizAccess(userID, scheduleID, assignmentID int64) (asg.Assignment, int, error) {
+	assignment, err := asg.GetByID(assignmentID)
+	if err != nil {
+		return assignment, http.StatusNotFound, fmt.Errorf("Assignment does not exist")
+	}
+
+	schID, err := cs.GetScheduleIDByGP(assignment.GradeParameterID)
+	if err != nil {
+		return assignment, http.StatusInternalServerError, err
+	}
+	if schID != scheduleID {
+		return assignment, http.StatusBadRequest, fmt.Errorf("Assignment does not belong to the schedule")
+	}
+
+	if !cs.IsAssistant(userID, scheduleID) {
+		return assignment, http.StatusForbidden, fmt.Errorf("You don't have privilege")
+	}
+	return assignment, http.StatusOK, nil
+}
+
+// handleStudentAccess returns the quiz of the assignment and the due date of the user including the extension,
+// the user must be enrolled in the schedule
+func handleStudentAccess(userID, assignmentID int64) (asg.Assignment, *qz.Quiz, time.Time, int, error) {
+	var deadline time.Time
+	now, err := publish.Now()
+	if err != nil {
+		return asg.Assignment{}, nil, deadline, http.StatusInternalServerError, err
+	}
+	assignment, err := asg.GetByID(assignmentID)
+	if err != nil || !assignment.IsPublished(now) {
+		return assignment, nil, deadline, http.StatusNotFound, fmt.Errorf("Quiz does not exist")
+	}
+
+	quiz, err := qz.Get(assignment.ID)
+	if err != nil {
+		return assignment, nil, deadline, http.StatusInternalServerError, err
+	}
+	if quiz == nil {
+		return assignment, nil, deadline, http.StatusNotFound, fmt.Errorf("Quiz does not exist")
+	}
+
+	scheduleID, err := cs.GetScheduleIDByGP(assignment.GradeParameterID)
+	if err != nil {
+		return assignment, nil, deadline, http.StatusInternalServerError, err
+	}
+	if !cs.IsEnrolled(userID, scheduleID) {
+		return assignment, nil, deadline, http.StatusForbidden, fmt.Errorf("You don't have privilege")
+	}
+
+	ext, err := asg.GetExtension(assignment.ID, userID)
+	if err != nil {
+		return assignment, nil, deadline, http.StatusInternalServerError, err
+	}
+	return assignment, quiz, assignment.Extend(ext).DueDate, http.StatusOK, nil
+}
+
+// handleAttempt returns the attempt when it belongs to the user on the quiz
+func handleAttempt(userID, assignmentID, attemptID int64) (*qz.Attempt, int, error) {
+	attempt, err := qz.GetAttempt(attemptID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if attempt == nil || attempt.UserID != userID || attempt.AssignmentID != assignmentID {
+		return nil, http.StatusNotFound, fmt.Errorf("Attempt does not exist")
+	}
+	return attempt, http.StatusOK, nil
+}
+
+// handleIsOpen is used to check whether answers of the attempt are still accepted or not
+func handleIsOpen(quiz qz.Quiz, attempt qz.Attempt, deadline, now time.Time) bool {
+	if attempt.Status != qz.AttemptInProgress {
+		return false
+	}
+	end := quiz.EndAt(attempt.StartedAt, deadline).Add(qz.TimeGrace * time.Second)
+	return !now.After(end)
+}
+
+// handleExpire submits every attempt which time has run out with its saved answers
+func handleExpire(quiz qz.Quiz, attempts []qz.Attempt, deadline, now time.Time) ([]qz.Attempt, error) {
+	for i, val := range attempts {
+		if val.Status != qz.AttemptInProgress || handleIsOpen(quiz, val, deadline, now) {
+			continue
+		}
+
+		tx := conn.DB.MustBegin()
+		status, err := handleFinish(val, tx)
+		if err != nil {
+			tx.Rollback()
+			return attempts, err
+		}
+		err = tx.Commit()
+		if err != nil {
+			return attempts, err
+		}
+		attempts[i].Status = status
+	}
+	return attempts, nil
+}
+
+// handleQuestionMap returns the questions of the answers keyed by id
+func handleQuestionMap(answers []qz.Answer) (map[int64]qz.Question, error) {
+	var questionsID []int64
+	for _, val := range answers {
+		questionsID = append(questionsID, val.QuestionID)
+	}
+	questions, err := qz.SelectQuestionByID(questionsID)
+	if err != nil {
+		return nil, err
+	}
+
+	questionMap := map[int64]qz.Question{}
+	for _, val := range questions {
+		questionMap[val.ID] = val
+	}
+	return questionMap, nil
+}
+
+// handleAnswerCheck makes sure every answer is for a question of the attempt and uses the shown options
+func handleAnswerCheck(answers []qz.Answer, questions map[int64]qz.Question, reqs []answerRequest) error {
+	attemptAnswer := map[int64]qz.Answer{}
+	for _, val := range answers {
+		attemptAnswer[val.QuestionID] = val
+	}
+
+	for _, req := range reqs {
+		answer, ok := attemptAnswer[req.QuestionID]
+		if !ok {
+			return fmt.Errorf("Question %d is not on the attempt", req.QuestionID)
+		}
+		if req.Answer == "" {
+			continue
+		}
+
+		question := questions[req.QuestionID]
+		switch question.Type {
+		case qz.TypeMultipleChoice, qz.TypeMultipleAnswer:
+			chosen := qz.SplitID(req.Answer)
+			if len(chosen) < 1 || len(chosen) != len(strings.Split(req.Answer, "~")) {
+				return fmt.Errorf("Invalid answer on question %d", answer.Position)
+			}
+			if question.Type == qz.TypeMultipleChoice && len(chosen) > 1 {
+				return fmt.Errorf("Question %d only accepts one option", answer.Position)
+			}
+			shown := qz.SplitID(answer.OptionOrder)
+			for _, val := range chosen {
+				isShown := false
+				for _, id := range shown {
+					if id == val {
+						isShown = true
+						break
+					}
+				}
+				if !isShown {
+					return fmt.Errorf("Invalid option on question %d", answer.Position)
+				}
+			}
+		case qz.TypeNumeric:
+			_, err := strconv.ParseFloat(req.Answer, 64)
+			if err != nil {
+				return fmt.Errorf("Question %d must be answered with a number", answer.Position)
+			}
+		}
+	}
+	return nil
+}
+
+// handleSave saves the answers of the running attempt and submits it when isSubmit is true.
+// Answers are refused once the time has run out, the attempt is submitted with its saved answers instead
+func handleSave(userID int64, args saveAnswerArgs, isSubmit bool) (int, error) {
+	_, quiz, deadline, code, err := handleStudentAccess(userID, args.assignmentID)
+	if err != nil {
+		return code, err
+	}
+	attempt, code, err := handleAttempt(userID, args.assignmentID, args.attemptID)
+	if err != nil {
+		return code, err
+	}
+	if attempt.Status != qz.AttemptInProgress {
+		return http.StatusBadRequest, fmt.Errorf("Attempt has been submitted")
+	}
+
+	now := time.Now()
+	if !handleIsOpen(*quiz, *attempt, deadline, now) {
+		_, err = handleExpire(*quiz, []qz.Attempt{*attempt}, deadline, now)
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		return http.StatusForbidden, fmt.Errorf("Time is up, your saved answers have been submitted")
+	}
+
+	answers, err := qz.SelectAnswer(attempt.ID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	questions, err := handleQuestionMap(answers)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	err = handleAnswerCheck(answers, questions, args.answers)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	position := map[int64]int{}
+	for i, val := range answers {
+		position[val.QuestionID] = i
+	}
+
+	tx := conn.DB.MustBegin()
+	for _, val := range args.answers {
+		answer := sql.NullString{Valid: val.Answer != "", String: val.Answer}
+		err = qz.UpdateAnswer(attempt.ID, val.QuestionID, answer, tx)
+		if err != nil {
+			tx.Rollback()
+			return http.StatusInternalServerError, err
+		}
+		answers[position[val.QuestionID]].Answer = answer
+	}
+	if isSubmit {
+		_, err = handleGrade(*attempt, questions, answers, tx)
+		if err != nil {
+			tx.Rollback()
+			return http.StatusInternalServerError, err
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+// handleFinish grades every answer of the attempt and submits it, the attempt waits for review
+// when any short answer can not be graded automatically. The new status is returned
+func handleFinish(attempt qz.Attempt, tx *sqlx.Tx) (int8, error) {
+	answers, err := qz.SelectAnswer(attempt.ID)
+	if err != nil {
+		return attempt.Status, err
+	}
+	questions, err := handleQuestionMap(answers)
+	if err != nil {
+		return attempt.Status, err
+	}
+	return handleGrade(attempt, questions, answers, tx)
+}
+
+// handleGrade grades the given answers of the attempt and saves the result
+func handleGrade(attempt qz.Attempt, questions map[int64]qz.Question, answers []qz.Answer, tx *sqlx.Tx) (int8, error) {
+	for i, val := range answers {
+		points, isGraded := questions[val.QuestionID].Grade(val.Answer)
+		answers[i].Points = sql.NullFloat64{Valid: isGraded, Float64: points}
+		err := qz.GradeAnswer(attempt.ID, val.QuestionID, answers[i].Points, val.Comment, tx)
+		if err != nil {
+			return attempt.Status, err
+		}
+	}
+
+	return handleAttemptTotal(attempt, questions, answers, attempt.UserID, tx)
+}
+
+// handleAttemptTotal saves the result of the submitted attempt, the assignment score is updated once it is graded.
+// Actor is the user who graded the attempt, it is the student when the attempt is graded automatically
+func handleAttemptTotal(attempt qz.Attempt, questions map[int64]qz.Question, answers []qz.Answer, actor int64, tx *sqlx.Tx) (int8, error) {
+	points, score, isGraded := qz.Total(questions, answers)
+
+	status := int8(qz.AttemptReview)
+	var nullPoints, nullScore sql.NullFloat64
+	if isGraded {
+		status = qz.AttemptGraded
+		nullPoints = sql.NullFloat64{Valid: true, Float64: points}
+		nullScore = sql.NullFloat64{Valid: true, Float64: score}
+	}
+
+	err := qz.UpdateAttempt(attempt.ID, status, nullPoints, nullScore, tx)
+	if err != nil {
+		return attempt.Status, err
+	}
+
+	err = handleScore(attempt.AssignmentID, attempt.UserID, actor, tx)
+	if err != nil {
+		return attempt.Status, err
+	}
+	return status, nil
+}
+
+// handleScore sets the assignment score of the user from the best graded attempt, the feedback is kept
+func handleScore(assignmentID, userID, actor int64, tx *sqlx.Tx) error {
+	best, err := qz.GetBestScore(assignmentID, userID, tx)
+	if err != nil {
+		return err
+	}
+	if !best.Valid {
+		return nil
+	}
+
+	old, err := asg.SelectScoreByUser(assignmentID, []int64{userID}, tx)
+	if err != nil {
+		return err
+	}
+	err = asg.UpsertScore(assignmentID, userID, float32(best.Float64), sql.NullString{}, tx)
+	if err != nil {
+		return err
+	}
+	return asg.LogScore(assignmentID, []int64{userID}, old, actor, asg.ScoreSourceQuiz, sql.NullString{}, tx)
+}
+
+// handleAttemptResponse builds the attempt with its questions in the shown order, points and comments
+// of the answers are only shown after the attempt has been submitted
+func handleAttemptResponse(quiz qz.Quiz, attempt qz.Attempt, deadline, now time.Time, isDetail bool) (attemptResponse, error) {
+	end := quiz.EndAt(attempt.StartedAt, deadline)
+	resp := attemptResponse{
+		ID:          attempt.ID,
+		Attempt:     attempt.Attempt,
+		Status:      qz.AttemptStatuses[attempt.Status],
+		StartedAt:   attempt.StartedAt.Format("Monday, 2 January 2006 15:04:05"),
+		EndAt:       end.Format("Monday, 2 January 2006 15:04:05"),
+		SubmittedAt: "-",
+		IsGraded:    attempt.Status == qz.AttemptGraded,
+		Points:      attempt.Points.Float64,
+		Score:       attempt.Score.Float64,
+	}
+	if attempt.SubmittedAt.Valid {
+		resp.SubmittedAt = attempt.SubmittedAt.Time.Format("Monday, 2 January 2006 15:04:05")
+	}
+	if attempt.Status == qz.AttemptInProgress && end.After(now) {
+		resp.RemainingSeconds = int64(end.Sub(now).Seconds())
+	}
+	if !isDetail {
+		return resp, nil
+	}
+
+	answers, err := qz.SelectAnswer(attempt.ID)
+	if err != nil {
+		return resp, err
+	}
+	questions, err := handleQuestionMap(answers)
+	if err != nil {
+		return resp, err
+	}
+
+	isSubmitted := attempt.Status != qz.AttemptInProgress
+	resp.Questions = []attemptQuestionResponse{}
+	for _, val := range answers {
+		question := questions[val.QuestionID]
+		optionMap := map[int64]qz.Option{}
+		for _, option := range question.Options {
+			optionMap[option.ID] = option
+		}
+		options := []attemptOptionResponse{}
+		for _, id := range qz.SplitID(val.OptionOrder) {
+			if option, ok := optionMap[id]; ok {
+				options = append(options, attemptOptionResponse{
+					ID:   option.ID,
+					Text: option.Text,
+				})
+			}
+		}
+
+		q := attemptQuestionResponse{
+			ID:       question.ID,
+			Type:     qz.Types[question.Type],
+			Question: question.Question,
+			Points:   question.Points,
+			Options:  options,
+			Answer:   val.Answer.String,
+		}
+		if isSubmitted {
+			q.IsGraded = val.Points.Valid
+			q.Earned = val.Points.Float64
+			q.Comment = val.Comment.String
+		}
+		resp.Questions = append(resp.Questions, q)
+	}
+	return resp, nil
+}
@@ -0,0 +1,280 @@
+package quiz
+
+import (
+	qz "github.com/asepnur/meiko_course/src/module/quiz"
+)
+
+type readBankParams struct {
+	scheduleID string
+}
+
+type readBankArgs struct {
+	scheduleID int64
+}
+
+type createBankParams struct {
+	scheduleID string
+	name       string
+}
+
+type createBankArgs struct {
+	scheduleID int64
+	name       string
+}
+
+type updateBankParams struct {
+	scheduleID string
+	bankID     string
+	name       string
+}
+
+type updateBankArgs struct {
+	scheduleID int64
+	bankID     int64
+	name       string
+}
+
+type bankParams struct {
+	scheduleID string
+	bankID     string
+}
+
+type bankArgs struct {
+	scheduleID int64
+	bankID     int64
+}
+
+type createQuestionParams struct {
+	scheduleID string
+	bankID     string
+	typ        string
+	question   string
+	points     string
+	answer     string
+	tolerance  string
+	options    string
+}
+
+type createQuestionArgs struct {
+	scheduleID int64
+	bankID     int64
+	question   qz.Question
+}
+
+type updateQuestionParams struct {
+	scheduleID string
+	bankID     string
+	questionID string
+	typ        string
+	question   string
+	points     string
+	answer     string
+	tolerance  string
+	options    string
+}
+
+type updateQuestionArgs struct {
+	scheduleID int64
+	bankID     int64
+	questionID int64
+	question   qz.Question
+}
+
+type deleteQuestionParams struct {
+	scheduleID string
+	bankID     string
+	questionID string
+}
+
+type deleteQuestionArgs struct {
+	scheduleID int64
+	bankID     int64
+	questionID int64
+}
+
+type quizParams struct {
+	scheduleID   string
+	assignmentID string
+}
+
+type quizArgs struct {
+	scheduleID   int64
+	assignmentID int64
+}
+
+type updateQuizParams struct {
+	scheduleID       string
+	assignmentID     string
+	bankID           string
+	questionCount    string
+	timeLimit        string
+	maxAttempts      string
+	isShuffled       string
+	isOptionShuffled string
+}
+
+type updateQuizArgs struct {
+	scheduleID   int64
+	assignmentID int64
+	quiz         qz.Quiz
+}
+
+type reviewParams struct {
+	scheduleID   string
+	assignmentID string
+	attemptID    string
+	answers      string
+}
+
+type reviewArgs struct {
+	scheduleID   int64
+	assignmentID int64
+	attemptID    int64
+	answers      []reviewRequest
+}
+
+type studentQuizParams struct {
+	assignmentID string
+}
+
+type studentQuizArgs struct {
+	assignmentID int64
+}
+
+type attemptParams struct {
+	assignmentID string
+	attemptID    string
+}
+
+type attemptArgs struct {
+	assignmentID int64
+	attemptID    int64
+}
+
+type saveAnswerParams struct {
+	assignmentID string
+	attemptID    string
+	answers      string
+}
+
+type saveAnswerArgs struct {
+	assignmentID int64
+	attemptID    int64
+	answers      []answerRequest
+}
+
+type optionRequest struct {
+	Text      string `json:"text"`
+	IsCorrect bool   `json:"is_correct"`
+}
+
+type answerRequest struct {
+	QuestionID int64  `json:"question_id"`
+	Answer     string `json:"answer"`
+}
+
+type reviewRequest struct {
+	QuestionID int64   `json:"question_id"`
+	Points     float64 `json:"points"`
+	Comment    string  `json:"comment"`
+}
+
+type bankResponse struct {
+	ID            int64  `json:"id"`
+	Name          string `json:"name"`
+	QuestionCount int    `json:"question_count"`
+	IsUsed        bool   `json:"is_used"`
+	CreatedAt     string `json:"created_at"`
+}
+
+type questionResponse struct {
+	ID         int64            `json:"id"`
+	Type       string           `json:"type"`
+	Question   string           `json:"question"`
+	Points     float64          `json:"points"`
+	Answer     string           `json:"answer"`
+	Tolerance  float64          `json:"tolerance"`
+	IsAnswered bool             `json:"is_answered"`
+	Options    []optionResponse `json:"options"`
+}
+
+type optionResponse struct {
+	ID        int64  `json:"id"`
+	Text      string `json:"text"`
+	IsCorrect bool   `json:"is_correct"`
+}
+
+type quizResponse struct {
+	BankID           int64  `json:"bank_id"`
+	BankName         string `json:"bank_name"`
+	QuestionCount    int    `json:"question_count"`
+	TimeLimit        int    `json:"time_limit"`
+	MaxAttempts      int    `json:"max_attempts"`
+	IsShuffled       bool   `json:"is_shuffled"`
+	IsOptionShuffled bool   `json:"is_option_shuffled"`
+	IsAttempted      bool   `json:"is_attempted"`
+}
+
+type studentQuizResponse struct {
+	ID            int64             `json:"id"`
+	Name          string            `json:"name"`
+	Description   string            `json:"description"`
+	DueDate       string            `json:"due_date"`
+	TimeLimit     int               `json:"time_limit"`
+	MaxAttempts   int               `json:"max_attempts"`
+	QuestionCount int               `json:"question_count"`
+	IsOpen        bool              `json:"is_open"`
+	CanAttempt    bool              `json:"can_attempt"`
+	Attempts      []attemptResponse `json:"attempts"`
+}
+
+type attemptResponse struct {
+	ID               int64                     `json:"id"`
+	Attempt          int                       `json:"attempt"`
+	Status           string                    `json:"status"`
+	StartedAt        string                    `json:"started_at"`
+	EndAt            string                    `json:"end_at"`
+	SubmittedAt      string                    `json:"submitted_at"`
+	RemainingSeconds int64                     `json:"remaining_seconds"`
+	IsGraded         bool                      `json:"is_graded"`
+	Points           float64                   `json:"points"`
+	Score            float64                   `json:"score"`
+	Questions        []attemptQuestionResponse `json:"questions,omitempty"`
+}
+
+type attemptQuestionResponse struct {
+	ID       int64                   `json:"id"`
+	Type     string                  `json:"type"`
+	Question string                  `json:"question"`
+	Points   float64                 `json:"points"`
+	Options  []attemptOptionResponse `json:"options"`
+	Answer   string                  `json:"answer"`
+	IsGraded bool                    `json:"is_graded"`
+	Earned   float64                 `json:"earned"`
+	Comment  string                  `json:"comment"`
+}
+
+type attemptOptionResponse struct {
+	ID   int64  `json:"id"`
+	Text string `json:"text"`
+}
+
+type reviewResponse struct {
+	AttemptID    int64                  `json:"attempt_id"`
+	IdentityCode int64                  `json:"identity_code"`
+	Name         string                 `json:"name"`
+	Attempt      int                    `json:"attempt"`
+	SubmittedAt  string                 `json:"submitted_at"`
+	Answers      []reviewAnswerResponse `json:"answers"`
+}
+
+type reviewAnswerResponse struct {
+	QuestionID int64   `json:"question_id"`
+	Question   string  `json:"question"`
+	Accepted   string  `json:"accepted"`
+	Answer     string  `json:"answer"`
+	MaxPoints  float64 `json:"max_points"`
+	IsGraded   bool    `json:"is_graded"`
+	Points     float64 `json:"points"`
+	Comment    string  `json:"comment"`
+}
@@ -0,0 +1,419 @@
+package quiz
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	asg "github.com/asepnur/meiko_course/src/module/assignment"
+	cs "github.com/asepnur/meiko_course/src/module/course"
+	qz "github.com/asepnur/meiko_course/src/module/quiz"
+	usr "github.com/asepnur/meiko_course/src/module/user"
+	"github.com/asepnur/meiko_course/src/util/auth"
+	"github.com/asepnur/meiko_course/src/util/conn"
+	"github.com/asepnur/meiko_course/src/webserver/template"
+	"github.com/julienschmidt/httprouter"
+)
+
+// ReadHandler returns the quiz setting of the assignment, data is null when the assignment is not a quiz
+func ReadHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := quizParams{
+		scheduleID:   ps.ByName("schedule_id"),
+		assignmentID: ps.ByName("assignment_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleQuizAccess(sess.ID, args.scheduleID, args.assignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	quiz, err := qz.Get(assignment.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if quiz == nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusOK))
+		return
+	}
+
+	bank, err := qz.GetBank(quiz.BankID)
+	if err != nil || bank == nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(quizResponse{
+			BankID:           bank.ID,
+			BankName:         bank.Name,
+			QuestionCount:    quiz.QuestionCount,
+			TimeLimit:        quiz.TimeLimit,
+			MaxAttempts:      quiz.MaxAttempts,
+			IsShuffled:       quiz.IsShuffled,
+			IsOptionShuffled: quiz.IsOptionShuffled,
+			IsAttempted:      qz.IsAttempted(assignment.ID),
+		}))
+	return
+}
+
+// UpdateHandler turns the assignment into a quiz using questions of a bank. The assignment must use a QUIZ
+// grade parameter and must not require upload, the bank can not be changed after the quiz has been attempted
+func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := updateQuizParams{
+		scheduleID:       ps.ByName("schedule_id"),
+		assignmentID:     ps.ByName("assignment_id"),
+		bankID:           r.FormValue("bank_id"),
+		questionCount:    r.FormValue("question_count"),
+		timeLimit:        r.FormValue("time_limit"),
+		maxAttempts:      r.FormValue("max_attempts"),
+		isShuffled:       r.FormValue("is_shuffled"),
+		isOptionShuffled: r.FormValue("is_option_shuffled"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleQuizAccess(sess.ID, args.scheduleID, args.assignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	gps, err := cs.SelectGPBySchedule([]int64{args.scheduleID})
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	isQuiz := false
+	for _, val := range gps {
+		if val.ID == assignment.GradeParameterID && val.Type == cs.GradeParameterQuiz {
+			isQuiz = true
+		}
+	}
+	if !isQuiz {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(fmt.Sprintf("Assignment must use the %s grade parameter", cs.GradeParameterQuiz)))
+		return
+	}
+	if assignment.Status != asg.StatusUploadNotRequired {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Quiz assignment must not require upload"))
+		return
+	}
+
+	_, code, err = handleBank(args.scheduleID, args.quiz.BankID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	quiz, err := qz.Get(assignment.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if quiz != nil && quiz.BankID != args.quiz.BankID && qz.IsAttempted(assignment.ID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Question bank can not be changed after the quiz has been attempted"))
+		return
+	}
+
+	count, err := qz.CountQuestion([]int64{args.quiz.BankID})
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if count[args.quiz.BankID] < 1 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Question bank does not have any question"))
+		return
+	}
+	if args.quiz.QuestionCount > count[args.quiz.BankID] {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(fmt.Sprintf("Question bank only has %d questions", count[args.quiz.BankID])))
+		return
+	}
+
+	err = qz.Upsert(args.quiz, nil)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Quiz has been saved"))
+	return
+}
+
+// ReadReviewHandler returns submitted attempts which have answers waiting for manual review
+func ReadReviewHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleRead, auth.RoleXRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := quizParams{
+		scheduleID:   ps.ByName("schedule_id"),
+		assignmentID: ps.ByName("assignment_id"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleQuizAccess(sess.ID, args.scheduleID, args.assignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	attempts, err := qz.SelectAttemptByStatus(assignment.ID, qz.AttemptReview)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	var usersID []int64
+	for _, val := range attempts {
+		usersID = append(usersID, val.UserID)
+	}
+	users, err := usr.RequestID(usersID, false)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	userMap := map[int64]usr.UserReq{}
+	for _, val := range users {
+		userMap[val.ID] = val
+	}
+
+	resp := []reviewResponse{}
+	for _, attempt := range attempts {
+		answers, err := qz.SelectAnswer(attempt.ID)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		questions, err := handleQuestionMap(answers)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+
+		review := reviewResponse{
+			AttemptID:    attempt.ID,
+			IdentityCode: userMap[attempt.UserID].IdentityCode,
+			Name:         userMap[attempt.UserID].Name,
+			Attempt:      attempt.Attempt,
+			SubmittedAt:  attempt.SubmittedAt.Time.Format("Monday, 2 January 2006 15:04:05"),
+			Answers:      []reviewAnswerResponse{},
+		}
+		for _, val := range answers {
+			question := questions[val.QuestionID]
+			if question.Type != qz.TypeShortAnswer {
+				continue
+			}
+			review.Answers = append(review.Answers, reviewAnswerResponse{
+				QuestionID: question.ID,
+				Question:   question.Question,
+				Accepted:   question.Answer.String,
+				Answer:     val.Answer.String,
+				MaxPoints:  question.Points,
+				IsGraded:   val.Points.Valid,
+				Points:     val.Points.Float64,
+				Comment:    val.Comment.String,
+			})
+		}
+		resp = append(resp, review)
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// ReviewHandler grades answers of a submitted attempt manually, it can also override automatic grading.
+// The attempt is graded once no answer is waiting for review and the best attempt becomes the assignment score
+func ReviewHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.IsHasRoles(auth.ModuleAssignment, auth.RoleUpdate, auth.RoleXUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := reviewParams{
+		scheduleID:   ps.ByName("schedule_id"),
+		assignmentID: ps.ByName("assignment_id"),
+		attemptID:    ps.ByName("attempt_id"),
+		answers:      r.FormValue("answers"),
+	}
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	assignment, code, err := handleQuizAccess(sess.ID, args.scheduleID, args.assignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	attempt, err := qz.GetAttempt(args.attemptID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if attempt == nil || attempt.AssignmentID != assignment.ID {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Attempt does not exist"))
+		return
+	}
+	if attempt.Status == qz.AttemptInProgress {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Attempt has not been submitted"))
+		return
+	}
+
+	answers, err := qz.SelectAnswer(attempt.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	questions, err := handleQuestionMap(answers)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	answerIndex := map[int64]int{}
+	for i, val := range answers {
+		answerIndex[val.QuestionID] = i
+	}
+	for _, val := range args.answers {
+		i, ok := answerIndex[val.QuestionID]
+		if !ok {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusBadRequest).
+				AddError(fmt.Sprintf("Question %d is not on the attempt", val.QuestionID)))
+			return
+		}
+		if val.Points > questions[val.QuestionID].Points {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusBadRequest).
+				AddError(fmt.Sprintf("Question %d maximum points is %g", answers[i].Position, questions[val.QuestionID].Points)))
+			return
+		}
+		answers[i].Points = sql.NullFloat64{Valid: true, Float64: val.Points}
+		answers[i].Comment = sql.NullString{Valid: val.Comment != "", String: val.Comment}
+	}
+
+	tx := conn.DB.MustBegin()
+	for _, val := range args.answers {
+		answer := answers[answerIndex[val.QuestionID]]
+		err = qz.GradeAnswer(attempt.ID, answer.QuestionID, answer.Points, answer.Comment, tx)
+		if err != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	_, err = handleAttemptTotal(*attempt, questions, answers, sess.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Answers have been graded"))
+	return
+}
@@ -0,0 +1,458 @@
+package quiz
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+	"strings"
+
+	qz "github.com/asepnur/meiko_course/src/module/quiz"
+	"github.com/asepnur/meiko_course/src/util/helper"
+)
+
+func (params readBankParams) validate() (readBankArgs, error) {
+	var args readBankArgs
+	scheduleID, err := strconv.ParseInt(params.scheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule id")
+	}
+
+	return readBankArgs{
+		scheduleID: scheduleID,
+	}, nil
+}
+
+func (params createBankParams) validate() (createBankArgs, error) {
+	var args createBankArgs
+	scheduleID, err := strconv.ParseInt(params.scheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule id")
+	}
+
+	name, err := validateName(params.name)
+	if err != nil {
+		return args, err
+	}
+
+	return createBankArgs{
+		scheduleID: scheduleID,
+		name:       name,
+	}, nil
+}
+
+func (params updateBankParams) validate() (updateBankArgs, error) {
+	var args updateBankArgs
+	v, err := bankParams{
+		scheduleID: params.scheduleID,
+		bankID:     params.bankID,
+	}.validate()
+	if err != nil {
+		return args, err
+	}
+
+	name, err := validateName(params.name)
+	if err != nil {
+		return args, err
+	}
+
+	return updateBankArgs{
+		scheduleID: v.scheduleID,
+		bankID:     v.bankID,
+		name:       name,
+	}, nil
+}
+
+func (params bankParams) validate() (bankArgs, error) {
+	var args bankArgs
+	scheduleID, err := strconv.ParseInt(params.scheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule id")
+	}
+
+	bankID, err := strconv.ParseInt(params.bankID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid bank id")
+	}
+
+	return bankArgs{
+		scheduleID: scheduleID,
+		bankID:     bankID,
+	}, nil
+}
+
+func (params createQuestionParams) validate() (createQuestionArgs, error) {
+	var args createQuestionArgs
+	v, err := bankParams{
+		scheduleID: params.scheduleID,
+		bankID:     params.bankID,
+	}.validate()
+	if err != nil {
+		return args, err
+	}
+
+	question, err := validateQuestion(params.typ, params.question, params.points, params.answer, params.tolerance, params.options)
+	if err != nil {
+		return args, err
+	}
+	question.BankID = v.bankID
+
+	return createQuestionArgs{
+		scheduleID: v.scheduleID,
+		bankID:     v.bankID,
+		question:   question,
+	}, nil
+}
+
+func (params updateQuestionParams) validate() (updateQuestionArgs, error) {
+	var args updateQuestionArgs
+	v, err := deleteQuestionParams{
+		scheduleID: params.scheduleID,
+		bankID:     params.bankID,
+		questionID: params.questionID,
+	}.validate()
+	if err != nil {
+		return args, err
+	}
+
+	question, err := validateQuestion(params.typ, params.question, params.points, params.answer, params.tolerance, params.options)
+	if err != nil {
+		return args, err
+	}
+	question.ID = v.questionID
+	question.BankID = v.bankID
+
+	return updateQuestionArgs{
+		scheduleID: v.scheduleID,
+		bankID:     v.bankID,
+		questionID: v.questionID,
+		question:   question,
+	}, nil
+}
+
+func (params deleteQuestionParams) validate() (deleteQuestionArgs, error) {
+	var args deleteQuestionArgs
+	v, err := bankParams{
+		scheduleID: params.scheduleID,
+		bankID:     params.bankID,
+	}.validate()
+	if err != nil {
+		return args, err
+	}
+
+	questionID, err := strconv.ParseInt(params.questionID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid question id")
+	}
+
+	return deleteQuestionArgs{
+		scheduleID: v.scheduleID,
+		bankID:     v.bankID,
+		questionID: questionID,
+	}, nil
+}
+
+func (params quizParams) validate() (quizArgs, error) {
+	var args quizArgs
+	scheduleID, err := strconv.ParseInt(params.scheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule id")
+	}
+
+	assignmentID, err := strconv.ParseInt(params.assignmentID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid assignment id")
+	}
+
+	return quizArgs{
+		scheduleID:   scheduleID,
+		assignmentID: assignmentID,
+	}, nil
+}
+
+func (params updateQuizParams) validate() (updateQuizArgs, error) {
+	var args updateQuizArgs
+	v, err := quizParams{
+		scheduleID:   params.scheduleID,
+		assignmentID: params.assignmentID,
+	}.validate()
+	if err != nil {
+		return args, err
+	}
+
+	bankID, err := strconv.ParseInt(params.bankID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid bank id")
+	}
+
+	questionCount, err := validateNumber(params.questionCount, "Question count", 0, math.MaxInt32)
+	if err != nil {
+		return args, err
+	}
+
+	timeLimit, err := validateNumber(params.timeLimit, "Time limit", 0, qz.MaxTimeLimit)
+	if err != nil {
+		return args, err
+	}
+
+	maxAttempts, err := validateNumber(params.maxAttempts, "Max attempts", 0, qz.MaxAttempts)
+	if err != nil {
+		return args, err
+	}
+
+	return updateQuizArgs{
+		scheduleID:   v.scheduleID,
+		assignmentID: v.assignmentID,
+		quiz: qz.Quiz{
+			AssignmentID:     v.assignmentID,
+			BankID:           bankID,
+			QuestionCount:    questionCount,
+			TimeLimit:        timeLimit,
+			MaxAttempts:      maxAttempts,
+			IsShuffled:       params.isShuffled == "true",
+			IsOptionShuffled: params.isOptionShuffled == "true",
+		},
+	}, nil
+}
+
+func (params reviewParams) validate() (reviewArgs, error) {
+	var args reviewArgs
+	v, err := quizParams{
+		scheduleID:   params.scheduleID,
+		assignmentID: params.assignmentID,
+	}.validate()
+	if err != nil {
+		return args, err
+	}
+
+	attemptID, err := strconv.ParseInt(params.attemptID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid attempt id")
+	}
+
+	var reqs []reviewRequest
+	if helper.IsEmpty(params.answers) {
+		return args, fmt.Errorf("Answers can not be empty")
+	}
+	err = json.Unmarshal([]byte(params.answers), &reqs)
+	if err != nil {
+		return args, fmt.Errorf("Invalid answers format")
+	}
+	if len(reqs) < 1 {
+		return args, fmt.Errorf("Answers can not be empty")
+	}
+	for i, val := range reqs {
+		if math.IsNaN(val.Points) || val.Points < 0 || val.Points > qz.MaxPoints {
+			return args, fmt.Errorf("Answer %d: points must be between 0 and %d", i+1, qz.MaxPoints)
+		}
+		reqs[i].Comment = html.EscapeString(helper.Trim(val.Comment))
+		if len(reqs[i].Comment) > qz.MaxComment {
+			return args, fmt.Errorf("Answer %d: comment maximum consist of %d character", i+1, qz.MaxComment)
+		}
+	}
+
+	return reviewArgs{
+		scheduleID:   v.scheduleID,
+		assignmentID: v.assignmentID,
+		attemptID:    attemptID,
+		answers:      reqs,
+	}, nil
+}
+
+func (params studentQuizParams) validate() (studentQuizArgs, error) {
+	var args studentQuizArgs
+	assignmentID, err := strconv.ParseInt(params.assignmentID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid assignment id")
+	}
+
+	return studentQuizArgs{
+		assignmentID: assignmentID,
+	}, nil
+}
+
+func (params attemptParams) validate() (attemptArgs, error) {
+	var args attemptArgs
+	assignmentID, err := strconv.ParseInt(params.assignmentID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid assignment id")
+	}
+
+	attemptID, err := strconv.ParseInt(params.attemptID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid attempt id")
+	}
+
+	return attemptArgs{
+		assignmentID: assignmentID,
+		attemptID:    attemptID,
+	}, nil
+}
+
+// validate parses the answers, empty answers is allowed on submission which only uses the saved answers
+func (params saveAnswerParams) validate() (saveAnswerArgs, error) {
+	var args saveAnswerArgs
+	v, err := attemptParams{
+		assignmentID: params.assignmentID,
+		attemptID:    params.attemptID,
+	}.validate()
+	if err != nil {
+		return args, err
+	}
+
+	var reqs []answerRequest
+	if !helper.IsEmpty(params.answers) {
+		err = json.Unmarshal([]byte(params.answers), &reqs)
+		if err != nil {
+			return args, fmt.Errorf("Invalid answers format")
+		}
+	}
+	for i, val := range reqs {
+		reqs[i].Answer = html.EscapeString(helper.Trim(val.Answer))
+		if len(reqs[i].Answer) > qz.MaxAnswer {
+			return args, fmt.Errorf("Answer %d maximum consist of %d character", i+1, qz.MaxAnswer)
+		}
+	}
+
+	return saveAnswerArgs{
+		assignmentID: v.assignmentID,
+		attemptID:    v.attemptID,
+		answers:      reqs,
+	}, nil
+}
+
+func validateName(name string) (string, error) {
+	name = html.EscapeString(helper.Trim(name))
+	if helper.IsEmpty(name) {
+		return name, fmt.Errorf("Name can not be empty")
+	}
+	if len(name) > qz.MaxName {
+		return name, fmt.Errorf("Name maximum consist of %d character", qz.MaxName)
+	}
+	return name, nil
+}
+
+// validateNumber parses an optional non negative number, empty value is 0
+func validateNumber(val, name string, min, max int) (int, error) {
+	if helper.IsEmpty(val) {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid %s", strings.ToLower(name))
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("%s must be between %d and %d", name, min, max)
+	}
+	return n, nil
+}
+
+// validateQuestion builds the question based on its type, choice questions need options with the correct ones marked,
+// short answer may have accepted answers separated by "~" and numeric question needs its value
+func validateQuestion(typ, text, points, answer, tolerance, options string) (qz.Question, error) {
+	var question qz.Question
+
+	t, err := strconv.ParseInt(typ, 10, 8)
+	if err != nil {
+		return question, fmt.Errorf("Invalid question type")
+	}
+	if _, ok := qz.Types[int8(t)]; !ok {
+		return question, fmt.Errorf("Invalid question type")
+	}
+	question.Type = int8(t)
+
+	question.Question = html.EscapeString(helper.Trim(text))
+	if helper.IsEmpty(question.Question) {
+		return question, fmt.Errorf("Question can not be empty")
+	}
+	if len(question.Question) > qz.MaxQuestion {
+		return question, fmt.Errorf("Question maximum consist of %d character", qz.MaxQuestion)
+	}
+
+	question.Points, err = strconv.ParseFloat(points, 64)
+	if err != nil || math.IsNaN(question.Points) || question.Points <= 0 || question.Points > qz.MaxPoints {
+		return question, fmt.Errorf("Points must be greater than 0 and at most %d", qz.MaxPoints)
+	}
+
+	switch question.Type {
+	case qz.TypeMultipleChoice, qz.TypeMultipleAnswer:
+		question.Options, err = validateOptions(question.Type, options)
+		if err != nil {
+			return question, err
+		}
+	case qz.TypeShortAnswer:
+		var accepted []string
+		for _, val := range strings.Split(answer, "~") {
+			val = html.EscapeString(helper.Trim(val))
+			if !helper.IsEmpty(val) {
+				accepted = append(accepted, val)
+			}
+		}
+		if len(accepted) > 0 {
+			question.Answer = sql.NullString{Valid: true, String: strings.Join(accepted, "~")}
+		}
+		if len(question.Answer.String) > qz.MaxAnswer {
+			return question, fmt.Errorf("Answer maximum consist of %d character", qz.MaxAnswer)
+		}
+	case qz.TypeNumeric:
+		value, err := strconv.ParseFloat(helper.Trim(answer), 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return question, fmt.Errorf("Numeric question must have a number as the answer")
+		}
+		question.Answer = sql.NullString{Valid: true, String: strconv.FormatFloat(value, 'f', -1, 64)}
+
+		if !helper.IsEmpty(tolerance) {
+			question.Tolerance, err = strconv.ParseFloat(tolerance, 64)
+			if err != nil || math.IsNaN(question.Tolerance) || math.IsInf(question.Tolerance, 0) || question.Tolerance < 0 {
+				return question, fmt.Errorf("Tolerance must be a positive number")
+			}
+		}
+	}
+	return question, nil
+}
+
+func validateOptions(typ int8, options string) ([]qz.Option, error) {
+	var reqs []optionRequest
+	if helper.IsEmpty(options) {
+		return nil, fmt.Errorf("Question must have at least 2 options")
+	}
+	err := json.Unmarshal([]byte(options), &reqs)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid options format")
+	}
+	if len(reqs) < 2 {
+		return nil, fmt.Errorf("Question must have at least 2 options")
+	}
+	if len(reqs) > qz.MaxOptions {
+		return nil, fmt.Errorf("Question maximum consist of %d options", qz.MaxOptions)
+	}
+
+	var result []qz.Option
+	correct := 0
+	for i, val := range reqs {
+		text := html.EscapeString(helper.Trim(val.Text))
+		if helper.IsEmpty(text) {
+			return nil, fmt.Errorf("Option %d can not be empty", i+1)
+		}
+		if len(text) > qz.MaxOption {
+			return nil, fmt.Errorf("Option %d maximum consist of %d character", i+1, qz.MaxOption)
+		}
+		if val.IsCorrect {
+			correct++
+		}
+		result = append(result, qz.Option{
+			Text:      text,
+			IsCorrect: val.IsCorrect,
+		})
+	}
+
+	if typ == qz.TypeMultipleChoice && correct != 1 {
+		return nil, fmt.Errorf("Multiple choice question must have exactly one correct option")
+	}
+	if correct < 1 {
+		return nil, fmt.Errorf("Question must have at least one correct option")
+	}
+	return result, nil
+}
@@ -0,0 +1,185 @@
+package quiz
+
+import (
+	"testing"
+
+	qz "github.com/asepnur/meiko_course/src/module/quiz"
+)
+
+func Test_validateOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     int8
+		options string
+		wantErr bool
+	}{
+		{
+			name:    "Empty",
+			typ:     qz.TypeMultipleChoice,
+			options: "",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid json",
+			typ:     qz.TypeMultipleChoice,
+			options: `{"text":"A"}`,
+			wantErr: true,
+		},
+		{
+			name:    "Single option",
+			typ:     qz.TypeMultipleChoice,
+			options: `[{"text":"A","is_correct":true}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Empty option",
+			typ:     qz.TypeMultipleChoice,
+			options: `[{"text":"A","is_correct":true},{"text":" "}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Without correct option",
+			typ:     qz.TypeMultipleAnswer,
+			options: `[{"text":"A"},{"text":"B"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Multiple choice with two correct options",
+			typ:     qz.TypeMultipleChoice,
+			options: `[{"text":"A","is_correct":true},{"text":"B","is_correct":true}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Too many options",
+			typ:     qz.TypeMultipleAnswer,
+			options: `[{"text":"1","is_correct":true},{"text":"2"},{"text":"3"},{"text":"4"},{"text":"5"},{"text":"6"},{"text":"7"},{"text":"8"},{"text":"9"},{"text":"10"},{"text":"11"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Valid multiple choice",
+			typ:     qz.TypeMultipleChoice,
+			options: `[{"text":"A","is_correct":true},{"text":"B"}]`,
+			wantErr: false,
+		},
+		{
+			name:    "Valid multiple answer",
+			typ:     qz.TypeMultipleAnswer,
+			options: `[{"text":"A","is_correct":true},{"text":"B","is_correct":true},{"text":"C"}]`,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validateOptions(tt.typ, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateQuestion(t *testing.T) {
+	tests := []struct {
+		name       string
+		typ        string
+		text       string
+		points     string
+		answer     string
+		tolerance  string
+		options    string
+		wantAnswer string
+		wantErr    bool
+	}{
+		{
+			name:    "Invalid type",
+			typ:     "9",
+			text:    "Question",
+			points:  "1",
+			wantErr: true,
+		},
+		{
+			name:    "Empty question",
+			typ:     "2",
+			text:    " ",
+			points:  "1",
+			wantErr: true,
+		},
+		{
+			name:    "Zero points",
+			typ:     "2",
+			text:    "Question",
+			points:  "0",
+			wantErr: true,
+		},
+		{
+			name:    "Points over max",
+			typ:     "2",
+			text:    "Question",
+			points:  "101",
+			wantErr: true,
+		},
+		{
+			name:    "Numeric without number",
+			typ:     "3",
+			text:    "Question",
+			points:  "1",
+			answer:  "ten",
+			wantErr: true,
+		},
+		{
+			name:      "Negative tolerance",
+			typ:       "3",
+			text:      "Question",
+			points:    "1",
+			answer:    "10",
+			tolerance: "-1",
+			wantErr:   true,
+		},
+		{
+			name:       "Valid numeric",
+			typ:        "3",
+			text:       "Question",
+			points:     "2.5",
+			answer:     " 10.50 ",
+			tolerance:  "0.1",
+			wantAnswer: "10.5",
+			wantErr:    false,
+		},
+		{
+			name:       "Valid short answer",
+			typ:        "2",
+			text:       "Question",
+			points:     "1",
+			answer:     " Jakarta ~~ DKI Jakarta",
+			wantAnswer: "Jakarta~DKI Jakarta",
+			wantErr:    false,
+		},
+		{
+			name:    "Choice without options",
+			typ:     "0",
+			text:    "Question",
+			points:  "1",
+			wantErr: true,
+		},
+		{
+			name:    "Valid choice",
+			typ:     "0",
+			text:    "Question",
+			points:  "1",
+			options: `[{"text":"A","is_correct":true},{"text":"B"}]`,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := validateQuestion(tt.typ, tt.text, tt.points, tt.answer, tt.tolerance, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateQuestion() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.Answer.String != tt.wantAnswer {
+				t.Errorf("validateQuestion() answer = %v, want %v", got.Answer.String, tt.wantAnswer)
+			}
+		})
+	}
+}
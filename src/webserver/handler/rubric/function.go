@@ -0,0 +1,85 @@
+package rubric
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	rb "github.com/asepnur/meiko_course/src/module/rubric"
+	"github.com/asepnur/meiko_course/src/util/helper"
+)
+
+// handleRubric returns the rubric when it belongs to the schedule
+func handleRubric(scheduleID, rubricID int64) (*rb.Rubric, int, error) {
+	rubric, err := rb.Get(rubricID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if rubric == nil || rubric.ScheduleID != scheduleID {
+		return nil, http.StatusNotFound, fmt.Errorf("Rubric does not exist")
+	}
+	return rubric, http.StatusOK, nil
+}
+
+// handleCriteria converts the validated criteria into rubric criteria
+func handleCriteria(reqs []criterionRequest) []rb.Criterion {
+	var criteria []rb.Criterion
+	for _, val := range reqs {
+		criterion := rb.Criterion{
+			Name:        val.Name,
+			Description: handleNullString(val.Description),
+		}
+		for _, level := range val.Levels {
+			criterion.Levels = append(criterion.Levels, rb.Level{
+				Name:        level.Name,
+				Description: handleNullString(level.Description),
+				Points:      level.Points,
+			})
+		}
+		criteria = append(criteria, criterion)
+	}
+	return criteria
+}
+
+func handleNullString(val string) sql.NullString {
+	if helper.IsEmpty(val) {
+		return sql.NullString{}
+	}
+	return sql.NullString{Valid: true, String: val}
+}
+
+// handleRubricResponse builds the rubric list with the criteria and the levels of every rubric
+func handleRubricResponse(rubrics []rb.Rubric) []rubricResponse {
+	resp := []rubricResponse{}
+	for _, rubric := range rubrics {
+		criteria := []criterionResponse{}
+		for _, criterion := range rubric.Criteria {
+			levels := []levelResponse{}
+			for _, level := range criterion.Levels {
+				levels = append(levels, levelResponse{
+					ID:          level.ID,
+					Name:        level.Name,
+					Description: level.Description.String,
+					Points:      level.Points,
+				})
+			}
+			criteria = append(criteria, criterionResponse{
+				ID:          criterion.ID,
+				Name:        criterion.Name,
+				Description: criterion.Description.String,
+				MaxPoints:   criterion.MaxPoints(),
+				Levels:      levels,
+			})
+		}
+		resp = append(resp, rubricResponse{
+			ID:          rubric.ID,
+			Name:        rubric.Name,
+			Description: rubric.Description.String,
+			MaxPoints:   rubric.MaxPoints(),
+			IsAttached:  rb.IsAttached(rubric.ID),
+			IsScored:    rb.IsScored(rubric.ID),
+			Criteria:    criteria,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,104 @@
+package rubric
+
+import (
+	"database/sql"
+)
+
+type readParams struct {
+	scheduleID string
+}
+
+type readArgs struct {
+	scheduleID int64
+}
+
+type createParams struct {
+	scheduleID  string
+	name        string
+	description string
+	criteria    string
+}
+
+type createArgs struct {
+	scheduleID  int64
+	name        string
+	description sql.NullString
+	criteria    []criterionRequest
+}
+
+type updateParams struct {
+	scheduleID  string
+	rubricID    string
+	name        string
+	description string
+	criteria    string
+}
+
+type updateArgs struct {
+	scheduleID  int64
+	rubricID    int64
+	name        string
+	description sql.NullString
+	criteria    []criterionRequest
+}
+
+type deleteParams struct {
+	scheduleID string
+	rubricID   string
+}
+
+type deleteArgs struct {
+	scheduleID int64
+	rubricID   int64
+}
+
+type copyParams struct {
+	scheduleID       string
+	rubricID         string
+	targetScheduleID string
+	name             string
+}
+
+type copyArgs struct {
+	scheduleID       int64
+	rubricID         int64
+	targetScheduleID int64
+	name             string
+}
+
+type criterionRequest struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Levels      []levelRequest `json:"levels"`
+}
+
+type levelRequest struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Points      float64 `json:"points"`
+}
+
+type rubricResponse struct {
+	ID          int64               `json:"id"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	MaxPoints   float64             `json:"max_points"`
+	IsAttached  bool                `json:"is_attached"`
+	IsScored    bool                `json:"is_scored"`
+	Criteria    []criterionResponse `json:"criteria"`
+}
+
+type criterionResponse struct {
+	ID          int64           `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	MaxPoints   float64         `json:"max_points"`
+	Levels      []levelResponse `json:"levels"`
+}
+
+type levelResponse struct {
+	ID          int64   `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Points      float64 `json:"points"`
+}
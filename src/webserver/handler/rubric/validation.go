@@ -0,0 +1,230 @@
+package rubric
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+
+	rb "github.com/asepnur/meiko_course/src/module/rubric"
+	"github.com/asepnur/meiko_course/src/util/helper"
+)
+
+func (params readParams) validate() (readArgs, error) {
+	var args readArgs
+	scheduleID, err := strconv.ParseInt(params.scheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule id")
+	}
+
+	return readArgs{
+		scheduleID: scheduleID,
+	}, nil
+}
+
+func (params createParams) validate() (createArgs, error) {
+	var args createArgs
+	scheduleID, err := strconv.ParseInt(params.scheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule id")
+	}
+
+	name, err := validateName(params.name)
+	if err != nil {
+		return args, err
+	}
+
+	description, err := validateDescription(params.description)
+	if err != nil {
+		return args, err
+	}
+
+	criteria, err := validateCriteria(params.criteria)
+	if err != nil {
+		return args, err
+	}
+
+	return createArgs{
+		scheduleID:  scheduleID,
+		name:        name,
+		description: description,
+		criteria:    criteria,
+	}, nil
+}
+
+func (params updateParams) validate() (updateArgs, error) {
+	var args updateArgs
+	scheduleID, err := strconv.ParseInt(params.scheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule id")
+	}
+
+	rubricID, err := strconv.ParseInt(params.rubricID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid rubric id")
+	}
+
+	name, err := validateName(params.name)
+	if err != nil {
+		return args, err
+	}
+
+	description, err := validateDescription(params.description)
+	if err != nil {
+		return args, err
+	}
+
+	criteria, err := validateCriteria(params.criteria)
+	if err != nil {
+		return args, err
+	}
+
+	return updateArgs{
+		scheduleID:  scheduleID,
+		rubricID:    rubricID,
+		name:        name,
+		description: description,
+		criteria:    criteria,
+	}, nil
+}
+
+func (params deleteParams) validate() (deleteArgs, error) {
+	var args deleteArgs
+	scheduleID, err := strconv.ParseInt(params.scheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule id")
+	}
+
+	rubricID, err := strconv.ParseInt(params.rubricID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid rubric id")
+	}
+
+	return deleteArgs{
+		scheduleID: scheduleID,
+		rubricID:   rubricID,
+	}, nil
+}
+
+func (params copyParams) validate() (copyArgs, error) {
+	var args copyArgs
+	scheduleID, err := strconv.ParseInt(params.scheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid schedule id")
+	}
+
+	rubricID, err := strconv.ParseInt(params.rubricID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid rubric id")
+	}
+
+	targetScheduleID, err := strconv.ParseInt(params.targetScheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Invalid target schedule id")
+	}
+
+	// name of the original rubric is used when it is empty
+	var name string
+	if !helper.IsEmpty(helper.Trim(params.name)) {
+		name, err = validateName(params.name)
+		if err != nil {
+			return args, err
+		}
+	}
+
+	return copyArgs{
+		scheduleID:       scheduleID,
+		rubricID:         rubricID,
+		targetScheduleID: targetScheduleID,
+		name:             name,
+	}, nil
+}
+
+func validateName(name string) (string, error) {
+	name = html.EscapeString(helper.Trim(name))
+	if helper.IsEmpty(name) {
+		return name, fmt.Errorf("Name can not be empty")
+	}
+	if len(name) > rb.MaxName {
+		return name, fmt.Errorf("Name maximum consist of %d character", rb.MaxName)
+	}
+	return name, nil
+}
+
+func validateDescription(description string) (sql.NullString, error) {
+	description = html.EscapeString(helper.Trim(description))
+	if helper.IsEmpty(description) {
+		return sql.NullString{}, nil
+	}
+	if len(description) > rb.MaxDescription {
+		return sql.NullString{}, fmt.Errorf("Description maximum consist of %d character", rb.MaxDescription)
+	}
+	return sql.NullString{Valid: true, String: description}, nil
+}
+
+// validateCriteria parses the criteria, every criterion must have at least one level
+// and the rubric must be able to give points
+func validateCriteria(criteria string) ([]criterionRequest, error) {
+	var reqs []criterionRequest
+	if helper.IsEmpty(criteria) {
+		return nil, fmt.Errorf("Rubric must have at least one criterion")
+	}
+	err := json.Unmarshal([]byte(criteria), &reqs)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid criteria format")
+	}
+	if len(reqs) < 1 {
+		return nil, fmt.Errorf("Rubric must have at least one criterion")
+	}
+	if len(reqs) > rb.MaxCriteria {
+		return nil, fmt.Errorf("Rubric maximum consist of %d criteria", rb.MaxCriteria)
+	}
+
+	var maxPoints float64
+	for i, criterion := range reqs {
+		reqs[i].Name, err = validateName(criterion.Name)
+		if err != nil {
+			return nil, fmt.Errorf("Criterion %d: %s", i+1, err.Error())
+		}
+		description, err := validateDescription(criterion.Description)
+		if err != nil {
+			return nil, fmt.Errorf("Criterion %d: %s", i+1, err.Error())
+		}
+		reqs[i].Description = description.String
+
+		if len(criterion.Levels) < 1 {
+			return nil, fmt.Errorf("Criterion %d must have at least one level", i+1)
+		}
+		if len(criterion.Levels) > rb.MaxLevels {
+			return nil, fmt.Errorf("Criterion %d maximum consist of %d levels", i+1, rb.MaxLevels)
+		}
+
+		var max float64
+		for j, level := range criterion.Levels {
+			reqs[i].Levels[j].Name, err = validateName(level.Name)
+			if err != nil {
+				return nil, fmt.Errorf("Criterion %d level %d: %s", i+1, j+1, err.Error())
+			}
+			description, err := validateDescription(level.Description)
+			if err != nil {
+				return nil, fmt.Errorf("Criterion %d level %d: %s", i+1, j+1, err.Error())
+			}
+			reqs[i].Levels[j].Description = description.String
+
+			if math.IsNaN(level.Points) || level.Points < 0 || level.Points > rb.MaxPoints {
+				return nil, fmt.Errorf("Criterion %d level %d: points must be between 0 and %d", i+1, j+1, rb.MaxPoints)
+			}
+			if level.Points > max {
+				max = level.Points
+			}
+		}
+		maxPoints += max
+	}
+
+	if maxPoints <= 0 {
+		return nil, fmt.Errorf("Rubric must have at least one level with points")
+	}
+	return reqs, nil
+}
@@ -0,0 +1,82 @@
+package rubric
+
+import (
+	"testing"
+)
+
+func Test_validateCriteria(t *testing.T) {
+	tests := []struct {
+		name     string
+		criteria string
+		wantErr  bool
+	}{
+		{
+			name:     "Empty",
+			criteria: "",
+			wantErr:  true,
+		},
+		{
+			name:     "Invalid json",
+			criteria: `{"name":"Analysis"}`,
+			wantErr:  true,
+		},
+		{
+			name:     "Without criterion",
+			criteria: `[]`,
+			wantErr:  true,
+		},
+		{
+			name:     "Without level",
+			criteria: `[{"name":"Analysis","levels":[]}]`,
+			wantErr:  true,
+		},
+		{
+			name:     "Empty criterion name",
+			criteria: `[{"name":" ","levels":[{"name":"Good","points":5}]}]`,
+			wantErr:  true,
+		},
+		{
+			name:     "Empty level name",
+			criteria: `[{"name":"Analysis","levels":[{"name":"","points":5}]}]`,
+			wantErr:  true,
+		},
+		{
+			name:     "Negative points",
+			criteria: `[{"name":"Analysis","levels":[{"name":"Poor","points":-1}]}]`,
+			wantErr:  true,
+		},
+		{
+			name:     "Points over max",
+			criteria: `[{"name":"Analysis","levels":[{"name":"Good","points":101}]}]`,
+			wantErr:  true,
+		},
+		{
+			name:     "Without points",
+			criteria: `[{"name":"Analysis","levels":[{"name":"Poor","points":0}]}]`,
+			wantErr:  true,
+		},
+		{
+			name:     "Valid",
+			criteria: `[{"name":"Analysis","levels":[{"name":"Poor","points":0},{"name":"Good","points":5}]},{"name":"Report","levels":[{"name":"Done","points":2}]}]`,
+			wantErr:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validateCriteria(tt.criteria)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateCriteria() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateCriteria_escape(t *testing.T) {
+	got, err := validateCriteria(`[{"name":"<b>Analysis</b>","description":" detail ","levels":[{"name":"Good & fair","points":5}]}]`)
+	if err != nil {
+		t.Fatalf("validateCriteria() got error %s", err.Error())
+	}
+	if got[0].Name != "&lt;b&gt;Analysis&lt;/b&gt;" || got[0].Description != "detail" || got[0].Levels[0].Name != "Good &amp; fair" {
+		t.Errorf("validateCriteria() got unescaped value %+v", got)
+	}
+}
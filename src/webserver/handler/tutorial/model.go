@@ -1,6 +1,11 @@
 package tutorial
 
-import "database/sql"
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
 
 type readParams struct {
 	payload    string
@@ -40,11 +45,13 @@ type readDetailArgs struct {
 }
 
 type readDetailResponse struct {
-	ID          int64  `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	URL         string `json:"file_url"`
-	Time        int64  `json:"time"`
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	URL         string     `json:"file_url"`
+	Time        int64      `json:"time"`
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
 }
 
 type createParams struct {
@@ -52,6 +59,8 @@ type createParams struct {
 	description string
 	fileID      string
 	scheduleID  string
+	publishAt   string
+	unpublishAt string
 }
 
 type createArgs struct {
@@ -59,6 +68,8 @@ type createArgs struct {
 	description sql.NullString
 	fileID      string
 	scheduleID  int64
+	publishAt   mysql.NullTime
+	unpublishAt mysql.NullTime
 }
 
 type deleteParams struct {
@@ -75,6 +86,8 @@ type updateParams struct {
 	description string
 	fileID      string
 	scheduleID  string
+	publishAt   string
+	unpublishAt string
 }
 
 type updateArgs struct {
@@ -83,4 +96,6 @@ type updateArgs struct {
 	description sql.NullString
 	fileID      string
 	scheduleID  int64
+	publishAt   mysql.NullTime
+	unpublishAt mysql.NullTime
 }
@@ -60,7 +60,7 @@ func ReadHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	}
 
 	offset := (args.page - 1) * args.total
-	tutorials, count, err := tt.SelectByPage(args.scheduleID, args.total, offset, true)
+	tutorials, count, err := tt.SelectByPage(args.scheduleID, args.total, offset, true, args.payload == "student")
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusInternalServerError))
@@ -170,6 +170,12 @@ func ReadDetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Par
 		Time:        tutorial.CreatedAt.Unix(),
 		URL:         fmt.Sprintf("/api/v1/filerouter/?id=%d&payload=tutorial", tutorial.ID),
 	}
+	if tutorial.PublishAt.Valid {
+		resp.PublishAt = &tutorial.PublishAt.Time
+	}
+	if tutorial.UnpublishAt.Valid {
+		resp.UnpublishAt = &tutorial.UnpublishAt.Time
+	}
 
 	template.RenderJSONResponse(w, new(template.Response).
 		SetCode(http.StatusOK).
@@ -193,6 +199,8 @@ func CreateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		description: r.FormValue("description"),
 		fileID:      r.FormValue("file_id"),
 		scheduleID:  r.FormValue("schedule_id"),
+		publishAt:   r.FormValue("publish_at"),
+		unpublishAt: r.FormValue("unpublish_at"),
 	}
 
 	args, err := params.validate()
@@ -233,6 +241,14 @@ func CreateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		return
 	}
 
+	err = tt.UpdatePublish(lastInsertID, args.publishAt, args.unpublishAt, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
 	tutorialID := strconv.FormatInt(lastInsertID, 10)
 
 	err = fl.UpdateRelation(args.fileID, fl.TypTutorial, tutorialID, tx)
@@ -332,6 +348,8 @@ func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		description: r.FormValue("description"),
 		fileID:      r.FormValue("file_id"),
 		scheduleID:  r.FormValue("schedule_id"),
+		publishAt:   r.FormValue("publish_at"),
+		unpublishAt: r.FormValue("unpublish_at"),
 	}
 
 	args, err := params.validate()
@@ -388,6 +406,14 @@ func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		return
 	}
 
+	err = tt.UpdatePublish(args.id, args.publishAt, args.unpublishAt, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
 	if file.ID != args.fileID {
 
 		if fl.IsHasRelation(args.fileID) {
@@ -6,8 +6,10 @@ import (
 	"html"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/asepnur/meiko_course/src/util/helper"
+	"github.com/go-sql-driver/mysql"
 )
 
 func (params readParams) validate() (readArgs, error) {
@@ -64,6 +66,8 @@ func (params createParams) validate() (createArgs, error) {
 		description: html.EscapeString(params.description),
 		fileID:      params.fileID,
 		scheduleID:  params.scheduleID,
+		publishAt:   helper.Trim(params.publishAt),
+		unpublishAt: helper.Trim(params.unpublishAt),
 	}
 
 	if helper.IsEmpty(params.name) {
@@ -93,11 +97,18 @@ func (params createParams) validate() (createArgs, error) {
 		return args, fmt.Errorf("Invalid Request")
 	}
 
+	publishAt, unpublishAt, err := validatePublish(params.publishAt, params.unpublishAt)
+	if err != nil {
+		return args, err
+	}
+
 	return createArgs{
 		name:        name,
 		description: desc,
 		fileID:      params.fileID,
 		scheduleID:  scheduleID,
+		publishAt:   publishAt,
+		unpublishAt: unpublishAt,
 	}, nil
 }
 
@@ -119,6 +130,8 @@ func (params updateParams) validate() (updateArgs, error) {
 		description: html.EscapeString(params.description),
 		fileID:      params.fileID,
 		scheduleID:  params.scheduleID,
+		publishAt:   helper.Trim(params.publishAt),
+		unpublishAt: helper.Trim(params.unpublishAt),
 	}
 
 	id, err := strconv.ParseInt(params.id, 10, 64)
@@ -153,11 +166,40 @@ func (params updateParams) validate() (updateArgs, error) {
 		return args, fmt.Errorf("Invalid Request")
 	}
 
+	publishAt, unpublishAt, err := validatePublish(params.publishAt, params.unpublishAt)
+	if err != nil {
+		return args, err
+	}
+
 	return updateArgs{
 		id:          id,
 		name:        name,
 		description: desc,
 		fileID:      params.fileID,
 		scheduleID:  scheduleID,
+		publishAt:   publishAt,
+		unpublishAt: unpublishAt,
 	}, nil
 }
+
+func validatePublish(publishAt, unpublishAt string) (mysql.NullTime, mysql.NullTime, error) {
+	var publish, unpublish mysql.NullTime
+	if !helper.IsEmpty(publishAt) {
+		t, err := time.Parse(`2006-01-02 15:04:05`, publishAt)
+		if err != nil {
+			return publish, unpublish, fmt.Errorf("Invalid publish date")
+		}
+		publish = mysql.NullTime{Valid: true, Time: t}
+	}
+	if !helper.IsEmpty(unpublishAt) {
+		t, err := time.Parse(`2006-01-02 15:04:05`, unpublishAt)
+		if err != nil {
+			return publish, unpublish, fmt.Errorf("Invalid unpublish date")
+		}
+		if publish.Valid && !t.After(publish.Time) {
+			return publish, unpublish, fmt.Errorf("Unpublish date must be after publish date")
+		}
+		unpublish = mysql.NullTime{Valid: true, Time: t}
+	}
+	return publish, unpublish, nil
+}
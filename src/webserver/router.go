@@ -7,7 +7,13 @@ import (
 	"github.com/asepnur/meiko_course/src/webserver/handler/bot"
 	"github.com/asepnur/meiko_course/src/webserver/handler/course"
 	"github.com/asepnur/meiko_course/src/webserver/handler/file"
+	"github.com/asepnur/meiko_course/src/webserver/handler/forum"
+	"github.com/asepnur/meiko_course/src/webserver/handler/grade"
+	"github.com/asepnur/meiko_course/src/webserver/handler/group"
+	"github.com/asepnur/meiko_course/src/webserver/handler/information"
 	"github.com/asepnur/meiko_course/src/webserver/handler/place"
+	"github.com/asepnur/meiko_course/src/webserver/handler/quiz"
+	"github.com/asepnur/meiko_course/src/webserver/handler/rubric"
 	"github.com/asepnur/meiko_course/src/webserver/handler/tutorial"
 
 	"github.com/julienschmidt/httprouter"
@@ -40,6 +46,7 @@ func loadRouter(r *httprouter.Router) {
 	r.POST("/api/admin/v1/course", auth.MustAuthorize(course.CreateHandler))
 	r.GET("/api/admin/v1/course/:schedule_id", auth.MustAuthorize(course.ReadDetailHandler))                      //read
 	r.GET("/api/admin/v1/course/:schedule_id/parameter", auth.MustAuthorize(course.ReadScheduleParameterHandler)) //read
+	r.GET("/api/admin/v1/course/:schedule_id/roster", auth.MustAuthorize(course.ExportRosterHandler))
 	r.PATCH("/api/admin/v1/course/:schedule_id", auth.MustAuthorize(course.UpdateHandler))
 	r.DELETE("/api/admin/v1/course/:schedule_id", auth.MustAuthorize(course.DeleteScheduleHandler))
 	r.POST("/api/admin/v1/course/:schedule_id/assistant", auth.MustAuthorize(course.AddAssistantHandler))
@@ -78,13 +85,45 @@ func loadRouter(r *httprouter.Router) {
 	r.PATCH("/api/admin/v1/assignment/:id", auth.MustAuthorize(assignment.UpdateHandler))
 	r.GET("/api/admin/v1/assignment/:id", auth.MustAuthorize(assignment.DetailHandler))
 	r.DELETE("/api/admin/v1/assignment/:id", auth.MustAuthorize(assignment.DeleteHandler))
+	r.GET("/api/admin/v1/assignment/:id/extension", auth.MustAuthorize(assignment.ReadExtensionHandler))
+	r.POST("/api/admin/v1/assignment/:id/extension", auth.MustAuthorize(assignment.CreateExtensionHandler))
+	r.DELETE("/api/admin/v1/assignment/:id/extension/:identity_code", auth.MustAuthorize(assignment.DeleteExtensionHandler))
 	// r.GET("/api/admin/v1/assignment/:id/:assignment_id", auth.MustAuthorize(assignment.GetUploadedAssignmentByAdminHandler))
-	// r.GET("/api/admin/v1/score/:schedule_id/:assignment_id", auth.MustAuthorize(assignment.GetDetailAssignmentByAdmin))
-	// r.POST("/api/admin/v1/score/:schedule_id/:assignment_id/create", auth.MustAuthorize(assignment.CreateScoreHandler)) // update score
+	r.GET("/api/admin/v1/score/:schedule_id/:assignment_id", auth.MustAuthorize(assignment.GetDetailAssignmentByAdmin))
+	r.PATCH("/api/admin/v1/score/:schedule_id/:assignment_id", auth.MustAuthorize(assignment.UpdateScoreHandler))
+	r.POST("/api/admin/v1/score/:schedule_id/:assignment_id/create", auth.MustAuthorize(assignment.CreateScoreHandler)) // update score
+	r.POST("/api/admin/v1/score/:schedule_id/:assignment_id/upload", auth.MustAuthorize(assignment.UploadScoreHandler))
+	r.POST("/api/admin/v1/score/:schedule_id/:assignment_id/group/:group_id", auth.MustAuthorize(assignment.UpdateGroupScoreHandler))
+	r.GET("/api/admin/v1/score/:schedule_id/:assignment_id/version/:identity_code", auth.MustAuthorize(assignment.ReadVersionHandler))
+	r.PATCH("/api/admin/v1/score/:schedule_id/:assignment_id/version/:identity_code", auth.MustAuthorize(assignment.PinVersionHandler))
+	r.PATCH("/api/admin/v1/score/:schedule_id/:assignment_id/rubric", auth.MustAuthorize(assignment.AttachRubricHandler))
+	r.GET("/api/admin/v1/score/:schedule_id/:assignment_id/rubric/:identity_code", auth.MustAuthorize(assignment.ReadRubricScoreHandler))
+	r.POST("/api/admin/v1/score/:schedule_id/:assignment_id/rubric/:identity_code", auth.MustAuthorize(assignment.FillRubricHandler))
+	r.GET("/api/admin/v1/score/:schedule_id/:assignment_id/feedback/:identity_code", auth.MustAuthorize(assignment.ReadFeedbackHandler))
+	r.POST("/api/admin/v1/score/:schedule_id/:assignment_id/feedback/:identity_code", auth.MustAuthorize(assignment.CreateFeedbackHandler))
+	r.GET("/api/admin/v1/score/:schedule_id/:assignment_id/similarity", auth.MustAuthorize(assignment.ReadSimilarityHandler))
+	r.POST("/api/admin/v1/score/:schedule_id/:assignment_id/similarity", auth.MustAuthorize(assignment.CreateSimilarityHandler))
+	r.GET("/api/admin/v1/score/:schedule_id/:assignment_id/similarity/:identity_code/:other_identity_code", auth.MustAuthorize(assignment.ReadSimilarityPairHandler))
+	r.GET("/api/admin/v1/score/:schedule_id/:assignment_id/log", auth.MustAuthorize(assignment.ReadScoreLogHandler))
+	r.GET("/api/admin/v1/score/:schedule_id/:assignment_id/peer", auth.MustAuthorize(assignment.ReadPeerReviewAdminHandler))
+	r.POST("/api/admin/v1/score/:schedule_id/:assignment_id/peer", auth.MustAuthorize(assignment.ApplyPeerScoreHandler))
+	r.PATCH("/api/admin/v1/score/:schedule_id/:assignment_id/peer/:review_id", auth.MustAuthorize(assignment.ModeratePeerReviewHandler))
+	r.POST("/api/admin/v1/score/:schedule_id/:assignment_id/reveal", auth.MustAuthorize(assignment.RevealHandler))
+	r.GET("/api/admin/v1/score/:schedule_id/:assignment_id/autograder", auth.MustAuthorize(assignment.ReadAutograderHandler))
+	r.POST("/api/admin/v1/score/:schedule_id/:assignment_id/autograder", auth.MustAuthorize(assignment.CreateAutograderHandler))
+	r.POST("/api/admin/v1/score/:schedule_id/:assignment_id/autograder/confirm", auth.MustAuthorize(assignment.ConfirmAutograderHandler))
+	r.GET("/api/admin/v1/score/:schedule_id/:assignment_id/autograder/test", auth.MustAuthorize(assignment.ReadAutograderTestHandler))
+	r.POST("/api/admin/v1/score/:schedule_id/:assignment_id/autograder/test", auth.MustAuthorize(assignment.CreateAutograderTestHandler))
+	r.DELETE("/api/admin/v1/score/:schedule_id/:assignment_id/autograder/test/:test_id", auth.MustAuthorize(assignment.DeleteAutograderTestHandler))
 
-	r.GET("/api/v1/assignment", auth.MustAuthorize(assignment.GetHandler))           // assignment list
-	r.GET("/api/v1/assignment/:id", auth.MustAuthorize(assignment.GetDetailHandler)) // assignment detail
-	r.PUT("/api/v1/assignment/:id", auth.MustAuthorize(assignment.SubmitHandler))    // assignment submit
+	r.GET("/api/v1/assignment", auth.MustAuthorize(assignment.GetHandler))                         // assignment list
+	r.GET("/api/v1/assignment/:id", auth.MustAuthorize(assignment.GetDetailHandler))               // assignment detail
+	r.PUT("/api/v1/assignment/:id", auth.MustAuthorize(assignment.SubmitHandler))                  // assignment submit
+	r.POST("/api/v1/assignment/:id/feedback", auth.MustAuthorize(assignment.ReplyFeedbackHandler)) // reply assistant feedback
+	r.GET("/api/v1/assignment/:id/regrade", auth.MustAuthorize(assignment.ReadRegradeHandler))
+	r.POST("/api/v1/assignment/:id/regrade", auth.MustAuthorize(assignment.CreateRegradeHandler))
+	r.GET("/api/v1/assignment/:id/peer", auth.MustAuthorize(assignment.ReadPeerReviewHandler))
+	r.POST("/api/v1/assignment/:id/peer/:review_id", auth.MustAuthorize(assignment.SubmitPeerReviewHandler))
 	// r.POST("/api/v1/assignment", auth.MustAuthorize(assignment.CreateHandlerByUser))                                     // create upload by user
 	// r.GET("/api/v1/assignment/:id/:schedule_id/:assignment_id", auth.MustAuthorize(assignment.GetUploadedDetailHandler)) // detail user assignments
 	// r.GET("/api/v1/assignment-schedule", auth.MustAuthorize(assignment.GetAssignmentByScheduleHandler))                  // List assignments
@@ -92,11 +131,94 @@ func loadRouter(r *httprouter.Router) {
 	// r.GET("/api/v1/grade/:id", auth.MustAuthorize(assignment.GradeBySchedule))
 	// ===================== End Assignment Handler =====================
 
+	// ========================== Grade Handler =========================
+	r.GET("/api/admin/v1/grade/:schedule_id/policy", auth.MustAuthorize(grade.ReadPolicyHandler))
+	r.PATCH("/api/admin/v1/grade/:schedule_id/policy", auth.MustAuthorize(grade.UpdatePolicyHandler))
+	r.GET("/api/admin/v1/grade/:schedule_id/parameter", auth.MustAuthorize(grade.ReadParameterHandler))
+	r.POST("/api/admin/v1/grade/:schedule_id/parameter/publish", auth.MustAuthorize(grade.PublishParameterHandler))
+	r.GET("/api/admin/v1/grade/:schedule_id/scale", auth.MustAuthorize(grade.ReadScaleHandler))
+	r.PATCH("/api/admin/v1/grade/:schedule_id/scale", auth.MustAuthorize(grade.UpdateScaleHandler))
+	r.GET("/api/admin/v1/grade/:schedule_id/publication", auth.MustAuthorize(grade.ReadPublicationHandler))
+	r.POST("/api/admin/v1/grade/:schedule_id/publish", auth.MustAuthorize(grade.PublishHandler))
+	r.POST("/api/admin/v1/grade/:schedule_id/unpublish", auth.MustAuthorize(grade.UnpublishHandler))
+	// ======================== End Grade Handler =======================
+
+	// ======================== Gradebook Handler =======================
+	r.GET("/api/admin/v1/gradebook/:schedule_id", auth.MustAuthorize(assignment.ReadGradebookHandler))
+	r.POST("/api/admin/v1/gradebook/:schedule_id", auth.MustAuthorize(assignment.ImportGradebookHandler))
+	r.GET("/api/admin/v1/gradebook/:schedule_id/log/:identity_code", auth.MustAuthorize(assignment.ReadStudentScoreLogHandler))
+	r.GET("/api/admin/v1/statistic/:schedule_id", auth.MustAuthorize(assignment.ReadStatisticHandler))
+	r.GET("/api/admin/v1/regrade/:schedule_id", auth.MustAuthorize(assignment.ReadRegradeQueueHandler))
+	r.POST("/api/admin/v1/regrade/:schedule_id/:regrade_id", auth.MustAuthorize(assignment.ResolveRegradeHandler))
+	// ====================== End Gradebook Handler =====================
+
+	// ========================== Group Handler =========================
+	r.GET("/api/admin/v1/group/:schedule_id", auth.MustAuthorize(group.ReadHandler))
+	r.POST("/api/admin/v1/group/:schedule_id", auth.MustAuthorize(group.CreateHandler))
+	r.PATCH("/api/admin/v1/group/:schedule_id/:group_id", auth.MustAuthorize(group.UpdateHandler))
+	r.DELETE("/api/admin/v1/group/:schedule_id/:group_id", auth.MustAuthorize(group.DeleteHandler))
+	// ======================== End Group Handler =======================
+
+	// ========================= Rubric Handler =========================
+	r.GET("/api/admin/v1/rubric/:schedule_id", auth.MustAuthorize(rubric.ReadHandler))
+	r.POST("/api/admin/v1/rubric/:schedule_id", auth.MustAuthorize(rubric.CreateHandler))
+	r.PATCH("/api/admin/v1/rubric/:schedule_id/:rubric_id", auth.MustAuthorize(rubric.UpdateHandler))
+	r.DELETE("/api/admin/v1/rubric/:schedule_id/:rubric_id", auth.MustAuthorize(rubric.DeleteHandler))
+	r.POST("/api/admin/v1/rubric/:schedule_id/:rubric_id/copy", auth.MustAuthorize(rubric.CopyHandler))
+	// ======================= End Rubric Handler =======================
+
+	// ========================== Quiz Handler ==========================
+	r.GET("/api/admin/v1/quiz/:schedule_id/bank", auth.MustAuthorize(quiz.ReadBankHandler))
+	r.POST("/api/admin/v1/quiz/:schedule_id/bank", auth.MustAuthorize(quiz.CreateBankHandler))
+	r.PATCH("/api/admin/v1/quiz/:schedule_id/bank/:bank_id", auth.MustAuthorize(quiz.UpdateBankHandler))
+	r.DELETE("/api/admin/v1/quiz/:schedule_id/bank/:bank_id", auth.MustAuthorize(quiz.DeleteBankHandler))
+	r.GET("/api/admin/v1/quiz/:schedule_id/bank/:bank_id/question", auth.MustAuthorize(quiz.ReadQuestionHandler))
+	r.POST("/api/admin/v1/quiz/:schedule_id/bank/:bank_id/question", auth.MustAuthorize(quiz.CreateQuestionHandler))
+	r.PATCH("/api/admin/v1/quiz/:schedule_id/bank/:bank_id/question/:question_id", auth.MustAuthorize(quiz.UpdateQuestionHandler))
+	r.DELETE("/api/admin/v1/quiz/:schedule_id/bank/:bank_id/question/:question_id", auth.MustAuthorize(quiz.DeleteQuestionHandler))
+	r.GET("/api/admin/v1/quiz/:schedule_id/assignment/:assignment_id", auth.MustAuthorize(quiz.ReadHandler))
+	r.PATCH("/api/admin/v1/quiz/:schedule_id/assignment/:assignment_id", auth.MustAuthorize(quiz.UpdateHandler))
+	r.GET("/api/admin/v1/quiz/:schedule_id/assignment/:assignment_id/review", auth.MustAuthorize(quiz.ReadReviewHandler))
+	r.POST("/api/admin/v1/quiz/:schedule_id/assignment/:assignment_id/review/:attempt_id", auth.MustAuthorize(quiz.ReviewHandler))
+
+	r.GET("/api/v1/quiz/:assignment_id", auth.MustAuthorize(quiz.ReadStudentHandler))
+	r.POST("/api/v1/quiz/:assignment_id/attempt", auth.MustAuthorize(quiz.StartHandler))
+	r.GET("/api/v1/quiz/:assignment_id/attempt/:attempt_id", auth.MustAuthorize(quiz.ReadAttemptHandler))
+	r.PATCH("/api/v1/quiz/:assignment_id/attempt/:attempt_id", auth.MustAuthorize(quiz.SaveAnswerHandler))
+	r.POST("/api/v1/quiz/:assignment_id/attempt/:attempt_id/submit", auth.MustAuthorize(quiz.SubmitHandler))
+	// ======================== End Quiz Handler ========================
+
 	// ========================== Place Handler =========================
 	// Public section
 	r.GET("/api/v1/place/search", place.SearchHandler)
 	// ======================== End Place Handler =======================
 
+	// ======================= Information Handler ======================
+	r.GET("/api/admin/v1/information", auth.MustAuthorize(information.ReadHandler))
+	r.POST("/api/admin/v1/information", auth.MustAuthorize(information.CreateHandler))
+	r.GET("/api/admin/v1/information/:information_id", auth.MustAuthorize(information.ReadDetailHandler))
+	r.PATCH("/api/admin/v1/information/:information_id", auth.MustAuthorize(information.UpdateHandler))
+	r.DELETE("/api/admin/v1/information/:information_id", auth.MustAuthorize(information.DeleteHandler))
+	r.PATCH("/api/admin/v1/information/:information_id/pin", auth.MustAuthorize(information.PinHandler))
+
+	r.GET("/api/v1/information", auth.MustAuthorize(information.GetHandler))                       // information feed
+	r.GET("/api/v1/information/:information_id", auth.MustAuthorize(information.GetDetailHandler)) // information detail
+	// ===================== End Information Handler ====================
+
+	// ========================== Forum Handler =========================
+	r.GET("/api/v1/forum", auth.MustAuthorize(forum.ReadHandler))
+	r.POST("/api/v1/forum", auth.MustAuthorize(forum.CreateHandler))
+	r.GET("/api/v1/forum/:thread_id", auth.MustAuthorize(forum.DetailHandler))
+	r.PATCH("/api/v1/forum/:thread_id", auth.MustAuthorize(forum.UpdateHandler))
+	r.DELETE("/api/v1/forum/:thread_id", auth.MustAuthorize(forum.DeleteHandler))
+	r.PATCH("/api/v1/forum/:thread_id/pin", auth.MustAuthorize(forum.PinHandler))
+	r.PATCH("/api/v1/forum/:thread_id/lock", auth.MustAuthorize(forum.LockHandler))
+	r.PATCH("/api/v1/forum/:thread_id/accept", auth.MustAuthorize(forum.AcceptHandler))
+	r.POST("/api/v1/forum/:thread_id/reply", auth.MustAuthorize(forum.CreateReplyHandler))
+	r.PATCH("/api/v1/forum/:thread_id/reply/:reply_id", auth.MustAuthorize(forum.UpdateReplyHandler))
+	r.DELETE("/api/v1/forum/:thread_id/reply/:reply_id", auth.MustAuthorize(forum.DeleteReplyHandler))
+	// ======================== End Forum Handler =======================
+
 	r.POST("/api/internal/v1/course/getall", auth.Oauth(course.ExchangeInvolvedHandler))
 	r.POST("/api/internal/v1/course/getone", auth.Oauth(course.ExchangeByScheduleHandler))
 }